@@ -0,0 +1,45 @@
+package adminauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		noAuth   bool
+		want     int
+	}{
+		{name: "valid token", token: "secret", password: "secret", want: http.StatusOK},
+		{name: "wrong token", token: "secret", password: "guess", want: http.StatusUnauthorized},
+		{name: "no credentials", token: "secret", noAuth: true, want: http.StatusUnauthorized},
+		{name: "no token configured", token: "", password: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if !tt.noAuth {
+				req.SetBasicAuth("admin", tt.password)
+			}
+			w := httptest.NewRecorder()
+			Require(tt.token)(ok).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="admin", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"))
+				assert.Contains(t, w.Body.String(), ErrUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package apiauth guards the JSON API with API keys. Clients send the token
+// of a key as a bearer token; requests are let through when the key is active
+// and was granted the scope the route needs, and then act as the reader who
+// owns the key.
+package apiauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+
+	"go.uber.org/zap"
+)
+
+// Error messages
+const (
+	ErrMissingToken      = "API key required: send it as a bearer token in the Authorization header"
+	ErrInvalidToken      = "Invalid API key"
+	ErrExpiredToken      = "API key expired"
+	ErrRevokedToken      = "API key revoked"
+	ErrInsufficientScope = "API key lacks the scope "
+	ErrInternalServer    = "Internal server error"
+)
+
+// Authenticator finds the key of a token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.APIKey, error)
+}
+
+type contextKey struct{}
+
+// errorBody is the JSON body of refused requests, shaped like the API's own
+// errors.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// Require returns middleware that lets through requests with the bearer token
+// of an active key granted scope. It puts the key and its owner's reader ID
+// into the request context.
+func Require(auth Authenticator, scope domain.APIScope, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				refuse(w, http.StatusUnauthorized, `Bearer realm="api"`, ErrMissingToken)
+				return
+			}
+
+			key, err := auth.Authenticate(r.Context(), token)
+			switch {
+			case errors.Is(err, domain.ErrInvalidAPIKey):
+				refuse(w, http.StatusUnauthorized, `Bearer realm="api", error="invalid_token"`, ErrInvalidToken)
+				return
+			case errors.Is(err, domain.ErrAPIKeyExpired):
+				refuse(w, http.StatusUnauthorized, `Bearer realm="api", error="invalid_token"`, ErrExpiredToken)
+				return
+			case errors.Is(err, domain.ErrAPIKeyRevoked):
+				refuse(w, http.StatusUnauthorized, `Bearer realm="api", error="invalid_token"`, ErrRevokedToken)
+				return
+			case err != nil:
+				logger.Error("failed to authenticate API key", zap.Error(err))
+				refuse(w, http.StatusInternalServerError, "", ErrInternalServer)
+				return
+			}
+			if !key.Allows(scope) {
+				refuse(w, http.StatusForbidden, `Bearer realm="api", error="insufficient_scope", scope="`+string(scope)+`"`, ErrInsufficientScope+string(scope))
+				return
+			}
+
+			ctx := reader.WithID(WithKey(r.Context(), key), key.OwnerID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// WithKey returns a copy of ctx carrying the API key of the request.
+func WithKey(ctx context.Context, key *domain.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// Key returns the API key put into ctx by Require, or nil outside of it.
+func Key(ctx context.Context) *domain.APIKey {
+	key, _ := ctx.Value(contextKey{}).(*domain.APIKey)
+	return key
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func refuse(w http.ResponseWriter, status int, challenge, message string) {
+	if challenge != "" {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorBody{Error: message})
+}
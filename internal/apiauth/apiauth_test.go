@@ -0,0 +1,93 @@
+package apiauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// authenticatorFunc adapts a function to Authenticator.
+type authenticatorFunc func(ctx context.Context, token string) (*domain.APIKey, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	return f(ctx, token)
+}
+
+func TestRequire(t *testing.T) {
+	key := &domain.APIKey{OwnerID: "owner", Scopes: []domain.APIScope{domain.ScopePostsRead}}
+	auth := authenticatorFunc(func(ctx context.Context, token string) (*domain.APIKey, error) {
+		switch token {
+		case "good":
+			return key, nil
+		case "expired":
+			return nil, domain.ErrAPIKeyExpired
+		case "revoked":
+			return nil, domain.ErrAPIKeyRevoked
+		case "broken":
+			return nil, errors.New("connection reset")
+		}
+		return nil, domain.ErrInvalidAPIKey
+	})
+
+	var gotKey *domain.APIKey
+	var gotReader string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = Key(r.Context())
+		gotReader = reader.ID(r.Context())
+	})
+
+	tests := []struct {
+		name      string
+		header    string
+		scope     domain.APIScope
+		status    int
+		challenge string
+		message   string
+	}{
+		{name: "valid", header: "Bearer good", scope: domain.ScopePostsRead, status: http.StatusOK},
+		{name: "scheme is case insensitive", header: "bearer good", scope: domain.ScopePostsRead, status: http.StatusOK},
+		{name: "missing", scope: domain.ScopePostsRead, status: http.StatusUnauthorized, challenge: `Bearer realm="api"`, message: ErrMissingToken},
+		{name: "basic auth", header: "Basic Z29vZDo=", scope: domain.ScopePostsRead, status: http.StatusUnauthorized, message: ErrMissingToken},
+		{name: "unknown", header: "Bearer bad", scope: domain.ScopePostsRead, status: http.StatusUnauthorized, challenge: `Bearer realm="api", error="invalid_token"`, message: ErrInvalidToken},
+		{name: "expired", header: "Bearer expired", scope: domain.ScopePostsRead, status: http.StatusUnauthorized, message: ErrExpiredToken},
+		{name: "revoked", header: "Bearer revoked", scope: domain.ScopePostsRead, status: http.StatusUnauthorized, message: ErrRevokedToken},
+		{name: "missing scope", header: "Bearer good", scope: domain.ScopePostsWrite, status: http.StatusForbidden, challenge: `Bearer realm="api", error="insufficient_scope", scope="posts:write"`, message: ErrInsufficientScope + "posts:write"},
+		{name: "storage failure", header: "Bearer broken", scope: domain.ScopePostsRead, status: http.StatusInternalServerError, message: ErrInternalServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKey, gotReader = nil, ""
+			req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			Require(auth, tt.scope, zap.NewNop())(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.status == http.StatusOK {
+				assert.Same(t, key, gotKey)
+				assert.Equal(t, "owner", gotReader, "requests act as the owner of the key")
+				return
+			}
+			assert.Nil(t, gotKey)
+			if tt.challenge != "" {
+				assert.Equal(t, tt.challenge, rec.Header().Get("WWW-Authenticate"))
+			}
+			var body struct{ Error string }
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.message, body.Error)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrInvalidAPIKeyName   = errors.New("API key name must be between 1 and 100 characters")
+	ErrInvalidAPIScope     = errors.New("API keys need at least one of the scopes posts:read and posts:write")
+	ErrInvalidAPIKeyExpiry = errors.New("API keys expire after at most 365 days")
+	// ErrInvalidAPIKey is returned for tokens that are malformed or match no key.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrAPIKeyExpired = errors.New("API key expired")
+	ErrAPIKeyRevoked = errors.New("API key revoked")
+)
+
+const (
+	// APITokenPrefix starts every API token, so that leaked tokens are easy
+	// to spot.
+	APITokenPrefix = "news_"
+	// MaxAPIKeyNameLength caps the length of an API key name.
+	MaxAPIKeyNameLength = 100
+	// MaxAPIKeyLifetime caps the time until an API key expires.
+	MaxAPIKeyLifetime = 365 * 24 * time.Hour
+	// APIKeyUsageResolution is how often the last use of a key is recorded:
+	// uses within this long of the recorded one are not written.
+	APIKeyUsageResolution = time.Minute
+
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+// APIScope is a permission granted to an API key.
+type APIScope string
+
+const (
+	ScopePostsRead  APIScope = "posts:read"
+	ScopePostsWrite APIScope = "posts:write"
+)
+
+// APIScopes lists the scopes an API key can be granted.
+var APIScopes = []APIScope{ScopePostsRead, ScopePostsWrite}
+
+func isAPIScope(s APIScope) bool {
+	for _, scope := range APIScopes {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyStatus is whether an API key can still be used.
+type APIKeyStatus string
+
+const (
+	APIKeyActive  APIKeyStatus = "active"
+	APIKeyExpired APIKeyStatus = "expired"
+	APIKeyRevoked APIKeyStatus = "revoked"
+)
+
+// APIKey lets integration scripts call the JSON API on behalf of a reader.
+// The token is shown once when the key is created; only its SHA-256 hash is
+// stored, next to the random prefix that finds the key again.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	OwnerID    string             `bson:"owner_id"`
+	Name       string             `bson:"name"`
+	Prefix     string             `bson:"prefix"`
+	Hash       string             `bson:"hash"`
+	Scopes     []APIScope         `bson:"scopes"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty"`
+	LastUsedAt time.Time          `bson:"last_used_at,omitempty"`
+	RevokedAt  time.Time          `bson:"revoked_at,omitempty"`
+}
+
+// APIKeyInput holds the user-supplied fields of an API key. A zero ExpiresIn
+// creates a key that does not expire.
+type APIKeyInput struct {
+	Name      string
+	Scopes    []APIScope
+	ExpiresIn time.Duration
+}
+
+// NewAPIKey creates a key of the reader ownerID and returns it together with
+// its token.
+func NewAPIKey(ownerID string, in APIKeyInput, now time.Time) (*APIKey, string, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || utf8.RuneCountInString(in.Name) > MaxAPIKeyNameLength {
+		return nil, "", ErrInvalidAPIKeyName
+	}
+	if in.ExpiresIn < 0 || in.ExpiresIn > MaxAPIKeyLifetime {
+		return nil, "", ErrInvalidAPIKeyExpiry
+	}
+
+	requested := make(map[APIScope]bool, len(in.Scopes))
+	for _, s := range in.Scopes {
+		if !isAPIScope(s) {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidAPIScope, s)
+		}
+		requested[s] = true
+	}
+	var scopes []APIScope
+	for _, s := range APIScopes {
+		if requested[s] {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidAPIScope
+	}
+
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key prefix: %w", err)
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key secret: %w", err)
+	}
+	token := APITokenPrefix + prefix + "_" + secret
+
+	key := &APIKey{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   ownerID,
+		Name:      in.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIToken(token),
+		Scopes:    scopes,
+		CreatedAt: now,
+	}
+	if in.ExpiresIn > 0 {
+		key.ExpiresAt = now.Add(in.ExpiresIn)
+	}
+	return key, token, nil
+}
+
+// ParseAPIToken returns the prefix of the key a token belongs to.
+func ParseAPIToken(token string) (string, error) {
+	rest, ok := strings.CutPrefix(token, APITokenPrefix)
+	if !ok {
+		return "", ErrInvalidAPIKey
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixBytes || len(secret) != 2*apiKeySecretBytes {
+		return "", ErrInvalidAPIKey
+	}
+	return prefix, nil
+}
+
+// Verify checks that token is the token of the key and that the key can
+// still be used at now.
+func (k *APIKey) Verify(token string, now time.Time) error {
+	if subtle.ConstantTimeCompare([]byte(hashAPIToken(token)), []byte(k.Hash)) != 1 {
+		return ErrInvalidAPIKey
+	}
+	switch k.Status(now) {
+	case APIKeyRevoked:
+		return ErrAPIKeyRevoked
+	case APIKeyExpired:
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// Status returns whether the key is active, expired or revoked at now.
+func (k *APIKey) Status(now time.Time) APIKeyStatus {
+	if !k.RevokedAt.IsZero() {
+		return APIKeyRevoked
+	}
+	if !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt) {
+		return APIKeyExpired
+	}
+	return APIKeyActive
+}
+
+// Allows reports whether the key was granted scope.
+func (k *APIKey) Allows(scope APIScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Masked returns the public part of the token, which identifies the key in
+// listings.
+func (k *APIKey) Masked() string {
+	return APITokenPrefix + k.Prefix + "_…"
+}
+
+// UsageDue reports whether a use of the key at now should be recorded.
+func (k *APIKey) UsageDue(now time.Time) bool {
+	return now.Sub(k.LastUsedAt) >= APIKeyUsageResolution
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// APIKeyRepository stores API keys.
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *APIKey) error
+	// GetByPrefix returns ErrAPIKeyNotFound when no key has the prefix.
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	// GetByOwner returns the keys of a reader, newest first.
+	GetByOwner(ctx context.Context, ownerID string) ([]*APIKey, error)
+	// Revoke revokes the key id of the reader ownerID at at. It returns
+	// ErrAPIKeyNotFound when the reader has no such key that is not revoked yet.
+	Revoke(ctx context.Context, ownerID, id string, at time.Time) error
+	// RecordUsage sets the last use of the key id to at.
+	RecordUsage(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
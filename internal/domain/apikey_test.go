@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+
+	key, token, err := NewAPIKey("reader", APIKeyInput{
+		Name:      " Import script ",
+		Scopes:    []APIScope{ScopePostsWrite, ScopePostsRead, ScopePostsWrite},
+		ExpiresIn: 30 * 24 * time.Hour,
+	}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "reader", key.OwnerID)
+	assert.Equal(t, "Import script", key.Name)
+	assert.Equal(t, []APIScope{ScopePostsRead, ScopePostsWrite}, key.Scopes)
+	assert.Equal(t, now.Add(30*24*time.Hour), key.ExpiresAt)
+	assert.True(t, strings.HasPrefix(token, APITokenPrefix+key.Prefix+"_"))
+	assert.NotContains(t, key.Hash, token[len(APITokenPrefix+key.Prefix+"_"):], "only the hash of the token is kept")
+	assert.Equal(t, APITokenPrefix+key.Prefix+"_…", key.Masked())
+
+	prefix, err := ParseAPIToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, key.Prefix, prefix)
+	assert.NoError(t, key.Verify(token, now))
+
+	_, other, err := NewAPIKey("reader", APIKeyInput{Name: "Other", Scopes: []APIScope{ScopePostsRead}}, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.ErrorIs(t, key.Verify(other, now), ErrInvalidAPIKey)
+}
+
+func TestNewAPIKey_Invalid(t *testing.T) {
+	read := []APIScope{ScopePostsRead}
+	tests := []struct {
+		name string
+		in   APIKeyInput
+		want error
+	}{
+		{name: "blank name", in: APIKeyInput{Name: "  ", Scopes: read}, want: ErrInvalidAPIKeyName},
+		{name: "long name", in: APIKeyInput{Name: strings.Repeat("a", MaxAPIKeyNameLength+1), Scopes: read}, want: ErrInvalidAPIKeyName},
+		{name: "no scope", in: APIKeyInput{Name: "Script"}, want: ErrInvalidAPIScope},
+		{name: "unknown scope", in: APIKeyInput{Name: "Script", Scopes: []APIScope{"posts:admin"}}, want: ErrInvalidAPIScope},
+		{name: "negative expiry", in: APIKeyInput{Name: "Script", Scopes: read, ExpiresIn: -time.Hour}, want: ErrInvalidAPIKeyExpiry},
+		{name: "long expiry", in: APIKeyInput{Name: "Script", Scopes: read, ExpiresIn: MaxAPIKeyLifetime + time.Hour}, want: ErrInvalidAPIKeyExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewAPIKey("reader", tt.in, time.Now())
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseAPIToken_Invalid(t *testing.T) {
+	for _, token := range []string{"", "news_", "news_abc_def", "token_0123456789ab_" + strings.Repeat("0", 64), "news_0123456789ab" + strings.Repeat("0", 64)} {
+		_, err := ParseAPIToken(token)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, token)
+	}
+}
+
+func TestAPIKey_Status(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	key, token, err := NewAPIKey("reader", APIKeyInput{Name: "Script", Scopes: []APIScope{ScopePostsRead}, ExpiresIn: time.Hour}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, APIKeyActive, key.Status(now))
+	assert.True(t, key.Allows(ScopePostsRead))
+	assert.False(t, key.Allows(ScopePostsWrite))
+
+	assert.Equal(t, APIKeyExpired, key.Status(now.Add(time.Hour)))
+	assert.ErrorIs(t, key.Verify(token, now.Add(time.Hour)), ErrAPIKeyExpired)
+
+	key.RevokedAt = now
+	assert.Equal(t, APIKeyRevoked, key.Status(now))
+	assert.ErrorIs(t, key.Verify(token, now), ErrAPIKeyRevoked)
+
+	never, _, err := NewAPIKey("reader", APIKeyInput{Name: "Script", Scopes: []APIScope{ScopePostsRead}}, now)
+	require.NoError(t, err)
+	assert.True(t, never.ExpiresAt.IsZero())
+	assert.Equal(t, APIKeyActive, never.Status(now.Add(10*MaxAPIKeyLifetime)))
+}
+
+func TestAPIKey_UsageDue(t *testing.T) {
+	now := time.Now()
+	key := &APIKey{}
+	assert.True(t, key.UsageDue(now), "a key never used")
+	key.LastUsedAt = now
+	assert.False(t, key.UsageDue(now.Add(APIKeyUsageResolution-time.Second)))
+	assert.True(t, key.UsageDue(now.Add(APIKeyUsageResolution)))
+}
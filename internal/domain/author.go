@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAuthorNotFound    = errors.New("author not found")
+	ErrInvalidAuthorBio  = errors.New("bio must be at most 1000 characters")
+	ErrInvalidAvatar     = errors.New("avatar must be an absolute http or https URL")
+	ErrInvalidSocialLink = errors.New("social links must be absolute http or https URLs, at most 5")
+)
+
+const (
+	// AuthorPageSize is the number of posts on a page of an author page.
+	AuthorPageSize = 9
+	// MaxAuthorBioLength caps the length of an author bio.
+	MaxAuthorBioLength = 1000
+	// MaxSocialLinks caps the number of social links of an author.
+	MaxSocialLinks = 5
+)
+
+// Author is the profile of a post author. Posts name their author as free
+// text; the profile is found by the handle derived from that name.
+type Author struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	Handle    string             `bson:"handle" json:"handle"`
+	Name      string             `bson:"name" json:"name"`
+	Bio       string             `bson:"bio,omitempty" json:"bio,omitempty"`
+	AvatarURL string             `bson:"avatar_url,omitempty" json:"avatar_url,omitempty"`
+	Links     []string           `bson:"links,omitempty" json:"links,omitempty"`
+	UpdatedAt time.Time          `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// AuthorInput holds the editable fields of an author profile.
+type AuthorInput struct {
+	Bio       string
+	AvatarURL string
+	Links     []string
+}
+
+// AuthorHandle derives the handle of the author named name: its letters and
+// digits, lowercased, with a hyphen for every run of other characters. It is
+// empty when name has no letters or digits.
+func AuthorHandle(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// NewAuthor creates the empty profile of the author named name.
+func NewAuthor(name string) *Author {
+	name = strings.TrimSpace(name)
+	return &Author{Handle: AuthorHandle(name), Name: name}
+}
+
+// Update replaces the editable fields of the profile. Blank links are
+// dropped.
+func (a *Author) Update(in AuthorInput, now time.Time) error {
+	in.Bio = strings.TrimSpace(in.Bio)
+	in.AvatarURL = strings.TrimSpace(in.AvatarURL)
+	var links []string
+	for _, link := range in.Links {
+		if link = strings.TrimSpace(link); link != "" {
+			links = append(links, link)
+		}
+	}
+
+	if utf8.RuneCountInString(in.Bio) > MaxAuthorBioLength {
+		return ErrInvalidAuthorBio
+	}
+	if in.AvatarURL != "" && !isHTTPURL(in.AvatarURL) {
+		return ErrInvalidAvatar
+	}
+	if len(links) > MaxSocialLinks {
+		return ErrInvalidSocialLink
+	}
+	for _, link := range links {
+		if !isHTTPURL(link) {
+			return ErrInvalidSocialLink
+		}
+	}
+
+	a.Bio = in.Bio
+	a.AvatarURL = in.AvatarURL
+	a.Links = links
+	a.UpdatedAt = now
+	return nil
+}
+
+// Initial returns the first letter of the name, shown in place of a missing
+// avatar.
+func (a *Author) Initial() string {
+	r, _ := utf8.DecodeRuneInString(a.Name)
+	if r == utf8.RuneError {
+		return "?"
+	}
+	return string(unicode.ToUpper(r))
+}
+
+// SocialLink is a social link of an author labelled for display.
+type SocialLink struct {
+	Label string
+	URL   string
+}
+
+// socialLabels names the sites whose links are labelled by site rather than
+// by host.
+var socialLabels = map[string]string{
+	"x.com":           "X",
+	"twitter.com":     "X",
+	"mastodon.social": "Mastodon",
+	"bsky.app":        "Bluesky",
+	"linkedin.com":    "LinkedIn",
+	"github.com":      "GitHub",
+	"instagram.com":   "Instagram",
+	"facebook.com":    "Facebook",
+	"threads.net":     "Threads",
+	"youtube.com":     "YouTube",
+}
+
+// SocialLinks returns the links of the author labelled with the name of their
+// site, or their host for other sites.
+func (a *Author) SocialLinks() []SocialLink {
+	links := make([]SocialLink, 0, len(a.Links))
+	for _, link := range a.Links {
+		u, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		label, ok := socialLabels[host]
+		if !ok {
+			label = host
+		}
+		links = append(links, SocialLink{Label: label, URL: link})
+	}
+	return links
+}
+
+// AuthorRepository stores author profiles.
+type AuthorRepository interface {
+	// GetByHandle returns ErrAuthorNotFound when the author has no profile.
+	GetByHandle(ctx context.Context, handle string) (*Author, error)
+	// Save creates or replaces the profile with the handle of a.
+	Save(ctx context.Context, a *Author) error
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorHandle(t *testing.T) {
+	tests := map[string]string{
+		"Anna Smith":        "anna-smith",
+		"  O'Brien, Seán  ": "o-brien-seán",
+		"Staff -- Reporter": "staff-reporter",
+		"Team 42":           "team-42",
+		"!!!":               "",
+		"":                  "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, AuthorHandle(name), name)
+	}
+}
+
+func TestAuthor_Update(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		in   AuthorInput
+		want error
+	}{
+		{name: "valid", in: AuthorInput{Bio: "Covers politics.", AvatarURL: "https://cdn.news.example/anna.jpg", Links: []string{"https://x.com/anna"}}},
+		{name: "empty", in: AuthorInput{}},
+		{name: "long bio", in: AuthorInput{Bio: strings.Repeat("a", MaxAuthorBioLength+1)}, want: ErrInvalidAuthorBio},
+		{name: "relative avatar", in: AuthorInput{AvatarURL: "/anna.jpg"}, want: ErrInvalidAvatar},
+		{name: "invalid link", in: AuthorInput{Links: []string{"javascript:alert(1)"}}, want: ErrInvalidSocialLink},
+		{name: "too many links", in: AuthorInput{Links: strings.Split(strings.Repeat("https://news.example/,", MaxSocialLinks+1), ",")}, want: ErrInvalidSocialLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAuthor("Anna Smith")
+			err := a.Update(tt.in, now)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				assert.True(t, a.UpdatedAt.IsZero())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, now, a.UpdatedAt)
+		})
+	}
+}
+
+func TestAuthor_SocialLinks(t *testing.T) {
+	a := &Author{Links: []string{"https://www.linkedin.com/in/anna", "https://anna.example/blog"}}
+	assert.Equal(t, []SocialLink{
+		{Label: "LinkedIn", URL: "https://www.linkedin.com/in/anna"},
+		{Label: "anna.example", URL: "https://anna.example/blog"},
+	}, a.SocialLinks())
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookmarkPageSize is the number of posts on a page of a reading list.
+const BookmarkPageSize = 9
+
+// Bookmark records that a reader saved a post to read later.
+type Bookmark struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReaderID  string             `bson:"reader_id" json:"-"`
+	PostID    primitive.ObjectID `bson:"post_id" json:"post_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NewBookmark creates a bookmark of postID made at now.
+func NewBookmark(readerID string, postID primitive.ObjectID, now time.Time) *Bookmark {
+	return &Bookmark{
+		ID:        primitive.NewObjectID(),
+		ReaderID:  readerID,
+		PostID:    postID,
+		CreatedAt: now,
+	}
+}
+
+// BookmarkRepository stores the bookmarks of readers.
+type BookmarkRepository interface {
+	// Add saves b unless the reader already bookmarked the post, and reports
+	// whether it did.
+	Add(ctx context.Context, b *Bookmark) (bool, error)
+	// Remove deletes the bookmark of a post and reports whether there was one.
+	Remove(ctx context.Context, readerID, postID string) (bool, error)
+	// GetByReader returns a page of the reader's bookmarks, most recent
+	// first, and the total number of them.
+	GetByReader(ctx context.Context, readerID string, page, pageSize int) ([]*Bookmark, int64, error)
+	// Bookmarked returns the IDs among postIDs the reader bookmarked.
+	Bookmarked(ctx context.Context, readerID string, postIDs []string) ([]string, error)
+	DeleteByPost(ctx context.Context, postID string) error
+}
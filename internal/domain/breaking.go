@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidBreakingDuration = errors.New("breaking news duration must be between 5 minutes and 48 hours")
+	ErrBreakingDraft           = errors.New("only published posts can be breaking news")
+)
+
+// Bounds of how long a post can be flagged as breaking news.
+const (
+	MinBreakingDuration = 5 * time.Minute
+	MaxBreakingDuration = 48 * time.Hour
+)
+
+// Breaking flags a post as breaking news from Since until it expires at Until.
+type Breaking struct {
+	Since time.Time `bson:"since" json:"since"`
+	Until time.Time `bson:"until" json:"until"`
+}
+
+// NewBreaking creates a flag starting at now that lasts for d.
+func NewBreaking(now time.Time, d time.Duration) (*Breaking, error) {
+	if d < MinBreakingDuration || d > MaxBreakingDuration {
+		return nil, ErrInvalidBreakingDuration
+	}
+	return &Breaking{Since: now, Until: now.Add(d)}, nil
+}
+
+// IsBreaking reports whether the post is published and flagged as breaking
+// news that has not expired yet.
+func (p *Post) IsBreaking() bool {
+	return p.Breaking != nil && p.IsPublished() && time.Now().Before(p.Breaking.Until)
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBreaking(t *testing.T) {
+	now := time.Now()
+
+	b, err := NewBreaking(now, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, now, b.Since)
+	assert.Equal(t, now.Add(2*time.Hour), b.Until)
+
+	_, err = NewBreaking(now, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidBreakingDuration)
+	_, err = NewBreaking(now, 72*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidBreakingDuration)
+}
+
+func TestPost_IsBreaking(t *testing.T) {
+	active := &Breaking{Since: time.Now(), Until: time.Now().Add(time.Hour)}
+	expired := &Breaking{Since: time.Now().Add(-2 * time.Hour), Until: time.Now().Add(-time.Hour)}
+
+	assert.False(t, (&Post{}).IsBreaking())
+	assert.True(t, (&Post{Breaking: active}).IsBreaking())
+	assert.False(t, (&Post{Breaking: expired}).IsBreaking())
+	assert.False(t, (&Post{Breaking: active, Status: PostStatusDraft}).IsBreaking())
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidContributor = errors.New("contributors need a name of at most 100 characters and a role of author, co-author, photographer or editor, at most 20 of them")
+
+const (
+	// MaxContributors caps the number of contributors credited on a post.
+	MaxContributors = 20
+	// MaxContributorNameLength caps the length of a contributor name.
+	MaxContributorNameLength = 100
+)
+
+// ContributorRole is what a contributor did for a post.
+type ContributorRole string
+
+const (
+	RoleAuthor       ContributorRole = "author"
+	RoleCoAuthor     ContributorRole = "co-author"
+	RolePhotographer ContributorRole = "photographer"
+	RoleEditor       ContributorRole = "editor"
+)
+
+// ContributorRoles lists the roles in the order the contributor picker offers
+// them.
+var ContributorRoles = []ContributorRole{RoleAuthor, RoleCoAuthor, RolePhotographer, RoleEditor}
+
+var roleLabels = map[ContributorRole]string{
+	RoleAuthor:       "Author",
+	RoleCoAuthor:     "Co-author",
+	RolePhotographer: "Photographer",
+	RoleEditor:       "Editor",
+}
+
+// Label returns the reader-facing name of the role.
+func (r ContributorRole) Label() string {
+	return roleLabels[r]
+}
+
+// IsWriter reports whether the role is credited in the byline: authors and
+// co-authors.
+func (r ContributorRole) IsWriter() bool {
+	return r == RoleAuthor || r == RoleCoAuthor
+}
+
+func isContributorRole(r ContributorRole) bool {
+	_, ok := roleLabels[r]
+	return ok
+}
+
+// Contributor is a person credited on a post.
+type Contributor struct {
+	Name string          `bson:"name" json:"name"`
+	Role ContributorRole `bson:"role" json:"role"`
+}
+
+// Handle returns the handle of the contributor's author page.
+func (c Contributor) Handle() string {
+	return AuthorHandle(c.Name)
+}
+
+// normalizeContributors trims names, drops rows without a name, defaults
+// the role to author and drops repeated credits, keeping the order.
+func normalizeContributors(contributors []Contributor) []Contributor {
+	var normalized []Contributor
+	seen := make(map[Contributor]struct{}, len(contributors))
+	for _, c := range contributors {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			continue
+		}
+		if c.Role == "" {
+			c.Role = RoleAuthor
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		normalized = append(normalized, c)
+	}
+	return normalized
+}
+
+func validateContributors(contributors []Contributor) error {
+	if len(contributors) > MaxContributors {
+		return ErrInvalidContributor
+	}
+	for _, c := range contributors {
+		if !isContributorRole(c.Role) || utf8.RuneCountInString(c.Name) > MaxContributorNameLength {
+			return ErrInvalidContributor
+		}
+	}
+	return nil
+}
+
+// leadAuthor returns the name of the first writer among contributors.
+func leadAuthor(contributors []Contributor) string {
+	for _, c := range contributors {
+		if c.Role.IsWriter() {
+			return c.Name
+		}
+	}
+	return ""
+}
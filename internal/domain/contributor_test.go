@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPostFromInput_Contributors(t *testing.T) {
+	base := PostInput{Title: "Budget vote", Content: "Parliament passed the budget"}
+
+	tests := []struct {
+		name         string
+		author       string
+		contributors []Contributor
+		wantAuthor   string
+		want         []Contributor
+		wantErr      error
+	}{
+		{
+			name:       "author only",
+			author:     " Anna ",
+			wantAuthor: "Anna",
+			want:       []Contributor{{Name: "Anna", Role: RoleAuthor}},
+		},
+		{
+			name:   "contributors replace the author",
+			author: "Anna",
+			contributors: []Contributor{
+				{Name: "Boris", Role: RolePhotographer},
+				{Name: " Clara ", Role: RoleCoAuthor},
+				{Name: "  "},
+				{Name: "Dan"},
+				{Name: "Boris", Role: RolePhotographer},
+			},
+			wantAuthor: "Clara",
+			want: []Contributor{
+				{Name: "Boris", Role: RolePhotographer},
+				{Name: "Clara", Role: RoleCoAuthor},
+				{Name: "Dan", Role: RoleAuthor},
+			},
+		},
+		{
+			name:         "no writer",
+			contributors: []Contributor{{Name: "Boris", Role: RoleEditor}},
+			want:         []Contributor{{Name: "Boris", Role: RoleEditor}},
+		},
+		{
+			name:         "unknown role",
+			contributors: []Contributor{{Name: "Anna", Role: "ghostwriter"}},
+			wantErr:      ErrInvalidContributor,
+		},
+		{
+			name:         "long name",
+			contributors: []Contributor{{Name: strings.Repeat("a", MaxContributorNameLength+1)}},
+			wantErr:      ErrInvalidContributor,
+		},
+		{
+			name:         "too many",
+			contributors: manyContributors(MaxContributors + 1),
+			wantErr:      ErrInvalidContributor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := base
+			in.Author = tt.author
+			in.Contributors = tt.contributors
+			p, err := NewPostFromInput(in)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAuthor, p.Author)
+			assert.Equal(t, tt.want, p.Contributors)
+		})
+	}
+}
+
+func manyContributors(n int) []Contributor {
+	contributors := make([]Contributor, n)
+	for i := range contributors {
+		contributors[i] = Contributor{Name: strings.Repeat("a", i+1)}
+	}
+	return contributors
+}
+
+func TestPost_Credits(t *testing.T) {
+	legacy := &Post{Author: "Anna"}
+	assert.Equal(t, []Contributor{{Name: "Anna", Role: RoleAuthor}}, legacy.Credits(), "posts stored before contributors credit their author")
+	assert.Empty(t, (&Post{}).Credits())
+
+	p := &Post{Author: "Anna", Contributors: []Contributor{
+		{Name: "Boris", Role: RolePhotographer},
+		{Name: "Anna", Role: RoleAuthor},
+		{Name: "Clara", Role: RoleCoAuthor},
+		{Name: "Dan", Role: RoleEditor},
+	}}
+	assert.Equal(t, []Contributor{{Name: "Anna", Role: RoleAuthor}, {Name: "Clara", Role: RoleCoAuthor}}, p.Writers())
+	assert.Equal(t, []Contributor{{Name: "Boris", Role: RolePhotographer}, {Name: "Dan", Role: RoleEditor}}, p.OtherCredits())
+}
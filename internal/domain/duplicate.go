@@ -0,0 +1,28 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// PostFingerprint is the content fingerprint of a stored post.
+type PostFingerprint struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Fingerprint int64              `bson:"fingerprint"`
+}
+
+// SimilarPost is a stored post that is a near-duplicate of another text.
+type SimilarPost struct {
+	*Post
+	// Distance is the number of differing fingerprint bits; 0 means the texts
+	// use the same words.
+	Distance int
+}
+
+// Similarity returns how alike the two texts are as a percentage.
+func (s SimilarPost) Similarity() int {
+	return 100 - s.Distance*100/64
+}
+
+// DuplicateCluster is a group of posts that are near-duplicates of each other,
+// oldest first.
+type DuplicateCluster struct {
+	Posts []*Post
+}
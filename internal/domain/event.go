@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PostEventType identifies the kind of change that happened to a post.
+type PostEventType string
+
+const (
+	PostCreated PostEventType = "post.created"
+	PostUpdated PostEventType = "post.updated"
+	PostDeleted PostEventType = "post.deleted"
+
+	// PostPublished follows PostCreated or PostUpdated when a post becomes
+	// publicly visible.
+	PostPublished PostEventType = "post.published"
+
+	// PostBreaking follows setting or clearing the breaking news flag of a
+	// post; Post.Breaking tells which.
+	PostBreaking PostEventType = "post.breaking"
+)
+
+// PostEvent describes a change to a post. Post is nil for deletions. ID is
+// only set on events relayed from the outbox and identifies redeliveries.
+type PostEvent struct {
+	ID         string
+	Type       PostEventType
+	PostID     string
+	Post       *Post
+	OccurredAt time.Time
+}
+
+// PostEventHandler is notified after a post mutation has been persisted.
+type PostEventHandler interface {
+	HandlePostEvent(ctx context.Context, event PostEvent) error
+}
+
+// PostRebuilder is implemented by event handlers that maintain derived state
+// which can be reloaded from the complete set of posts, e.g. on startup.
+type PostRebuilder interface {
+	Rebuild(ctx context.Context, posts []*Post) error
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeedPageSize is the number of posts on a page of the "For you" feed.
+const FeedPageSize = 9
+
+// PostRead records that a reader opened a post. The category, writers and
+// tags of the post are copied, so that the reader's interests can be told
+// without loading the posts.
+type PostRead struct {
+	ReaderID string             `bson:"reader_id" json:"-"`
+	PostID   primitive.ObjectID `bson:"post_id" json:"post_id"`
+	Category string             `bson:"category,omitempty" json:"category,omitempty"`
+	Authors  []string           `bson:"authors,omitempty" json:"authors,omitempty"`
+	Tags     []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	ReadAt   time.Time          `bson:"read_at" json:"read_at"`
+}
+
+// NewPostRead records that the reader opened p at now.
+func NewPostRead(readerID string, p *Post, now time.Time) *PostRead {
+	read := &PostRead{
+		ReaderID: readerID,
+		PostID:   p.ID,
+		Category: p.Category,
+		Tags:     p.Tags,
+		ReadAt:   now,
+	}
+	for _, c := range p.Writers() {
+		read.Authors = append(read.Authors, c.Name)
+	}
+	return read
+}
+
+// FollowTargets returns the category, writers and tags of the post read, as
+// PostFollowTargets does for the post itself.
+func (r *PostRead) FollowTargets() []FollowTarget {
+	p := &Post{Category: r.Category, Tags: r.Tags}
+	for _, name := range r.Authors {
+		p.Contributors = append(p.Contributors, Contributor{Name: name, Role: RoleAuthor})
+	}
+	return PostFollowTargets(p)
+}
+
+// FeedPage is a page of the "For you" feed. Personalized is false when
+// nothing is known about the reader yet, and the page lists the latest posts
+// instead.
+type FeedPage struct {
+	PostList
+	Personalized bool
+}
+
+// ReadingHistoryRepository stores the posts readers opened.
+type ReadingHistoryRepository interface {
+	// Record saves r, replacing an earlier read of the same post.
+	Record(ctx context.Context, r *PostRead) error
+	// GetByReader returns the latest reads of the reader, most recent first.
+	GetByReader(ctx context.Context, readerID string, limit int) ([]*PostRead, error)
+}
@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidEmail           = errors.New("email must be a valid address")
+	ErrInvalidDigestFrequency = errors.New("frequency must be daily or weekly")
+	ErrSubscriberNotFound     = errors.New("subscriber not found")
+)
+
+// DigestFrequency is how often a subscriber receives the newsletter digest.
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// DigestFrequencies lists the frequencies a subscriber can choose from.
+var DigestFrequencies = []DigestFrequency{DigestDaily, DigestWeekly}
+
+// Period returns the time between two digests.
+func (f DigestFrequency) Period() time.Duration {
+	if f == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+const (
+	// MaxDigestPosts caps the number of posts in one digest.
+	MaxDigestPosts = 20
+	// DigestRetryDelay is the wait before a digest that could not be sent is
+	// tried again.
+	DigestRetryDelay = 15 * time.Minute
+)
+
+// Subscriber is an email address signed up for the newsletter digest. It
+// receives digests only once it confirmed the address through the link with
+// Token, which also serves the unsubscribe link.
+type Subscriber struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email       string             `bson:"email" json:"email"`
+	Frequency   DigestFrequency    `bson:"frequency" json:"frequency"`
+	Token       string             `bson:"token" json:"-"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	ConfirmedAt *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+
+	// LastDigestAt is when the last digest was sent; the next one holds the
+	// posts created since. NextDigestAt is when it is due.
+	LastDigestAt time.Time `bson:"last_digest_at,omitempty" json:"last_digest_at,omitempty"`
+	NextDigestAt time.Time `bson:"next_digest_at,omitempty" json:"next_digest_at,omitempty"`
+}
+
+// NewSubscriber creates an unconfirmed subscriber with a random token.
+func NewSubscriber(email string, frequency DigestFrequency) (*Subscriber, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if !isDigestFrequency(frequency) {
+		return nil, ErrInvalidDigestFrequency
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate subscriber token: %w", err)
+	}
+
+	return &Subscriber{
+		ID:        primitive.NewObjectID(),
+		Email:     email,
+		Frequency: frequency,
+		Token:     hex.EncodeToString(token),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NormalizeEmail checks that s is a bare email address and lower-cases it.
+func NormalizeEmail(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(s), nil
+}
+
+func isDigestFrequency(f DigestFrequency) bool {
+	for _, v := range DigestFrequencies {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Confirmed reports whether the subscriber confirmed its address.
+func (s *Subscriber) Confirmed() bool {
+	return s.ConfirmedAt != nil
+}
+
+// Confirm records the confirmation at now and schedules the first digest one
+// period later. Confirming again changes nothing.
+func (s *Subscriber) Confirm(now time.Time) {
+	if s.Confirmed() {
+		return
+	}
+	s.ConfirmedAt = &now
+	s.LastDigestAt = now
+	s.NextDigestAt = now.Add(s.Frequency.Period())
+}
+
+// SetFrequency changes the frequency and reschedules the next digest.
+func (s *Subscriber) SetFrequency(f DigestFrequency) error {
+	if !isDigestFrequency(f) {
+		return ErrInvalidDigestFrequency
+	}
+	s.Frequency = f
+	if s.Confirmed() {
+		s.NextDigestAt = s.LastDigestAt.Add(f.Period())
+	}
+	return nil
+}
+
+// DigestSent schedules the next digest after a digest was sent, or was
+// skipped for lack of new posts, at the given time.
+func (s *Subscriber) DigestSent(at time.Time) {
+	s.LastDigestAt = at
+	s.NextDigestAt = at.Add(s.Frequency.Period())
+}
+
+// DigestFailed schedules another try after the digest could not be sent.
+func (s *Subscriber) DigestFailed(at time.Time) {
+	s.NextDigestAt = at.Add(DigestRetryDelay)
+}
+
+// EmailKind tells the emails sent to subscribers apart in the send log.
+type EmailKind string
+
+const (
+	EmailConfirmation EmailKind = "confirmation"
+	EmailDigest       EmailKind = "digest"
+)
+
+// EmailMessage is an email with an HTML and a plain-text body. Unsubscribe
+// is the URL advertised in the List-Unsubscribe header, if any.
+type EmailMessage struct {
+	To          string
+	Subject     string
+	HTML        string
+	Text        string
+	Unsubscribe string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg *EmailMessage) error
+}
+
+// NewsletterSend is an entry of the send log: one email sent, or attempted,
+// to one subscriber. Email is kept so the log stays readable after the
+// subscriber unsubscribed.
+type NewsletterSend struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	SubscriberID primitive.ObjectID `bson:"subscriber_id"`
+	Email        string             `bson:"email"`
+	Kind         EmailKind          `bson:"kind"`
+	Subject      string             `bson:"subject"`
+	PostCount    int                `bson:"post_count,omitempty"`
+	SentAt       time.Time          `bson:"sent_at"`
+	Error        string             `bson:"error,omitempty"`
+}
+
+// NewNewsletterSend records msg sent to sub at the given time. A non-nil err
+// means sending failed.
+func NewNewsletterSend(sub *Subscriber, kind EmailKind, msg *EmailMessage, postCount int, at time.Time, err error) *NewsletterSend {
+	send := &NewsletterSend{
+		ID:           primitive.NewObjectID(),
+		SubscriberID: sub.ID,
+		Email:        sub.Email,
+		Kind:         kind,
+		Subject:      msg.Subject,
+		PostCount:    postCount,
+		SentAt:       at,
+	}
+	if err != nil {
+		send.Error = err.Error()
+	}
+	return send
+}
+
+// Failed reports whether the email could not be sent.
+func (s *NewsletterSend) Failed() bool {
+	return s.Error != ""
+}
+
+// SubscriberRepository stores newsletter subscribers.
+type SubscriberRepository interface {
+	Create(ctx context.Context, sub *Subscriber) error
+	GetByID(ctx context.Context, id string) (*Subscriber, error)
+	GetByEmail(ctx context.Context, email string) (*Subscriber, error)
+	GetByToken(ctx context.Context, token string) (*Subscriber, error)
+	GetAll(ctx context.Context) ([]*Subscriber, error)
+	Save(ctx context.Context, sub *Subscriber) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// ClaimDue returns a confirmed subscriber whose digest is due at now and
+	// postpones the digest by lease so that no other scheduler claims it while
+	// it is sent. It returns nil when no digest is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*Subscriber, error)
+}
+
+// NewsletterSendRepository stores the send log.
+type NewsletterSendRepository interface {
+	Create(ctx context.Context, send *NewsletterSend) error
+	GetRecent(ctx context.Context, limit int) ([]*NewsletterSend, error)
+	GetBySubscriber(ctx context.Context, subscriberID primitive.ObjectID, limit int) ([]*NewsletterSend, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubscriber(t *testing.T) {
+	sub, err := NewSubscriber("  Anna@Example.com ", DigestWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, "anna@example.com", sub.Email)
+	assert.Len(t, sub.Token, 64)
+	assert.False(t, sub.Confirmed())
+	assert.True(t, sub.NextDigestAt.IsZero(), "unconfirmed subscribers get no digest")
+
+	for _, email := range []string{"", "anna", "Anna <anna@example.com>", "anna@example.com, boris@example.com"} {
+		_, err := NewSubscriber(email, DigestDaily)
+		assert.ErrorIs(t, err, ErrInvalidEmail, email)
+	}
+	_, err = NewSubscriber("anna@example.com", "hourly")
+	assert.ErrorIs(t, err, ErrInvalidDigestFrequency)
+}
+
+func TestSubscriber_Schedule(t *testing.T) {
+	sub, err := NewSubscriber("anna@example.com", DigestDaily)
+	require.NoError(t, err)
+	now := time.Now()
+
+	sub.Confirm(now)
+	require.True(t, sub.Confirmed())
+	assert.Equal(t, now, sub.LastDigestAt)
+	assert.Equal(t, now.Add(24*time.Hour), sub.NextDigestAt)
+
+	sub.Confirm(now.Add(time.Hour))
+	assert.Equal(t, now, *sub.ConfirmedAt, "confirming again changes nothing")
+
+	require.NoError(t, sub.SetFrequency(DigestWeekly))
+	assert.Equal(t, now.Add(7*24*time.Hour), sub.NextDigestAt)
+
+	later := now.Add(7 * 24 * time.Hour)
+	sub.DigestFailed(later)
+	assert.Equal(t, now, sub.LastDigestAt)
+	assert.Equal(t, later.Add(DigestRetryDelay), sub.NextDigestAt)
+
+	sub.DigestSent(later)
+	assert.Equal(t, later, sub.LastDigestAt)
+	assert.Equal(t, later.Add(7*24*time.Hour), sub.NextDigestAt)
+}
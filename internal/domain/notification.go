@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidFollowKind    = errors.New("follow kind must be category, author or tag")
+	ErrInvalidFollowValue   = errors.New("a category, author or tag must be between 1 and 100 characters")
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrSettingsNotFound     = errors.New("notification settings not found")
+	ErrEmailUnavailable     = errors.New("email notifications are not available")
+)
+
+// FollowKind is what a reader follows.
+type FollowKind string
+
+const (
+	FollowCategory FollowKind = "category"
+	FollowAuthor   FollowKind = "author"
+	FollowTag      FollowKind = "tag"
+)
+
+// FollowKinds lists the kinds of follows in the order a post is matched
+// against them.
+var FollowKinds = []FollowKind{FollowCategory, FollowAuthor, FollowTag}
+
+// MaxFollowValueLength caps the length of a followed category or author.
+const MaxFollowValueLength = 100
+
+// FollowTarget is a category, an author or a tag a reader can follow.
+type FollowTarget struct {
+	Kind  FollowKind `bson:"kind" json:"kind"`
+	Value string     `bson:"value" json:"value"`
+}
+
+// NewFollowTarget checks kind and trims value. Tags are lowercased like the
+// tags of posts.
+func NewFollowTarget(kind FollowKind, value string) (FollowTarget, error) {
+	if !isFollowKind(kind) {
+		return FollowTarget{}, ErrInvalidFollowKind
+	}
+	value = strings.TrimSpace(value)
+	if kind == FollowTag {
+		value = strings.ToLower(value)
+	}
+	if n := utf8.RuneCountInString(value); n == 0 || n > MaxFollowValueLength {
+		return FollowTarget{}, ErrInvalidFollowValue
+	}
+	return FollowTarget{Kind: kind, Value: value}, nil
+}
+
+func isFollowKind(k FollowKind) bool {
+	for _, v := range FollowKinds {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+// PostFollowTargets returns the targets whose followers are notified of p:
+// its category, when set, its authors and co-authors, and its tags.
+func PostFollowTargets(p *Post) []FollowTarget {
+	var targets []FollowTarget
+	if p.Category != "" {
+		targets = append(targets, FollowTarget{Kind: FollowCategory, Value: p.Category})
+	}
+	for _, c := range p.Writers() {
+		targets = append(targets, FollowTarget{Kind: FollowAuthor, Value: c.Name})
+	}
+	for _, tag := range p.Tags {
+		targets = append(targets, FollowTarget{Kind: FollowTag, Value: tag})
+	}
+	return targets
+}
+
+// Follow records that a reader wants to be notified of the posts published
+// in a category, by an author or with a tag.
+type Follow struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReaderID     string             `bson:"reader_id" json:"-"`
+	FollowTarget `bson:",inline"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+// NewFollow creates a follow of target by the reader.
+func NewFollow(readerID string, target FollowTarget) *Follow {
+	return &Follow{
+		ID:           primitive.NewObjectID(),
+		ReaderID:     readerID,
+		FollowTarget: target,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// FollowState tells whether the reader follows a target, for rendering
+// follow buttons.
+type FollowState struct {
+	FollowTarget
+	Following bool
+}
+
+// Notification tells a reader that a post matching one of its follows was
+// published. Reason is the follow that matched; a reader gets at most one
+// notification per post.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReaderID  string             `bson:"reader_id" json:"-"`
+	PostID    primitive.ObjectID `bson:"post_id" json:"post_id"`
+	Title     string             `bson:"title" json:"title"`
+	Reason    FollowTarget       `bson:"reason" json:"reason"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ReadAt    *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+}
+
+// NewNotification creates an unread notification of post for the reader.
+func NewNotification(readerID string, post *Post, reason FollowTarget, at time.Time) *Notification {
+	return &Notification{
+		ID:        primitive.NewObjectID(),
+		ReaderID:  readerID,
+		PostID:    post.ID,
+		Title:     post.Title,
+		Reason:    reason,
+		CreatedAt: at,
+	}
+}
+
+// Read reports whether the reader has seen the notification.
+func (n *Notification) Read() bool {
+	return n.ReadAt != nil
+}
+
+// NotificationSettings holds how a reader wants to be notified besides the
+// inbox. Notifications are emailed to Email once the reader confirmed it
+// through the link with Token, which also serves the unsubscribe link.
+type NotificationSettings struct {
+	ReaderID    string     `bson:"_id"`
+	Email       string     `bson:"email,omitempty"`
+	Token       string     `bson:"token,omitempty"`
+	ConfirmedAt *time.Time `bson:"confirmed_at,omitempty"`
+}
+
+// SetEmail changes the address notifications are emailed to. A new address
+// gets a new token and has to be confirmed; an empty one stops the emails.
+// It reports whether a confirmation email is needed.
+func (s *NotificationSettings) SetEmail(email string) (bool, error) {
+	if strings.TrimSpace(email) == "" {
+		s.StopEmail()
+		return false, nil
+	}
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return false, err
+	}
+	if email == s.Email && s.EmailConfirmed() {
+		return false, nil
+	}
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return false, fmt.Errorf("failed to generate notification token: %w", err)
+	}
+	s.Email = email
+	s.Token = hex.EncodeToString(token)
+	s.ConfirmedAt = nil
+	return true, nil
+}
+
+// ConfirmEmail records that the reader confirmed its address at now.
+func (s *NotificationSettings) ConfirmEmail(now time.Time) {
+	if s.ConfirmedAt == nil {
+		s.ConfirmedAt = &now
+	}
+}
+
+// StopEmail forgets the address so that no more emails are sent.
+func (s *NotificationSettings) StopEmail() {
+	s.Email = ""
+	s.Token = ""
+	s.ConfirmedAt = nil
+}
+
+// EmailConfirmed reports whether notifications are emailed.
+func (s *NotificationSettings) EmailConfirmed() bool {
+	return s.Email != "" && s.ConfirmedAt != nil
+}
+
+// Notifier delivers notifications outside the app, e.g. by email.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+// FollowRepository stores the follows of readers.
+type FollowRepository interface {
+	// Add stores f. Following a target again changes nothing.
+	Add(ctx context.Context, f *Follow) error
+	Remove(ctx context.Context, readerID string, target FollowTarget) error
+	GetByReader(ctx context.Context, readerID string) ([]*Follow, error)
+	// GetByTargets returns the follows of any of targets.
+	GetByTargets(ctx context.Context, targets []FollowTarget) ([]*Follow, error)
+}
+
+// NotificationRepository stores the inboxes of readers.
+type NotificationRepository interface {
+	// Add stores the notifications a reader has none for the same post yet
+	// and returns them.
+	Add(ctx context.Context, notifications []*Notification) ([]*Notification, error)
+	GetByReader(ctx context.Context, readerID string, limit int) ([]*Notification, error)
+	CountUnread(ctx context.Context, readerID string) (int64, error)
+	// MarkRead marks a notification of the reader as read at now and returns it.
+	MarkRead(ctx context.Context, readerID, id string, now time.Time) (*Notification, error)
+	MarkAllRead(ctx context.Context, readerID string, now time.Time) error
+	DeleteByPost(ctx context.Context, postID string) error
+}
+
+// NotificationSettingsRepository stores the notification settings of readers.
+type NotificationSettingsRepository interface {
+	// Get returns the settings of the reader; a reader that never saved any
+	// gets empty settings.
+	Get(ctx context.Context, readerID string) (*NotificationSettings, error)
+	GetByToken(ctx context.Context, token string) (*NotificationSettings, error)
+	Save(ctx context.Context, s *NotificationSettings) error
+}
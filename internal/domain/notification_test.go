@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFollowTarget(t *testing.T) {
+	target, err := NewFollowTarget(FollowCategory, "  Politics ")
+	require.NoError(t, err)
+	assert.Equal(t, FollowTarget{Kind: FollowCategory, Value: "Politics"}, target)
+
+	_, err = NewFollowTarget("source", "Reuters")
+	assert.ErrorIs(t, err, ErrInvalidFollowKind)
+	_, err = NewFollowTarget(FollowAuthor, "   ")
+	assert.ErrorIs(t, err, ErrInvalidFollowValue)
+	_, err = NewFollowTarget(FollowAuthor, strings.Repeat("a", MaxFollowValueLength+1))
+	assert.ErrorIs(t, err, ErrInvalidFollowValue)
+
+	target, err = NewFollowTarget(FollowTag, " Elections ")
+	require.NoError(t, err)
+	assert.Equal(t, FollowTarget{Kind: FollowTag, Value: "elections"}, target)
+}
+
+func TestPostFollowTargets(t *testing.T) {
+	assert.Equal(t, []FollowTarget{
+		{Kind: FollowCategory, Value: "Politics"},
+		{Kind: FollowAuthor, Value: "Anna"},
+		{Kind: FollowTag, Value: "elections"},
+	}, PostFollowTargets(&Post{Category: "Politics", Author: "Anna", Tags: []string{"elections"}}))
+	assert.Empty(t, PostFollowTargets(&Post{}))
+}
+
+func TestNotificationSettings_SetEmail(t *testing.T) {
+	s := &NotificationSettings{ReaderID: "reader"}
+
+	confirm, err := s.SetEmail("Anna@Example.com")
+	require.NoError(t, err)
+	assert.True(t, confirm)
+	assert.Equal(t, "anna@example.com", s.Email)
+	assert.Len(t, s.Token, 64)
+	assert.False(t, s.EmailConfirmed())
+
+	s.ConfirmEmail(time.Now())
+	assert.True(t, s.EmailConfirmed())
+	token := s.Token
+
+	confirm, err = s.SetEmail("anna@example.com")
+	require.NoError(t, err)
+	assert.False(t, confirm, "the confirmed address needs no new confirmation")
+	assert.Equal(t, token, s.Token)
+
+	confirm, err = s.SetEmail("boris@example.com")
+	require.NoError(t, err)
+	assert.True(t, confirm)
+	assert.NotEqual(t, token, s.Token)
+	assert.False(t, s.EmailConfirmed())
+
+	_, err = s.SetEmail("boris")
+	assert.ErrorIs(t, err, ErrInvalidEmail)
+	assert.Equal(t, "boris@example.com", s.Email)
+
+	confirm, err = s.SetEmail("")
+	require.NoError(t, err)
+	assert.False(t, confirm)
+	assert.Empty(t, s.Email)
+	assert.Empty(t, s.Token)
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxRetryDelay is the wait before an entry that failed to publish is
+// relayed again. Every further retry waits twice as long, up to MaxOutboxRetryDelay.
+const OutboxRetryDelay = time.Second
+
+// MaxOutboxRetryDelay caps the backoff between relay attempts. Entries are
+// retried until they are published.
+const MaxOutboxRetryDelay = 5 * time.Minute
+
+// OutboxEntry is a post event recorded in the same transaction as the
+// mutation that caused it. The relay publishes entries until it succeeds,
+// so every committed mutation is published at least once.
+type OutboxEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Event      PostEventType      `bson:"event"`
+	PostID     string             `bson:"post_id"`
+	Post       *Post              `bson:"post,omitempty"`
+	OccurredAt time.Time          `bson:"occurred_at"`
+
+	SentAt        time.Time `bson:"sent_at,omitempty"`
+	Attempts      int       `bson:"attempts"`
+	NextAttemptAt time.Time `bson:"next_attempt_at"`
+	LastError     string    `bson:"last_error,omitempty"`
+}
+
+// NewOutboxEntry creates an entry for event that is due right away.
+func NewOutboxEntry(event PostEvent) *OutboxEntry {
+	return &OutboxEntry{
+		ID:            primitive.NewObjectID(),
+		Event:         event.Type,
+		PostID:        event.PostID,
+		Post:          event.Post,
+		OccurredAt:    event.OccurredAt,
+		NextAttemptAt: event.OccurredAt,
+	}
+}
+
+// PostEvent returns the event to publish. Its ID is the entry ID, which stays
+// the same when the entry is published again.
+func (e *OutboxEntry) PostEvent() PostEvent {
+	return PostEvent{
+		ID:         e.ID.Hex(),
+		Type:       e.Event,
+		PostID:     e.PostID,
+		Post:       e.Post,
+		OccurredAt: e.OccurredAt,
+	}
+}
+
+// Sent reports whether the entry has been published.
+func (e *OutboxEntry) Sent() bool {
+	return !e.SentAt.IsZero()
+}
+
+// Relayed records a publish attempt made at the given time. A nil err marks
+// the entry as sent; otherwise it is retried with exponential backoff.
+func (e *OutboxEntry) Relayed(at time.Time, err error) {
+	e.Attempts++
+	if err == nil {
+		e.SentAt = at
+		e.LastError = ""
+		return
+	}
+
+	e.LastError = err.Error()
+	delay := MaxOutboxRetryDelay
+	if e.Attempts <= 16 {
+		delay = min(OutboxRetryDelay<<(e.Attempts-1), MaxOutboxRetryDelay)
+	}
+	e.NextAttemptAt = at.Add(delay)
+}
+
+// EventPublisher delivers relayed post events to a consumer such as in-process
+// handlers, webhooks or a message broker. Publishing may be repeated for the
+// same event, so consumers should deduplicate by PostEvent.ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event PostEvent) error
+}
+
+// OutboxRepository stores outbox entries and hands due ones to the relay.
+type OutboxRepository interface {
+	// Add records entries. Called with the context passed to a Transactor, it
+	// is part of the transaction.
+	Add(ctx context.Context, entries ...*OutboxEntry) error
+	// ClaimDue returns an unsent entry due at now and postpones it by lease so
+	// that no other relay claims it while it is published. Entries are claimed
+	// in the order they were recorded. It returns nil when nothing is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*OutboxEntry, error)
+	Save(ctx context.Context, entry *OutboxEntry) error
+}
+
+// Transactor runs fn so that the repository writes it makes with the context
+// it is given are committed together or not at all.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
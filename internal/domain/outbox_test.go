@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutboxEntry(t *testing.T) {
+	now := time.Now()
+	post := &Post{Title: "Budget approved"}
+	entry := NewOutboxEntry(PostEvent{Type: PostPublished, PostID: "post-1", Post: post, OccurredAt: now})
+
+	assert.False(t, entry.ID.IsZero())
+	assert.Equal(t, now, entry.NextAttemptAt)
+	assert.False(t, entry.Sent())
+
+	event := entry.PostEvent()
+	assert.Equal(t, entry.ID.Hex(), event.ID)
+	assert.Equal(t, PostPublished, event.Type)
+	assert.Equal(t, "post-1", event.PostID)
+	assert.Same(t, post, event.Post)
+	assert.Equal(t, now, event.OccurredAt)
+}
+
+func TestOutboxEntry_Relayed(t *testing.T) {
+	now := time.Now()
+	entry := NewOutboxEntry(PostEvent{Type: PostDeleted, PostID: "post-1", OccurredAt: now})
+
+	entry.Relayed(now, errors.New("broker unavailable"))
+	assert.False(t, entry.Sent())
+	assert.Equal(t, 1, entry.Attempts)
+	assert.Equal(t, "broker unavailable", entry.LastError)
+	assert.Equal(t, now.Add(OutboxRetryDelay), entry.NextAttemptAt)
+
+	entry.Relayed(now, errors.New("broker unavailable"))
+	assert.Equal(t, now.Add(2*OutboxRetryDelay), entry.NextAttemptAt)
+
+	entry.Attempts = 40
+	entry.Relayed(now, errors.New("broker unavailable"))
+	assert.Equal(t, now.Add(MaxOutboxRetryDelay), entry.NextAttemptAt)
+
+	entry.Relayed(now, nil)
+	require.True(t, entry.Sent())
+	assert.Equal(t, now, entry.SentAt)
+	assert.Empty(t, entry.LastError)
+}
@@ -2,7 +2,10 @@ package domain
 
 import (
 	"errors"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,6 +13,21 @@ import (
 var (
 	ErrInvalidTitle   = errors.New("title must be between 3 and 200 characters")
 	ErrInvalidContent = errors.New("content must be at least 10 characters")
+	ErrInvalidStatus  = errors.New("status must be draft or published")
+	ErrInvalidImage   = errors.New("image must be an absolute http or https URL")
+	ErrDuplicatePost  = errors.New("post was already imported")
+	ErrPostNotFound   = errors.New("post not found")
+
+	ErrInvalidSEOTitle       = errors.New("SEO title must be at most 100 characters")
+	ErrInvalidSEODescription = errors.New("SEO description must be at most 300 characters")
+)
+
+// PostStatus is the publication state of a post.
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
 )
 
 // Post represents a blog post with a title, content, and timestamps.
@@ -17,38 +35,183 @@ type Post struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Title     string             `bson:"title" json:"title" validate:"required,min=3,max=200"`
 	Content   string             `bson:"content" json:"content" validate:"required,min=10"`
+	Category  string             `bson:"category,omitempty" json:"category,omitempty"`
+	Tags      []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Author    string             `bson:"author,omitempty" json:"author,omitempty"`
+	ImageURL  string             `bson:"image_url,omitempty" json:"image_url,omitempty"`
+	Status    PostStatus         `bson:"status" json:"status"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+
+	// Contributors are the people credited on the post, in byline order.
+	// Author is the name of the first writer among them.
+	Contributors []Contributor `bson:"contributors,omitempty" json:"contributors,omitempty"`
+
+	// SEOTitle and SEODescription override the title and excerpt in search
+	// results and link previews.
+	SEOTitle       string `bson:"seo_title,omitempty" json:"seo_title,omitempty"`
+	SEODescription string `bson:"seo_description,omitempty" json:"seo_description,omitempty"`
+
+	// Breaking is set while the post is, or last was, flagged as breaking news.
+	Breaking *Breaking `bson:"breaking,omitempty" json:"breaking,omitempty"`
+
+	// Origin is set on posts ingested from an external feed.
+	Origin *PostOrigin `bson:"origin,omitempty" json:"origin,omitempty"`
+
+	// Fingerprint is the SimHash of the content, used to find
+	// near-duplicate stories. BSON has no unsigned integers, so the 64 bits are
+	// stored as int64.
+	Fingerprint int64 `bson:"fingerprint,omitempty" json:"-"`
+
+	ViewCount     int64 `bson:"view_count" json:"view_count"`
+	CommentCount  int64 `bson:"comment_count" json:"comment_count"`
+	BookmarkCount int64 `bson:"bookmark_count" json:"bookmark_count"`
+
+	// Highlight is populated for search results only and is never persisted.
+	Highlight *Highlight `bson:"-" json:"highlight,omitempty"`
+
+	// Bookmarked is set when the reader the post is shown to bookmarked it.
+	// It is never persisted.
+	Bookmarked bool `bson:"-" json:"-"`
 }
 
-// PostList is a paginated list of posts.
+// ParseTags splits a comma-separated tag list as typed into a form.
+func ParseTags(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// PostList is a paginated list of posts. TotalCount is zero when the query
+// asked to skip counting. NextCursor is empty on the last page.
 type PostList struct {
 	Posts      []*Post `json:"posts"`
-	TotalCount int64   `json:"total_count"`
+	TotalCount int64   `json:"total_count,omitempty"`
 	Page       int     `json:"page"`
 	PageSize   int     `json:"page_size"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// PostInput holds the editor-supplied fields of a post.
+type PostInput struct {
+	Title    string
+	Content  string
+	Category string
+	Tags     []string
+	Author   string
+	ImageURL string
+	Status   PostStatus
+
+	// Contributors replaces Author when set.
+	Contributors []Contributor
+
+	SEOTitle       string
+	SEODescription string
 }
 
-// NewPost creates a new post with the given title and content.
+// NewPost creates a new published post with the given title and content.
 // It returns an error if the title or content is invalid.
 func NewPost(title, content string) (*Post, error) {
-	if err := validatePostData(title, content); err != nil {
+	return NewPostFromInput(PostInput{Title: title, Content: content})
+}
+
+// NewPostFromInput creates a new post from editor input. An empty status
+// defaults to published.
+func NewPostFromInput(in PostInput) (*Post, error) {
+	in = normalizeInput(in)
+	if err := validateInput(in); err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
-	return &Post{
+	p := &Post{
 		ID:        primitive.NewObjectID(),
-		Title:     title,
-		Content:   content,
 		CreatedAt: now,
 		UpdatedAt: now,
-	}, nil
+	}
+	p.apply(in)
+	return p, nil
 }
 
-// Validate checks if the post's title and content meet the required constraints.
+// Validate checks if the post's title, content and status meet the required constraints.
 func (p *Post) Validate() error {
-	return validatePostData(p.Title, p.Content)
+	if err := validatePostData(p.Title, p.Content); err != nil {
+		return err
+	}
+	return validateStatus(p.Status)
+}
+
+// IsPublished reports whether the post is visible to readers.
+// Posts stored before statuses were introduced have no status and count as published.
+func (p *Post) IsPublished() bool {
+	return p.Status == PostStatusPublished || p.Status == ""
+}
+
+// Excerpt returns the start of the content cut at a word boundary to at most
+// maxLen characters, with an ellipsis appended when the content was cut.
+func (p *Post) Excerpt(maxLen int) string {
+	text := strings.Join(strings.Fields(p.Content), " ")
+	if utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+
+	// One rune past the limit shows whether the limit falls between words.
+	runes := []rune(text)
+	cut := string(runes[:maxLen+1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	} else {
+		cut = string(runes[:maxLen])
+	}
+	return strings.TrimRight(cut, ",.;:!?-") + "…"
+}
+
+// MetaTitle returns the title for search results and link previews.
+func (p *Post) MetaTitle() string {
+	if p.SEOTitle != "" {
+		return p.SEOTitle
+	}
+	return p.Title
+}
+
+// MetaDescription returns the description for search results and link
+// previews, falling back to an excerpt of the content.
+func (p *Post) MetaDescription() string {
+	if p.SEODescription != "" {
+		return p.SEODescription
+	}
+	return p.Excerpt(160)
+}
+
+// Credits returns the contributors of the post. Posts stored before
+// contributors existed credit their author.
+func (p *Post) Credits() []Contributor {
+	if len(p.Contributors) == 0 && p.Author != "" {
+		return []Contributor{{Name: p.Author, Role: RoleAuthor}}
+	}
+	return p.Contributors
+}
+
+// Writers returns the authors and co-authors of the post, in byline order.
+func (p *Post) Writers() []Contributor {
+	return p.creditsWhere(true)
+}
+
+// OtherCredits returns the contributors who did not write the post, such as
+// photographers and editors.
+func (p *Post) OtherCredits() []Contributor {
+	return p.creditsWhere(false)
+}
+
+func (p *Post) creditsWhere(writer bool) []Contributor {
+	var credits []Contributor
+	for _, c := range p.Credits() {
+		if c.Role.IsWriter() == writer {
+			credits = append(credits, c)
+		}
+	}
+	return credits
 }
 
 // Update changes the post's title and content.
@@ -64,6 +227,111 @@ func (p *Post) Update(title, content string) error {
 	return nil
 }
 
+// UpdateFromInput replaces all editor-supplied fields of the post.
+// It returns an error if the new data is invalid.
+func (p *Post) UpdateFromInput(in PostInput) error {
+	in = normalizeInput(in)
+	if err := validateInput(in); err != nil {
+		return err
+	}
+
+	p.apply(in)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *Post) apply(in PostInput) {
+	p.Title = in.Title
+	p.Content = in.Content
+	p.Category = in.Category
+	p.Tags = in.Tags
+	p.Author = in.Author
+	p.Contributors = in.Contributors
+	p.ImageURL = in.ImageURL
+	p.SEOTitle = in.SEOTitle
+	p.SEODescription = in.SEODescription
+	p.Status = in.Status
+}
+
+// normalizeInput trims free-text metadata, lowercases and de-duplicates tags
+// and defaults the status to published.
+func normalizeInput(in PostInput) PostInput {
+	in.Category = strings.TrimSpace(in.Category)
+	in.Author = strings.TrimSpace(in.Author)
+	in.Contributors = normalizeContributors(in.Contributors)
+	if len(in.Contributors) == 0 && in.Author != "" {
+		in.Contributors = []Contributor{{Name: in.Author, Role: RoleAuthor}}
+	}
+	in.Author = leadAuthor(in.Contributors)
+	in.ImageURL = strings.TrimSpace(in.ImageURL)
+	in.SEOTitle = strings.TrimSpace(in.SEOTitle)
+	in.SEODescription = strings.Join(strings.Fields(in.SEODescription), " ")
+	if in.Status == "" {
+		in.Status = PostStatusPublished
+	}
+
+	tags := make([]string, 0, len(in.Tags))
+	seen := make(map[string]struct{}, len(in.Tags))
+	for _, tag := range in.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+	in.Tags = tags
+	return in
+}
+
+func validateInput(in PostInput) error {
+	if err := validatePostData(in.Title, in.Content); err != nil {
+		return err
+	}
+	if err := validateImageURL(in.ImageURL); err != nil {
+		return err
+	}
+	if err := validateContributors(in.Contributors); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(in.SEOTitle) > 100 {
+		return ErrInvalidSEOTitle
+	}
+	if utf8.RuneCountInString(in.SEODescription) > 300 {
+		return ErrInvalidSEODescription
+	}
+	return validateStatus(in.Status)
+}
+
+// validateImageURL accepts an empty URL or an absolute http(s) one.
+func validateImageURL(raw string) error {
+	if raw != "" && !isHTTPURL(raw) {
+		return ErrInvalidImage
+	}
+	return nil
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL.
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateStatus accepts the known statuses and the empty status of legacy posts.
+func validateStatus(status PostStatus) error {
+	switch status {
+	case "", PostStatusDraft, PostStatusPublished:
+		return nil
+	}
+	return ErrInvalidStatus
+}
+
 // validatePostData validates the title and content according to length rules.
 func validatePostData(title, content string) error {
 	if len(title) < 3 || len(title) > 200 {
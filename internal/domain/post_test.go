@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -180,3 +181,163 @@ func TestPost_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPostFromInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      PostInput
+		wantErr    error
+		wantTags   []string
+		wantStatus PostStatus
+	}{
+		{
+			name: "normalizes metadata",
+			input: PostInput{
+				Title:    "Valid Title",
+				Content:  "Valid content with more than 10 characters",
+				Category: "  politics ",
+				Tags:     []string{" Budget", "budget", "", "Parliament "},
+				Author:   " anna ",
+			},
+			wantTags:   []string{"budget", "parliament"},
+			wantStatus: PostStatusPublished,
+		},
+		{
+			name: "draft",
+			input: PostInput{
+				Title:   "Valid Title",
+				Content: "Valid content with more than 10 characters",
+				Status:  PostStatusDraft,
+			},
+			wantStatus: PostStatusDraft,
+		},
+		{
+			name: "invalid status",
+			input: PostInput{
+				Title:   "Valid Title",
+				Content: "Valid content with more than 10 characters",
+				Status:  "archived",
+			},
+			wantErr: ErrInvalidStatus,
+		},
+		{
+			name: "image URL",
+			input: PostInput{
+				Title:    "Valid Title",
+				Content:  "Valid content with more than 10 characters",
+				ImageURL: " https://cdn.example.com/a.jpg ",
+			},
+			wantStatus: PostStatusPublished,
+		},
+		{
+			name: "relative image URL",
+			input: PostInput{
+				Title:    "Valid Title",
+				Content:  "Valid content with more than 10 characters",
+				ImageURL: "/a.jpg",
+			},
+			wantErr: ErrInvalidImage,
+		},
+		{
+			name: "long SEO title",
+			input: PostInput{
+				Title:    "Valid Title",
+				Content:  "Valid content with more than 10 characters",
+				SEOTitle: strings.Repeat("я", 101),
+			},
+			wantErr: ErrInvalidSEOTitle,
+		},
+		{
+			name: "long SEO description",
+			input: PostInput{
+				Title:          "Valid Title",
+				Content:        "Valid content with more than 10 characters",
+				SEODescription: strings.Repeat("word ", 61),
+			},
+			wantErr: ErrInvalidSEODescription,
+		},
+		{
+			name:    "invalid title",
+			input:   PostInput{Title: "A", Content: "Valid content with more than 10 characters"},
+			wantErr: ErrInvalidTitle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post, err := NewPostFromInput(tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("NewPostFromInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if post.Status != tt.wantStatus {
+				t.Errorf("NewPostFromInput() status = %v, want %v", post.Status, tt.wantStatus)
+			}
+			if len(post.Tags) != len(tt.wantTags) {
+				t.Fatalf("NewPostFromInput() tags = %v, want %v", post.Tags, tt.wantTags)
+			}
+			for i := range tt.wantTags {
+				if post.Tags[i] != tt.wantTags[i] {
+					t.Errorf("NewPostFromInput() tags = %v, want %v", post.Tags, tt.wantTags)
+				}
+			}
+			if tt.input.Category != "" && post.Category != "politics" {
+				t.Errorf("NewPostFromInput() category = %q, want trimmed", post.Category)
+			}
+		})
+	}
+}
+
+func TestPost_IsPublished(t *testing.T) {
+	if !(&Post{}).IsPublished() {
+		t.Error("post without status should count as published")
+	}
+	if (&Post{Status: PostStatusDraft}).IsPublished() {
+		t.Error("draft should not be published")
+	}
+}
+
+func TestPost_Excerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		maxLen  int
+		want    string
+	}{
+		{name: "short content", content: "Short\n\ncontent", maxLen: 50, want: "Short content"},
+		{name: "cut at word", content: "The quick brown fox jumps", maxLen: 12, want: "The quick…"},
+		{name: "trailing punctuation", content: "Hello, wonderful world", maxLen: 10, want: "Hello…"},
+		{name: "single long word", content: "Supercalifragilistic", maxLen: 5, want: "Super…"},
+		{name: "multibyte", content: "Привет мир и все", maxLen: 10, want: "Привет мир…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Post{Content: tt.content}
+			if got := p.Excerpt(tt.maxLen); got != tt.want {
+				t.Errorf("Excerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPost_MetaFields(t *testing.T) {
+	p := &Post{Title: "Budget vote", Content: "Parliament passed the budget after a long debate"}
+	if got := p.MetaTitle(); got != "Budget vote" {
+		t.Errorf("MetaTitle() = %q, want the title", got)
+	}
+	if got := p.MetaDescription(); got != p.Content {
+		t.Errorf("MetaDescription() = %q, want the content excerpt", got)
+	}
+
+	p.SEOTitle = "Parliament passes the budget"
+	p.SEODescription = "What the new budget means for you"
+	if got := p.MetaTitle(); got != p.SEOTitle {
+		t.Errorf("MetaTitle() = %q, want %q", got, p.SEOTitle)
+	}
+	if got := p.MetaDescription(); got != p.SEODescription {
+		t.Errorf("MetaDescription() = %q, want %q", got, p.SEODescription)
+	}
+}
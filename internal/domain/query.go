@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidSort   = errors.New("invalid sort order")
+)
+
+// PostQuery selects a page of posts. Zero-valued filters are ignored; multiple
+// tags match posts carrying all of them. From and To bound CreatedAt inclusively.
+//
+// When Cursor is set the page starts right after the post the cursor was taken
+// from and Page is ignored; the cursor must come from a listing in the same
+// order. SkipCount leaves PostList.TotalCount unset, saving a count over the
+// whole filtered collection.
+type PostQuery struct {
+	Page      int
+	PageSize  int
+	Cursor    string
+	SkipCount bool
+	Sort      PostSort
+	Search    string
+	Category  string
+	Tags      []string
+	Author    string
+	Status    PostStatus
+	From      time.Time
+	To        time.Time
+}
+
+// MaxFacetValues caps the number of values listed for a filter dimension.
+const MaxFacetValues = 20
+
+// PostFacets holds, for each filter dimension, how many posts every value would yield
+// combined with the other active filters.
+type PostFacets struct {
+	Categories []FacetValue `json:"categories"`
+	Tags       []FacetValue `json:"tags"`
+	Authors    []FacetValue `json:"authors"`
+	Statuses   []FacetValue `json:"statuses"`
+}
+
+// FacetValue is a filter value together with the number of matching posts.
+type FacetValue struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// ArchiveMonth is the number of posts created in a calendar month (UTC).
+type ArchiveMonth struct {
+	Year  int        `bson:"year" json:"year"`
+	Month time.Month `bson:"month" json:"month"`
+	Count int64      `bson:"count" json:"count"`
+}
+
+// Start returns the first instant of the month.
+func (m ArchiveMonth) Start() time.Time {
+	return time.Date(m.Year, m.Month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the last instant of the month.
+func (m ArchiveMonth) End() time.Time {
+	return m.Start().AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+// PostSort is a listing order.
+type PostSort string
+
+const (
+	SortNewest    PostSort = "newest"
+	SortOldest    PostSort = "oldest"
+	SortUpdated   PostSort = "updated"
+	SortViews     PostSort = "views"
+	SortTitle     PostSort = "title"
+	SortRelevance PostSort = "relevance"
+)
+
+// PostSorts lists the supported orders in the order they are offered to readers.
+var PostSorts = []PostSort{SortRelevance, SortNewest, SortOldest, SortUpdated, SortViews, SortTitle}
+
+// ParsePostSort validates s. An empty string selects the default order.
+func ParsePostSort(s string) (PostSort, error) {
+	if s == "" {
+		return "", nil
+	}
+	for _, sort := range PostSorts {
+		if string(sort) == s {
+			return sort, nil
+		}
+	}
+	return "", ErrInvalidSort
+}
+
+// EffectiveSort resolves the default order: relevance when searching and newest
+// otherwise. Relevance without search text falls back to newest as well.
+func (q PostQuery) EffectiveSort() PostSort {
+	switch {
+	case q.Sort == "" && q.Search != "":
+		return SortRelevance
+	case q.Sort == "", q.Sort == SortRelevance && q.Search == "":
+		return SortNewest
+	}
+	return q.Sort
+}
+
+// key returns the value post is ordered by under s.
+func (s PostSort) key(post *Post) any {
+	switch s {
+	case SortUpdated:
+		return post.UpdatedAt
+	case SortViews:
+		return post.ViewCount
+	case SortTitle:
+		return post.Title
+	}
+	return post.CreatedAt
+}
+
+// PostCursor is the keyset position of a post in a listing: the value of the
+// sort key and the id breaking ties between equal values.
+type PostCursor struct {
+	Sort  PostSort
+	Value any
+	ID    primitive.ObjectID
+}
+
+// cursorDocument is the serialized form of PostCursor.
+type cursorDocument struct {
+	Sort  string             `bson:"s"`
+	Value any                `bson:"v"`
+	ID    primitive.ObjectID `bson:"i"`
+}
+
+// CursorAfter returns the cursor pointing just past post in the given order.
+func CursorAfter(post *Post, sort PostSort) PostCursor {
+	return PostCursor{Sort: sort, Value: sort.key(post), ID: post.ID}
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c PostCursor) Encode() string {
+	raw, err := bson.Marshal(cursorDocument{Sort: string(c.Sort), Value: c.Value, ID: c.ID})
+	if err != nil {
+		// Sort keys are times, integers or strings, which always marshal.
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParsePostCursor decodes a cursor produced by PostCursor.Encode. Time values
+// come back as primitive.DateTime.
+func ParsePostCursor(s string) (PostCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return PostCursor{}, ErrInvalidCursor
+	}
+
+	var doc cursorDocument
+	if err := bson.Unmarshal(raw, &doc); err != nil || doc.ID.IsZero() || doc.Value == nil {
+		return PostCursor{}, ErrInvalidCursor
+	}
+	sort, err := ParsePostSort(doc.Sort)
+	if err != nil || sort == "" || sort == SortRelevance {
+		return PostCursor{}, ErrInvalidCursor
+	}
+	return PostCursor{Sort: sort, Value: doc.Value, ID: doc.ID}, nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPostCursor(t *testing.T) {
+	post := &Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Budget vote",
+		ViewCount: 42,
+		CreatedAt: time.Date(2025, 3, 14, 9, 26, 53, 589000000, time.UTC),
+	}
+
+	tests := []struct {
+		sort PostSort
+		want any
+	}{
+		{sort: SortNewest, want: primitive.NewDateTimeFromTime(post.CreatedAt)},
+		{sort: SortViews, want: int64(42)},
+		{sort: SortTitle, want: "Budget vote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			cursor, err := ParsePostCursor(CursorAfter(post, tt.sort).Encode())
+			require.NoError(t, err)
+			assert.Equal(t, tt.sort, cursor.Sort)
+			assert.Equal(t, tt.want, cursor.Value)
+			assert.Equal(t, post.ID, cursor.ID)
+		})
+	}
+
+	for _, invalid := range []string{"", "!!!", "bm9wZQ", CursorAfter(post, SortNewest).Encode()[:10], CursorAfter(post, SortRelevance).Encode()} {
+		_, err := ParsePostCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+}
+
+func TestPostQuery_EffectiveSort(t *testing.T) {
+	assert.Equal(t, SortNewest, PostQuery{}.EffectiveSort())
+	assert.Equal(t, SortRelevance, PostQuery{Search: "vote"}.EffectiveSort())
+	assert.Equal(t, SortNewest, PostQuery{Sort: SortRelevance}.EffectiveSort())
+	assert.Equal(t, SortTitle, PostQuery{Sort: SortTitle, Search: "vote"}.EffectiveSort())
+
+	_, err := ParsePostSort("popular")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
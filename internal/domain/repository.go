@@ -2,15 +2,39 @@ package domain
 
 import (
 	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Repository defines the interface for post storage operations
 type Repository interface {
 	Create(ctx context.Context, post *Post) error
 	GetAll(ctx context.Context) ([]*Post, error)
+	// GetByID returns ErrPostNotFound when no post has the id.
 	GetByID(ctx context.Context, id string) (*Post, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*Post, error)
 	Update(ctx context.Context, post *Post) error
 	Delete(ctx context.Context, id string) error
-	GetPaginated(ctx context.Context, page, pageSize int, search string) (*PostList, error)
+	GetPaginated(ctx context.Context, query PostQuery) (*PostList, error)
+	GetFacets(ctx context.Context, query PostQuery) (*PostFacets, error)
+	GetArchive(ctx context.Context) ([]ArchiveMonth, error)
 	GetRecent(ctx context.Context, limit int) ([]*Post, error)
+	IncrementViews(ctx context.Context, id string) error
+	// IncrementBookmarks adds delta to the bookmark count of a post.
+	IncrementBookmarks(ctx context.Context, id string, delta int) error
+	CountPublished(ctx context.Context) (int64, error)
+	// GetAuthors returns the distinct contributors named by posts.
+	GetAuthors(ctx context.Context) ([]string, error)
+	EachPublished(ctx context.Context, query SitemapQuery, fn func(*Post) error) error
+	// ExistsByOrigin reports whether a post was imported from the source with
+	// the same item GUID or link.
+	ExistsByOrigin(ctx context.Context, sourceID primitive.ObjectID, guid, link string) (bool, error)
+	GetFingerprints(ctx context.Context) ([]PostFingerprint, error)
+	SetFingerprint(ctx context.Context, id string, fingerprint int64) error
+	// SetBreaking sets the breaking news flag of a post, or removes it when breaking is nil.
+	SetBreaking(ctx context.Context, id string, breaking *Breaking) error
+	// GetBreaking returns the published posts whose breaking news flag is
+	// active at now, most recently flagged first.
+	GetBreaking(ctx context.Context, now time.Time) ([]*Post, error)
 }
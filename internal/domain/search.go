@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SearchIndex defines the interface for full-text search over posts
+type SearchIndex interface {
+	Index(ctx context.Context, post *Post) error
+	Remove(ctx context.Context, id string) error
+	Query(ctx context.Context, query SearchQuery) (*SearchResult, error)
+	// Facets counts the values of every filter dimension among the posts
+	// matching text, like Repository.GetFacets does for a query.
+	Facets(ctx context.Context, text string, filters SearchFilters) (*PostFacets, error)
+	Rebuild(ctx context.Context, posts []*Post) error
+}
+
+// SearchQuery describes a full-text query with optional filters and pagination.
+// Sort defaults to relevance. Indexes may order by relevance instead of sorts
+// depending on counters that change without post events, like views.
+type SearchQuery struct {
+	Text     string
+	Filters  SearchFilters
+	Sort     PostSort
+	Page     int
+	PageSize int
+}
+
+// SearchFilters narrows a search to posts matching all non-zero fields.
+type SearchFilters struct {
+	Category string
+	Tags     []string
+	Author   string
+	Status   PostStatus
+	From     time.Time
+	To       time.Time
+}
+
+// SearchHit is a single ranked search match.
+type SearchHit struct {
+	ID        string
+	Score     float64
+	Highlight *Highlight
+}
+
+// Highlight marks where a query matched a post. Title covers the whole title,
+// Snippet is a short excerpt of the content around the best cluster of matches.
+type Highlight struct {
+	Title   []TextFragment `json:"title"`
+	Snippet []TextFragment `json:"snippet"`
+}
+
+// TextFragment is a run of plain text; Match is set for runs that matched the query.
+// Fragments are unescaped and must be rendered through html/template.
+type TextFragment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match"`
+}
+
+// SearchResult is a page of ranked hits together with the total number of matches.
+type SearchResult struct {
+	Hits  []SearchHit
+	Total int
+}
+
+// SearchLogRepository records submitted search terms and ranks them by popularity.
+type SearchLogRepository interface {
+	Record(ctx context.Context, term string, at time.Time) error
+	Popular(ctx context.Context, prefix string, since time.Time, limit int) ([]PopularQuery, error)
+}
+
+// PopularQuery is a previously searched term and how often it was searched.
+type PopularQuery struct {
+	Term  string `bson:"_id" json:"term"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// TitleSuggestion is a post title completing a partially typed query.
+type TitleSuggestion struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Suggestions are the autocomplete results for a partially typed query.
+type Suggestions struct {
+	Query   string            `json:"query"`
+	Titles  []TitleSuggestion `json:"titles"`
+	Popular []PopularQuery    `json:"popular"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// SitemapQuery selects published posts for a sitemap. Posts are ordered by ID,
+// oldest first unless NewestFirst is set, so a page of the listing stays
+// stable while new posts are added.
+type SitemapQuery struct {
+	Since       time.Time
+	Offset      int64
+	Limit       int64
+	NewestFirst bool
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSourceName     = errors.New("source name is required")
+	ErrInvalidSourceURL      = errors.New("source URL must be an absolute http or https URL")
+	ErrInvalidSourceInterval = errors.New("source interval must be between 1 minute and 24 hours")
+	ErrDuplicateSource       = errors.New("source with this URL already exists")
+)
+
+// DefaultSourceInterval is the polling interval of sources created without one.
+const DefaultSourceInterval = 15 * time.Minute
+
+// Source is an external RSS or Atom feed whose items are ingested as posts.
+// ETag and LastModified hold the validators of the last successful fetch and
+// are sent back as a conditional GET.
+type Source struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name     string             `bson:"name" json:"name"`
+	URL      string             `bson:"url" json:"url"`
+	Category string             `bson:"category,omitempty" json:"category,omitempty"`
+	// Publish makes ingested items visible immediately instead of creating drafts.
+	Publish   bool          `bson:"publish" json:"publish"`
+	Interval  time.Duration `bson:"interval" json:"-"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+
+	ETag          string    `bson:"etag,omitempty" json:"-"`
+	LastModified  string    `bson:"last_modified,omitempty" json:"-"`
+	LastFetchedAt time.Time `bson:"last_fetched_at,omitempty" json:"last_fetched_at,omitempty"`
+	NextFetchAt   time.Time `bson:"next_fetch_at" json:"next_fetch_at"`
+	LastError     string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+}
+
+// SourceInput holds the user-supplied fields of a source.
+type SourceInput struct {
+	Name     string
+	URL      string
+	Category string
+	Publish  bool
+	Interval time.Duration
+}
+
+// NewSource creates a source that is due for its first fetch right away.
+// A zero interval defaults to DefaultSourceInterval.
+func NewSource(in SourceInput) (*Source, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.URL = strings.TrimSpace(in.URL)
+	in.Category = strings.TrimSpace(in.Category)
+	if in.Interval == 0 {
+		in.Interval = DefaultSourceInterval
+	}
+
+	if in.Name == "" {
+		return nil, ErrInvalidSourceName
+	}
+	if !isHTTPURL(in.URL) {
+		return nil, ErrInvalidSourceURL
+	}
+	if in.Interval < time.Minute || in.Interval > 24*time.Hour {
+		return nil, ErrInvalidSourceInterval
+	}
+
+	now := time.Now()
+	return &Source{
+		ID:          primitive.NewObjectID(),
+		Name:        in.Name,
+		URL:         in.URL,
+		Category:    in.Category,
+		Publish:     in.Publish,
+		Interval:    in.Interval,
+		CreatedAt:   now,
+		NextFetchAt: now,
+	}, nil
+}
+
+// Fetched records the outcome of a fetch at the given time and schedules the
+// next one an interval later.
+func (s *Source) Fetched(at time.Time, err error) {
+	s.LastFetchedAt = at
+	s.NextFetchAt = at.Add(s.Interval)
+	s.LastError = ""
+	if err != nil {
+		s.LastError = err.Error()
+	}
+}
+
+// PostOrigin attributes an ingested post to the feed item it was created from.
+// GUID is the item's guid or id, falling back to its link.
+type PostOrigin struct {
+	SourceID primitive.ObjectID `bson:"source_id" json:"source_id"`
+	Name     string             `bson:"name" json:"name"`
+	GUID     string             `bson:"guid" json:"guid"`
+	Link     string             `bson:"link,omitempty" json:"link,omitempty"`
+}
+
+// SourceRepository defines the interface for feed source storage operations
+type SourceRepository interface {
+	Create(ctx context.Context, source *Source) error
+	GetAll(ctx context.Context) ([]*Source, error)
+	GetDue(ctx context.Context, now time.Time) ([]*Source, error)
+	SaveFetchState(ctx context.Context, source *Source) error
+}
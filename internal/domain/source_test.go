@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        SourceInput
+		wantErr      error
+		wantInterval time.Duration
+	}{
+		{
+			name:         "defaults interval",
+			input:        SourceInput{Name: " Wire ", URL: "https://wire.example/rss"},
+			wantInterval: DefaultSourceInterval,
+		},
+		{
+			name:         "custom interval",
+			input:        SourceInput{Name: "Wire", URL: "https://wire.example/rss", Interval: time.Hour},
+			wantInterval: time.Hour,
+		},
+		{name: "missing name", input: SourceInput{URL: "https://wire.example/rss"}, wantErr: ErrInvalidSourceName},
+		{name: "relative URL", input: SourceInput{Name: "Wire", URL: "/rss"}, wantErr: ErrInvalidSourceURL},
+		{name: "ftp URL", input: SourceInput{Name: "Wire", URL: "ftp://wire.example/rss"}, wantErr: ErrInvalidSourceURL},
+		{name: "too frequent", input: SourceInput{Name: "Wire", URL: "https://wire.example/rss", Interval: time.Second}, wantErr: ErrInvalidSourceInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewSource(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if source.Name != "Wire" {
+				t.Errorf("NewSource() name = %q, want trimmed", source.Name)
+			}
+			if source.Interval != tt.wantInterval {
+				t.Errorf("NewSource() interval = %v, want %v", source.Interval, tt.wantInterval)
+			}
+			if source.NextFetchAt.After(time.Now()) {
+				t.Error("new source should be due immediately")
+			}
+		})
+	}
+}
+
+func TestSource_Fetched(t *testing.T) {
+	source := &Source{Interval: 10 * time.Minute}
+	at := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+
+	source.Fetched(at, errors.New("timeout"))
+	if source.LastError != "timeout" {
+		t.Errorf("LastError = %q, want timeout", source.LastError)
+	}
+	if !source.NextFetchAt.Equal(at.Add(10 * time.Minute)) {
+		t.Errorf("NextFetchAt = %v, want an interval later", source.NextFetchAt)
+	}
+
+	source.Fetched(at.Add(time.Hour), nil)
+	if source.LastError != "" {
+		t.Errorf("LastError = %q, want cleared", source.LastError)
+	}
+	if !source.LastFetchedAt.Equal(at.Add(time.Hour)) {
+		t.Errorf("LastFetchedAt = %v, want the fetch time", source.LastFetchedAt)
+	}
+}
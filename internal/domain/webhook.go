@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidWebhookURL   = errors.New("webhook URL must be an absolute http or https URL")
+	ErrInvalidWebhookEvent = errors.New("unknown webhook event")
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrDeliveryNotFound    = errors.New("webhook delivery not found")
+	// ErrDuplicateDelivery is returned when an event was already recorded for
+	// delivery to a webhook.
+	ErrDuplicateDelivery = errors.New("webhook delivery already recorded")
+)
+
+// WebhookEvents lists the post events a webhook can subscribe to.
+var WebhookEvents = []PostEventType{PostCreated, PostUpdated, PostPublished, PostBreaking, PostDeleted}
+
+// Webhook is a subscription of an external URL to post lifecycle events.
+// Deliveries are signed with Secret. A webhook without events receives all of them.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"-"`
+	Events    []PostEventType    `bson:"events,omitempty" json:"events,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// WebhookInput holds the user-supplied fields of a webhook.
+type WebhookInput struct {
+	URL    string
+	Events []PostEventType
+}
+
+// NewWebhook creates a webhook with a random signing secret.
+func NewWebhook(in WebhookInput) (*Webhook, error) {
+	in.URL = strings.TrimSpace(in.URL)
+	if !isHTTPURL(in.URL) {
+		return nil, ErrInvalidWebhookURL
+	}
+
+	var events []PostEventType
+	seen := make(map[PostEventType]bool)
+	for _, e := range in.Events {
+		if !isWebhookEvent(e) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWebhookEvent, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	return &Webhook{
+		ID:        primitive.NewObjectID(),
+		URL:       in.URL,
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Subscribes reports whether the webhook receives events of type t.
+func (w *Webhook) Subscribes(t PostEventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebhookEvent(t PostEventType) bool {
+	for _, e := range WebhookEvents {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// MaxDeliveryAttempts is the number of attempts after which a delivery is
+// given up as failed.
+const MaxDeliveryAttempts = 6
+
+// FirstRetryDelay is the wait before the second attempt. Every further retry
+// waits twice as long as the previous one.
+const FirstRetryDelay = 30 * time.Second
+
+// WebhookDelivery is one event sent to one webhook, together with the outcome
+// of its attempts. The payload and its signature are stored so that it can be
+// redelivered as is, even after the webhook was deleted. EventID is the post
+// event the delivery was created for; an event is recorded at most once per
+// webhook. Redeliveries leave it empty.
+type WebhookDelivery struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	EventID   string             `bson:"event_id,omitempty"`
+	WebhookID primitive.ObjectID `bson:"webhook_id"`
+	URL       string             `bson:"url"`
+	Event     PostEventType      `bson:"event"`
+	PostID    string             `bson:"post_id"`
+	Payload   string             `bson:"payload"`
+	Signature string             `bson:"signature"`
+	CreatedAt time.Time          `bson:"created_at"`
+
+	Status         DeliveryStatus `bson:"status"`
+	Attempts       int            `bson:"attempts"`
+	NextAttemptAt  time.Time      `bson:"next_attempt_at"`
+	LastAttemptAt  time.Time      `bson:"last_attempt_at,omitempty"`
+	ResponseStatus int            `bson:"response_status,omitempty"`
+	LastError      string         `bson:"last_error,omitempty"`
+}
+
+// NewWebhookDelivery creates a pending delivery of event to hook with payload
+// that is due right away. signature authenticates payload with the webhook
+// secret.
+func NewWebhookDelivery(hook *Webhook, event PostEvent, payload []byte, signature string) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		EventID:       event.ID,
+		WebhookID:     hook.ID,
+		URL:           hook.URL,
+		Event:         event.Type,
+		PostID:        event.PostID,
+		Payload:       string(payload),
+		Signature:     signature,
+		CreatedAt:     now,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+	}
+}
+
+// Redelivery creates a new pending delivery with the same payload and target.
+func (d *WebhookDelivery) Redelivery() *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		WebhookID:     d.WebhookID,
+		URL:           d.URL,
+		Event:         d.Event,
+		PostID:        d.PostID,
+		Payload:       d.Payload,
+		Signature:     d.Signature,
+		CreatedAt:     now,
+		Status:        DeliveryPending,
+		NextAttemptAt: now,
+	}
+}
+
+// Attempted records an attempt made at the given time. A nil err means the
+// endpoint answered with a 2xx status. Failed attempts are retried with
+// exponential backoff until MaxDeliveryAttempts is reached.
+func (d *WebhookDelivery) Attempted(at time.Time, responseStatus int, err error) {
+	d.Attempts++
+	d.LastAttemptAt = at
+	d.ResponseStatus = responseStatus
+	d.LastError = ""
+
+	switch {
+	case err == nil:
+		d.Status = DeliverySucceeded
+	case d.Attempts >= MaxDeliveryAttempts:
+		d.Status = DeliveryFailed
+		d.LastError = err.Error()
+	default:
+		d.Status = DeliveryPending
+		d.LastError = err.Error()
+		d.NextAttemptAt = at.Add(FirstRetryDelay << (d.Attempts - 1))
+	}
+}
+
+// WebhookRepository stores webhook subscriptions.
+type WebhookRepository interface {
+	Create(ctx context.Context, hook *Webhook) error
+	GetAll(ctx context.Context) ([]*Webhook, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository stores webhook deliveries and hands due ones to workers.
+type WebhookDeliveryRepository interface {
+	// Create returns ErrDuplicateDelivery when a delivery of the same event to
+	// the same webhook exists.
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	GetByID(ctx context.Context, id string) (*WebhookDelivery, error)
+	GetRecent(ctx context.Context, limit int) ([]*WebhookDelivery, error)
+	// ClaimDue returns a pending delivery due at now and postpones it by lease so
+	// that no other worker claims it while it is attempted. It returns nil when
+	// nothing is due.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*WebhookDelivery, error)
+	Save(ctx context.Context, delivery *WebhookDelivery) error
+}
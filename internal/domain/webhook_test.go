@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      WebhookInput
+		wantErr    error
+		wantEvents []PostEventType
+	}{
+		{
+			name:  "all events",
+			input: WebhookInput{URL: " https://hooks.example/news "},
+		},
+		{
+			name:       "selected events are de-duplicated",
+			input:      WebhookInput{URL: "https://hooks.example/news", Events: []PostEventType{PostPublished, PostDeleted, PostPublished}},
+			wantEvents: []PostEventType{PostPublished, PostDeleted},
+		},
+		{
+			name:    "relative URL",
+			input:   WebhookInput{URL: "/hooks"},
+			wantErr: ErrInvalidWebhookURL,
+		},
+		{
+			name:    "unknown event",
+			input:   WebhookInput{URL: "https://hooks.example/news", Events: []PostEventType{"post.viewed"}},
+			wantErr: ErrInvalidWebhookEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := NewWebhook(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "https://hooks.example/news", hook.URL)
+			assert.Equal(t, tt.wantEvents, hook.Events)
+			assert.Len(t, hook.Secret, 64)
+			assert.False(t, hook.ID.IsZero())
+		})
+	}
+
+	a, err := NewWebhook(WebhookInput{URL: "https://hooks.example/a"})
+	require.NoError(t, err)
+	b, err := NewWebhook(WebhookInput{URL: "https://hooks.example/b"})
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Secret, b.Secret)
+}
+
+func TestWebhook_Subscribes(t *testing.T) {
+	all := &Webhook{}
+	assert.True(t, all.Subscribes(PostCreated))
+	assert.True(t, all.Subscribes(PostDeleted))
+
+	selected := &Webhook{Events: []PostEventType{PostPublished}}
+	assert.True(t, selected.Subscribes(PostPublished))
+	assert.False(t, selected.Subscribes(PostCreated))
+}
+
+func TestWebhookDelivery_Attempted(t *testing.T) {
+	hook := &Webhook{URL: "https://hooks.example/news"}
+	delivery := NewWebhookDelivery(hook, PostEvent{ID: "event-1", Type: PostCreated, PostID: "post-1"}, []byte(`{}`), "sha256=00")
+	assert.Equal(t, "event-1", delivery.EventID)
+	assert.Equal(t, DeliveryPending, delivery.Status)
+	assert.False(t, delivery.NextAttemptAt.After(time.Now()))
+
+	at := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	wantDelays := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, delay := range wantDelays {
+		delivery.Attempted(at, 503, errors.New("unexpected status 503"))
+		assert.Equal(t, i+1, delivery.Attempts)
+		assert.Equal(t, DeliveryPending, delivery.Status)
+		assert.Equal(t, at.Add(delay), delivery.NextAttemptAt)
+		assert.Equal(t, "unexpected status 503", delivery.LastError)
+	}
+
+	delivery.Attempted(at, 0, errors.New("connection refused"))
+	assert.Equal(t, MaxDeliveryAttempts, delivery.Attempts)
+	assert.Equal(t, DeliveryFailed, delivery.Status)
+	assert.Equal(t, "connection refused", delivery.LastError)
+
+	redelivery := delivery.Redelivery()
+	assert.NotEqual(t, delivery.ID, redelivery.ID)
+	assert.Empty(t, redelivery.EventID)
+	assert.Equal(t, delivery.Payload, redelivery.Payload)
+	assert.Equal(t, "sha256=00", redelivery.Signature)
+	assert.Equal(t, DeliveryPending, redelivery.Status)
+	assert.Zero(t, redelivery.Attempts)
+
+	redelivery.Attempted(at, 200, nil)
+	assert.Equal(t, DeliverySucceeded, redelivery.Status)
+	assert.Equal(t, 200, redelivery.ResponseStatus)
+	assert.Empty(t, redelivery.LastError)
+}
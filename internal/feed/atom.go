@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID           string         `xml:"id"`
+	Title        string         `xml:"title"`
+	Published    string         `xml:"published"`
+	Updated      string         `xml:"updated"`
+	Authors      []atomPerson   `xml:"author"`
+	Contributors []atomPerson   `xml:"contributor"`
+	Link         atomLink       `xml:"link"`
+	Categories   []atomCategory `xml:"category"`
+	Content      atomText       `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteAtom writes posts as an Atom 1.0 feed. Content is embedded as escaped HTML.
+func WriteAtom(w io.Writer, meta Meta, urls URLs, posts []*domain.Post) error {
+	updated := Updated(posts)
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	feed := atomFeed{
+		ID:      meta.Self,
+		Title:   meta.Title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: meta.Title},
+		Links: []atomLink{
+			{Href: meta.Link, Rel: "alternate", Type: "text/html"},
+			{Href: meta.Self, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	if meta.NextURL != "" {
+		feed.Links = append(feed.Links, atomLink{Href: meta.NextURL, Rel: "next", Type: "application/atom+xml"})
+	}
+
+	for _, p := range posts {
+		link := urls.Post(p)
+		entry := atomEntry{
+			ID:        link,
+			Title:     p.Title,
+			Published: p.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   modifiedAt(p).UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: link, Rel: "alternate", Type: "text/html"},
+			Content:   atomText{Type: "html", Value: ContentHTML(p.Content)},
+		}
+		for _, c := range p.Writers() {
+			entry.Authors = append(entry.Authors, atomPerson{Name: c.Name, URI: urls.Author(c)})
+		}
+		for _, c := range p.OtherCredits() {
+			entry.Contributors = append(entry.Contributors, atomPerson{Name: c.Name, URI: urls.Author(c)})
+		}
+		for _, c := range categories(p) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write atom: %w", err)
+	}
+	if err := xml.NewEncoder(w).Encode(feed); err != nil {
+		return fmt.Errorf("failed to encode atom: %w", err)
+	}
+	return nil
+}
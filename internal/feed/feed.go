@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// Meta describes a feed as a whole. Link is the HTML page the feed mirrors,
+// Self the absolute URL of the feed itself and NextURL, when set, the URL of
+// the following page of older posts.
+type Meta struct {
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	NextURL     string
+}
+
+// URLs builds absolute links to the public site.
+type URLs struct {
+	BaseURL string
+}
+
+// Post returns the absolute URL of a post.
+func (u URLs) Post(p *domain.Post) string {
+	return u.BaseURL + "/posts/" + p.ID.Hex()
+}
+
+// Author returns the absolute URL of the author page of a contributor, or ""
+// when the name has no letters or digits to derive a handle from.
+func (u URLs) Author(c domain.Contributor) string {
+	handle := c.Handle()
+	if handle == "" {
+		return ""
+	}
+	return u.BaseURL + "/authors/" + url.PathEscape(handle)
+}
+
+// Absolute returns path prefixed with the base URL.
+func (u URLs) Absolute(path string) string {
+	return u.BaseURL + path
+}
+
+// ContentHTML renders plain post content as HTML. Blank lines separate
+// paragraphs and single line breaks are kept.
+func ContentHTML(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n")
+	if text == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// Updated returns the latest modification time of posts, or the zero time
+// for an empty feed.
+func Updated(posts []*domain.Post) time.Time {
+	var latest time.Time
+	for _, p := range posts {
+		if modified := modifiedAt(p); modified.After(latest) {
+			latest = modified
+		}
+	}
+	return latest
+}
+
+// modifiedAt falls back to the creation time for posts never updated.
+func modifiedAt(p *domain.Post) time.Time {
+	if p.UpdatedAt.After(p.CreatedAt) {
+		return p.UpdatedAt
+	}
+	return p.CreatedAt
+}
+
+// categories lists the post category followed by its tags.
+func categories(p *domain.Post) []string {
+	var out []string
+	if p.Category != "" {
+		out = append(out, p.Category)
+	}
+	return append(out, p.Tags...)
+}
+
+// names returns the names of contributors.
+func names(contributors []domain.Contributor) []string {
+	out := make([]string, 0, len(contributors))
+	for _, c := range contributors {
+		out = append(out, c.Name)
+	}
+	return out
+}
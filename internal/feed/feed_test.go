@@ -0,0 +1,282 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testPosts() []*domain.Post {
+	created := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	return []*domain.Post{
+		{
+			ID:        primitive.NewObjectID(),
+			Title:     "Budget <vote> & more",
+			Content:   "First line\nsecond line\n\nNext <b>paragraph</b>",
+			Category:  "politics",
+			Tags:      []string{"budget"},
+			Author:    "anna",
+			ImageURL:  "https://cdn.news.example/budget.jpg",
+			CreatedAt: created,
+			UpdatedAt: created.Add(2 * time.Hour),
+		},
+		{
+			ID:        primitive.NewObjectID(),
+			Title:     "Derby result",
+			Content:   "The home side won",
+			CreatedAt: created.Add(-time.Hour),
+			UpdatedAt: created.Add(-time.Hour),
+		},
+	}
+}
+
+var testMeta = Meta{
+	Title:       "News Portal",
+	Description: "Latest posts",
+	Link:        "https://news.example/",
+	Self:        "https://news.example/feed.rss",
+}
+
+func TestContentHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "empty", text: "  ", want: ""},
+		{name: "single paragraph", text: "Hello", want: "<p>Hello</p>"},
+		{name: "paragraphs and breaks", text: "a\nb\r\n\r\nc", want: "<p>a<br>b</p><p>c</p>"},
+		{name: "escaped", text: `<script>alert("x")</script>`, want: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ContentHTML(tt.text))
+		})
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	posts := testPosts()
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testMeta, URLs{BaseURL: "https://news.example"}, posts))
+
+	var doc struct {
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string   `xml:"title"`
+				Link        string   `xml:"link"`
+				GUID        string   `xml:"guid"`
+				Description string   `xml:"description"`
+				Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories  []string `xml:"category"`
+				PubDate     string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, "News Portal", doc.Channel.Title)
+	assert.Equal(t, "Fri, 14 Mar 2025 11:00:00 +0000", doc.Channel.LastBuildDate)
+	require.Len(t, doc.Channel.Items, 2)
+
+	item := doc.Channel.Items[0]
+	assert.Equal(t, "Budget <vote> & more", item.Title)
+	assert.Equal(t, "https://news.example/posts/"+posts[0].ID.Hex(), item.Link)
+	assert.Equal(t, item.Link, item.GUID)
+	assert.Equal(t, "<p>First line<br>second line</p><p>Next &lt;b&gt;paragraph&lt;/b&gt;</p>", item.Description)
+	assert.Equal(t, []string{"anna"}, item.Creators)
+	assert.Equal(t, []string{"politics", "budget"}, item.Categories)
+	assert.Equal(t, "Fri, 14 Mar 2025 09:00:00 +0000", item.PubDate)
+	assert.Contains(t, buf.String(), "&lt;p&gt;First line", "HTML content must be escaped")
+}
+
+func TestWriteAtom(t *testing.T) {
+	posts := testPosts()
+	var buf bytes.Buffer
+	require.NoError(t, WriteAtom(&buf, testMeta, URLs{BaseURL: "https://news.example"}, posts))
+
+	var feed struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Authors   []struct {
+				Name string `xml:"name"`
+				URI  string `xml:"uri"`
+			} `xml:"author"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+
+	assert.Equal(t, testMeta.Self, feed.ID)
+	assert.Equal(t, "2025-03-14T11:00:00Z", feed.Updated)
+	require.Len(t, feed.Links, 2)
+	assert.Equal(t, "self", feed.Links[1].Rel)
+	require.Len(t, feed.Entries, 2)
+
+	entry := feed.Entries[0]
+	assert.Equal(t, "https://news.example/posts/"+posts[0].ID.Hex(), entry.ID)
+	assert.Equal(t, "2025-03-14T09:00:00Z", entry.Published)
+	assert.Equal(t, "2025-03-14T11:00:00Z", entry.Updated)
+	require.Len(t, entry.Authors, 1)
+	assert.Equal(t, "anna", entry.Authors[0].Name)
+	assert.Equal(t, "https://news.example/authors/anna", entry.Authors[0].URI)
+	assert.Len(t, entry.Categories, 2)
+	assert.Equal(t, "html", entry.Content.Type)
+	assert.Equal(t, "<p>First line<br>second line</p><p>Next &lt;b&gt;paragraph&lt;/b&gt;</p>", entry.Content.Value)
+	assert.Empty(t, feed.Entries[1].Authors)
+
+	meta := testMeta
+	meta.NextURL = "https://news.example/feed.atom?cursor=abc"
+	buf.Reset()
+	require.NoError(t, WriteAtom(&buf, meta, URLs{BaseURL: "https://news.example"}, posts))
+	feed.Links = nil
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	require.Len(t, feed.Links, 3)
+	assert.Equal(t, "next", feed.Links[2].Rel)
+	assert.Equal(t, meta.NextURL, feed.Links[2].Href)
+}
+
+func TestWriteJSON(t *testing.T) {
+	posts := testPosts()
+	meta := testMeta
+	meta.Self = "https://news.example/feed.json"
+	meta.NextURL = "https://news.example/feed.json?cursor=abc"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, meta, URLs{BaseURL: "https://news.example"}, posts))
+
+	var feed struct {
+		Version     string `json:"version"`
+		HomePageURL string `json:"home_page_url"`
+		FeedURL     string `json:"feed_url"`
+		NextURL     string `json:"next_url"`
+		Items       []struct {
+			ID            string `json:"id"`
+			URL           string `json:"url"`
+			Title         string `json:"title"`
+			ContentHTML   string `json:"content_html"`
+			Summary       string `json:"summary"`
+			Image         string `json:"image"`
+			DatePublished string `json:"date_published"`
+			DateModified  string `json:"date_modified"`
+			Authors       []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+			Tags []string `json:"tags"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &feed))
+
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", feed.Version)
+	assert.Equal(t, meta.Link, feed.HomePageURL)
+	assert.Equal(t, meta.Self, feed.FeedURL)
+	assert.Equal(t, meta.NextURL, feed.NextURL)
+	require.Len(t, feed.Items, 2)
+
+	item := feed.Items[0]
+	assert.Equal(t, "https://news.example/posts/"+posts[0].ID.Hex(), item.ID)
+	assert.Equal(t, item.ID, item.URL)
+	assert.Equal(t, "Budget <vote> & more", item.Title)
+	assert.Equal(t, "<p>First line<br>second line</p><p>Next &lt;b&gt;paragraph&lt;/b&gt;</p>", item.ContentHTML)
+	assert.Equal(t, "First line second line Next <b>paragraph</b>", item.Summary)
+	assert.Equal(t, "https://cdn.news.example/budget.jpg", item.Image)
+	assert.Equal(t, "2025-03-14T09:00:00Z", item.DatePublished)
+	assert.Equal(t, "2025-03-14T11:00:00Z", item.DateModified)
+	require.Len(t, item.Authors, 1)
+	assert.Equal(t, "anna", item.Authors[0].Name)
+	assert.Equal(t, []string{"politics", "budget"}, item.Tags)
+
+	assert.Empty(t, feed.Items[1].Authors)
+	assert.Empty(t, feed.Items[1].Image)
+	assert.NotContains(t, buf.String(), `"next_url":""`)
+}
+
+func TestWriteContributors(t *testing.T) {
+	posts := []*domain.Post{{
+		ID:    primitive.NewObjectID(),
+		Title: "Budget vote",
+		Contributors: []domain.Contributor{
+			{Name: "Anna Lee", Role: domain.RoleAuthor},
+			{Name: "Boris", Role: domain.RolePhotographer},
+			{Name: "José", Role: domain.RoleCoAuthor},
+			{Name: "???", Role: domain.RoleEditor},
+		},
+		CreatedAt: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+	}}
+	urls := URLs{BaseURL: "https://news.example"}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteRSS(&buf, testMeta, urls, posts))
+	var rss struct {
+		Items []struct {
+			Creators []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		} `xml:"channel>item"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &rss))
+	require.Len(t, rss.Items, 1)
+	assert.Equal(t, []string{"Anna Lee", "José"}, rss.Items[0].Creators, "only writers are creators")
+
+	buf.Reset()
+	require.NoError(t, WriteAtom(&buf, testMeta, urls, posts))
+	type person struct {
+		Name string `xml:"name"`
+		URI  string `xml:"uri"`
+	}
+	var atom struct {
+		Entries []struct {
+			Authors      []person `xml:"author"`
+			Contributors []person `xml:"contributor"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &atom))
+	require.Len(t, atom.Entries, 1)
+	assert.Equal(t, []person{
+		{Name: "Anna Lee", URI: "https://news.example/authors/anna-lee"},
+		{Name: "José", URI: "https://news.example/authors/jos%C3%A9"},
+	}, atom.Entries[0].Authors, "writers are the authors, in byline order")
+	assert.Equal(t, []person{
+		{Name: "Boris", URI: "https://news.example/authors/boris"},
+		{Name: "???"},
+	}, atom.Entries[0].Contributors, "names without a handle have no author page")
+
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, testMeta, urls, posts))
+	var jsonFeed struct {
+		Items []struct {
+			Authors []struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &jsonFeed))
+	require.Len(t, jsonFeed.Items, 1)
+	require.Len(t, jsonFeed.Items[0].Authors, 2, "only writers are authors")
+	assert.Equal(t, "José", jsonFeed.Items[0].Authors[1].Name)
+	assert.Equal(t, "https://news.example/authors/jos%C3%A9", jsonFeed.Items[0].Authors[1].URL)
+}
@@ -0,0 +1,86 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// jsonFeedVersion identifies JSON Feed 1.1 documents.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// summaryLength caps the plain-text summary of JSON Feed items.
+const summaryLength = 280
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	NextURL     string         `json:"next_url,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	Summary       string           `json:"summary,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// WriteJSON writes posts as a JSON Feed 1.1 document. The feed links to the
+// next page when meta.NextURL is set.
+func WriteJSON(w io.Writer, meta Meta, urls URLs, posts []*domain.Post) error {
+	feed := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       meta.Title,
+		HomePageURL: meta.Link,
+		FeedURL:     meta.Self,
+		Description: meta.Description,
+		NextURL:     meta.NextURL,
+		Language:    "en",
+		Items:       make([]jsonFeedItem, 0, len(posts)),
+	}
+
+	for _, p := range posts {
+		link := urls.Post(p)
+		item := jsonFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         p.Title,
+			ContentHTML:   ContentHTML(p.Content),
+			Summary:       p.Excerpt(summaryLength),
+			Image:         p.ImageURL,
+			DatePublished: p.CreatedAt.UTC().Format(time.RFC3339),
+			DateModified:  modifiedAt(p).UTC().Format(time.RFC3339),
+			Tags:          categories(p),
+		}
+		for _, c := range p.Writers() {
+			item.Authors = append(item.Authors, jsonFeedAuthor{Name: c.Name, URL: urls.Author(c)})
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(feed); err != nil {
+		return fmt.Errorf("failed to encode json feed: %w", err)
+	}
+	return nil
+}
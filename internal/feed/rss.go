@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	AtomLinks     []atomLink `xml:"atom:link"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem  `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Creators    []string `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes posts as an RSS 2.0 document. Content is embedded as escaped HTML.
+func WriteRSS(w io.Writer, meta Meta, urls URLs, posts []*domain.Post) error {
+	doc := rssDocument{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       meta.Title,
+			Link:        meta.Link,
+			Description: meta.Description,
+			AtomLinks:   []atomLink{{Href: meta.Self, Rel: "self", Type: "application/rss+xml"}},
+		},
+	}
+	if meta.NextURL != "" {
+		doc.Channel.AtomLinks = append(doc.Channel.AtomLinks, atomLink{Href: meta.NextURL, Rel: "next", Type: "application/rss+xml"})
+	}
+	if updated := Updated(posts); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, p := range posts {
+		link := urls.Post(p)
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       p.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			Description: ContentHTML(p.Content),
+			Creators:    names(p.Writers()),
+			Categories:  categories(p),
+			PubDate:     p.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write rss: %w", err)
+	}
+	if err := xml.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode rss: %w", err)
+	}
+	return nil
+}
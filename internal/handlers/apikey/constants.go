@@ -0,0 +1,7 @@
+package apikey
+
+// HTMX headers
+const (
+	HXErrorHeader   = "HX-Error-Message"
+	HXRefreshHeader = "HX-Refresh"
+)
@@ -0,0 +1,11 @@
+package apikey
+
+// Error messages
+const (
+	ErrInvalidFormData      = "Invalid form data"
+	ErrAPIKeyNotFound       = "API key not found"
+	ErrFailedToLoadAPIKeys  = "Failed to load API keys"
+	ErrFailedToCreateAPIKey = "Failed to create API key"
+	ErrFailedToRevokeAPIKey = "Failed to revoke API key"
+	ErrInternalServer       = "Internal server error"
+)
@@ -0,0 +1,213 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/adminauth"
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const adminToken = "admin-secret"
+
+func setupTestHandler() (http.Handler, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	r.Use(reader.Middleware)
+	RegisterRoutes(r, New(mockService, tmpl, adminToken, logger))
+	return r, mockService
+}
+
+func TestHandler_Index(t *testing.T) {
+	router, mockService := setupTestHandler()
+	now := time.Now()
+	active := &domain.APIKey{
+		ID:         primitive.NewObjectID(),
+		Name:       "Import script",
+		Prefix:     "0123456789ab",
+		Scopes:     []domain.APIScope{domain.ScopePostsRead, domain.ScopePostsWrite},
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+	revoked := &domain.APIKey{
+		ID:        primitive.NewObjectID(),
+		Name:      "Old script",
+		Prefix:    "ba9876543210",
+		Scopes:    []domain.APIScope{domain.ScopePostsRead},
+		CreatedAt: now.Add(-48 * time.Hour),
+		ExpiresAt: now.Add(24 * time.Hour),
+		RevokedAt: now.Add(-time.Hour),
+	}
+	var owner string
+	mockService.ListFunc = func(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+		owner = ownerID
+		return []*domain.APIKey{active, revoked}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me/api-keys", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, reader.Valid(owner), "keys are listed per reader")
+	body := w.Body.String()
+	assert.Contains(t, body, "news_0123456789ab_…")
+	assert.Contains(t, body, "posts:read, posts:write")
+	assert.Contains(t, body, "never expires")
+	assert.Contains(t, body, `hx-delete="/me/api-keys/`+active.ID.Hex()+`"`)
+	assert.NotContains(t, body, `hx-delete="/me/api-keys/`+revoked.ID.Hex()+`"`)
+	assert.Contains(t, body, "Revoked ")
+	assert.Contains(t, body, `<option value="90" selected>90 days</option>`)
+	assert.Contains(t, body, `value="posts:read" checked`)
+
+	mockService.ListFunc = func(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+		return nil, assert.AnError
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me/api-keys", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandler_Create(t *testing.T) {
+	router, mockService := setupTestHandler()
+
+	tests := []struct {
+		name           string
+		form           string
+		password       string
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid key",
+			form:           "name=Import+script&scopes=posts%3Aread&scopes=posts%3Awrite&expiry=30",
+			password:       adminToken,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "news_0123456789ab_secret",
+		},
+		{
+			name:           "read key without admin token",
+			form:           "name=Import+script&scopes=posts%3Aread&expiry=30",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "news_0123456789ab_secret",
+		},
+		{
+			name:           "write key without admin token",
+			form:           "name=Import+script&scopes=posts%3Aread&scopes=posts%3Awrite&expiry=30",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   adminauth.ErrUnauthorized,
+		},
+		{
+			name:           "write key with wrong admin token",
+			form:           "name=Import+script&scopes=posts%3Awrite&expiry=30",
+			password:       "guess",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   adminauth.ErrUnauthorized,
+		},
+		{
+			name:           "invalid expiry",
+			form:           "name=Import+script&scopes=posts%3Aread&expiry=soon",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidAPIKeyExpiry.Error(),
+		},
+		{
+			name:           "no scope",
+			form:           "name=Import+script&expiry=30",
+			serviceErr:     domain.ErrInvalidAPIScope,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidAPIScope.Error(),
+		},
+		{
+			name:           "unknown scope",
+			form:           "name=Import+script&scopes=posts%3Aadmin&expiry=30",
+			serviceErr:     fmt.Errorf("%w: posts:admin", domain.ErrInvalidAPIScope),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidAPIScope.Error(),
+		},
+		{
+			name:           "repository error",
+			form:           "name=Import+script&scopes=posts%3Aread&expiry=30",
+			serviceErr:     assert.AnError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   ErrFailedToCreateAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.APIKeyInput
+			created := false
+			mockService.CreateFunc = func(ctx context.Context, ownerID string, in domain.APIKeyInput) (*domain.APIKey, string, error) {
+				got = in
+				created = true
+				if tt.serviceErr != nil {
+					return nil, "", tt.serviceErr
+				}
+				return &domain.APIKey{ID: primitive.NewObjectID(), Name: in.Name, Prefix: "0123456789ab", Scopes: in.Scopes}, "news_0123456789ab_secret", nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/me/api-keys", strings.NewReader(tt.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.password != "" {
+				req.SetBasicAuth("admin", tt.password)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.False(t, created, "no key is created without the admin token")
+			}
+			switch tt.name {
+			case "valid key":
+				assert.Equal(t, []domain.APIScope{domain.ScopePostsRead, domain.ScopePostsWrite}, got.Scopes)
+				assert.Equal(t, 30*24*time.Hour, got.ExpiresIn)
+				assert.Contains(t, w.Body.String(), "cannot be shown again")
+			case "no scope":
+				assert.Contains(t, w.Body.String(), `value="Import script"`, "the form is refilled")
+				assert.Contains(t, w.Body.String(), `<option value="30" selected>30 days</option>`)
+			}
+		})
+	}
+}
+
+func TestHandler_Revoke(t *testing.T) {
+	router, mockService := setupTestHandler()
+	id := primitive.NewObjectID().Hex()
+
+	var revoked string
+	mockService.RevokeFunc = func(ctx context.Context, ownerID, keyID string) error {
+		revoked = keyID
+		return nil
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me/api-keys/"+id, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "true", w.Header().Get(HXRefreshHeader))
+	assert.Equal(t, id, revoked)
+
+	mockService.RevokeFunc = func(ctx context.Context, ownerID, keyID string) error {
+		return domain.ErrAPIKeyNotFound
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/me/api-keys/"+id, nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ErrAPIKeyNotFound, w.Header().Get(HXErrorHeader))
+}
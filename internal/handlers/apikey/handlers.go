@@ -0,0 +1,196 @@
+package apikey
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kir/news-app/internal/adminauth"
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// defaultExpiry is the expiry preselected in the form, in days.
+const defaultExpiry = "90"
+
+// expiry is a choice of the expiry select of the form.
+type expiry struct {
+	Days  string
+	Label string
+}
+
+var expiries = []expiry{
+	{Days: "30", Label: "30 days"},
+	{Days: "90", Label: "90 days"},
+	{Days: "365", Label: "1 year"},
+	{Days: "0", Label: "Never"},
+}
+
+// Handler handles the page where readers manage their API keys
+type Handler struct {
+	service    APIKeyService
+	templates  *template.Template
+	adminToken string
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// New creates a new API key handler. Keys granted posts:write can only be
+// created with adminToken, and not at all when it is empty.
+func New(service APIKeyService, templates *template.Template, adminToken string, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		templates:  templates,
+		adminToken: adminToken,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// keysPage is the data of the API key page. Name, Scopes and Expiry refill
+// the form after a rejected submission; Created and Token show a new key,
+// whose token cannot be shown again.
+type keysPage struct {
+	Keys      []*domain.APIKey
+	AllScopes []domain.APIScope
+	Expiries  []expiry
+	Now       time.Time
+
+	Name   string
+	Scopes []domain.APIScope
+	Expiry string
+	Error  string
+
+	Created *domain.APIKey
+	Token   string
+}
+
+// Checked reports whether the form has scope checked.
+func (p keysPage) Checked(scope domain.APIScope) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Index handles the page listing the reader's API keys with the form
+// creating one
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	h.render(w, r, http.StatusOK, keysPage{Scopes: []domain.APIScope{domain.ScopePostsRead}, Expiry: defaultExpiry})
+}
+
+// Create handles the form creating an API key and shows its token once.
+// Keys granted posts:write need the admin token, which the browser asks for.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	page := keysPage{Name: r.PostForm.Get("name"), Expiry: r.PostForm.Get("expiry")}
+	for _, s := range r.PostForm["scopes"] {
+		page.Scopes = append(page.Scopes, domain.APIScope(s))
+	}
+	if page.Checked(domain.ScopePostsWrite) {
+		// Any reader may read posts, but only the admin may write them.
+		adminauth.Require(h.adminToken)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.create(w, r, page)
+		})).ServeHTTP(w, r)
+		return
+	}
+	h.create(w, r, page)
+}
+
+// create creates the key submitted in page and shows its token.
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, page keysPage) {
+	in := domain.APIKeyInput{Name: page.Name, Scopes: page.Scopes}
+	days, err := strconv.Atoi(page.Expiry)
+	if err != nil {
+		page.Error = domain.ErrInvalidAPIKeyExpiry.Error()
+		h.render(w, r, http.StatusBadRequest, page)
+		return
+	}
+	in.ExpiresIn = time.Duration(days) * 24 * time.Hour
+
+	key, token, err := h.service.Create(r.Context(), reader.ID(r.Context()), in)
+	if invalid := validationError(err); invalid != nil {
+		page.Error = invalid.Error()
+		h.render(w, r, http.StatusBadRequest, page)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToCreateAPIKey, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("created API key", zap.String("prefix", key.Prefix))
+	h.render(w, r, http.StatusCreated, keysPage{
+		Scopes:  []domain.APIScope{domain.ScopePostsRead},
+		Expiry:  defaultExpiry,
+		Created: key,
+		Token:   token,
+	})
+}
+
+// Revoke handles revoking an API key and reloads the page
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	err := h.service.Revoke(r.Context(), reader.ID(r.Context()), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		h.handleError(w, err, ErrAPIKeyNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToRevokeAPIKey, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(HXRefreshHeader, "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// render loads the reader's keys into page and renders it with status.
+func (h *Handler) render(w http.ResponseWriter, r *http.Request, status int, page keysPage) {
+	keys, err := h.service.List(r.Context(), reader.ID(r.Context()))
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadAPIKeys, http.StatusInternalServerError)
+		return
+	}
+	page.Keys = keys
+	page.AllScopes = domain.APIScopes
+	page.Expiries = expiries
+	page.Now = h.now()
+
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "apikeys/page", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidAPIKeyName, domain.ErrInvalidAPIScope, domain.ErrInvalidAPIKeyExpiry} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, ownerID string, in domain.APIKeyInput) (*domain.APIKey, string, error)
+	List(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, ownerID, id string) error
+}
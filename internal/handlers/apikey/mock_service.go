@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService is a mock implementation of APIKeyService
+type MockService struct {
+	CreateFunc func(ctx context.Context, ownerID string, in domain.APIKeyInput) (*domain.APIKey, string, error)
+	ListFunc   func(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	RevokeFunc func(ctx context.Context, ownerID, id string) error
+}
+
+func (m *MockService) Create(ctx context.Context, ownerID string, in domain.APIKeyInput) (*domain.APIKey, string, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, ownerID, in)
+	}
+	return nil, "", nil
+}
+
+func (m *MockService) List(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, ownerID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Revoke(ctx context.Context, ownerID, id string) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(ctx, ownerID, id)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package apikey
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the API key page. The routes expect
+// the reader ID put into the request context by reader.Middleware.
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/me/api-keys", func(r chi.Router) {
+		r.Get("/", h.Index)
+		r.Post("/", h.Create)
+		r.Delete("/{id}", h.Revoke)
+	})
+}
@@ -0,0 +1,6 @@
+package author
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
@@ -0,0 +1,11 @@
+package author
+
+// Error messages
+const (
+	ErrInvalidFormData      = "Invalid form data"
+	ErrAuthorNotFound       = "Author not found"
+	ErrFailedToLoadAuthor   = "Failed to load author"
+	ErrFailedToLoadAuthors  = "Failed to load authors"
+	ErrFailedToUpdateAuthor = "Failed to update author"
+	ErrInternalServer       = "Internal server error"
+)
@@ -0,0 +1,175 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func setupTestServer(t *testing.T) (*httptest.Server, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, tmpl, logger))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, mockService
+}
+
+func get(t *testing.T, target string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestHandler_Page(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	author := &domain.Author{
+		Handle:    "anna-smith",
+		Name:      "Anna Smith",
+		Bio:       "Covers politics.",
+		AvatarURL: "https://cdn.news.example/anna.jpg",
+		Links:     []string{"https://x.com/anna"},
+	}
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget passes", Content: "The budget passed.", Author: "Anna Smith", CreatedAt: time.Now()}
+
+	mockService.PageFunc = func(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error) {
+		if handle != author.Handle {
+			return nil, nil, domain.ErrAuthorNotFound
+		}
+		return author, &domain.PostList{Posts: []*domain.Post{post}, TotalCount: 10, Page: page, PageSize: domain.AuthorPageSize}, nil
+	}
+
+	t.Run("page", func(t *testing.T) {
+		resp, body := get(t, server.URL+"/authors/anna-smith?page=2", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "<title>Anna Smith — News Portal</title>")
+		assert.Contains(t, body, "Covers politics.")
+		assert.Contains(t, body, `src="https://cdn.news.example/anna.jpg"`)
+		assert.Contains(t, body, `href="https://x.com/anna"`)
+		assert.Contains(t, body, ">X<")
+		assert.Contains(t, body, "Budget passes")
+		assert.Contains(t, body, `hx-get="/authors/anna-smith"`, "page 2 links back to the first page")
+		assert.Contains(t, body, `href="/authors/anna-smith/edit"`)
+	})
+
+	t.Run("htmx fragment", func(t *testing.T) {
+		resp, body := get(t, server.URL+"/authors/anna-smith?page=2", http.Header{"Hx-Request": {"true"}})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, body, "<html")
+		assert.Contains(t, body, `id="posts-grid"`)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		resp, _ := get(t, server.URL+"/authors/nobody", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, ErrAuthorNotFound, resp.Header.Get(HXErrorHeader))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mockService.PageFunc = func(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error) {
+			return nil, nil, errors.New("connection reset")
+		}
+		resp, _ := get(t, server.URL+"/authors/anna-smith", nil)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, ErrFailedToLoadAuthor, resp.Header.Get(HXErrorHeader))
+	})
+}
+
+func TestHandler_Names(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	mockService.NamesFunc = func(ctx context.Context) ([]string, error) {
+		return []string{"Anna Smith", "Boris"}, nil
+	}
+
+	resp, body := get(t, server.URL+"/authors", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `<datalist id="contributor-names">`)
+	assert.Contains(t, body, `<option value="Anna Smith">`)
+	assert.Contains(t, body, `<option value="Boris">`)
+
+	mockService.NamesFunc = func(ctx context.Context) ([]string, error) {
+		return nil, errors.New("connection reset")
+	}
+	resp, _ = get(t, server.URL+"/authors", nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, ErrFailedToLoadAuthors, resp.Header.Get(HXErrorHeader))
+}
+
+func TestHandler_EditForm(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	mockService.GetFunc = func(ctx context.Context, handle string) (*domain.Author, error) {
+		return &domain.Author{Handle: handle, Name: "Anna Smith", Bio: "Covers politics.", Links: []string{"https://x.com/anna"}}, nil
+	}
+
+	resp, body := get(t, server.URL+"/authors/anna-smith/edit", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `action="/authors/anna-smith"`)
+	assert.Contains(t, body, ">Covers politics.</textarea>")
+	assert.Contains(t, body, `value="https://x.com/anna"`)
+	assert.Equal(t, domain.MaxSocialLinks, strings.Count(body, `name="link"`), "the form has room for every link")
+}
+
+func TestHandler_Update(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	var got domain.AuthorInput
+	mockService.GetFunc = func(ctx context.Context, handle string) (*domain.Author, error) {
+		return &domain.Author{Handle: handle, Name: "Anna Smith"}, nil
+	}
+	mockService.UpdateFunc = func(ctx context.Context, handle string, in domain.AuthorInput) (*domain.Author, error) {
+		got = in
+		if in.AvatarURL == "avatar.png" {
+			return nil, domain.ErrInvalidAvatar
+		}
+		return &domain.Author{Handle: handle, Name: "Anna Smith"}, nil
+	}
+
+	t.Run("saved", func(t *testing.T) {
+		form := url.Values{"bio": {"Covers politics."}, "link": {"https://x.com/anna", " ", ""}}
+		resp, err := client.PostForm(server.URL+"/authors/anna-smith", form)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+		assert.Equal(t, "/authors/anna-smith", resp.Header.Get("Location"))
+		assert.Equal(t, domain.AuthorInput{Bio: "Covers politics.", Links: []string{"https://x.com/anna"}}, got)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		resp, err := client.PostForm(server.URL+"/authors/anna-smith", url.Values{"bio": {"Covers politics."}, "avatar_url": {"avatar.png"}})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, string(b), domain.ErrInvalidAvatar.Error())
+		assert.Contains(t, string(b), `value="avatar.png"`, "the form keeps what was typed")
+	})
+}
@@ -0,0 +1,200 @@
+package author
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler handles author pages and profile editing
+type Handler struct {
+	service   AuthorService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new author handler
+func New(service AuthorService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// authorPage is the data of an author page, paged like the post listing by
+// post/pagination.
+type authorPage struct {
+	Author     *domain.Author
+	Posts      []*domain.Post
+	TotalCount int64
+	Page       int
+	TotalPages int
+}
+
+// PageURL returns the URL of the given page of the author's posts.
+func (p authorPage) PageURL(page int) string {
+	path := authorPath(p.Author.Handle)
+	if page <= 1 {
+		return path
+	}
+	return path + "?" + url.Values{"page": {strconv.Itoa(page)}}.Encode()
+}
+
+// editPage is the data of the profile form. Input and Error refill the form
+// after a rejected submission.
+type editPage struct {
+	Author *domain.Author
+	Input  domain.AuthorInput
+	Error  string
+}
+
+// Links returns the link fields of the form, with room for new ones.
+func (p editPage) Links() []string {
+	links := make([]string, max(domain.MaxSocialLinks, len(p.Input.Links)))
+	copy(links, p.Input.Links)
+	return links
+}
+
+func authorPath(handle string) string {
+	return "/authors/" + url.PathEscape(handle)
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Names handles the suggestions of the contributor picker: a datalist of
+// the names credited on posts
+func (h *Handler) Names(w http.ResponseWriter, r *http.Request) {
+	names, err := h.service.Names(r.Context())
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadAuthors, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "authors/names", names); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Page handles the author page with the profile and the author's published
+// posts, or only the list fragment for HTMX requests
+func (h *Handler) Page(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	author, list, err := h.service.Page(r.Context(), chi.URLParam(r, "handle"), page)
+	if errors.Is(err, domain.ErrAuthorNotFound) {
+		h.handleError(w, err, ErrAuthorNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadAuthor, http.StatusInternalServerError)
+		return
+	}
+
+	data := authorPage{
+		Author:     author,
+		Posts:      list.Posts,
+		TotalCount: list.TotalCount,
+		Page:       list.Page,
+		TotalPages: int((list.TotalCount + domain.AuthorPageSize - 1) / domain.AuthorPageSize),
+	}
+
+	name := "authors/page"
+	if r.Header.Get("HX-Request") == "true" {
+		name = "authors/list"
+	}
+	if err := h.templates.ExecuteTemplate(w, name, data); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// EditForm handles the profile form of an author
+func (h *Handler) EditForm(w http.ResponseWriter, r *http.Request) {
+	author, err := h.service.Get(r.Context(), chi.URLParam(r, "handle"))
+	if errors.Is(err, domain.ErrAuthorNotFound) {
+		h.handleError(w, err, ErrAuthorNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadAuthor, http.StatusInternalServerError)
+		return
+	}
+
+	input := domain.AuthorInput{Bio: author.Bio, AvatarURL: author.AvatarURL, Links: author.Links}
+	h.renderEdit(w, http.StatusOK, editPage{Author: author, Input: input})
+}
+
+// Update handles the profile form and shows the updated author page
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	handle := chi.URLParam(r, "handle")
+	in := domain.AuthorInput{
+		Bio:       r.PostForm.Get("bio"),
+		AvatarURL: r.PostForm.Get("avatar_url"),
+	}
+	for _, link := range r.PostForm["link"] {
+		if link = strings.TrimSpace(link); link != "" {
+			in.Links = append(in.Links, link)
+		}
+	}
+
+	author, err := h.service.Update(r.Context(), handle, in)
+	if invalid := validationError(err); invalid != nil {
+		current, err := h.service.Get(r.Context(), handle)
+		if err != nil {
+			h.handleError(w, err, ErrFailedToLoadAuthor, http.StatusInternalServerError)
+			return
+		}
+		h.renderEdit(w, http.StatusBadRequest, editPage{Author: current, Input: in, Error: invalid.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrAuthorNotFound) {
+		h.handleError(w, err, ErrAuthorNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToUpdateAuthor, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("updated author", zap.String("handle", author.Handle))
+	http.Redirect(w, r, authorPath(author.Handle), http.StatusSeeOther)
+}
+
+// renderEdit renders the profile form with status.
+func (h *Handler) renderEdit(w http.ResponseWriter, status int, page editPage) {
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "authors/edit", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidAuthorBio, domain.ErrInvalidAvatar, domain.ErrInvalidSocialLink} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
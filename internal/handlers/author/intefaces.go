@@ -0,0 +1,14 @@
+package author
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type AuthorService interface {
+	Get(ctx context.Context, handle string) (*domain.Author, error)
+	Names(ctx context.Context) ([]string, error)
+	Page(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error)
+	Update(ctx context.Context, handle string, in domain.AuthorInput) (*domain.Author, error)
+}
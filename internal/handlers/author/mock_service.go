@@ -0,0 +1,43 @@
+package author
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService is a mock implementation of AuthorService
+type MockService struct {
+	GetFunc    func(ctx context.Context, handle string) (*domain.Author, error)
+	NamesFunc  func(ctx context.Context) ([]string, error)
+	PageFunc   func(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error)
+	UpdateFunc func(ctx context.Context, handle string, in domain.AuthorInput) (*domain.Author, error)
+}
+
+func (m *MockService) Get(ctx context.Context, handle string) (*domain.Author, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, handle)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Names(ctx context.Context) ([]string, error) {
+	if m.NamesFunc != nil {
+		return m.NamesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Page(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error) {
+	if m.PageFunc != nil {
+		return m.PageFunc(ctx, handle, page)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockService) Update(ctx context.Context, handle string, in domain.AuthorInput) (*domain.Author, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, handle, in)
+	}
+	return nil, nil
+}
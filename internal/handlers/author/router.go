@@ -0,0 +1,15 @@
+package author
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the author handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/authors", h.Names)
+	r.Route("/authors/{handle}", func(r chi.Router) {
+		r.Get("/", h.Page)
+		r.Post("/", h.Update)
+		r.Get("/edit", h.EditForm)
+	})
+}
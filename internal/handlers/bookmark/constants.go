@@ -0,0 +1,6 @@
+package bookmark
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
@@ -0,0 +1,9 @@
+package bookmark
+
+// Error messages
+const (
+	ErrPostNotFound          = "Post not found"
+	ErrFailedToBookmark      = "Failed to update bookmark"
+	ErrFailedToLoadBookmarks = "Failed to load bookmarks"
+	ErrInternalServer        = "Internal server error"
+)
@@ -0,0 +1,148 @@
+package bookmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const testReader = "0123456789abcdef0123456789abcdef"
+
+func setupTestServer(t *testing.T) (*httptest.Server, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	r.Use(reader.Middleware)
+	RegisterRoutes(r, New(mockService, tmpl, logger))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, mockService
+}
+
+// do sends a request as testReader.
+func do(t *testing.T, method, target string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, target, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.AddCookie(&http.Cookie{Name: reader.CookieName, Value: testReader})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestHandler_Toggle(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget passes", BookmarkCount: 4}
+	path := "/posts/" + post.ID.Hex() + "/bookmark"
+
+	mockService.AddFunc = func(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+		assert.Equal(t, testReader, readerID)
+		assert.Equal(t, post.ID.Hex(), postID)
+		return &domain.Post{ID: post.ID, BookmarkCount: 5, Bookmarked: true}, nil
+	}
+	mockService.RemoveFunc = func(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+		return &domain.Post{ID: post.ID, BookmarkCount: 4}, nil
+	}
+
+	resp, body := do(t, http.MethodPost, server.URL+path, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `hx-delete="`+path+`"`, "a bookmarked post offers removing it")
+	assert.Contains(t, body, ">5<")
+
+	resp, body = do(t, http.MethodDelete, server.URL+path, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `hx-post="`+path+`"`)
+	assert.Contains(t, body, ">4<")
+}
+
+func TestHandler_ToggleErrors(t *testing.T) {
+	server, mockService := setupTestServer(t)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "missing post", err: fmt.Errorf("%w: no documents", domain.ErrPostNotFound), expectedStatus: http.StatusNotFound, expectedError: ErrPostNotFound},
+		{name: "storage failure", err: errors.New("connection reset"), expectedStatus: http.StatusInternalServerError, expectedError: ErrFailedToBookmark},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.GetFunc = func(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+				return nil, tt.err
+			}
+			resp, _ := do(t, http.MethodGet, server.URL+"/posts/"+primitive.NewObjectID().Hex()+"/bookmark", nil)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedError, resp.Header.Get(HXErrorHeader))
+		})
+	}
+}
+
+func TestHandler_Marks(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	marked := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	mockService.MarkedFunc = func(ctx context.Context, readerID string, postIDs []string) ([]*domain.Post, error) {
+		assert.Equal(t, testReader, readerID)
+		assert.Equal(t, []string{marked.Hex(), other.Hex()}, postIDs)
+		return []*domain.Post{{ID: marked, Bookmarked: true, BookmarkCount: 1}}, nil
+	}
+
+	resp, body := do(t, http.MethodGet, server.URL+"/me/bookmarks/marks?post="+marked.Hex()+"&post="+other.Hex(), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `id="bookmark-`+marked.Hex()+`" hx-swap-oob="true"`)
+	assert.NotContains(t, body, other.Hex())
+}
+
+func TestHandler_List(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget passes", Content: "The budget passed.", CreatedAt: time.Now(), Bookmarked: true}
+
+	mockService.ListFunc = func(ctx context.Context, readerID string, page int) (*domain.PostList, error) {
+		assert.Equal(t, testReader, readerID)
+		return &domain.PostList{Posts: []*domain.Post{post}, TotalCount: 10, Page: page, PageSize: domain.BookmarkPageSize}, nil
+	}
+
+	t.Run("page", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, server.URL+"/me/bookmarks?page=2", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "<title>Bookmarks")
+		assert.Contains(t, body, "Budget passes")
+		assert.Contains(t, body, `hx-get="/me/bookmarks"`, "page 2 links back to the first page")
+		assert.NotContains(t, body, `/me/bookmarks?page=3`, "ten bookmarks fill two pages")
+	})
+
+	t.Run("htmx fragment", func(t *testing.T) {
+		resp, body := do(t, http.MethodGet, server.URL+"/me/bookmarks?page=2", http.Header{"Hx-Request": {"true"}})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotContains(t, body, "<html")
+		assert.Contains(t, body, `id="posts-grid"`)
+	})
+}
@@ -0,0 +1,129 @@
+package bookmark
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler handles bookmark toggles and the reading list
+type Handler struct {
+	service   BookmarkService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new bookmark handler
+func New(service BookmarkService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// listPage is the data of the reading list, paged like the post listing by
+// post/pagination.
+type listPage struct {
+	Posts      []*domain.Post
+	TotalCount int64
+	Page       int
+	TotalPages int
+}
+
+// PageURL returns the URL of the given page of the reading list.
+func (p listPage) PageURL(page int) string {
+	if page <= 1 {
+		return "/me/bookmarks"
+	}
+	return "/me/bookmarks?" + url.Values{"page": {strconv.Itoa(page)}}.Encode()
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Button renders the bookmark button of a post for the reader. Article pages
+// load it after the page.
+func (h *Handler) Button(w http.ResponseWriter, r *http.Request) {
+	post, err := h.service.Get(r.Context(), reader.ID(r.Context()), chi.URLParam(r, "id"))
+	h.renderButton(w, post, err)
+}
+
+// Add bookmarks a post and renders the button as bookmarked
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	post, err := h.service.Add(r.Context(), reader.ID(r.Context()), chi.URLParam(r, "id"))
+	h.renderButton(w, post, err)
+}
+
+// Remove takes a post off the reading list and renders the button as not
+// bookmarked
+func (h *Handler) Remove(w http.ResponseWriter, r *http.Request) {
+	post, err := h.service.Remove(r.Context(), reader.ID(r.Context()), chi.URLParam(r, "id"))
+	h.renderButton(w, post, err)
+}
+
+func (h *Handler) renderButton(w http.ResponseWriter, post *domain.Post, err error) {
+	if errors.Is(err, domain.ErrPostNotFound) {
+		h.handleError(w, err, ErrPostNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToBookmark, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "bookmarks/button", post); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Marks swaps the buttons of the listed posts the reader bookmarked out of
+// band. Post cards are rendered the same for everyone, with the button not
+// bookmarked, and request this after loading.
+func (h *Handler) Marks(w http.ResponseWriter, r *http.Request) {
+	posts, err := h.service.Marked(r.Context(), reader.ID(r.Context()), r.URL.Query()["post"])
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadBookmarks, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "bookmarks/marks", posts); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// List handles the reader's reading list, or only the list fragment for HTMX
+// requests
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	list, err := h.service.List(r.Context(), reader.ID(r.Context()), page)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadBookmarks, http.StatusInternalServerError)
+		return
+	}
+
+	data := listPage{
+		Posts:      list.Posts,
+		TotalCount: list.TotalCount,
+		Page:       list.Page,
+		TotalPages: int((list.TotalCount + domain.BookmarkPageSize - 1) / domain.BookmarkPageSize),
+	}
+
+	name := "bookmarks/page"
+	if r.Header.Get("HX-Request") == "true" {
+		name = "bookmarks/list"
+	}
+	if err := h.templates.ExecuteTemplate(w, name, data); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
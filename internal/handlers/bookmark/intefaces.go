@@ -0,0 +1,15 @@
+package bookmark
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type BookmarkService interface {
+	Get(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	Add(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	Remove(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	Marked(ctx context.Context, readerID string, postIDs []string) ([]*domain.Post, error)
+	List(ctx context.Context, readerID string, page int) (*domain.PostList, error)
+}
@@ -0,0 +1,51 @@
+package bookmark
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService is a mock implementation of BookmarkService
+type MockService struct {
+	GetFunc    func(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	AddFunc    func(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	RemoveFunc func(ctx context.Context, readerID, postID string) (*domain.Post, error)
+	MarkedFunc func(ctx context.Context, readerID string, postIDs []string) ([]*domain.Post, error)
+	ListFunc   func(ctx context.Context, readerID string, page int) (*domain.PostList, error)
+}
+
+func (m *MockService) Get(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, readerID, postID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Add(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, readerID, postID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Remove(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(ctx, readerID, postID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Marked(ctx context.Context, readerID string, postIDs []string) ([]*domain.Post, error) {
+	if m.MarkedFunc != nil {
+		return m.MarkedFunc(ctx, readerID, postIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockService) List(ctx context.Context, readerID string, page int) (*domain.PostList, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, readerID, page)
+	}
+	return &domain.PostList{Page: 1, PageSize: domain.BookmarkPageSize}, nil
+}
@@ -0,0 +1,17 @@
+package bookmark
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the bookmark handler. The routes
+// expect the reader ID put into the request context by reader.Middleware.
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/posts/{id}/bookmark", h.Button)
+	r.Post("/posts/{id}/bookmark", h.Add)
+	r.Delete("/posts/{id}/bookmark", h.Remove)
+	r.Route("/me/bookmarks", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Get("/marks", h.Marks)
+	})
+}
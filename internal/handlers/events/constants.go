@@ -0,0 +1,14 @@
+package events
+
+import "time"
+
+const (
+	// LastEventIDHeader is sent by EventSource when it reconnects.
+	LastEventIDHeader = "Last-Event-ID"
+
+	// heartbeatInterval is how often a comment is sent on an idle stream, so
+	// that proxies keep the connection open and dead clients are noticed.
+	heartbeatInterval = 15 * time.Second
+	// retryInterval is how long browsers wait before reconnecting.
+	retryInterval = 3 * time.Second
+)
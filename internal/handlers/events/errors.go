@@ -0,0 +1,7 @@
+package events
+
+// Error messages
+const (
+	ErrStreamingUnsupported = "Streaming unsupported"
+	ErrShuttingDown         = "Server is shutting down"
+)
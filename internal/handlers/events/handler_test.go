@@ -0,0 +1,121 @@
+package events
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/live"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func setupTestServer(t *testing.T) (*httptest.Server, *live.Hub, *Handler) {
+	hub := live.NewHub()
+	logger, _ := zap.NewDevelopment()
+	h := New(hub, logger)
+	r := chi.NewRouter()
+	RegisterRoutes(r, h)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, hub, h
+}
+
+// readEvent reads lines up to the next blank line.
+func readEvent(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func waitForSubscribers(t *testing.T, hub *live.Hub, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return hub.Len() == n }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHandler_Stream(t *testing.T) {
+	server, hub, _ := setupTestServer(t)
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, "retry: 3000", readEvent(t, reader))
+
+	waitForSubscribers(t, hub, 1)
+	msg := hub.Publish("post", "<div>\n  card\n</div>")
+	assert.Equal(t, "id: "+msg.ID+"\nevent: post\ndata: <div>\ndata:   card\ndata: </div>", readEvent(t, reader))
+
+	hub.Close()
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err, "the stream ends when the hub is closed")
+}
+
+func TestHandler_StreamReplaysAfterLastEventID(t *testing.T) {
+	server, hub, _ := setupTestServer(t)
+	seen := hub.Publish("post", "seen")
+	hub.Publish("post", "missed")
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set(LastEventIDHeader, seen.ID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent(t, reader)
+	assert.Contains(t, readEvent(t, reader), "data: missed")
+}
+
+func TestHandler_StreamHeartbeat(t *testing.T) {
+	server, _, h := setupTestServer(t)
+	h.heartbeat = 10 * time.Millisecond
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent(t, reader)
+	assert.Equal(t, ": heartbeat", readEvent(t, reader))
+}
+
+func TestHandler_StreamUnsubscribesOnDisconnect(t *testing.T) {
+	server, hub, _ := setupTestServer(t)
+
+	resp, err := http.Get(server.URL + "/events")
+	require.NoError(t, err)
+	waitForSubscribers(t, hub, 1)
+
+	resp.Body.Close()
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestHandler_StreamAfterShutdown(t *testing.T) {
+	hub := live.NewHub()
+	hub.Close()
+	logger, _ := zap.NewDevelopment()
+
+	w := httptest.NewRecorder()
+	New(hub, logger).Stream(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/live"
+
+	"go.uber.org/zap"
+)
+
+// Handler streams hub messages to browsers as server-sent events
+type Handler struct {
+	hub       Hub
+	heartbeat time.Duration
+	logger    *zap.Logger
+}
+
+// New creates a new events handler
+func New(hub Hub, logger *zap.Logger) *Handler {
+	return &Handler{
+		hub:       hub,
+		heartbeat: heartbeatInterval,
+		logger:    logger,
+	}
+}
+
+// Stream handles the event stream. Messages missed since the Last-Event-ID
+// of a reconnecting client are sent first. The stream ends when the client
+// goes away or the hub is closed on shutdown.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	sub, replay := h.hub.Subscribe(r.Header.Get(LastEventIDHeader))
+	if sub == nil {
+		http.Error(w, ErrShuttingDown, http.StatusServiceUnavailable)
+		return
+	}
+	defer sub.Close()
+
+	// The stream outlives the server read and write timeouts.
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to clear read deadline", zap.Error(err))
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to clear write deadline", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryInterval.Milliseconds())
+	for _, msg := range replay {
+		writeMessage(w, msg)
+	}
+	if err := rc.Flush(); err != nil {
+		h.logger.Error(ErrStreamingUnsupported, zap.Error(err))
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			writeMessage(w, msg)
+		case <-heartbeat.C:
+			io.WriteString(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeMessage writes msg in the event stream format. Every line of the data
+// gets its own data field; browsers join them with newlines again.
+func writeMessage(w io.Writer, msg live.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\n", msg.ID, msg.Event)
+	for _, line := range strings.Split(msg.Data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", strings.TrimSuffix(line, "\r"))
+	}
+	io.WriteString(w, "\n")
+}
@@ -0,0 +1,9 @@
+package events
+
+import (
+	"github.com/kir/news-app/internal/live"
+)
+
+type Hub interface {
+	Subscribe(lastEventID string) (*live.Subscription, []live.Message)
+}
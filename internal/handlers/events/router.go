@@ -0,0 +1,11 @@
+package events
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the events handler. The stream must
+// not be behind a request timeout middleware.
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/events", h.Stream)
+}
@@ -0,0 +1,9 @@
+package feed
+
+// Response headers
+const (
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+	ContentTypeJSON = "application/feed+json; charset=utf-8"
+	CacheControl    = "public, max-age=300"
+)
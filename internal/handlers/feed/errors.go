@@ -0,0 +1,8 @@
+package feed
+
+// Error messages
+const (
+	ErrFailedToLoadFeed   = "Failed to load feed"
+	ErrFailedToRenderFeed = "Failed to render feed"
+	ErrInvalidCursor      = "Invalid feed cursor"
+)
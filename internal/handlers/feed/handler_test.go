@@ -0,0 +1,172 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func setupTestHandler() (*Handler, *MockService) {
+	mockService := &MockService{}
+	logger, _ := zap.NewDevelopment()
+	return New(mockService, "https://news.example/", logger), mockService
+}
+
+func TestHandler_Feeds(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	updated := time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)
+	post := &domain.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Budget vote",
+		Content:   "Parliament passed the budget",
+		CreatedAt: updated.Add(-time.Hour),
+		UpdatedAt: updated,
+	}
+
+	var gotQuery domain.PostQuery
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		gotQuery = query
+		return &domain.PostList{Posts: []*domain.Post{post}}, nil
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		serve       http.HandlerFunc
+		contentType string
+		contains    []string
+	}{
+		{
+			name:        "rss",
+			url:         "/feed.rss",
+			serve:       handler.RSS,
+			contentType: ContentTypeRSS,
+			contains: []string{
+				`<rss version="2.0"`,
+				"<title>News Portal</title>",
+				`<atom:link href="https://news.example/feed.rss" rel="self"`,
+				"<link>https://news.example/posts/" + post.ID.Hex() + "</link>",
+			},
+		},
+		{
+			name:        "atom for a category and tag",
+			url:         "/feed.atom?category=politics&tag=Budget&page=3",
+			serve:       handler.Atom,
+			contentType: ContentTypeAtom,
+			contains: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				"<title>News Portal — politics #budget</title>",
+				`<link href="https://news.example/?category=politics&amp;tag=budget" rel="alternate"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			tt.serve(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Fri, 14 Mar 2025 11:00:00 GMT", w.Header().Get("Last-Modified"))
+			assert.NotEmpty(t, w.Header().Get("ETag"))
+			assert.Equal(t, domain.PostStatusPublished, gotQuery.Status)
+			assert.Equal(t, feedSize, gotQuery.PageSize)
+			for _, s := range tt.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+		})
+	}
+
+	assert.Equal(t, "politics", gotQuery.Category)
+	assert.Equal(t, []string{"budget"}, gotQuery.Tags)
+}
+
+func TestHandler_JSONPaging(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote", Content: "Parliament passed the budget"}
+
+	var gotQuery domain.PostQuery
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		gotQuery = query
+		if query.Cursor == "bad" {
+			return nil, fmt.Errorf("parse: %w", domain.ErrInvalidCursor)
+		}
+		return &domain.PostList{Posts: []*domain.Post{post}, NextCursor: "next+page"}, nil
+	}
+
+	w := httptest.NewRecorder()
+	handler.JSON(w, httptest.NewRequest(http.MethodGet, "/feed.json?category=politics&cursor=first", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ContentTypeJSON, w.Header().Get("Content-Type"))
+	assert.Equal(t, "first", gotQuery.Cursor)
+	assert.Contains(t, w.Body.String(), `"version":"https://jsonfeed.org/version/1.1"`)
+	assert.Contains(t, w.Body.String(), `"feed_url":"https://news.example/feed.json?category=politics&cursor=first"`)
+	assert.Contains(t, w.Body.String(), `"next_url":"https://news.example/feed.json?category=politics&cursor=next%2Bpage"`)
+
+	w = httptest.NewRecorder()
+	handler.JSON(w, httptest.NewRequest(http.MethodGet, "/feed.json?cursor=bad", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_ConditionalRequests(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	updated := time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote", CreatedAt: updated, UpdatedAt: updated}
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		return &domain.PostList{Posts: []*domain.Post{post}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	handler.RSS(w, httptest.NewRequest(http.MethodGet, "/feed.rss", nil))
+	etag := w.Header().Get("ETag")
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "matching etag", header: "If-None-Match", value: etag, want: http.StatusNotModified},
+		{name: "stale etag", header: "If-None-Match", value: `"stale"`, want: http.StatusOK},
+		{name: "not modified since", header: "If-Modified-Since", value: "Fri, 14 Mar 2025 11:00:00 GMT", want: http.StatusNotModified},
+		{name: "modified since", header: "If-Modified-Since", value: "Fri, 14 Mar 2025 10:00:00 GMT", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/feed.rss", nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+
+			handler.RSS(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestHandler_ServiceError(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		return nil, assert.AnError
+	}
+
+	w := httptest.NewRecorder()
+	handler.Atom(w, httptest.NewRequest(http.MethodGet, "/feed.atom", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
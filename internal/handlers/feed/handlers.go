@@ -0,0 +1,150 @@
+package feed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/feed"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// feedSize is the number of latest posts included in a feed.
+	feedSize = 20
+
+	siteTitle       = "News Portal"
+	siteDescription = "Latest news"
+)
+
+// writeFunc renders posts in one feed format.
+type writeFunc func(w io.Writer, meta feed.Meta, urls feed.URLs, posts []*domain.Post) error
+
+// Handler serves syndication feeds of the latest published posts
+type Handler struct {
+	service FeedService
+	urls    feed.URLs
+	logger  *zap.Logger
+}
+
+// New creates a new feed handler. Links in feeds are built on baseURL.
+func New(service FeedService, baseURL string, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		urls:    feed.URLs{BaseURL: strings.TrimRight(baseURL, "/")},
+		logger:  logger,
+	}
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	http.Error(w, message, status)
+}
+
+// RSS serves the RSS 2.0 feed
+func (h *Handler) RSS(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, ContentTypeRSS, feed.WriteRSS)
+}
+
+// Atom serves the Atom feed
+func (h *Handler) Atom(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, ContentTypeAtom, feed.WriteAtom)
+}
+
+// JSON serves the JSON Feed
+func (h *Handler) JSON(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, ContentTypeJSON, feed.WriteJSON)
+}
+
+// serve renders the feed selected by the category and tag query parameters.
+// Older posts are paged through with the cursor parameter. Conditional
+// requests are answered by http.ServeContent from the ETag, a hash of the
+// rendered feed, and Last-Modified, the latest post modification.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, contentType string, write writeFunc) {
+	filter := feedFilter(r.URL.Query())
+	query := domain.PostQuery{
+		PageSize:  feedSize,
+		Category:  filter.Get("category"),
+		Tags:      filter["tag"],
+		Status:    domain.PostStatusPublished,
+		Sort:      domain.SortNewest,
+		Cursor:    r.URL.Query().Get("cursor"),
+		SkipCount: true,
+	}
+
+	list, err := h.service.GetPaginated(r.Context(), query)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		h.handleError(w, err, ErrInvalidCursor, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadFeed, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, h.meta(r, filter, list.NextCursor), h.urls, list.Posts); err != nil {
+		h.handleError(w, err, ErrFailedToRenderFeed, http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", CacheControl)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	http.ServeContent(w, r, "", feed.Updated(list.Posts), bytes.NewReader(buf.Bytes()))
+}
+
+// meta describes the feed; filtered feeds name their category and tags.
+// A next cursor links the feed to the page of older posts.
+func (h *Handler) meta(r *http.Request, filter url.Values, nextCursor string) feed.Meta {
+	title := siteTitle
+	if category := filter.Get("category"); category != "" {
+		title += " — " + category
+	}
+	for _, tag := range filter["tag"] {
+		title += " #" + tag
+	}
+
+	link := h.urls.Absolute("/")
+	if len(filter) > 0 {
+		link += "?" + filter.Encode()
+	}
+
+	meta := feed.Meta{
+		Title:       title,
+		Description: siteDescription,
+		Link:        link,
+		Self:        h.urls.Absolute(r.URL.RequestURI()),
+	}
+	if nextCursor != "" {
+		next := url.Values{"cursor": {nextCursor}}
+		for key, values := range filter {
+			next[key] = values
+		}
+		meta.NextURL = h.urls.Absolute(r.URL.Path + "?" + next.Encode())
+	}
+	return meta
+}
+
+// feedFilter keeps the supported, non-empty filter parameters.
+func feedFilter(values url.Values) url.Values {
+	filter := url.Values{}
+	if category := strings.TrimSpace(values.Get("category")); category != "" {
+		filter.Set("category", category)
+	}
+	for _, tag := range values["tag"] {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			filter.Add("tag", tag)
+		}
+	}
+	return filter
+}
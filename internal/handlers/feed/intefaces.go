@@ -0,0 +1,11 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type FeedService interface {
+	GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+}
@@ -0,0 +1,19 @@
+package feed
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService implements FeedService interface for testing
+type MockService struct {
+	GetPaginatedFunc func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+}
+
+func (m *MockService) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	if m.GetPaginatedFunc != nil {
+		return m.GetPaginatedFunc(ctx, query)
+	}
+	return &domain.PostList{}, nil
+}
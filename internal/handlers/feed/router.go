@@ -0,0 +1,12 @@
+package feed
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the feed handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/feed.rss", h.RSS)
+	r.Get("/feed.atom", h.Atom)
+	r.Get("/feed.json", h.JSON)
+}
@@ -0,0 +1,6 @@
+package newsletter
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
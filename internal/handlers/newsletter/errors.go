@@ -0,0 +1,13 @@
+package newsletter
+
+// Error messages
+const (
+	ErrInvalidFormData     = "Invalid form data"
+	ErrInvalidLink         = "This link is invalid or has already been used"
+	ErrSubscriberNotFound  = "Subscriber not found"
+	ErrFailedToSubscribe   = "Failed to subscribe, please try again later"
+	ErrFailedToLoadLog     = "Failed to load the newsletter log"
+	ErrFailedToUnsubscribe = "Failed to unsubscribe"
+	ErrFailedToConfirm     = "Failed to confirm the subscription"
+	ErrInternalServer      = "Internal server error"
+)
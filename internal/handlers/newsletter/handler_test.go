@@ -0,0 +1,196 @@
+package newsletter
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const adminToken = "admin-secret"
+
+func setupTestServer(t *testing.T) (*httptest.Server, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	handler := New(mockService, tmpl, logger)
+	RegisterRoutes(r, handler)
+	RegisterAdminRoutes(r, handler, adminToken)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, mockService
+}
+
+func newSubscriber(t *testing.T) *domain.Subscriber {
+	sub, err := domain.NewSubscriber("anna@example.com", domain.DigestWeekly)
+	require.NoError(t, err)
+	return sub
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	server, mockService := setupTestServer(t)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "confirmation sent", expectedStatus: http.StatusOK},
+		{name: "invalid email", err: fmt.Errorf("failed to create subscriber: %w", domain.ErrInvalidEmail), expectedStatus: http.StatusBadRequest, expectedError: domain.ErrInvalidEmail.Error()},
+		{name: "mail failure", err: assert.AnError, expectedStatus: http.StatusInternalServerError, expectedError: ErrFailedToSubscribe},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEmail string
+			var gotFrequency domain.DigestFrequency
+			mockService.SubscribeFunc = func(ctx context.Context, email string, frequency domain.DigestFrequency) error {
+				gotEmail, gotFrequency = email, frequency
+				return tt.err
+			}
+
+			resp, err := http.PostForm(server.URL+"/newsletter", map[string][]string{"email": {"anna@example.com"}, "frequency": {"weekly"}})
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, "anna@example.com", gotEmail)
+			assert.Equal(t, domain.DigestWeekly, gotFrequency)
+			assert.Equal(t, tt.expectedError, resp.Header.Get(HXErrorHeader))
+		})
+	}
+}
+
+func TestHandler_Confirm(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	sub := newSubscriber(t)
+	mockService.ConfirmFunc = func(ctx context.Context, token string) (*domain.Subscriber, error) {
+		if token != sub.Token {
+			return nil, domain.ErrSubscriberNotFound
+		}
+		sub.Confirm(time.Now())
+		return sub, nil
+	}
+
+	resp, err := http.Get(server.URL + "/newsletter/confirm?token=" + sub.Token)
+	require.NoError(t, err)
+	body := readBody(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "You will receive the weekly digest at anna@example.com.")
+
+	resp, err = http.Get(server.URL + "/newsletter/confirm?token=unknown")
+	require.NoError(t, err)
+	body = readBody(t, resp)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Contains(t, body, ErrInvalidLink)
+}
+
+func TestHandler_Unsubscribe(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	sub := newSubscriber(t)
+	mockService.GetByTokenFunc = func(ctx context.Context, token string) (*domain.Subscriber, error) {
+		return sub, nil
+	}
+	var unsubscribed string
+	mockService.UnsubscribeFunc = func(ctx context.Context, token string) (*domain.Subscriber, error) {
+		unsubscribed = token
+		return sub, nil
+	}
+
+	resp, err := http.Get(server.URL + "/newsletter/unsubscribe?token=" + sub.Token)
+	require.NoError(t, err)
+	body := readBody(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `action="/newsletter/unsubscribe?token=`+sub.Token+`"`)
+	assert.Empty(t, unsubscribed, "opening the link does not unsubscribe")
+
+	resp, err = http.Post(server.URL+"/newsletter/unsubscribe?token="+sub.Token, "application/x-www-form-urlencoded", strings.NewReader("List-Unsubscribe=One-Click"))
+	require.NoError(t, err)
+	body = readBody(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, sub.Token, unsubscribed)
+	assert.Contains(t, body, "anna@example.com will not receive any more digests.")
+}
+
+func TestHandler_Admin(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	sub := newSubscriber(t)
+	sub.Confirm(time.Now())
+	send := domain.NewNewsletterSend(sub, domain.EmailDigest, &domain.EmailMessage{Subject: "Your weekly News Portal digest: Budget approved"}, 3, time.Now(), assert.AnError)
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Subscriber, error) {
+		return []*domain.Subscriber{sub}, nil
+	}
+	mockService.RecentSendsFunc = func(ctx context.Context) ([]*domain.NewsletterSend, error) {
+		return []*domain.NewsletterSend{send}, nil
+	}
+	mockService.GetSubscriberFunc = func(ctx context.Context, id string) (*domain.Subscriber, []*domain.NewsletterSend, error) {
+		if id != sub.ID.Hex() {
+			return nil, nil, domain.ErrSubscriberNotFound
+		}
+		return sub, []*domain.NewsletterSend{send}, nil
+	}
+
+	resp, err := http.Get(server.URL + "/admin/newsletter")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "subscribers are only listed with the admin token")
+
+	resp = adminGet(t, server.URL+"/admin/newsletter")
+	body := readBody(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `href="/admin/newsletter/subscribers/`+sub.ID.Hex()+`"`)
+	assert.Contains(t, body, "confirmed")
+	assert.Contains(t, body, "digest (3 posts)")
+	assert.Contains(t, body, "failed")
+
+	resp = adminGet(t, server.URL+"/admin/newsletter/subscribers/"+sub.ID.Hex())
+	body = readBody(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "Your weekly News Portal digest: Budget approved")
+
+	resp = adminGet(t, server.URL+"/admin/newsletter/subscribers/unknown")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRegisterAdminRoutes_WithoutToken(t *testing.T) {
+	r := chi.NewRouter()
+	RegisterAdminRoutes(r, New(&MockService{}, template.New(""), zap.NewNop()), "")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/newsletter", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func adminGet(t *testing.T, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("admin", adminToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
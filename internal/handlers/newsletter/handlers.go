@@ -0,0 +1,199 @@
+package newsletter
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler handles newsletter sign-ups, the links in newsletter emails and the
+// admin pages with subscribers and the send log
+type Handler struct {
+	service   NewsletterService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new newsletter handler
+func New(service NewsletterService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// messagePage is the data of the pages the links in emails lead to. Token is
+// set on the page asking to confirm unsubscribing.
+type messagePage struct {
+	Title   string
+	Message string
+	Token   string
+}
+
+// adminPage is the data of the newsletter admin page.
+type adminPage struct {
+	Subscribers []*domain.Subscriber
+	Sends       []*domain.NewsletterSend
+}
+
+// subscriberPage is the data of the send log page of one subscriber.
+type subscriberPage struct {
+	Subscriber *domain.Subscriber
+	Sends      []*domain.NewsletterSend
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Subscribe handles the sign-up form and replaces it with a notice to check
+// the inbox
+func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.Subscribe(r.Context(), r.PostForm.Get("email"), domain.DigestFrequency(r.PostForm.Get("frequency")))
+	if invalid := validationError(err); invalid != nil {
+		h.handleError(w, err, invalid.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToSubscribe, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "newsletter/subscribed", nil); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Confirm handles the link in the confirmation email
+func (h *Handler) Confirm(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.Confirm(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, domain.ErrSubscriberNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToConfirm, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("confirmed newsletter subscriber", zap.String("subscriber_id", sub.ID.Hex()))
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Subscription confirmed",
+		Message: "You will receive the " + string(sub.Frequency) + " digest at " + sub.Email + ".",
+	})
+}
+
+// UnsubscribeForm handles the unsubscribe link in digests. Unsubscribing
+// takes a POST, so that link scanners of mail providers do not unsubscribe.
+func (h *Handler) UnsubscribeForm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	sub, err := h.service.GetByToken(r.Context(), token)
+	if errors.Is(err, domain.ErrSubscriberNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Unsubscribe",
+		Message: "Stop sending the " + string(sub.Frequency) + " digest to " + sub.Email + "?",
+		Token:   token,
+	})
+}
+
+// Unsubscribe handles the unsubscribe form, and the one-click unsubscribe
+// requests mail clients send for the List-Unsubscribe-Post header
+func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.Unsubscribe(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, domain.ErrSubscriberNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToUnsubscribe, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("unsubscribed newsletter subscriber", zap.String("subscriber_id", sub.ID.Hex()))
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Unsubscribed",
+		Message: sub.Email + " will not receive any more digests.",
+	})
+}
+
+// Index handles the admin page listing subscribers and the latest sends
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subs, err := h.service.List(ctx)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadLog, http.StatusInternalServerError)
+		return
+	}
+	sends, err := h.service.RecentSends(ctx)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadLog, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "admin/newsletter", adminPage{Subscribers: subs, Sends: sends}); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Subscriber handles the admin page with the send log of one subscriber
+func (h *Handler) Subscriber(w http.ResponseWriter, r *http.Request) {
+	sub, sends, err := h.service.GetSubscriber(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrSubscriberNotFound) {
+		h.handleError(w, err, ErrSubscriberNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadLog, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "admin/newsletter-subscriber", subscriberPage{Subscriber: sub, Sends: sends}); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// renderMessage renders page with status.
+func (h *Handler) renderMessage(w http.ResponseWriter, status int, page messagePage) {
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "newsletter/message", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidEmail, domain.ErrInvalidDigestFrequency} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
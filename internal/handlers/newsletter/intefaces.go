@@ -0,0 +1,17 @@
+package newsletter
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type NewsletterService interface {
+	Subscribe(ctx context.Context, email string, frequency domain.DigestFrequency) error
+	Confirm(ctx context.Context, token string) (*domain.Subscriber, error)
+	GetByToken(ctx context.Context, token string) (*domain.Subscriber, error)
+	Unsubscribe(ctx context.Context, token string) (*domain.Subscriber, error)
+	List(ctx context.Context) ([]*domain.Subscriber, error)
+	RecentSends(ctx context.Context) ([]*domain.NewsletterSend, error)
+	GetSubscriber(ctx context.Context, id string) (*domain.Subscriber, []*domain.NewsletterSend, error)
+}
@@ -0,0 +1,67 @@
+package newsletter
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService is a mock implementation of NewsletterService
+type MockService struct {
+	SubscribeFunc     func(ctx context.Context, email string, frequency domain.DigestFrequency) error
+	ConfirmFunc       func(ctx context.Context, token string) (*domain.Subscriber, error)
+	GetByTokenFunc    func(ctx context.Context, token string) (*domain.Subscriber, error)
+	UnsubscribeFunc   func(ctx context.Context, token string) (*domain.Subscriber, error)
+	ListFunc          func(ctx context.Context) ([]*domain.Subscriber, error)
+	RecentSendsFunc   func(ctx context.Context) ([]*domain.NewsletterSend, error)
+	GetSubscriberFunc func(ctx context.Context, id string) (*domain.Subscriber, []*domain.NewsletterSend, error)
+}
+
+func (m *MockService) Subscribe(ctx context.Context, email string, frequency domain.DigestFrequency) error {
+	if m.SubscribeFunc != nil {
+		return m.SubscribeFunc(ctx, email, frequency)
+	}
+	return nil
+}
+
+func (m *MockService) Confirm(ctx context.Context, token string) (*domain.Subscriber, error) {
+	if m.ConfirmFunc != nil {
+		return m.ConfirmFunc(ctx, token)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockService) GetByToken(ctx context.Context, token string) (*domain.Subscriber, error) {
+	if m.GetByTokenFunc != nil {
+		return m.GetByTokenFunc(ctx, token)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockService) Unsubscribe(ctx context.Context, token string) (*domain.Subscriber, error) {
+	if m.UnsubscribeFunc != nil {
+		return m.UnsubscribeFunc(ctx, token)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockService) List(ctx context.Context) ([]*domain.Subscriber, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) RecentSends(ctx context.Context) ([]*domain.NewsletterSend, error) {
+	if m.RecentSendsFunc != nil {
+		return m.RecentSendsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetSubscriber(ctx context.Context, id string) (*domain.Subscriber, []*domain.NewsletterSend, error) {
+	if m.GetSubscriberFunc != nil {
+		return m.GetSubscriberFunc(ctx, id)
+	}
+	return nil, nil, domain.ErrSubscriberNotFound
+}
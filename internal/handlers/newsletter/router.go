@@ -0,0 +1,30 @@
+package newsletter
+
+import (
+	"github.com/kir/news-app/internal/adminauth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up the public routes for the newsletter handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/newsletter", func(r chi.Router) {
+		r.Post("/", h.Subscribe)
+		r.Get("/confirm", h.Confirm)
+		r.Get("/unsubscribe", h.UnsubscribeForm)
+		r.Post("/unsubscribe", h.Unsubscribe)
+	})
+}
+
+// RegisterAdminRoutes sets up the admin pages, which list the email addresses
+// of subscribers, behind the admin token. Without a token they are not mounted.
+func RegisterAdminRoutes(r chi.Router, h *Handler, token string) {
+	if token == "" {
+		return
+	}
+	r.Route("/admin/newsletter", func(r chi.Router) {
+		r.Use(adminauth.Require(token))
+		r.Get("/", h.Index)
+		r.Get("/subscribers/{id}", h.Subscriber)
+	})
+}
@@ -0,0 +1,6 @@
+package notification
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
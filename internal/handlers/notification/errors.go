@@ -0,0 +1,15 @@
+package notification
+
+// Error messages
+const (
+	ErrInvalidFormData      = "Invalid form data"
+	ErrInvalidLink          = "This link is invalid or has already been used"
+	ErrNotificationNotFound = "Notification not found"
+	ErrFailedToLoadInbox    = "Failed to load notifications"
+	ErrFailedToFollow       = "Failed to update follow"
+	ErrFailedToMarkRead     = "Failed to mark notifications as read"
+	ErrFailedToSaveEmail    = "Failed to save the email address, please try again later"
+	ErrFailedToConfirmEmail = "Failed to confirm the email address"
+	ErrFailedToStopEmail    = "Failed to stop the emails"
+	ErrInternalServer       = "Internal server error"
+)
@@ -0,0 +1,213 @@
+package notification
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const testReader = "0123456789abcdef0123456789abcdef"
+
+func setupTestServer(t *testing.T) (*httptest.Server, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	r.Use(reader.Middleware)
+	RegisterRoutes(r, New(mockService, tmpl, logger))
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, mockService
+}
+
+// do sends a request as testReader without following redirects.
+func do(t *testing.T, method, target string, form url.Values) (*http.Response, string) {
+	t.Helper()
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, target, body)
+	require.NoError(t, err)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.AddCookie(&http.Cookie{Name: reader.CookieName, Value: testReader})
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestHandler_Follow(t *testing.T) {
+	server, mockService := setupTestServer(t)
+
+	tests := []struct {
+		name           string
+		kind           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "follow author", kind: "author", expectedStatus: http.StatusOK},
+		{name: "invalid kind", kind: "source", expectedStatus: http.StatusBadRequest, expectedError: domain.ErrInvalidFollowKind.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.FollowFunc = func(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+				assert.Equal(t, testReader, readerID)
+				target, err := domain.NewFollowTarget(kind, value)
+				return domain.FollowState{FollowTarget: target, Following: true}, err
+			}
+
+			resp, body := do(t, http.MethodPost, server.URL+"/follows", url.Values{"kind": {tt.kind}, "value": {"Anna"}})
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedError, resp.Header.Get(HXErrorHeader))
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, body, `hx-delete="/follows"`)
+				assert.Contains(t, body, "Following Anna")
+			}
+		})
+	}
+}
+
+func TestHandler_Unfollow(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	var unfollowed domain.FollowTarget
+	mockService.UnfollowFunc = func(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+		unfollowed = domain.FollowTarget{Kind: kind, Value: value}
+		return domain.FollowState{FollowTarget: unfollowed}, nil
+	}
+
+	resp, body := do(t, http.MethodDelete, server.URL+"/follows?kind=category&value=World+News", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, domain.FollowTarget{Kind: domain.FollowCategory, Value: "World News"}, unfollowed)
+	assert.Contains(t, body, `hx-post="/follows"`)
+	assert.Contains(t, body, "Follow World News")
+}
+
+func TestHandler_Buttons(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	var asked []domain.FollowTarget
+	mockService.FollowStatesFunc = func(ctx context.Context, readerID string, targets []domain.FollowTarget) ([]domain.FollowState, error) {
+		asked = targets
+		return []domain.FollowState{{FollowTarget: targets[0], Following: true}}, nil
+	}
+
+	resp, body := do(t, http.MethodGet, server.URL+"/follows/buttons?category=Politics&author=&tag=elections&tag=budget", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []domain.FollowTarget{
+		{Kind: domain.FollowCategory, Value: "Politics"},
+		{Kind: domain.FollowTag, Value: "elections"},
+		{Kind: domain.FollowTag, Value: "budget"},
+	}, asked, "an empty author gets no button")
+	assert.Contains(t, body, "Following Politics")
+}
+
+func TestHandler_Inbox(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved"}
+	unread := domain.NewNotification(testReader, post, domain.FollowTarget{Kind: domain.FollowCategory, Value: "Politics"}, time.Now())
+	mockService.InboxFunc = func(ctx context.Context, readerID string) ([]*domain.Notification, error) {
+		return []*domain.Notification{unread}, nil
+	}
+	mockService.UnreadCountFunc = func(ctx context.Context, readerID string) (int64, error) {
+		return 1, nil
+	}
+	mockService.FollowsFunc = func(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+		return []*domain.Follow{domain.NewFollow(readerID, unread.Reason)}, nil
+	}
+
+	resp, body := do(t, http.MethodGet, server.URL+"/notifications", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "Inbox (1 unread)")
+	assert.Contains(t, body, `href="/notifications/`+unread.ID.Hex()+`"`)
+	assert.Contains(t, body, "Following Politics")
+	assert.Contains(t, body, `hx-post="/notifications/email"`)
+
+	resp, body = do(t, http.MethodGet, server.URL+"/notifications/count", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `hx-trigger="every 60s"`)
+	assert.Contains(t, body, ">1</span>")
+}
+
+func TestHandler_Open(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	postID := primitive.NewObjectID()
+	mockService.OpenFunc = func(ctx context.Context, readerID, id string) (*domain.Notification, error) {
+		if readerID != testReader || id != "n1" {
+			return nil, domain.ErrNotificationNotFound
+		}
+		now := time.Now()
+		return &domain.Notification{PostID: postID, ReadAt: &now}, nil
+	}
+
+	resp, _ := do(t, http.MethodGet, server.URL+"/notifications/n1", nil)
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "/posts/"+postID.Hex(), resp.Header.Get("Location"))
+
+	resp, _ = do(t, http.MethodGet, server.URL+"/notifications/n2", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandler_SetEmail(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	mockService.SetEmailFunc = func(ctx context.Context, readerID, email string) (*domain.NotificationSettings, error) {
+		s := &domain.NotificationSettings{ReaderID: readerID}
+		_, err := s.SetEmail(email)
+		return s, err
+	}
+
+	resp, body := do(t, http.MethodPost, server.URL+"/notifications/email", url.Values{"email": {"anna@example.com"}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "click the link in the email we sent to anna@example.com")
+
+	resp, _ = do(t, http.MethodPost, server.URL+"/notifications/email", url.Values{"email": {"anna"}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, domain.ErrInvalidEmail.Error(), resp.Header.Get(HXErrorHeader))
+}
+
+func TestHandler_StopEmail(t *testing.T) {
+	server, mockService := setupTestServer(t)
+	settings := &domain.NotificationSettings{ReaderID: testReader}
+	_, err := settings.SetEmail("anna@example.com")
+	require.NoError(t, err)
+	mockService.GetByTokenFunc = func(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+		return settings, nil
+	}
+	var stopped string
+	mockService.StopEmailFunc = func(ctx context.Context, token string) (string, error) {
+		stopped = token
+		return settings.Email, nil
+	}
+
+	resp, body := do(t, http.MethodGet, server.URL+"/notifications/email/unsubscribe?token="+settings.Token, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, `action="/notifications/email/unsubscribe?token=`+settings.Token+`"`)
+	assert.Empty(t, stopped, "opening the link does not stop the emails")
+
+	resp, body = do(t, http.MethodPost, server.URL+"/notifications/email/unsubscribe?token="+settings.Token, url.Values{"List-Unsubscribe": {"One-Click"}})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, settings.Token, stopped)
+	assert.Contains(t, body, "anna@example.com will not receive any more notification emails.")
+}
@@ -0,0 +1,311 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler handles follows, the notification inbox and the links in
+// notification emails
+type Handler struct {
+	service   NotificationService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new notification handler
+func New(service NotificationService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// inboxPage is the data of the notification inbox.
+type inboxPage struct {
+	Notifications []*domain.Notification
+	Unread        int64
+	Follows       []domain.FollowState
+	Email         emailForm
+}
+
+// emailForm is the data of the form setting the address notifications are
+// emailed to. Notice reports the outcome of the last change.
+type emailForm struct {
+	Available bool
+	Settings  *domain.NotificationSettings
+	Notice    string
+}
+
+// messagePage is the data of the pages the links in emails lead to. Token is
+// set on the page asking to confirm stopping the emails.
+type messagePage struct {
+	Title   string
+	Message string
+	Token   string
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Inbox handles the page with the reader's notifications, follows and email
+// settings
+func (h *Handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	readerID := reader.ID(ctx)
+
+	notifications, err := h.service.Inbox(ctx, readerID)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+	unread, err := h.service.UnreadCount(ctx, readerID)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+	follows, err := h.service.Follows(ctx, readerID)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+	settings, err := h.service.Settings(ctx, readerID)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+
+	page := inboxPage{
+		Notifications: notifications,
+		Unread:        unread,
+		Email:         emailForm{Available: h.service.EmailAvailable(), Settings: settings},
+	}
+	for _, f := range follows {
+		page.Follows = append(page.Follows, domain.FollowState{FollowTarget: f.FollowTarget, Following: true})
+	}
+	if err := h.templates.ExecuteTemplate(w, "notifications/inbox", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Count handles the unread badge in the page header, which polls it
+func (h *Handler) Count(w http.ResponseWriter, r *http.Request) {
+	unread, err := h.service.UnreadCount(r.Context(), reader.ID(r.Context()))
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "notifications/count", unread); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Open marks a notification as read and redirects to its post
+func (h *Handler) Open(w http.ResponseWriter, r *http.Request) {
+	n, err := h.service.Open(r.Context(), reader.ID(r.Context()), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrNotificationNotFound) {
+		h.handleError(w, err, ErrNotificationNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/posts/"+n.PostID.Hex(), http.StatusSeeOther)
+}
+
+// MarkAllRead marks every notification as read and re-renders the inbox list
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	readerID := reader.ID(ctx)
+
+	if err := h.service.MarkAllRead(ctx, readerID); err != nil {
+		h.handleError(w, err, ErrFailedToMarkRead, http.StatusInternalServerError)
+		return
+	}
+	notifications, err := h.service.Inbox(ctx, readerID)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadInbox, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "notifications/list", inboxPage{Notifications: notifications}); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Buttons renders the follow buttons for the category, author and tags of a
+// post
+func (h *Handler) Buttons(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var targets []domain.FollowTarget
+	for _, kind := range domain.FollowKinds {
+		for _, value := range query[string(kind)] {
+			if target, err := domain.NewFollowTarget(kind, value); err == nil {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	states, err := h.service.FollowStates(r.Context(), reader.ID(r.Context()), targets)
+	if err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "notifications/follow-buttons", states); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Follow handles a follow button and renders it as followed
+func (h *Handler) Follow(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+	state, err := h.service.Follow(r.Context(), reader.ID(r.Context()), domain.FollowKind(r.PostForm.Get("kind")), r.PostForm.Get("value"))
+	h.renderButton(w, state, err)
+}
+
+// Unfollow handles a followed button and renders it as not followed
+func (h *Handler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state, err := h.service.Unfollow(r.Context(), reader.ID(r.Context()), domain.FollowKind(query.Get("kind")), query.Get("value"))
+	h.renderButton(w, state, err)
+}
+
+func (h *Handler) renderButton(w http.ResponseWriter, state domain.FollowState, err error) {
+	if invalid := validationError(err); invalid != nil {
+		h.handleError(w, err, invalid.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToFollow, http.StatusInternalServerError)
+		return
+	}
+	if err := h.templates.ExecuteTemplate(w, "notifications/follow-button", state); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// SetEmail handles the email settings form and re-renders it
+func (h *Handler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.SetEmail(r.Context(), reader.ID(r.Context()), r.PostForm.Get("email"))
+	if invalid := validationError(err); invalid != nil {
+		h.handleError(w, err, invalid.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToSaveEmail, http.StatusInternalServerError)
+		return
+	}
+
+	form := emailForm{Available: true, Settings: settings, Notice: "Notifications are no longer emailed."}
+	switch {
+	case settings.EmailConfirmed():
+		form.Notice = "Notifications are emailed to " + settings.Email + "."
+	case settings.Email != "":
+		form.Notice = "Check your inbox and click the link in the email we sent to " + settings.Email + " to confirm it."
+	}
+	if err := h.templates.ExecuteTemplate(w, "notifications/email-form", form); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// ConfirmEmail handles the link in the confirmation email
+func (h *Handler) ConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.ConfirmEmail(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, domain.ErrSettingsNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToConfirmEmail, http.StatusInternalServerError)
+		return
+	}
+
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Email confirmed",
+		Message: "Notifications of the categories and authors you follow are emailed to " + settings.Email + ".",
+	})
+}
+
+// UnsubscribeForm handles the link stopping the emails. Stopping takes a
+// POST, so that link scanners of mail providers do not stop the emails.
+func (h *Handler) UnsubscribeForm(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	settings, err := h.service.GetByToken(r.Context(), token)
+	if errors.Is(err, domain.ErrSettingsNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Stop notification emails",
+		Message: "Stop emailing notifications to " + settings.Email + "? They stay in your inbox on the site.",
+		Token:   token,
+	})
+}
+
+// Unsubscribe handles the form stopping the emails, and the one-click
+// requests mail clients send for the List-Unsubscribe-Post header
+func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	email, err := h.service.StopEmail(r.Context(), r.URL.Query().Get("token"))
+	if errors.Is(err, domain.ErrSettingsNotFound) {
+		h.renderMessage(w, http.StatusNotFound, messagePage{Title: "Invalid link", Message: ErrInvalidLink})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToStopEmail, http.StatusInternalServerError)
+		return
+	}
+
+	h.renderMessage(w, http.StatusOK, messagePage{
+		Title:   "Emails stopped",
+		Message: email + " will not receive any more notification emails.",
+	})
+}
+
+// renderMessage renders page with status.
+func (h *Handler) renderMessage(w http.ResponseWriter, status int, page messagePage) {
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "notifications/message", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidFollowKind, domain.ErrInvalidFollowValue, domain.ErrInvalidEmail, domain.ErrEmailUnavailable} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type NotificationService interface {
+	Follow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error)
+	Unfollow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error)
+	Follows(ctx context.Context, readerID string) ([]*domain.Follow, error)
+	FollowStates(ctx context.Context, readerID string, targets []domain.FollowTarget) ([]domain.FollowState, error)
+	Inbox(ctx context.Context, readerID string) ([]*domain.Notification, error)
+	UnreadCount(ctx context.Context, readerID string) (int64, error)
+	Open(ctx context.Context, readerID, id string) (*domain.Notification, error)
+	MarkAllRead(ctx context.Context, readerID string) error
+	EmailAvailable() bool
+	Settings(ctx context.Context, readerID string) (*domain.NotificationSettings, error)
+	SetEmail(ctx context.Context, readerID, email string) (*domain.NotificationSettings, error)
+	ConfirmEmail(ctx context.Context, token string) (*domain.NotificationSettings, error)
+	GetByToken(ctx context.Context, token string) (*domain.NotificationSettings, error)
+	StopEmail(ctx context.Context, token string) (string, error)
+}
@@ -0,0 +1,123 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService is a mock implementation of NotificationService
+type MockService struct {
+	FollowFunc         func(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error)
+	UnfollowFunc       func(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error)
+	FollowsFunc        func(ctx context.Context, readerID string) ([]*domain.Follow, error)
+	FollowStatesFunc   func(ctx context.Context, readerID string, targets []domain.FollowTarget) ([]domain.FollowState, error)
+	InboxFunc          func(ctx context.Context, readerID string) ([]*domain.Notification, error)
+	UnreadCountFunc    func(ctx context.Context, readerID string) (int64, error)
+	OpenFunc           func(ctx context.Context, readerID, id string) (*domain.Notification, error)
+	MarkAllReadFunc    func(ctx context.Context, readerID string) error
+	EmailAvailableFunc func() bool
+	SettingsFunc       func(ctx context.Context, readerID string) (*domain.NotificationSettings, error)
+	SetEmailFunc       func(ctx context.Context, readerID, email string) (*domain.NotificationSettings, error)
+	ConfirmEmailFunc   func(ctx context.Context, token string) (*domain.NotificationSettings, error)
+	GetByTokenFunc     func(ctx context.Context, token string) (*domain.NotificationSettings, error)
+	StopEmailFunc      func(ctx context.Context, token string) (string, error)
+}
+
+func (m *MockService) Follow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+	if m.FollowFunc != nil {
+		return m.FollowFunc(ctx, readerID, kind, value)
+	}
+	return domain.FollowState{}, nil
+}
+
+func (m *MockService) Unfollow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+	if m.UnfollowFunc != nil {
+		return m.UnfollowFunc(ctx, readerID, kind, value)
+	}
+	return domain.FollowState{}, nil
+}
+
+func (m *MockService) Follows(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+	if m.FollowsFunc != nil {
+		return m.FollowsFunc(ctx, readerID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) FollowStates(ctx context.Context, readerID string, targets []domain.FollowTarget) ([]domain.FollowState, error) {
+	if m.FollowStatesFunc != nil {
+		return m.FollowStatesFunc(ctx, readerID, targets)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Inbox(ctx context.Context, readerID string) ([]*domain.Notification, error) {
+	if m.InboxFunc != nil {
+		return m.InboxFunc(ctx, readerID)
+	}
+	return nil, nil
+}
+
+func (m *MockService) UnreadCount(ctx context.Context, readerID string) (int64, error) {
+	if m.UnreadCountFunc != nil {
+		return m.UnreadCountFunc(ctx, readerID)
+	}
+	return 0, nil
+}
+
+func (m *MockService) Open(ctx context.Context, readerID, id string) (*domain.Notification, error) {
+	if m.OpenFunc != nil {
+		return m.OpenFunc(ctx, readerID, id)
+	}
+	return nil, domain.ErrNotificationNotFound
+}
+
+func (m *MockService) MarkAllRead(ctx context.Context, readerID string) error {
+	if m.MarkAllReadFunc != nil {
+		return m.MarkAllReadFunc(ctx, readerID)
+	}
+	return nil
+}
+
+func (m *MockService) EmailAvailable() bool {
+	if m.EmailAvailableFunc != nil {
+		return m.EmailAvailableFunc()
+	}
+	return true
+}
+
+func (m *MockService) Settings(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+	if m.SettingsFunc != nil {
+		return m.SettingsFunc(ctx, readerID)
+	}
+	return &domain.NotificationSettings{ReaderID: readerID}, nil
+}
+
+func (m *MockService) SetEmail(ctx context.Context, readerID, email string) (*domain.NotificationSettings, error) {
+	if m.SetEmailFunc != nil {
+		return m.SetEmailFunc(ctx, readerID, email)
+	}
+	return &domain.NotificationSettings{ReaderID: readerID}, nil
+}
+
+func (m *MockService) ConfirmEmail(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	if m.ConfirmEmailFunc != nil {
+		return m.ConfirmEmailFunc(ctx, token)
+	}
+	return nil, domain.ErrSettingsNotFound
+}
+
+func (m *MockService) GetByToken(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	if m.GetByTokenFunc != nil {
+		return m.GetByTokenFunc(ctx, token)
+	}
+	return nil, domain.ErrSettingsNotFound
+}
+
+func (m *MockService) StopEmail(ctx context.Context, token string) (string, error) {
+	if m.StopEmailFunc != nil {
+		return m.StopEmailFunc(ctx, token)
+	}
+	return "", domain.ErrSettingsNotFound
+}
@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the notification handler. The routes
+// expect the reader ID put into the request context by reader.Middleware.
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", h.Inbox)
+		r.Get("/count", h.Count)
+		r.Post("/read", h.MarkAllRead)
+		r.Get("/{id}", h.Open)
+		r.Post("/email", h.SetEmail)
+		r.Get("/email/confirm", h.ConfirmEmail)
+		r.Get("/email/unsubscribe", h.UnsubscribeForm)
+		r.Post("/email/unsubscribe", h.Unsubscribe)
+	})
+	r.Route("/follows", func(r chi.Router) {
+		r.Get("/buttons", h.Buttons)
+		r.Post("/", h.Follow)
+		r.Delete("/", h.Unfollow)
+	})
+}
@@ -0,0 +1,247 @@
+package post
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// paramCount asks the JSON API for the exact total, which is skipped by default.
+const paramCount = "count"
+
+// apiError is the JSON body of API error responses.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// ListPosts handles the JSON posts listing. It accepts the same filters as the
+// index page plus cursor, and returns next_cursor for fetching the following page.
+// Unlike the index page, an unknown sort is rejected rather than ignored.
+func (h *Handler) ListPosts(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	if _, err := domain.ParsePostSort(values.Get(paramSort)); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidSort})
+		return
+	}
+
+	query := parseListQuery(values)
+	query.SkipCount = values.Get(paramCount) != "true"
+
+	response, err := h.service.GetPaginated(r.Context(), query)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidCursor})
+		return
+	}
+	if err != nil {
+		h.logger.Error(ErrFailedToLoadPosts, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToLoadPosts})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// postRequest is the JSON body creating or replacing a post. Contributors
+// replace author when set. Fields are pointers so that a missing field can be
+// told from an empty one: creating a post leaves missing fields empty, while
+// replacing one requires all of them.
+type postRequest struct {
+	Title          *string               `json:"title"`
+	Content        *string               `json:"content"`
+	Category       *string               `json:"category"`
+	Tags           *[]string             `json:"tags"`
+	Author         *string               `json:"author"`
+	Contributors   *[]domain.Contributor `json:"contributors"`
+	ImageURL       *string               `json:"image_url"`
+	Status         *domain.PostStatus    `json:"status"`
+	SEOTitle       *string               `json:"seo_title"`
+	SEODescription *string               `json:"seo_description"`
+}
+
+func (p postRequest) input() domain.PostInput {
+	return domain.PostInput{
+		Title:          value(p.Title),
+		Content:        value(p.Content),
+		Category:       value(p.Category),
+		Tags:           value(p.Tags),
+		Author:         value(p.Author),
+		Contributors:   value(p.Contributors),
+		ImageURL:       value(p.ImageURL),
+		Status:         value(p.Status),
+		SEOTitle:       value(p.SEOTitle),
+		SEODescription: value(p.SEODescription),
+	}
+}
+
+// missing returns the name of the first field a replacement lacks, or "" when
+// the body has them all. Either contributors or author names the writers.
+func (p postRequest) missing() string {
+	fields := []struct {
+		name    string
+		present bool
+	}{
+		{"title", p.Title != nil},
+		{"content", p.Content != nil},
+		{"category", p.Category != nil},
+		{"tags", p.Tags != nil},
+		{"contributors", p.Contributors != nil || p.Author != nil},
+		{"image_url", p.ImageURL != nil},
+		{"status", p.Status != nil},
+		{"seo_title", p.SEOTitle != nil},
+		{"seo_description", p.SEODescription != nil},
+	}
+	for _, f := range fields {
+		if !f.present {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// value returns what p points to, or the zero value when p is nil.
+func value[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// postValidationErrors are the errors of posts rejected for their content.
+var postValidationErrors = []error{
+	domain.ErrInvalidTitle,
+	domain.ErrInvalidContent,
+	domain.ErrInvalidStatus,
+	domain.ErrInvalidImage,
+	domain.ErrInvalidSEOTitle,
+	domain.ErrInvalidSEODescription,
+	domain.ErrInvalidContributor,
+}
+
+// GetPost handles fetching one post as JSON.
+func (h *Handler) GetPost(w http.ResponseWriter, r *http.Request) {
+	post, ok := h.apiPost(w, r.Context(), chi.URLParam(r, "id"))
+	if !ok {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, post)
+}
+
+// apiPost loads the post id. When that fails it writes a 404 for a missing
+// post, a 500 otherwise, and reports false.
+func (h *Handler) apiPost(w http.ResponseWriter, ctx context.Context, id string) (*domain.Post, bool) {
+	post, err := h.service.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrPostNotFound) {
+		h.writeJSON(w, http.StatusNotFound, apiError{Error: ErrPostNotFound})
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error(ErrFailedToLoadPost, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToLoadPost})
+		return nil, false
+	}
+	return post, true
+}
+
+// CreatePost handles creating a post from a JSON body. It responds with the
+// created post and its URL in the Location header.
+func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
+	var req postRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidJSON})
+		return
+	}
+
+	post, err := h.service.Create(r.Context(), req.input())
+	if invalid := postValidationError(err); invalid != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: invalid.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error(ErrFailedToSavePost, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToSavePost})
+		return
+	}
+
+	w.Header().Set("Location", "/api/posts/"+post.ID.Hex())
+	h.writeJSON(w, http.StatusCreated, post)
+}
+
+// UpdatePost handles replacing the editable fields of a post with a JSON
+// body. It is a full replacement: bodies lacking a field are rejected rather
+// than clearing it. It responds with the updated post.
+func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	if _, ok := h.apiPost(w, ctx, id); !ok {
+		return
+	}
+
+	var req postRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidJSON})
+		return
+	}
+	if field := req.missing(); field != "" {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrMissingField + field})
+		return
+	}
+
+	err := h.service.Update(ctx, id, req.input())
+	if invalid := postValidationError(err); invalid != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: invalid.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error(ErrFailedToSavePost, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToSavePost})
+		return
+	}
+
+	post, ok := h.apiPost(w, ctx, id)
+	if !ok {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, post)
+}
+
+// DeletePost handles deleting a post.
+func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+	if _, ok := h.apiPost(w, ctx, id); !ok {
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.logger.Error(ErrFailedToDeletePost, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToDeletePost})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// postValidationError returns the domain validation error wrapped in err, if any.
+func postValidationError(err error) error {
+	for _, target := range postValidationErrors {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
+
+// writeJSON encodes v as the JSON response body.
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to encode response", zap.Error(err))
+	}
+}
@@ -0,0 +1,316 @@
+package post
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func TestHandler_ListPosts(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	tests := []struct {
+		name             string
+		url              string
+		mockGetPaginated func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+		expectedStatus   int
+		expectedBody     map[string]any
+	}{
+		{
+			name: "cursor page without count",
+			url:  "/api/posts?cursor=abc&page_size=1",
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+				assert.Equal(t, "abc", query.Cursor)
+				assert.True(t, query.SkipCount)
+				return &domain.PostList{
+					Posts:      []*domain.Post{{ID: primitive.NewObjectID(), Title: "Test Post", Content: "Test content"}},
+					Page:       1,
+					PageSize:   query.PageSize,
+					NextCursor: "next",
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]any{"page": 1.0, "page_size": 1.0, "next_cursor": "next"},
+		},
+		{
+			name: "count requested",
+			url:  "/api/posts?count=true",
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+				assert.False(t, query.SkipCount)
+				return &domain.PostList{TotalCount: 12, Page: 1, PageSize: query.PageSize}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]any{"total_count": 12.0, "page": 1.0, "page_size": 9.0},
+		},
+		{
+			name: "invalid cursor",
+			url:  "/api/posts?cursor=bad",
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+				return nil, domain.ErrInvalidCursor
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]any{"error": ErrInvalidCursor},
+		},
+		{
+			name: "invalid sort",
+			url:  "/api/posts?sort=popular",
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+				t.Fatal("service must not be called with an invalid sort")
+				return nil, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]any{"error": ErrInvalidSort},
+		},
+		{
+			name: "service error",
+			url:  "/api/posts",
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+				return nil, assert.AnError
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]any{"error": ErrFailedToLoadPosts},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.GetPaginatedFunc = tt.mockGetPaginated
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListPosts(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			for key, want := range tt.expectedBody {
+				assert.Equal(t, want, body[key], key)
+			}
+		})
+	}
+}
+
+// apiKeys authenticates the tokens "reader" and "writer" with keys granted
+// posts:read and posts:read plus posts:write.
+type apiKeys struct{}
+
+func (apiKeys) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	switch token {
+	case "reader":
+		return &domain.APIKey{OwnerID: "owner", Scopes: []domain.APIScope{domain.ScopePostsRead}}, nil
+	case "writer":
+		return &domain.APIKey{OwnerID: "owner", Scopes: []domain.APIScope{domain.ScopePostsRead, domain.ScopePostsWrite}}, nil
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
+
+func setupTestAPI() (http.Handler, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger := zap.NewNop()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, tmpl, "https://news.example", logger, WithAPIKeys(apiKeys{})), logger)
+	return r, mockService
+}
+
+func apiRequest(router http.Handler, method, target, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPI_Scopes(t *testing.T) {
+	router, mockService := setupTestAPI()
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote"}
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		return &domain.PostList{Posts: []*domain.Post{post}}, nil
+	}
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return post, nil
+	}
+
+	tests := []struct {
+		method string
+		target string
+		token  string
+		status int
+	}{
+		{http.MethodGet, "/api/posts", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/posts", "unknown", http.StatusUnauthorized},
+		{http.MethodGet, "/api/posts", "reader", http.StatusOK},
+		{http.MethodGet, "/api/posts/" + post.ID.Hex(), "reader", http.StatusOK},
+		{http.MethodDelete, "/api/posts/" + post.ID.Hex(), "", http.StatusUnauthorized},
+		{http.MethodDelete, "/api/posts/" + post.ID.Hex(), "reader", http.StatusForbidden},
+		{http.MethodDelete, "/api/posts/" + post.ID.Hex(), "writer", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target+" as "+tt.token, func(t *testing.T) {
+			w := apiRequest(router, tt.method, tt.target, tt.token, "")
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestAPI_CreatePost(t *testing.T) {
+	router, mockService := setupTestAPI()
+
+	var got domain.PostInput
+	created := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote"}
+	mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
+		got = in
+		return created, nil
+	}
+	w := apiRequest(router, http.MethodPost, "/api/posts", "writer", `{
+		"title": "Budget vote",
+		"content": "Parliament passed the budget",
+		"tags": ["budget"],
+		"contributors": [{"name": "Anna", "role": "author"}, {"name": "Boris", "role": "photographer"}],
+		"status": "draft"
+	}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/posts/"+created.ID.Hex(), w.Header().Get("Location"))
+	assert.Equal(t, "Budget vote", got.Title)
+	assert.Equal(t, []string{"budget"}, got.Tags)
+	assert.Equal(t, domain.PostStatusDraft, got.Status)
+	assert.Equal(t, []domain.Contributor{{Name: "Anna", Role: domain.RoleAuthor}, {Name: "Boris", Role: domain.RolePhotographer}}, got.Contributors)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, created.ID.Hex(), body["id"])
+
+	w = apiRequest(router, http.MethodPost, "/api/posts", "writer", `{"title":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), ErrInvalidJSON)
+
+	mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
+		return nil, fmt.Errorf("failed to create post: %w", domain.ErrInvalidTitle)
+	}
+	w = apiRequest(router, http.MethodPost, "/api/posts", "writer", `{"title": "A"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), domain.ErrInvalidTitle.Error())
+
+	mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
+		return nil, assert.AnError
+	}
+	w = apiRequest(router, http.MethodPost, "/api/posts", "writer", `{"title": "Budget vote"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), ErrFailedToSavePost)
+}
+
+// replacement is a PUT body carrying every field of a post.
+const replacement = `{
+	"title": "Budget passed",
+	"content": "Parliament passed the budget",
+	"category": "politics",
+	"tags": [],
+	"contributors": [{"name": "Anna", "role": "author"}],
+	"image_url": "",
+	"status": "published",
+	"seo_title": "",
+	"seo_description": ""
+}`
+
+func TestAPI_UpdatePost(t *testing.T) {
+	router, mockService := setupTestAPI()
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote", Tags: []string{"budget"}}
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		if id != post.ID.Hex() {
+			return nil, domain.ErrPostNotFound
+		}
+		return post, nil
+	}
+	var got domain.PostInput
+	mockService.UpdateFunc = func(ctx context.Context, id string, in domain.PostInput) error {
+		got = in
+		post.Title, post.Tags = in.Title, in.Tags
+		return nil
+	}
+
+	w := apiRequest(router, http.MethodPut, "/api/posts/"+post.ID.Hex(), "writer", replacement)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Budget passed", body["title"], "the updated post is returned")
+	assert.Equal(t, "politics", got.Category)
+	assert.Empty(t, got.Tags, "an empty list clears the tags")
+
+	got = domain.PostInput{}
+	w = apiRequest(router, http.MethodPut, "/api/posts/"+post.ID.Hex(), "writer", `{"title": "Budget passed", "content": "Parliament passed the budget"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a partial body must not erase the other fields")
+	assert.Contains(t, w.Body.String(), ErrMissingField+"category")
+	assert.Zero(t, got)
+
+	// The legacy author field may name the writer instead of contributors.
+	legacy := strings.Replace(replacement, `"contributors": [{"name": "Anna", "role": "author"}]`, `"author": "Anna"`, 1)
+	w = apiRequest(router, http.MethodPut, "/api/posts/"+post.ID.Hex(), "writer", legacy)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Anna", got.Author)
+
+	w = apiRequest(router, http.MethodPut, "/api/posts/"+primitive.NewObjectID().Hex(), "writer", replacement)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.UpdateFunc = func(ctx context.Context, id string, in domain.PostInput) error {
+		return fmt.Errorf("failed to update post: %w", domain.ErrInvalidContributor)
+	}
+	invalid := strings.Replace(replacement, `"role": "author"`, `"role": "ghostwriter"`, 1)
+	w = apiRequest(router, http.MethodPut, "/api/posts/"+post.ID.Hex(), "writer", invalid)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), domain.ErrInvalidContributor.Error())
+}
+
+func TestAPI_GetPostFailure(t *testing.T) {
+	router, mockService := setupTestAPI()
+	id := primitive.NewObjectID().Hex()
+
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return nil, fmt.Errorf("failed to get post: %w", domain.ErrPostNotFound)
+	}
+	w := apiRequest(router, http.MethodGet, "/api/posts/"+id, "reader", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return nil, errors.New("server selection timeout")
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		w := apiRequest(router, method, "/api/posts/"+id, "writer", replacement)
+		assert.Equal(t, http.StatusInternalServerError, w.Code, "%s must not report a failure as not found", method)
+		assert.Contains(t, w.Body.String(), ErrFailedToLoadPost)
+	}
+}
+
+func TestAPI_DeletePost(t *testing.T) {
+	router, mockService := setupTestAPI()
+	id := primitive.NewObjectID().Hex()
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return nil, domain.ErrPostNotFound
+	}
+	mockService.DeleteFunc = func(ctx context.Context, id string) error {
+		t.Fatal("a missing post must not be deleted")
+		return nil
+	}
+
+	w := apiRequest(router, http.MethodDelete, "/api/posts/"+id, "writer", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), ErrPostNotFound)
+}
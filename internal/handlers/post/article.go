@@ -0,0 +1,94 @@
+package post
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// siteName names the publisher in link previews and structured data.
+const siteName = "News Portal"
+
+// articlePage is the data of the standalone article page. It adds the
+// absolute URLs and structured data that search engines and link previews need.
+type articlePage struct {
+	*domain.Post
+	CanonicalURL string
+	AuthorURLs   []string
+	Published    string
+	Modified     string
+	JSONLD       newsArticle
+}
+
+// newsArticle is the schema.org NewsArticle embedded as JSON-LD.
+type newsArticle struct {
+	Context          string      `json:"@context"`
+	Type             string      `json:"@type"`
+	Headline         string      `json:"headline"`
+	Description      string      `json:"description"`
+	Image            []string    `json:"image,omitempty"`
+	DatePublished    string      `json:"datePublished"`
+	DateModified     string      `json:"dateModified"`
+	Author           []jsonLDRef `json:"author,omitempty"`
+	Editor           []jsonLDRef `json:"editor,omitempty"`
+	Contributor      []jsonLDRef `json:"contributor,omitempty"`
+	Publisher        jsonLDRef   `json:"publisher"`
+	MainEntityOfPage string      `json:"mainEntityOfPage"`
+	ArticleSection   string      `json:"articleSection,omitempty"`
+	Keywords         []string    `json:"keywords,omitempty"`
+}
+
+type jsonLDRef struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// newArticlePage builds the article page of post, linking it under baseURL.
+func newArticlePage(post *domain.Post, baseURL string) articlePage {
+	modifiedAt := post.UpdatedAt
+	if modifiedAt.Before(post.CreatedAt) {
+		modifiedAt = post.CreatedAt
+	}
+
+	page := articlePage{
+		Post:         post,
+		CanonicalURL: baseURL + "/posts/" + post.ID.Hex(),
+		Published:    post.CreatedAt.UTC().Format(time.RFC3339),
+		Modified:     modifiedAt.UTC().Format(time.RFC3339),
+	}
+	page.JSONLD = newsArticle{
+		Context:          "https://schema.org",
+		Type:             "NewsArticle",
+		Headline:         post.MetaTitle(),
+		Description:      post.MetaDescription(),
+		DatePublished:    page.Published,
+		DateModified:     page.Modified,
+		Publisher:        jsonLDRef{Type: "Organization", Name: siteName},
+		MainEntityOfPage: page.CanonicalURL,
+		ArticleSection:   post.Category,
+		Keywords:         post.Tags,
+	}
+	if post.ImageURL != "" {
+		page.JSONLD.Image = []string{post.ImageURL}
+	}
+	for _, c := range post.Credits() {
+		person := jsonLDRef{Type: "Person", Name: c.Name}
+		if handle := c.Handle(); handle != "" {
+			person.URL = baseURL + "/authors/" + url.PathEscape(handle)
+		}
+		switch {
+		case c.Role.IsWriter():
+			page.JSONLD.Author = append(page.JSONLD.Author, person)
+			if person.URL != "" {
+				page.AuthorURLs = append(page.AuthorURLs, person.URL)
+			}
+		case c.Role == domain.RoleEditor:
+			page.JSONLD.Editor = append(page.JSONLD.Editor, person)
+		default:
+			page.JSONLD.Contributor = append(page.JSONLD.Contributor, person)
+		}
+	}
+	return page
+}
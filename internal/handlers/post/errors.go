@@ -2,10 +2,22 @@ package post
 
 // Error messages
 const (
-	ErrInvalidFormData    = "Invalid form data"
-	ErrEmptyFields        = "Title and content are required"
-	ErrPostNotFound       = "Post not found"
-	ErrFailedToLoadPosts  = "Failed to load posts"
-	ErrInternalServer     = "Internal server error"
-	ErrFailedToDeletePost = "Failed to delete post"
+	ErrInvalidFormData        = "Invalid form data"
+	ErrEmptyFields            = "Title and content are required"
+	ErrPostNotFound           = "Post not found"
+	ErrFailedToLoadPosts      = "Failed to load posts"
+	ErrFailedToLoadPost       = "Failed to load post"
+	ErrFailedToLoadFeed       = "Failed to load your feed"
+	ErrInvalidCursor          = "Invalid pagination cursor"
+	ErrInvalidSort            = "Invalid sort order"
+	ErrInvalidArchiveDate     = "Invalid archive date"
+	ErrInternalServer         = "Internal server error"
+	ErrFailedToDeletePost     = "Failed to delete post"
+	ErrFailedToSavePost       = "Failed to save post"
+	ErrInvalidJSON            = "Invalid JSON body"
+	ErrMissingField           = "PUT replaces the whole post; missing field "
+	ErrFailedToFindSimilar    = "Failed to check for similar posts"
+	ErrFailedToLoadDuplicates = "Failed to load duplicate posts"
+	ErrInvalidDuration        = "Invalid duration, use a duration such as 30m or 2h"
+	ErrFailedToSetBreaking    = "Failed to update breaking news"
 )
@@ -3,49 +3,33 @@ package post
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
+	"github.com/kir/news-app/internal/templates"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
 func setupTestHandler() (*Handler, *MockService) {
 	mockService := &MockService{}
-	tmpl := template.New("")
-	tmpl.Funcs(template.FuncMap{
-		"add": func(a, b int) int {
-			return a + b
-		},
-		"subtract": func(a, b int) int {
-			return a - b
-		},
-		"multiply": func(a, b int) int {
-			return a * b
-		},
-		"sequence": func(start, end int) []int {
-			var result []int
-			for i := start; i <= end; i++ {
-				result = append(result, i)
-			}
-			return result
-		},
-		"objectIDToString": func(id primitive.ObjectID) string {
-			return id.Hex()
-		},
-	})
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/post/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/modals/*.html"))
+	tmpl := template.Must(templates.Parse("../../../templates"))
 	logger, _ := zap.NewDevelopment()
-	handler := New(mockService, tmpl, logger)
+	handler := New(mockService, tmpl, "https://news.example/", logger)
 	return handler, mockService
 }
 
@@ -56,7 +40,7 @@ func TestHandler_Create(t *testing.T) {
 		name           string
 		title          string
 		content        string
-		mockCreate     func(ctx context.Context, title, content string) (*domain.Post, error)
+		mockCreate     func(ctx context.Context, in domain.PostInput) (*domain.Post, error)
 		expectedStatus int
 		expectedError  bool
 	}{
@@ -64,11 +48,11 @@ func TestHandler_Create(t *testing.T) {
 			name:    "successful creation",
 			title:   "Test Post",
 			content: "Test content",
-			mockCreate: func(ctx context.Context, title, content string) (*domain.Post, error) {
+			mockCreate: func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 				return &domain.Post{
 					ID:      primitive.NewObjectID(),
-					Title:   title,
-					Content: content,
+					Title:   in.Title,
+					Content: in.Content,
 				}, nil
 			},
 			expectedStatus: http.StatusNoContent,
@@ -78,7 +62,7 @@ func TestHandler_Create(t *testing.T) {
 			name:    "empty title",
 			title:   "",
 			content: "Test content",
-			mockCreate: func(ctx context.Context, title, content string) (*domain.Post, error) {
+			mockCreate: func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 				return nil, nil
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -88,7 +72,7 @@ func TestHandler_Create(t *testing.T) {
 			name:    "empty content",
 			title:   "Test Post",
 			content: "",
-			mockCreate: func(ctx context.Context, title, content string) (*domain.Post, error) {
+			mockCreate: func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 				return nil, nil
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -98,7 +82,7 @@ func TestHandler_Create(t *testing.T) {
 			name:    "service error",
 			title:   "Test Post",
 			content: "Test content",
-			mockCreate: func(ctx context.Context, title, content string) (*domain.Post, error) {
+			mockCreate: func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 				return nil, assert.AnError
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -139,7 +123,7 @@ func TestHandler_Update(t *testing.T) {
 		id             string
 		title          string
 		content        string
-		mockUpdate     func(ctx context.Context, id, title, content string) error
+		mockUpdate     func(ctx context.Context, id string, in domain.PostInput) error
 		expectedStatus int
 		expectedError  bool
 	}{
@@ -148,7 +132,7 @@ func TestHandler_Update(t *testing.T) {
 			id:      postID.Hex(),
 			title:   "Updated Title",
 			content: "Updated content",
-			mockUpdate: func(ctx context.Context, id, title, content string) error {
+			mockUpdate: func(ctx context.Context, id string, in domain.PostInput) error {
 				return nil
 			},
 			expectedStatus: http.StatusNoContent,
@@ -159,7 +143,7 @@ func TestHandler_Update(t *testing.T) {
 			id:      postID.Hex(),
 			title:   "",
 			content: "Updated content",
-			mockUpdate: func(ctx context.Context, id, title, content string) error {
+			mockUpdate: func(ctx context.Context, id string, in domain.PostInput) error {
 				return nil
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -170,7 +154,7 @@ func TestHandler_Update(t *testing.T) {
 			id:      postID.Hex(),
 			title:   "Updated Title",
 			content: "",
-			mockUpdate: func(ctx context.Context, id, title, content string) error {
+			mockUpdate: func(ctx context.Context, id string, in domain.PostInput) error {
 				return nil
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -181,7 +165,7 @@ func TestHandler_Update(t *testing.T) {
 			id:      postID.Hex(),
 			title:   "Updated Title",
 			content: "Updated content",
-			mockUpdate: func(ctx context.Context, id, title, content string) error {
+			mockUpdate: func(ctx context.Context, id string, in domain.PostInput) error {
 				return assert.AnError
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -307,6 +291,11 @@ func TestHandler_GetByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService.GetByIDFunc = tt.mockGetByID
+			var viewed []string
+			mockService.RecordViewFunc = func(ctx context.Context, id string) error {
+				viewed = append(viewed, id)
+				return nil
+			}
 
 			req := httptest.NewRequest(http.MethodGet, "/posts/"+tt.id, nil)
 			chiCtx := chi.NewRouteContext()
@@ -319,6 +308,9 @@ func TestHandler_GetByID(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedError {
 				assert.Contains(t, w.Header().Get(HXErrorHeader), "")
+				assert.Empty(t, viewed)
+			} else {
+				assert.Equal(t, []string{tt.id}, viewed)
 			}
 		})
 	}
@@ -332,7 +324,7 @@ func TestHandler_GetPaginated(t *testing.T) {
 		page             int
 		pageSize         int
 		search           string
-		mockGetPaginated func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error)
+		mockGetPaginated func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
 		expectedStatus   int
 		expectedError    bool
 	}{
@@ -341,7 +333,7 @@ func TestHandler_GetPaginated(t *testing.T) {
 			page:     1,
 			pageSize: 10,
 			search:   "",
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return &domain.PostList{
 					Posts: []*domain.Post{
 						{
@@ -363,7 +355,7 @@ func TestHandler_GetPaginated(t *testing.T) {
 			page:     1,
 			pageSize: 10,
 			search:   "",
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return nil, assert.AnError
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -387,3 +379,647 @@ func TestHandler_GetPaginated(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_IndexFilters(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	var gotQuery domain.PostQuery
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		gotQuery = query
+		return &domain.PostList{
+			Posts:      []*domain.Post{{ID: primitive.NewObjectID(), Title: "Budget vote", Content: "Parliament passed the budget", Category: "politics"}},
+			TotalCount: 20,
+			Page:       query.Page,
+			PageSize:   query.PageSize,
+		}, nil
+	}
+	mockService.GetFacetsFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+		return &domain.PostFacets{
+			Categories: []domain.FacetValue{{Value: "politics", Count: 20}, {Value: "sport", Count: 4}},
+			Tags:       []domain.FacetValue{{Value: "budget", Count: 7}},
+		}, nil
+	}
+	defer func() { mockService.GetFacetsFunc = nil }()
+
+	req := httptest.NewRequest(http.MethodGet, "/?category=politics&tag=budget&page=2", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	handler.Index(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "politics", gotQuery.Category)
+	assert.Equal(t, []string{"budget"}, gotQuery.Tags)
+	assert.Equal(t, 2, gotQuery.Page)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `id="facets" hx-swap-oob="true"`)
+	assert.Contains(t, body, "sport (4)")
+	assert.Contains(t, body, `hx-get="/?category=politics&amp;page=3&amp;tag=budget"`)
+}
+
+func TestHandler_IndexLoadMore(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	var gotQuery domain.PostQuery
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		gotQuery = query
+		if query.Cursor == "bad" {
+			return nil, domain.ErrInvalidCursor
+		}
+		return &domain.PostList{
+			Posts:      []*domain.Post{{ID: primitive.NewObjectID(), Title: "Older post", Content: "Some older content"}},
+			PageSize:   query.PageSize,
+			NextCursor: "next",
+		}, nil
+	}
+	mockService.GetFacetsFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+		t.Fatal("facets must not be loaded when appending posts")
+		return nil, nil
+	}
+	defer func() { mockService.GetFacetsFunc = nil }()
+
+	req := httptest.NewRequest(http.MethodGet, "/?cursor=abc&category=politics", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+
+	handler.Index(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", gotQuery.Cursor)
+	assert.True(t, gotQuery.SkipCount)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "Older post")
+	assert.Contains(t, body, `id="load-more" hx-swap-oob="true"`)
+	assert.Contains(t, body, `hx-get="/?category=politics&amp;cursor=next"`)
+	assert.Contains(t, body, `id="pagination" hx-swap-oob="true"`)
+	assert.NotContains(t, body, `id="posts-grid"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/?cursor=bad", nil)
+	req.Header.Set("HX-Request", "true")
+	w = httptest.NewRecorder()
+
+	handler.Index(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrInvalidCursor, w.Header().Get(HXErrorHeader))
+}
+
+func TestHandler_Archive(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	var gotQuery domain.PostQuery
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		gotQuery = query
+		return &domain.PostList{
+			Posts:      []*domain.Post{{ID: primitive.NewObjectID(), Title: "March news", Content: "Something happened in March"}},
+			TotalCount: 20,
+			Page:       query.Page,
+			PageSize:   query.PageSize,
+		}, nil
+	}
+	mockService.GetArchiveFunc = func(ctx context.Context) ([]domain.ArchiveMonth, error) {
+		return []domain.ArchiveMonth{{Year: 2025, Month: time.April, Count: 3}, {Year: 2025, Month: time.March, Count: 20}}, nil
+	}
+	defer func() { mockService.GetArchiveFunc = nil }()
+
+	tests := []struct {
+		name           string
+		year           string
+		month          string
+		expectedStatus int
+	}{
+		{name: "valid month", year: "2025", month: "03", expectedStatus: http.StatusOK},
+		{name: "invalid month", year: "2025", month: "13", expectedStatus: http.StatusNotFound},
+		{name: "invalid year", year: "twenty", month: "03", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/archive/"+tt.year+"/"+tt.month+"?category=politics", nil)
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("year", tt.year)
+			chiCtx.URLParams.Add("month", tt.month)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			w := httptest.NewRecorder()
+
+			handler.Archive(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, ErrInvalidArchiveDate, w.Header().Get(HXErrorHeader))
+				return
+			}
+
+			assert.Equal(t, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), gotQuery.From)
+			assert.Equal(t, time.Date(2025, time.March, 31, 23, 59, 59, 999999999, time.UTC), gotQuery.To)
+			assert.Equal(t, "politics", gotQuery.Category)
+
+			body := w.Body.String()
+			assert.Contains(t, body, "Archive: March 2025")
+			assert.Contains(t, body, `<link rel="alternate" type="application/feed+json" title="News Portal (JSON Feed)" href="/feed.json">`)
+			assert.Contains(t, body, `href="/feed.rss?category=politics"`)
+			assert.Contains(t, body, `href="/archive/2025/04"`)
+			assert.Contains(t, body, `hx-get="/archive/2025/03?category=politics&amp;page=2"`)
+			assert.NotContains(t, body, `id="filter-from"`)
+		})
+	}
+}
+
+func TestHandler_ViewPageMetadata(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	post := &domain.Post{
+		ID:             primitive.NewObjectID(),
+		Title:          "Budget </script> vote",
+		Content:        "Parliament passed the budget",
+		Author:         "anna",
+		Tags:           []string{"budget"},
+		SEODescription: "What the budget means",
+		Status:         domain.PostStatusDraft,
+		CreatedAt:      time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+	}
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return post, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/"+post.ID.Hex(), nil)
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add("id", post.ID.Hex())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	w := httptest.NewRecorder()
+
+	handler.View(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	canonical := "https://news.example/posts/" + post.ID.Hex()
+	assert.Contains(t, body, `<link rel="canonical" href="`+canonical+`">`)
+	assert.Contains(t, body, `<meta name="description" content="What the budget means">`)
+	assert.Contains(t, body, `<meta name="robots" content="noindex">`)
+	assert.Contains(t, body, `<meta property="og:url" content="`+canonical+`">`)
+	assert.Contains(t, body, `<meta property="article:modified_time" content="2025-03-14T09:00:00Z">`)
+	assert.Contains(t, body, `<meta property="article:tag" content="budget">`)
+	assert.Contains(t, body, `<meta property="article:author" content="https://news.example/authors/anna">`)
+	assert.Contains(t, body, `By <a href="/authors/anna"`)
+	assert.Contains(t, body, `<meta name="twitter:card" content="summary">`)
+	assert.NotContains(t, body, "og:image")
+
+	start := strings.Index(body, `<script type="application/ld+json">`)
+	require.NotEqual(t, -1, start)
+	raw := body[start+len(`<script type="application/ld+json">`):]
+	raw = raw[:strings.Index(raw, "</script>")]
+
+	var article map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &article))
+	assert.Equal(t, "NewsArticle", article["@type"])
+	assert.Equal(t, "Budget </script> vote", article["headline"])
+	assert.Equal(t, "What the budget means", article["description"])
+	assert.Equal(t, "2025-03-14T09:00:00Z", article["datePublished"])
+	assert.Equal(t, canonical, article["mainEntityOfPage"])
+	assert.Equal(t, []any{map[string]any{"@type": "Person", "name": "anna", "url": "https://news.example/authors/anna"}}, article["author"])
+	assert.NotContains(t, article, "image")
+}
+
+func TestHandler_ViewPage(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	post := &domain.Post{
+		ID:       primitive.NewObjectID(),
+		Title:    "Budget vote",
+		Content:  "Parliament passed the budget",
+		Category: "home news",
+		Author:   "anna",
+		ImageURL: "https://cdn.news.example/budget.jpg",
+		SEOTitle: "Parliament passes the budget",
+
+		CreatedAt: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC),
+	}
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return post, nil
+	}
+
+	for _, htmx := range []bool{true, false} {
+		req := httptest.NewRequest(http.MethodGet, "/posts/"+post.ID.Hex(), nil)
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", post.ID.Hex())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		w := httptest.NewRecorder()
+
+		handler.View(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Parliament passed the budget")
+		assert.Contains(t, w.Body.String(), `src="https://cdn.news.example/budget.jpg"`)
+		if htmx {
+			assert.NotContains(t, w.Body.String(), "<html")
+		} else {
+			assert.Contains(t, w.Body.String(), "<title>Parliament passes the budget — News Portal</title>")
+			assert.Contains(t, w.Body.String(), `href="/feed.atom"`)
+			assert.Contains(t, w.Body.String(), `href="/feed.json?category=home%20news"`)
+		}
+	}
+}
+
+func TestHandler_ViewPageContributors(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	post := &domain.Post{
+		ID:      primitive.NewObjectID(),
+		Title:   "Budget vote",
+		Content: "Parliament passed the budget",
+		Author:  "Anna Lee",
+		Contributors: []domain.Contributor{
+			{Name: "Anna Lee", Role: domain.RoleAuthor},
+			{Name: "Boris", Role: domain.RoleCoAuthor},
+			{Name: "Clara", Role: domain.RoleCoAuthor},
+			{Name: "Dan", Role: domain.RolePhotographer},
+			{Name: "Eve", Role: domain.RoleEditor},
+		},
+		CreatedAt: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+	}
+	mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+		return post, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/"+post.ID.Hex(), nil)
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add("id", post.ID.Hex())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	w := httptest.NewRecorder()
+
+	handler.View(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	link := func(handle, name string) string {
+		return `<a href="/authors/` + handle + `" class="hover:text-primary-600 hover:underline">` + name + `</a>`
+	}
+	assert.Contains(t, body, "By "+link("anna-lee", "Anna Lee")+", "+link("boris", "Boris")+" and "+link("clara", "Clara"))
+	assert.Contains(t, body, "Photographer: "+link("dan", "Dan")+" · Editor: "+link("eve", "Eve"))
+	assert.Contains(t, body, `<meta property="article:author" content="https://news.example/authors/boris">`)
+	assert.NotContains(t, body, `<meta property="article:author" content="https://news.example/authors/dan">`)
+	assert.Contains(t, body, "&author=Anna Lee&author=Boris&author=Clara\"")
+
+	start := strings.Index(body, `<script type="application/ld+json">`)
+	require.NotEqual(t, -1, start)
+	raw := body[start+len(`<script type="application/ld+json">`):]
+	raw = raw[:strings.Index(raw, "</script>")]
+
+	var article struct {
+		Author      []struct{ Name string } `json:"author"`
+		Editor      []struct{ Name string } `json:"editor"`
+		Contributor []struct{ Name string } `json:"contributor"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(raw), &article))
+	assert.Len(t, article.Author, 3)
+	require.Len(t, article.Editor, 1)
+	assert.Equal(t, "Eve", article.Editor[0].Name)
+	require.Len(t, article.Contributor, 1)
+	assert.Equal(t, "Dan", article.Contributor[0].Name)
+}
+
+func TestHandler_UpdateContributors(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	var got domain.PostInput
+	mockService.UpdateFunc = func(ctx context.Context, id string, in domain.PostInput) error {
+		got = in
+		return nil
+	}
+
+	form := url.Values{
+		"title":            {"Budget vote"},
+		"content":          {"Parliament passed the budget"},
+		"contributor_name": {"Anna", "Boris", ""},
+		"contributor_role": {"co-author", "photographer", "author"},
+	}
+	req := httptest.NewRequest(http.MethodPut, "/posts/abc", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []domain.Contributor{
+		{Name: "Anna", Role: domain.RoleCoAuthor},
+		{Name: "Boris", Role: domain.RolePhotographer},
+		{Name: "", Role: domain.RoleAuthor},
+	}, got.Contributors, "rows keep their order and pair names with roles")
+}
+
+func TestHandler_Similar(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	existing := &domain.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     "Council approves budget",
+		CreatedAt: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		mockSimilar    func(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error)
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name: "similar posts found",
+			mockSimilar: func(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error) {
+				assert.Equal(t, "The council approved the budget", in.Content)
+				return []domain.SimilarPost{{Post: existing, Distance: 2}}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				`href="/posts/` + existing.ID.Hex() + `"`,
+				"Council approves budget",
+				"97% similar",
+			},
+		},
+		{
+			name: "no similar posts",
+			mockSimilar: func(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error) {
+				return nil, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "service error",
+			mockSimilar: func(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error) {
+				return nil, assert.AnError
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.FindSimilarFunc = tt.mockSimilar
+
+			form := "title=Budget&content=The+council+approved+the+budget"
+			req := httptest.NewRequest(http.MethodPost, "/posts/similar", strings.NewReader(form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("HX-Request", "true")
+			w := httptest.NewRecorder()
+
+			handler.Similar(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, ErrFailedToFindSimilar, w.Header().Get(HXErrorHeader))
+				return
+			}
+			if len(tt.expectedBody) == 0 {
+				assert.Empty(t, strings.TrimSpace(w.Body.String()))
+			}
+			for _, want := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestHandler_Duplicates(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	original := &domain.Post{ID: primitive.NewObjectID(), Title: "Council approves budget", Status: domain.PostStatusPublished}
+	imported := &domain.Post{
+		ID:     primitive.NewObjectID(),
+		Title:  "Budget passed by council",
+		Status: domain.PostStatusDraft,
+		Origin: &domain.PostOrigin{Name: "City Wire"},
+	}
+
+	mockService.GetDuplicateClustersFunc = func(ctx context.Context) ([]domain.DuplicateCluster, error) {
+		return []domain.DuplicateCluster{{Posts: []*domain.Post{original, imported}}}, nil
+	}
+	w := httptest.NewRecorder()
+	handler.Duplicates(w, httptest.NewRequest(http.MethodGet, "/duplicates", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "2 posts")
+	assert.Contains(t, body, `href="/posts/`+original.ID.Hex()+`"`)
+	assert.Contains(t, body, `href="/posts/`+imported.ID.Hex()+`"`)
+	assert.Contains(t, body, "via City Wire")
+	assert.Contains(t, body, "draft")
+	assert.Less(t, strings.Index(body, original.Title), strings.Index(body, imported.Title))
+
+	mockService.GetDuplicateClustersFunc = func(ctx context.Context) ([]domain.DuplicateCluster, error) {
+		return nil, nil
+	}
+	w = httptest.NewRecorder()
+	handler.Duplicates(w, httptest.NewRequest(http.MethodGet, "/duplicates", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "No duplicate stories found.")
+
+	mockService.GetDuplicateClustersFunc = func(ctx context.Context) ([]domain.DuplicateCluster, error) {
+		return nil, assert.AnError
+	}
+	w = httptest.NewRecorder()
+	handler.Duplicates(w, httptest.NewRequest(http.MethodGet, "/duplicates", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandler_SetBreaking(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	postID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		duration       string
+		mockSet        func(ctx context.Context, id string, d time.Duration) (*domain.Post, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "flag post",
+			duration: "1h",
+			mockSet: func(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+				assert.Equal(t, time.Hour, d)
+				breaking, err := domain.NewBreaking(time.Now(), d)
+				require.NoError(t, err)
+				return &domain.Post{ID: postID, Title: "Flood warning", Breaking: breaking}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `hx-delete="/posts/` + postID.Hex() + `/breaking"`,
+		},
+		{
+			name:           "invalid duration",
+			duration:       "soon",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "draft post",
+			duration: "1h",
+			mockSet: func(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+				return nil, domain.ErrBreakingDraft
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "service error",
+			duration: "1h",
+			mockSet: func(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+				return nil, assert.AnError
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.SetBreakingFunc = tt.mockSet
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/"+postID.Hex()+"/breaking", strings.NewReader("duration="+tt.duration))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			chiCtx := chi.NewRouteContext()
+			chiCtx.URLParams.Add("id", postID.Hex())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+			w := httptest.NewRecorder()
+
+			handler.SetBreaking(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestHandler_ClearBreaking(t *testing.T) {
+	handler, mockService := setupTestHandler()
+	postID := primitive.NewObjectID()
+
+	var gotDuration time.Duration = -1
+	mockService.SetBreakingFunc = func(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+		gotDuration = d
+		return &domain.Post{ID: postID, Title: "Flood warning"}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/posts/"+postID.Hex()+"/breaking", nil)
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add("id", postID.Hex())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+	w := httptest.NewRecorder()
+
+	handler.ClearBreaking(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, gotDuration)
+	assert.Contains(t, w.Body.String(), `hx-post="/posts/`+postID.Hex()+`/breaking"`)
+}
+
+func TestHandler_IndexBreakingBanner(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	breaking, err := domain.NewBreaking(time.Now(), time.Hour)
+	require.NoError(t, err)
+	flagged := &domain.Post{ID: primitive.NewObjectID(), Title: "Flood warning", Content: "Rivers are rising.", Breaking: breaking}
+	mockService.GetPaginatedFunc = func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+		return &domain.PostList{Posts: []*domain.Post{flagged}, TotalCount: 1, Page: 1, PageSize: query.PageSize}, nil
+	}
+	mockService.GetBreakingFunc = func(ctx context.Context) ([]*domain.Post, error) {
+		return []*domain.Post{flagged}, nil
+	}
+	defer func() { mockService.GetBreakingFunc = nil }()
+
+	w := httptest.NewRecorder()
+	handler.Index(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `id="breaking-`+flagged.ID.Hex()+`"`)
+	assert.Contains(t, body, `data-breaking-until="`+strconv.FormatInt(breaking.Until.UnixMilli(), 10)+`"`)
+}
+
+func TestHandler_ForYou(t *testing.T) {
+	mockService := &MockService{}
+	feed := &MockPersonalFeed{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	handler := New(mockService, tmpl, "https://news.example/", zap.NewNop(), WithPersonalFeed(feed))
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote", Content: "Parliament passed the budget", Category: "politics"}
+
+	t.Run("personalized", func(t *testing.T) {
+		feed.ForYouFunc = func(ctx context.Context, readerID string, page int) (*domain.FeedPage, error) {
+			assert.Equal(t, "reader", readerID)
+			return &domain.FeedPage{
+				PostList:     domain.PostList{Posts: []*domain.Post{post}, TotalCount: 20, Page: page, PageSize: domain.FeedPageSize},
+				Personalized: true,
+			}, nil
+		}
+		req := httptest.NewRequest(http.MethodGet, "/for-you?page=2", nil)
+		req = req.WithContext(reader.WithID(req.Context(), "reader"))
+		w := httptest.NewRecorder()
+
+		handler.ForYou(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, "Budget vote")
+		assert.Contains(t, body, `href="/for-you"`)
+		assert.Contains(t, body, `hx-get="/for-you?page=3"`)
+		assert.NotContains(t, body, "data-live", "ranked posts are not prepended as they are published")
+		assert.NotContains(t, body, `id="facets"`)
+		assert.NotContains(t, body, "Until then it shows the latest posts")
+	})
+
+	t.Run("latest posts for unknown readers", func(t *testing.T) {
+		feed.ForYouFunc = func(ctx context.Context, readerID string, page int) (*domain.FeedPage, error) {
+			return &domain.FeedPage{PostList: domain.PostList{Posts: []*domain.Post{post}, TotalCount: 1, Page: 1}}, nil
+		}
+		req := httptest.NewRequest(http.MethodGet, "/for-you", nil)
+		req.Header.Set("HX-Request", "true")
+		w := httptest.NewRecorder()
+
+		handler.ForYou(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "<html")
+		assert.Contains(t, w.Body.String(), "Until then it shows the latest posts")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		feed.ForYouFunc = func(ctx context.Context, readerID string, page int) (*domain.FeedPage, error) {
+			return nil, errors.New("connection reset")
+		}
+		w := httptest.NewRecorder()
+
+		handler.ForYou(w, httptest.NewRequest(http.MethodGet, "/for-you", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, ErrFailedToLoadFeed, w.Header().Get(HXErrorHeader))
+	})
+
+	t.Run("view records the read", func(t *testing.T) {
+		var recorded *domain.Post
+		feed.RecordReadFunc = func(ctx context.Context, readerID string, p *domain.Post) error {
+			assert.Equal(t, "reader", readerID)
+			recorded = p
+			return nil
+		}
+		mockService.GetByIDFunc = func(ctx context.Context, id string) (*domain.Post, error) {
+			return post, nil
+		}
+		req := httptest.NewRequest(http.MethodGet, "/posts/"+post.ID.Hex(), nil)
+		req.Header.Set("HX-Request", "true")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", post.ID.Hex())
+		ctx := context.WithValue(reader.WithID(req.Context(), "reader"), chi.RouteCtxKey, chiCtx)
+		w := httptest.NewRecorder()
+
+		handler.View(w, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Same(t, post, recorded)
+	})
+}
+
+func TestHandler_ForYouDisabled(t *testing.T) {
+	handler, _ := setupTestHandler()
+	w := httptest.NewRecorder()
+
+	handler.ForYou(w, httptest.NewRequest(http.MethodGet, "/for-you", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -1,11 +1,16 @@
 package post
 
 import (
+	"errors"
 	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/kir/news-app/internal/apiauth"
 	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/reader"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -15,16 +20,42 @@ import (
 type Handler struct {
 	service   PostService
 	templates *template.Template
+	baseURL   string
 	logger    *zap.Logger
+	feed      PersonalFeed
+	apiKeys   apiauth.Authenticator
 }
 
-// New creates a new post handler
-func New(service PostService, templates *template.Template, logger *zap.Logger) *Handler {
-	return &Handler{
+// Option configures optional Handler dependencies.
+type Option func(*Handler)
+
+// WithPersonalFeed enables the "For you" feed and records the posts readers open.
+func WithPersonalFeed(feed PersonalFeed) Option {
+	return func(h *Handler) {
+		h.feed = feed
+	}
+}
+
+// WithAPIKeys puts the JSON API behind API keys: reads need the posts:read
+// scope and writes posts:write.
+func WithAPIKeys(auth apiauth.Authenticator) Option {
+	return func(h *Handler) {
+		h.apiKeys = auth
+	}
+}
+
+// New creates a new post handler. Canonical links of article pages are built on baseURL.
+func New(service PostService, templates *template.Template, baseURL string, logger *zap.Logger, opts ...Option) *Handler {
+	h := &Handler{
 		service:   service,
 		templates: templates,
+		baseURL:   strings.TrimRight(baseURL, "/"),
 		logger:    logger,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // handleError is a helper function to handle errors consistently
@@ -44,55 +75,145 @@ func (h *Handler) handleHTMXSuccess(w http.ResponseWriter, trigger string) {
 
 // Index handles the main page request
 func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	page := 1
-	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
+	h.list(w, r, parseListQuery(r.URL.Query()), nil)
+}
 
-	pageSize := 9
-	if sizeStr := r.URL.Query().Get("page_size"); sizeStr != "" {
-		if s, err := strconv.Atoi(sizeStr); err == nil && s > 0 {
-			pageSize = s
-		}
+// Archive handles the page listing the posts of one month
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) {
+	year, yearErr := strconv.Atoi(chi.URLParam(r, "year"))
+	month, monthErr := strconv.Atoi(chi.URLParam(r, "month"))
+	if yearErr != nil || monthErr != nil || year < 1 || year > 9999 || month < 1 || month > 12 {
+		h.handleError(w, nil, ErrInvalidArchiveDate, http.StatusNotFound)
+		return
 	}
 
-	search := r.URL.Query().Get("search")
+	archive := &domain.ArchiveMonth{Year: year, Month: time.Month(month)}
+	query := parseListQuery(r.URL.Query())
+	query.From, query.To = archive.Start(), archive.End()
+	h.list(w, r, query, archive)
+}
+
+// list renders a page of posts, or only the list fragments for HTMX requests.
+// Archive pages take their date range from the path rather than the query.
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, query domain.PostQuery, archive *domain.ArchiveMonth) {
+	ctx := r.Context()
 
-	response, err := h.service.GetPaginated(ctx, page, pageSize, search)
+	response, err := h.service.GetPaginated(ctx, query)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		h.handleError(w, err, ErrInvalidCursor, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		h.handleError(w, err, ErrFailedToLoadPosts, http.StatusInternalServerError)
 		return
 	}
 
+	isHTMX := r.Header.Get("HX-Request") == "true"
+	cursor := query.Cursor
+	view := query
+	view.Cursor = ""
+	view.SkipCount = false
+	if archive != nil {
+		view.From, view.To = time.Time{}, time.Time{}
+	}
+
+	if isHTMX && cursor != "" {
+		data := listPage{
+			Posts:      response.Posts,
+			PageSize:   response.PageSize,
+			Query:      view,
+			NextCursor: response.NextCursor,
+			Archive:    archive,
+		}
+		if err := h.templates.ExecuteTemplate(w, "post/posts-more", data); err != nil {
+			h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	query.Cursor = ""
+	facets, err := h.service.GetFacets(ctx, query)
+	if err != nil {
+		h.logger.Error("failed to get facets", zap.Error(err))
+	}
+
 	recentPosts, err := h.service.GetRecent(ctx, 5)
 	if err != nil {
 		h.logger.Error("failed to get recent posts", zap.Error(err))
 	}
 
-	totalPages := int(response.TotalCount) / pageSize
-	if int(response.TotalCount)%pageSize > 0 {
+	totalPages := int(response.TotalCount) / query.PageSize
+	if int(response.TotalCount)%query.PageSize > 0 {
 		totalPages++
 	}
 
-	data := struct {
-		Posts       []*domain.Post
-		TotalCount  int64
-		Page        int
-		PageSize    int
-		TotalPages  int
-		Search      string
-		RecentPosts []*domain.Post
-	}{
+	data := listPage{
 		Posts:       response.Posts,
 		TotalCount:  response.TotalCount,
 		Page:        response.Page,
 		PageSize:    response.PageSize,
 		TotalPages:  totalPages,
-		Search:      search,
+		Search:      query.Search,
 		RecentPosts: recentPosts,
+		Query:       view,
+		Facets:      facets,
+		NextCursor:  response.NextCursor,
+		Archive:     archive,
+		Feed:        h.feed != nil,
+	}
+
+	if isHTMX {
+		if err := h.templates.ExecuteTemplate(w, "post/posts-list", data); err != nil {
+			h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+			return
+		}
+		// The facet sidebar lives outside #posts-list and is swapped out of band.
+		if err := h.templates.ExecuteTemplate(w, "post/facets", data); err != nil {
+			h.logger.Error("failed to render facets", zap.Error(err))
+		}
+		return
+	}
+
+	data.Archives, err = h.service.GetArchive(ctx)
+	if err != nil {
+		h.logger.Error("failed to get archive", zap.Error(err))
+	}
+	data.Breaking, err = h.service.GetBreaking(ctx)
+	if err != nil {
+		h.logger.Error("failed to get breaking news", zap.Error(err))
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "index", data); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// ForYou handles the reader's personalized feed, or only the list fragment
+// for HTMX requests
+func (h *Handler) ForYou(w http.ResponseWriter, r *http.Request) {
+	if h.feed == nil {
+		http.NotFound(w, r)
+		return
+	}
+	ctx := r.Context()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get(paramPage))
+	feed, err := h.feed.ForYou(ctx, reader.ID(ctx), page)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadFeed, http.StatusInternalServerError)
+		return
+	}
+
+	data := listPage{
+		Posts:        feed.Posts,
+		TotalCount:   feed.TotalCount,
+		Page:         feed.Page,
+		PageSize:     domain.FeedPageSize,
+		TotalPages:   int((feed.TotalCount + domain.FeedPageSize - 1) / domain.FeedPageSize),
+		Query:        domain.PostQuery{Page: feed.Page, PageSize: domain.FeedPageSize},
+		Feed:         true,
+		ForYou:       true,
+		Personalized: feed.Personalized,
 	}
 
 	if r.Header.Get("HX-Request") == "true" {
@@ -102,6 +223,19 @@ func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data.RecentPosts, err = h.service.GetRecent(ctx, 5)
+	if err != nil {
+		h.logger.Error("failed to get recent posts", zap.Error(err))
+	}
+	data.Archives, err = h.service.GetArchive(ctx)
+	if err != nil {
+		h.logger.Error("failed to get archive", zap.Error(err))
+	}
+	data.Breaking, err = h.service.GetBreaking(ctx)
+	if err != nil {
+		h.logger.Error("failed to get breaking news", zap.Error(err))
+	}
+
 	if err := h.templates.ExecuteTemplate(w, "index", data); err != nil {
 		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
 	}
@@ -114,8 +248,39 @@ func (h *Handler) CreateForm(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Duplicates handles the report page of near-duplicate post clusters
+func (h *Handler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	clusters, err := h.service.GetDuplicateClusters(r.Context())
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadDuplicates, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "post/duplicates", clusters); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
 // HTMX handlers
 
+// Similar renders the near-duplicate warning for the post being typed into the create form
+func (h *Handler) Similar(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	similar, err := h.service.FindSimilar(r.Context(), postInputFromForm(r.Form))
+	if err != nil {
+		h.handleError(w, err, ErrFailedToFindSimilar, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "modals/similar-posts", similar); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
 // View handles the post view request
 func (h *Handler) View(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -132,7 +297,24 @@ func (h *Handler) View(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.templates.ExecuteTemplate(w, "modals/view-content", post); err != nil {
+	if err := h.service.RecordView(ctx, id); err != nil {
+		h.logger.Error("failed to record view", zap.Error(err))
+	}
+	if h.feed != nil {
+		if err := h.feed.RecordRead(ctx, reader.ID(ctx), post); err != nil {
+			h.logger.Error("failed to record read", zap.Error(err))
+		}
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		if err := h.templates.ExecuteTemplate(w, "modals/view-content", post); err != nil {
+			h.handleError(w, err, "Error displaying the post", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Outside HTMX, e.g. when following a feed or shared link, the post gets a page of its own.
+	if err := h.templates.ExecuteTemplate(w, "post/article", newArticlePage(post, h.baseURL)); err != nil {
 		h.handleError(w, err, "Error displaying the post", http.StatusInternalServerError)
 	}
 }
@@ -179,17 +361,15 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	title := r.FormValue("title")
-	content := r.FormValue("content")
-
-	if title == "" || content == "" {
+	input := postInputFromForm(r.Form)
+	if input.Title == "" || input.Content == "" {
 		h.handleError(w, nil, ErrEmptyFields, http.StatusBadRequest)
 		return
 	}
 
-	h.logger.Info("creating post", zap.String("title", title))
+	h.logger.Info("creating post", zap.String("title", input.Title))
 
-	_, err := h.service.Create(ctx, title, content)
+	_, err := h.service.Create(ctx, input)
 	if err != nil {
 		h.logger.Error("failed to create post", zap.Error(err))
 		h.handleError(w, err, err.Error(), http.StatusBadRequest)
@@ -209,21 +389,56 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	title := r.FormValue("title")
-	content := r.FormValue("content")
-
-	if title == "" || content == "" {
+	input := postInputFromForm(r.Form)
+	if input.Title == "" || input.Content == "" {
 		h.handleError(w, nil, ErrEmptyFields, http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.Update(ctx, id, title, content); err != nil {
+	if err := h.service.Update(ctx, id, input); err != nil {
 		h.handleError(w, err, err.Error(), http.StatusBadRequest)
 		return
 	}
 	h.handleHTMXSuccess(w, TriggerPostUpdated)
 }
 
+// SetBreaking handles flagging a post as breaking news for the form's duration
+func (h *Handler) SetBreaking(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+	d, err := time.ParseDuration(r.PostForm.Get("duration"))
+	if err != nil || d <= 0 {
+		h.handleError(w, err, ErrInvalidDuration, http.StatusBadRequest)
+		return
+	}
+	h.setBreaking(w, r, d)
+}
+
+// ClearBreaking handles removing the breaking news flag of a post
+func (h *Handler) ClearBreaking(w http.ResponseWriter, r *http.Request) {
+	h.setBreaking(w, r, 0)
+}
+
+// setBreaking flags the post for d, or clears the flag when d is zero, and
+// renders the updated breaking news controls of the edit form.
+func (h *Handler) setBreaking(w http.ResponseWriter, r *http.Request, d time.Duration) {
+	post, err := h.service.SetBreaking(r.Context(), chi.URLParam(r, "id"), d)
+	if errors.Is(err, domain.ErrInvalidBreakingDuration) || errors.Is(err, domain.ErrBreakingDraft) {
+		h.handleError(w, err, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToSetBreaking, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "post/breaking-controls", post); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
 // Delete handles the post deletion request
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
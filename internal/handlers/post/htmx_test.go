@@ -36,11 +36,11 @@ func TestHTMX_Responses(t *testing.T) {
 				return req
 			},
 			mockService: func() {
-				mockService.CreateFunc = func(ctx context.Context, title, content string) (*domain.Post, error) {
+				mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 					return &domain.Post{
 						ID:      postID,
-						Title:   title,
-						Content: content,
+						Title:   in.Title,
+						Content: in.Content,
 					}, nil
 				}
 			},
@@ -61,7 +61,7 @@ func TestHTMX_Responses(t *testing.T) {
 				return req
 			},
 			mockService: func() {
-				mockService.UpdateFunc = func(ctx context.Context, id, title, content string) error {
+				mockService.UpdateFunc = func(ctx context.Context, id string, in domain.PostInput) error {
 					return nil
 				}
 			},
@@ -121,7 +121,7 @@ func TestHTMX_Responses(t *testing.T) {
 				return req
 			},
 			mockService: func() {
-				mockService.CreateFunc = func(ctx context.Context, title, content string) (*domain.Post, error) {
+				mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 					return nil, nil
 				}
 			},
@@ -175,11 +175,11 @@ func TestHTMX_NonHTMXRequests(t *testing.T) {
 				return req
 			},
 			mockService: func() {
-				mockService.CreateFunc = func(ctx context.Context, title, content string) (*domain.Post, error) {
+				mockService.CreateFunc = func(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 					return &domain.Post{
 						ID:      postID,
-						Title:   title,
-						Content: content,
+						Title:   in.Title,
+						Content: in.Content,
 					}, nil
 				}
 			},
@@ -197,7 +197,7 @@ func TestHTMX_NonHTMXRequests(t *testing.T) {
 				return req
 			},
 			mockService: func() {
-				mockService.UpdateFunc = func(ctx context.Context, id, title, content string) error {
+				mockService.UpdateFunc = func(ctx context.Context, id string, in domain.PostInput) error {
 					return nil
 				}
 			},
@@ -2,16 +2,30 @@ package post
 
 import (
 	"context"
+	"time"
 
 	"github.com/kir/news-app/internal/domain"
 )
 
 type PostService interface {
-	Create(ctx context.Context, title, content string) (*domain.Post, error)
+	Create(ctx context.Context, in domain.PostInput) (*domain.Post, error)
 	GetAll(ctx context.Context) ([]*domain.Post, error)
 	GetByID(ctx context.Context, id string) (*domain.Post, error)
-	Update(ctx context.Context, id, title, content string) error
+	Update(ctx context.Context, id string, in domain.PostInput) error
 	Delete(ctx context.Context, id string) error
-	GetPaginated(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error)
+	GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetFacets(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error)
+	GetArchive(ctx context.Context) ([]domain.ArchiveMonth, error)
 	GetRecent(ctx context.Context, limit int) ([]*domain.Post, error)
+	RecordView(ctx context.Context, id string) error
+	FindSimilar(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error)
+	GetDuplicateClusters(ctx context.Context) ([]domain.DuplicateCluster, error)
+	SetBreaking(ctx context.Context, id string, d time.Duration) (*domain.Post, error)
+	GetBreaking(ctx context.Context) ([]*domain.Post, error)
+}
+
+// PersonalFeed ranks the "For you" feed of a reader from what they follow and read.
+type PersonalFeed interface {
+	ForYou(ctx context.Context, readerID string, page int) (*domain.FeedPage, error)
+	RecordRead(ctx context.Context, readerID string, p *domain.Post) error
 }
@@ -2,24 +2,32 @@ package post
 
 import (
 	"context"
+	"time"
 
 	"github.com/kir/news-app/internal/domain"
 )
 
 // MockService implements PostService interface for testing
 type MockService struct {
-	CreateFunc       func(ctx context.Context, title, content string) (*domain.Post, error)
-	GetAllFunc       func(ctx context.Context) ([]*domain.Post, error)
-	GetByIDFunc      func(ctx context.Context, id string) (*domain.Post, error)
-	UpdateFunc       func(ctx context.Context, id, title, content string) error
-	DeleteFunc       func(ctx context.Context, id string) error
-	GetPaginatedFunc func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error)
-	GetRecentFunc    func(ctx context.Context, limit int) ([]*domain.Post, error)
+	CreateFunc               func(ctx context.Context, in domain.PostInput) (*domain.Post, error)
+	GetAllFunc               func(ctx context.Context) ([]*domain.Post, error)
+	GetByIDFunc              func(ctx context.Context, id string) (*domain.Post, error)
+	UpdateFunc               func(ctx context.Context, id string, in domain.PostInput) error
+	DeleteFunc               func(ctx context.Context, id string) error
+	GetPaginatedFunc         func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetFacetsFunc            func(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error)
+	GetArchiveFunc           func(ctx context.Context) ([]domain.ArchiveMonth, error)
+	GetRecentFunc            func(ctx context.Context, limit int) ([]*domain.Post, error)
+	RecordViewFunc           func(ctx context.Context, id string) error
+	FindSimilarFunc          func(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error)
+	GetDuplicateClustersFunc func(ctx context.Context) ([]domain.DuplicateCluster, error)
+	SetBreakingFunc          func(ctx context.Context, id string, d time.Duration) (*domain.Post, error)
+	GetBreakingFunc          func(ctx context.Context) ([]*domain.Post, error)
 }
 
-func (m *MockService) Create(ctx context.Context, title, content string) (*domain.Post, error) {
+func (m *MockService) Create(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
 	if m.CreateFunc != nil {
-		return m.CreateFunc(ctx, title, content)
+		return m.CreateFunc(ctx, in)
 	}
 	return nil, nil
 }
@@ -38,9 +46,9 @@ func (m *MockService) GetByID(ctx context.Context, id string) (*domain.Post, err
 	return nil, nil
 }
 
-func (m *MockService) Update(ctx context.Context, id, title, content string) error {
+func (m *MockService) Update(ctx context.Context, id string, in domain.PostInput) error {
 	if m.UpdateFunc != nil {
-		return m.UpdateFunc(ctx, id, title, content)
+		return m.UpdateFunc(ctx, id, in)
 	}
 	return nil
 }
@@ -52,9 +60,16 @@ func (m *MockService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *MockService) GetPaginated(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+func (m *MockService) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 	if m.GetPaginatedFunc != nil {
-		return m.GetPaginatedFunc(ctx, page, pageSize, search)
+		return m.GetPaginatedFunc(ctx, query)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetFacets(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+	if m.GetFacetsFunc != nil {
+		return m.GetFacetsFunc(ctx, query)
 	}
 	return nil, nil
 }
@@ -65,3 +80,65 @@ func (m *MockService) GetRecent(ctx context.Context, limit int) ([]*domain.Post,
 	}
 	return nil, nil
 }
+
+func (m *MockService) RecordView(ctx context.Context, id string) error {
+	if m.RecordViewFunc != nil {
+		return m.RecordViewFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockService) GetArchive(ctx context.Context) ([]domain.ArchiveMonth, error) {
+	if m.GetArchiveFunc != nil {
+		return m.GetArchiveFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) FindSimilar(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error) {
+	if m.FindSimilarFunc != nil {
+		return m.FindSimilarFunc(ctx, in)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetDuplicateClusters(ctx context.Context) ([]domain.DuplicateCluster, error) {
+	if m.GetDuplicateClustersFunc != nil {
+		return m.GetDuplicateClustersFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) SetBreaking(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+	if m.SetBreakingFunc != nil {
+		return m.SetBreakingFunc(ctx, id, d)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetBreaking(ctx context.Context) ([]*domain.Post, error) {
+	if m.GetBreakingFunc != nil {
+		return m.GetBreakingFunc(ctx)
+	}
+	return nil, nil
+}
+
+// MockPersonalFeed implements PersonalFeed interface for testing
+type MockPersonalFeed struct {
+	ForYouFunc     func(ctx context.Context, readerID string, page int) (*domain.FeedPage, error)
+	RecordReadFunc func(ctx context.Context, readerID string, p *domain.Post) error
+}
+
+func (m *MockPersonalFeed) ForYou(ctx context.Context, readerID string, page int) (*domain.FeedPage, error) {
+	if m.ForYouFunc != nil {
+		return m.ForYouFunc(ctx, readerID, page)
+	}
+	return nil, nil
+}
+
+func (m *MockPersonalFeed) RecordRead(ctx context.Context, readerID string, p *domain.Post) error {
+	if m.RecordReadFunc != nil {
+		return m.RecordReadFunc(ctx, readerID, p)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package post
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// Listing query parameters
+const (
+	paramPage     = "page"
+	paramPageSize = "page_size"
+	paramCursor   = "cursor"
+	paramSort     = "sort"
+	paramSearch   = "search"
+	paramCategory = "category"
+	paramTag      = "tag"
+	paramAuthor   = "author"
+	paramStatus   = "status"
+	paramFrom     = "from"
+	paramTo       = "to"
+
+	dateLayout      = "2006-01-02"
+	defaultPageSize = 9
+)
+
+// parseListQuery reads the listing filters from URL query values.
+// Invalid values are ignored rather than rejected so that hand-edited URLs still work.
+func parseListQuery(values url.Values) domain.PostQuery {
+	q := domain.PostQuery{
+		Page:     1,
+		PageSize: defaultPageSize,
+		Cursor:   values.Get(paramCursor),
+		Search:   strings.TrimSpace(values.Get(paramSearch)),
+		Category: strings.TrimSpace(values.Get(paramCategory)),
+		Author:   strings.TrimSpace(values.Get(paramAuthor)),
+	}
+
+	// Pages loaded by cursor are appended to the ones already shown,
+	// so the total is not needed again.
+	q.SkipCount = q.Cursor != ""
+
+	if p, err := strconv.Atoi(values.Get(paramPage)); err == nil && p > 0 {
+		q.Page = p
+	}
+	if s, err := strconv.Atoi(values.Get(paramPageSize)); err == nil && s > 0 {
+		q.PageSize = s
+	}
+
+	for _, tag := range values[paramTag] {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(q.Tags, tag) {
+			q.Tags = append(q.Tags, tag)
+		}
+	}
+
+	if sort, err := domain.ParsePostSort(values.Get(paramSort)); err == nil {
+		q.Sort = sort
+	}
+
+	switch status := domain.PostStatus(values.Get(paramStatus)); status {
+	case domain.PostStatusDraft, domain.PostStatusPublished:
+		q.Status = status
+	}
+
+	if from, err := time.ParseInLocation(dateLayout, values.Get(paramFrom), time.UTC); err == nil {
+		q.From = from
+	}
+	if to, err := time.ParseInLocation(dateLayout, values.Get(paramTo), time.UTC); err == nil {
+		// The "to" date is inclusive, so extend it to the end of that day.
+		q.To = to.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return q
+}
+
+// listQueryValues encodes q back into URL query values, omitting defaults.
+func listQueryValues(q domain.PostQuery) url.Values {
+	values := url.Values{}
+	if q.Page > 1 {
+		values.Set(paramPage, strconv.Itoa(q.Page))
+	}
+	if q.PageSize > 0 && q.PageSize != defaultPageSize {
+		values.Set(paramPageSize, strconv.Itoa(q.PageSize))
+	}
+	if q.Cursor != "" {
+		values.Set(paramCursor, q.Cursor)
+	}
+	if q.Search != "" {
+		values.Set(paramSearch, q.Search)
+	}
+	if q.Sort != "" {
+		values.Set(paramSort, string(q.Sort))
+	}
+	if q.Category != "" {
+		values.Set(paramCategory, q.Category)
+	}
+	for _, tag := range q.Tags {
+		values.Add(paramTag, tag)
+	}
+	if q.Author != "" {
+		values.Set(paramAuthor, q.Author)
+	}
+	if q.Status != "" {
+		values.Set(paramStatus, string(q.Status))
+	}
+	if !q.From.IsZero() {
+		values.Set(paramFrom, q.From.Format(dateLayout))
+	}
+	if !q.To.IsZero() {
+		values.Set(paramTo, q.To.Format(dateLayout))
+	}
+	return values
+}
+
+// listPage is the template data for the index page and the posts list fragment.
+// Query never carries a cursor, so the URL helpers always link to numbered pages.
+type listPage struct {
+	Posts       []*domain.Post
+	TotalCount  int64
+	Page        int
+	PageSize    int
+	TotalPages  int
+	Search      string
+	RecentPosts []*domain.Post
+	Query       domain.PostQuery
+	Facets      *domain.PostFacets
+	NextCursor  string
+	Archives    []domain.ArchiveMonth
+	Breaking    []*domain.Post
+
+	// Archive is the month shown by an archive page, whose date range comes
+	// from the path; Query then has no From and To.
+	Archive *domain.ArchiveMonth
+
+	// Feed is set when the "For you" feed is available. ForYou marks its
+	// pages, and Personalized whether it is ranked for the reader rather
+	// than the latest posts.
+	Feed         bool
+	ForYou       bool
+	Personalized bool
+}
+
+// BasePath returns the path the listing URLs are built on.
+func (p listPage) BasePath() string {
+	if p.Archive != nil {
+		return p.ArchiveURL(*p.Archive)
+	}
+	if p.ForYou {
+		return "/for-you"
+	}
+	return "/"
+}
+
+// FeedTabs reports whether the "Latest" and "For you" tabs are shown. They
+// switch between the two unfiltered listings only.
+func (p listPage) FeedTabs() bool {
+	return p.Feed && (p.ForYou || p.Archive == nil && p.Query.Search == "" && !p.HasFilters())
+}
+
+// URL returns the listing URL for q.
+func (p listPage) URL(q domain.PostQuery) string {
+	if encoded := listQueryValues(q).Encode(); encoded != "" {
+		return p.BasePath() + "?" + encoded
+	}
+	return p.BasePath()
+}
+
+// ArchiveURL returns the URL of the archive page of a month.
+func (p listPage) ArchiveURL(m domain.ArchiveMonth) string {
+	return fmt.Sprintf("/archive/%04d/%02d", m.Year, int(m.Month))
+}
+
+// PageURL returns the URL of the given page with the current filters.
+func (p listPage) PageURL(page int) string {
+	q := p.Query
+	q.Page = page
+	return p.URL(q)
+}
+
+// MoreURL returns the URL loading the posts after the current ones, or an
+// empty string when there are none.
+func (p listPage) MoreURL() string {
+	if p.NextCursor == "" {
+		return ""
+	}
+	q := p.Query
+	q.Page = 0
+	q.Cursor = p.NextCursor
+	return p.URL(q)
+}
+
+// Live reports whether posts created by anyone are inserted into the grid as
+// they are published over /events. That is only the case on the first page of
+// the unfiltered listing, where a new post belongs at the top.
+func (p listPage) Live() bool {
+	q := p.Query
+	return p.Archive == nil && !p.ForYou && p.Page <= 1 &&
+		q.Search == "" && q.Category == "" && len(q.Tags) == 0 && q.Author == "" && q.Status == "" &&
+		q.From.IsZero() && q.To.IsZero() &&
+		(q.Sort == "" || q.Sort == domain.SortNewest)
+}
+
+// FilterURL returns the URL with a single-valued filter set to value, or
+// removed when value is empty. Changing a filter starts again from page one.
+func (p listPage) FilterURL(name, value string) string {
+	q := p.Query
+	q.Page = 1
+	switch name {
+	case paramSearch:
+		q.Search = value
+	case paramCategory:
+		q.Category = value
+	case paramAuthor:
+		q.Author = value
+	case paramStatus:
+		q.Status = domain.PostStatus(value)
+	case paramFrom:
+		q.From = time.Time{}
+	case paramTo:
+		q.To = time.Time{}
+	}
+	return p.URL(q)
+}
+
+// ToggleTagURL returns the URL with tag added to or removed from the tag filter.
+func (p listPage) ToggleTagURL(tag string) string {
+	q := p.Query
+	q.Page = 1
+	if p.TagSelected(tag) {
+		q.Tags = slices.DeleteFunc(slices.Clone(q.Tags), func(t string) bool { return t == tag })
+	} else {
+		q.Tags = append(slices.Clone(q.Tags), tag)
+	}
+	return p.URL(q)
+}
+
+// TagSelected reports whether tag is part of the current filter.
+func (p listPage) TagSelected(tag string) bool {
+	return slices.Contains(p.Query.Tags, tag)
+}
+
+// ClearFiltersURL returns the URL with every filter removed, keeping the search text and order.
+func (p listPage) ClearFiltersURL() string {
+	return p.URL(domain.PostQuery{Search: p.Query.Search, Sort: p.Query.Sort, PageSize: p.Query.PageSize})
+}
+
+// sortLabels are the reader-facing names of the listing orders.
+var sortLabels = map[domain.PostSort]string{
+	domain.SortRelevance: "Relevance",
+	domain.SortNewest:    "Newest first",
+	domain.SortOldest:    "Oldest first",
+	domain.SortUpdated:   "Recently updated",
+	domain.SortViews:     "Most viewed",
+	domain.SortTitle:     "Title A–Z",
+}
+
+// sortOption is an entry of the sort select.
+type sortOption struct {
+	Value    domain.PostSort
+	Label    string
+	Selected bool
+}
+
+// SortOptions lists the orders available for the current query. Relevance is
+// only offered while searching.
+func (p listPage) SortOptions() []sortOption {
+	current := p.Query.EffectiveSort()
+	options := make([]sortOption, 0, len(domain.PostSorts))
+	for _, sort := range domain.PostSorts {
+		if sort == domain.SortRelevance && p.Query.Search == "" {
+			continue
+		}
+		options = append(options, sortOption{Value: sort, Label: sortLabels[sort], Selected: sort == current})
+	}
+	return options
+}
+
+// HasFilters reports whether any filter besides the search text is active.
+func (p listPage) HasFilters() bool {
+	q := p.Query
+	return q.Category != "" || len(q.Tags) > 0 || q.Author != "" || q.Status != "" || !q.From.IsZero() || !q.To.IsZero()
+}
+
+// FromValue returns the "from" filter formatted for a date input.
+func (p listPage) FromValue() string {
+	if p.Query.From.IsZero() {
+		return ""
+	}
+	return p.Query.From.Format(dateLayout)
+}
+
+// ToValue returns the "to" filter formatted for a date input.
+func (p listPage) ToValue() string {
+	if p.Query.To.IsZero() {
+		return ""
+	}
+	return p.Query.To.Format(dateLayout)
+}
+
+// postInputFromForm reads the editable post fields from a parsed form.
+func postInputFromForm(form url.Values) domain.PostInput {
+	return domain.PostInput{
+		Title:    form.Get("title"),
+		Content:  form.Get("content"),
+		Category: form.Get("category"),
+		Tags:     domain.ParseTags(form.Get("tags")),
+		Author:   form.Get("author"),
+		ImageURL: form.Get("image_url"),
+		Status:   domain.PostStatus(form.Get("status")),
+
+		Contributors: contributorsFromForm(form),
+
+		SEOTitle:       form.Get("seo_title"),
+		SEODescription: form.Get("seo_description"),
+	}
+}
+
+// contributorsFromForm pairs the rows of the contributor picker: the n-th
+// contributor_name goes with the n-th contributor_role.
+func contributorsFromForm(form url.Values) []domain.Contributor {
+	names, roles := form["contributor_name"], form["contributor_role"]
+	contributors := make([]domain.Contributor, 0, len(names))
+	for i, name := range names {
+		c := domain.Contributor{Name: name}
+		if i < len(roles) {
+			c.Role = domain.ContributorRole(roles[i])
+		}
+		contributors = append(contributors, c)
+	}
+	return contributors
+}
@@ -0,0 +1,141 @@
+package post
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  domain.PostQuery
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  domain.PostQuery{Page: 1, PageSize: 9},
+		},
+		{
+			name:  "all filters",
+			query: "page=2&page_size=12&search=+vote+&category=politics&tag=Budget&tag=parliament&tag=budget&author=anna&status=draft&from=2025-03-01&to=2025-03-31",
+			want: domain.PostQuery{
+				Page:     2,
+				PageSize: 12,
+				Search:   "vote",
+				Category: "politics",
+				Tags:     []string{"budget", "parliament"},
+				Author:   "anna",
+				Status:   domain.PostStatusDraft,
+				From:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2025, 3, 31, 23, 59, 59, 999999999, time.UTC),
+			},
+		},
+		{
+			name:  "invalid values are ignored",
+			query: "page=-1&page_size=abc&status=archived&from=yesterday&sort=popular",
+			want:  domain.PostQuery{Page: 1, PageSize: 9},
+		},
+		{
+			name:  "cursor skips the count",
+			query: "cursor=abc&category=politics",
+			want:  domain.PostQuery{Page: 1, PageSize: 9, Cursor: "abc", SkipCount: true, Category: "politics"},
+		},
+		{
+			name:  "sort",
+			query: "sort=views",
+			want:  domain.PostQuery{Page: 1, PageSize: 9, Sort: domain.SortViews},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, parseListQuery(values))
+		})
+	}
+}
+
+func TestListQuery_RoundTrip(t *testing.T) {
+	values, err := url.ParseQuery("page=3&search=vote&sort=oldest&category=politics&tag=budget&tag=parliament&author=anna&status=published&from=2025-03-01&to=2025-03-31")
+	assert.NoError(t, err)
+
+	q := parseListQuery(values)
+	assert.Equal(t, values, listQueryValues(q))
+	assert.Equal(t, q, parseListQuery(listQueryValues(q)))
+}
+
+func TestListPage_URLs(t *testing.T) {
+	page := listPage{Query: domain.PostQuery{
+		Page:     3,
+		PageSize: 9,
+		Search:   "vote",
+		Category: "politics",
+		Tags:     []string{"budget"},
+	}}
+
+	assert.Equal(t, "/?category=politics&page=2&search=vote&tag=budget", page.PageURL(2))
+	assert.Equal(t, "/?search=vote&tag=budget", page.FilterURL(paramCategory, ""))
+	assert.Equal(t, "/?category=politics&search=vote", page.ToggleTagURL("budget"))
+	assert.Equal(t, "/?category=politics&search=vote&tag=budget&tag=economy", page.ToggleTagURL("economy"))
+	assert.Equal(t, "/?search=vote", page.ClearFiltersURL())
+	assert.Equal(t, []string{"budget"}, page.Query.Tags, "URL helpers must not modify the current query")
+	assert.True(t, page.HasFilters())
+	assert.Empty(t, page.MoreURL())
+	page.NextCursor = "abc"
+	assert.Equal(t, "/?category=politics&cursor=abc&search=vote&tag=budget", page.MoreURL())
+	assert.Equal(t, "/", listPage{}.URL(domain.PostQuery{Page: 1, PageSize: 9}))
+}
+
+func TestListPage_SortOptions(t *testing.T) {
+	selected := func(options []sortOption) domain.PostSort {
+		for _, o := range options {
+			if o.Selected {
+				return o.Value
+			}
+		}
+		return ""
+	}
+
+	browsing := listPage{Query: domain.PostQuery{}}.SortOptions()
+	assert.Equal(t, domain.SortNewest, browsing[0].Value, "relevance is only offered while searching")
+	assert.Equal(t, domain.SortNewest, selected(browsing))
+
+	searching := listPage{Query: domain.PostQuery{Search: "vote"}}.SortOptions()
+	assert.Equal(t, domain.SortRelevance, selected(searching))
+
+	page := listPage{Query: domain.PostQuery{Sort: domain.SortTitle, Category: "politics"}}
+	assert.Equal(t, domain.SortTitle, selected(page.SortOptions()))
+	assert.Equal(t, "/?category=politics&page=2&sort=title", page.PageURL(2))
+	assert.Equal(t, "/?sort=title", page.ClearFiltersURL())
+}
+
+func TestListPage_ArchiveURLs(t *testing.T) {
+	page := listPage{
+		Query:   domain.PostQuery{Page: 1, PageSize: 9, Tags: []string{"budget"}},
+		Archive: &domain.ArchiveMonth{Year: 2025, Month: time.March},
+	}
+
+	assert.Equal(t, "/archive/2025/03", page.BasePath())
+	assert.Equal(t, "/archive/2025/03?page=2&tag=budget", page.PageURL(2))
+	assert.Equal(t, "/archive/2025/03", page.ClearFiltersURL())
+	assert.Equal(t, "/archive/2024/11", page.ArchiveURL(domain.ArchiveMonth{Year: 2024, Month: time.November}))
+	assert.Equal(t, "/", listPage{}.BasePath())
+}
+
+func TestListPage_Live(t *testing.T) {
+	assert.True(t, listPage{Page: 1, Query: domain.PostQuery{Page: 1, PageSize: 9}}.Live())
+	assert.True(t, listPage{Page: 1, Query: domain.PostQuery{Sort: domain.SortNewest}}.Live())
+
+	assert.False(t, listPage{Page: 2}.Live())
+	assert.False(t, listPage{Query: domain.PostQuery{Search: "vote"}}.Live())
+	assert.False(t, listPage{Query: domain.PostQuery{Tags: []string{"budget"}}}.Live())
+	assert.False(t, listPage{Query: domain.PostQuery{Sort: domain.SortTitle}}.Live())
+	assert.False(t, listPage{Archive: &domain.ArchiveMonth{Year: 2025, Month: time.March}}.Live())
+}
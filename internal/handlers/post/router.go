@@ -4,6 +4,9 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/kir/news-app/internal/apiauth"
+	"github.com/kir/news-app/internal/domain"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
@@ -39,6 +42,18 @@ func RegisterRoutes(r chi.Router, h *Handler, logger *zap.Logger) {
 	// Web routes
 	r.Group(func(r chi.Router) {
 		r.Get("/", h.Index)
+		r.Get("/for-you", h.ForYou)
+		r.Get("/archive/{year}/{month}", h.Archive)
+		r.Get("/duplicates", h.Duplicates)
+	})
+
+	// JSON API
+	r.Route("/api/posts", func(r chi.Router) {
+		r.With(h.requireScope(domain.ScopePostsRead)).Get("/", h.ListPosts)
+		r.With(h.requireScope(domain.ScopePostsRead)).Get("/{id}", h.GetPost)
+		r.With(h.requireScope(domain.ScopePostsWrite)).Post("/", h.CreatePost)
+		r.With(h.requireScope(domain.ScopePostsWrite)).Put("/{id}", h.UpdatePost)
+		r.With(h.requireScope(domain.ScopePostsWrite)).Delete("/{id}", h.DeletePost)
 	})
 
 	// HTMX routes
@@ -48,7 +63,19 @@ func RegisterRoutes(r chi.Router, h *Handler, logger *zap.Logger) {
 		r.Get("/posts/{id}/delete", h.DeleteForm)
 		r.Get("/posts/new", h.CreateForm)
 		r.Post("/posts", h.Create)
+		r.Post("/posts/similar", h.Similar)
 		r.Put("/posts/{id}", h.Update)
 		r.Delete("/posts/{id}", h.Delete)
+		r.Post("/posts/{id}/breaking", h.SetBreaking)
+		r.Delete("/posts/{id}/breaking", h.ClearBreaking)
 	})
 }
+
+// requireScope guards a JSON API route with API keys granted scope, or lets
+// every request through when the handler has no API keys.
+func (h *Handler) requireScope(scope domain.APIScope) func(http.Handler) http.Handler {
+	if h.apiKeys == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return apiauth.Require(h.apiKeys, scope, h.logger)
+}
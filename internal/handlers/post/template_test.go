@@ -3,45 +3,22 @@ package post
 import (
 	"bytes"
 	"html/template"
+	"strings"
 	"testing"
 
 	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
 
 	"github.com/stretchr/testify/assert"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestTemplates_Render(t *testing.T) {
-	tmpl := template.New("")
-	tmpl.Funcs(template.FuncMap{
-		"add":      func(a, b int) int { return a + b },
-		"subtract": func(a, b int) int { return a - b },
-		"multiply": func(a, b int) int { return a * b },
-		"sequence": func(start, end int) []int {
-			var result []int
-			for i := start; i <= end; i++ {
-				result = append(result, i)
-			}
-			return result
-		},
-		"objectIDToString": func(id primitive.ObjectID) string { return id.Hex() },
-	})
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/post/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("../../../templates/modals/*.html"))
+	tmpl := template.Must(templates.Parse("../../../templates"))
 
 	t.Run("index_template_with_posts", func(t *testing.T) {
 		var buf bytes.Buffer
 		posts := []*domain.Post{{Title: "Test Post", Content: "Test content"}}
-		data := struct {
-			Posts       []*domain.Post
-			TotalCount  int64
-			Page        int
-			PageSize    int
-			TotalPages  int
-			Search      string
-			RecentPosts []*domain.Post
-		}{
+		data := listPage{
 			Posts:       posts,
 			TotalCount:  1,
 			Page:        1,
@@ -70,6 +47,21 @@ func TestTemplates_Render(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, buf.String(), "Edit Post")
 		assert.Contains(t, buf.String(), "Edit content")
+		assert.Equal(t, 2, strings.Count(buf.String(), `name="contributor_name"`), "one empty row and the row template")
+	})
+
+	t.Run("edit_form_contributors", func(t *testing.T) {
+		var buf bytes.Buffer
+		post := &domain.Post{Title: "Edit Post", Content: "Edit content", Contributors: []domain.Contributor{
+			{Name: "Anna", Role: domain.RoleAuthor},
+			{Name: "Boris", Role: domain.RolePhotographer},
+		}}
+		err := tmpl.ExecuteTemplate(&buf, "modals/edit-content", post)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), `value="Anna"`)
+		assert.Contains(t, buf.String(), `value="Boris"`)
+		assert.Contains(t, buf.String(), `<option value="photographer" selected>Photographer</option>`)
+		assert.Contains(t, buf.String(), `hx-get="/authors"`)
 	})
 
 	t.Run("view_content_template", func(t *testing.T) {
@@ -88,6 +80,32 @@ func TestTemplates_Render(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, buf.String(), "Delete Post")
 	})
+
+	t.Run("search_highlight_template", func(t *testing.T) {
+		var buf bytes.Buffer
+		post := &domain.Post{
+			Title:   "Election <b>night</b>",
+			Content: "Full content",
+			Highlight: &domain.Highlight{
+				Title: []domain.TextFragment{
+					{Text: "Election", Match: true},
+					{Text: " <b>night</b>"},
+				},
+				Snippet: []domain.TextFragment{
+					{Text: "… turnout in the "},
+					{Text: "<script>election</script>", Match: true},
+					{Text: " …"},
+				},
+			},
+		}
+		err := tmpl.ExecuteTemplate(&buf, "post/post-item", struct{ Posts []*domain.Post }{Posts: []*domain.Post{post}})
+		assert.NoError(t, err)
+		out := buf.String()
+		assert.Contains(t, out, "Election</mark> &lt;b&gt;night&lt;/b&gt;")
+		assert.Contains(t, out, "&lt;script&gt;election&lt;/script&gt;</mark> …")
+		assert.NotContains(t, out, "<script>election")
+		assert.NotContains(t, out, "Full content")
+	})
 }
 
 func TestTemplates_ErrorHandling(t *testing.T) {
@@ -0,0 +1,20 @@
+package presence
+
+import "time"
+
+const (
+	// NameCookie remembers the name an editor last entered, so that they do
+	// not have to enter it in every edit form.
+	NameCookie = "editor_name"
+	// DefaultName stands in for editors that did not enter a name.
+	DefaultName = "Someone"
+
+	nameCookieMaxAge = 365 * 24 * time.Hour
+	maxNameLength    = 50
+	sessionBytes     = 16
+)
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
@@ -0,0 +1,10 @@
+package presence
+
+// Error messages
+const (
+	ErrInvalidFormData = "Invalid form data"
+	ErrInvalidSession  = "Invalid session"
+	ErrInvalidPostID   = "Invalid post ID"
+	ErrPostNotFound    = "Post not found"
+	ErrInternalServer  = "Internal server error"
+)
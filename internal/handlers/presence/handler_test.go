@@ -0,0 +1,157 @@
+package presence
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/presence"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// post1 is the post the tests edit; no other post exists.
+var post1 = primitive.NewObjectID().Hex()
+
+func setupTestHandler() (*Handler, *presence.Tracker) {
+	tracker := presence.NewTracker()
+	posts := &MockPostService{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			if id != post1 {
+				return nil, fmt.Errorf("failed to get post: %w", domain.ErrPostNotFound)
+			}
+			return &domain.Post{Title: "Budget vote"}, nil
+		},
+	}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	return New(tracker, posts, tmpl, logger), tracker
+}
+
+func withPostID(req *http.Request, id string) *http.Request {
+	chiCtx := chi.NewRouteContext()
+	chiCtx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+}
+
+func heartbeat(h *Handler, postID string, form url.Values, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/posts/"+postID+"/presence", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	h.Heartbeat(w, withPostID(req, postID))
+	return w
+}
+
+var sessionInput = regexp.MustCompile(`name="session" value="([0-9a-f]{32})"`)
+
+func TestHandler_Heartbeat(t *testing.T) {
+	handler, tracker := setupTestHandler()
+
+	w := heartbeat(handler, post1, url.Values{}, &http.Cookie{Name: NameCookie, Value: url.QueryEscape("Anna K.")})
+	require.Equal(t, http.StatusOK, w.Code)
+	match := sessionInput.FindStringSubmatch(w.Body.String())
+	require.NotNil(t, match, "the first heartbeat is given a session")
+	anna := match[1]
+	assert.Contains(t, w.Body.String(), `value="Anna K."`, "the name is taken from the cookie")
+	assert.Contains(t, w.Body.String(), `hx-trigger="every 10s, change"`)
+	assert.NotContains(t, w.Body.String(), "editing this post")
+	assert.Empty(t, w.Result().Cookies())
+
+	w = heartbeat(handler, post1, url.Values{"name": {"  Boris  "}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Anna K. is editing this post.")
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, NameCookie, cookies[0].Name)
+	assert.Equal(t, "Boris", cookies[0].Value)
+
+	w = heartbeat(handler, post1, url.Values{"session": {anna}, "name": {"Anna K."}}, &http.Cookie{Name: NameCookie, Value: url.QueryEscape("Anna K.")})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `value="`+anna+`"`, "the session is kept")
+	assert.Contains(t, w.Body.String(), "Boris is editing this post.")
+	assert.Empty(t, w.Result().Cookies(), "an unchanged name is not stored again")
+
+	assert.Equal(t, []string{"Anna K.", "Boris"}, tracker.Editors(post1).Names(""))
+}
+
+func TestHandler_HeartbeatWithoutName(t *testing.T) {
+	handler, tracker := setupTestHandler()
+
+	w := heartbeat(handler, post1, url.Values{})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{DefaultName}, tracker.Editors(post1).Names(""))
+}
+
+func TestHandler_HeartbeatInvalidSession(t *testing.T) {
+	handler, tracker := setupTestHandler()
+
+	w := heartbeat(handler, post1, url.Values{"session": {"not-a-session"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrInvalidSession, w.Header().Get(HXErrorHeader))
+	assert.Empty(t, tracker.All())
+}
+
+func TestHandler_HeartbeatUnknownPost(t *testing.T) {
+	handler, tracker := setupTestHandler()
+
+	w := heartbeat(handler, "not-a-post", url.Values{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrInvalidPostID, w.Header().Get(HXErrorHeader))
+
+	w = heartbeat(handler, primitive.NewObjectID().Hex(), url.Values{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ErrPostNotFound, w.Header().Get(HXErrorHeader))
+
+	assert.Empty(t, tracker.All(), "no badge is broadcast for a post that does not exist")
+}
+
+func TestHandler_Leave(t *testing.T) {
+	handler, tracker := setupTestHandler()
+	session := strings.Repeat("ab", sessionBytes)
+	tracker.Heartbeat("post-1", session, "Anna")
+
+	req := httptest.NewRequest(http.MethodDelete, "/posts/post-1/presence?session=nope", nil)
+	w := httptest.NewRecorder()
+	handler.Leave(w, withPostID(req, "post-1"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, tracker.All(), 1)
+
+	req = httptest.NewRequest(http.MethodDelete, "/posts/post-1/presence?session="+session, nil)
+	w = httptest.NewRecorder()
+	handler.Leave(w, withPostID(req, "post-1"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, tracker.All())
+}
+
+func TestHandler_Badges(t *testing.T) {
+	handler, tracker := setupTestHandler()
+	tracker.Heartbeat("post-1", "s1", "Anna")
+	tracker.Heartbeat("post-2", "s2", "Boris")
+	tracker.Heartbeat("post-2", "s3", "Clara")
+	tracker.Heartbeat("post-2", "s4", "Dmitri")
+
+	w := httptest.NewRecorder()
+	handler.Badges(w, httptest.NewRequest(http.MethodGet, "/presence", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `id="presence-post-1" hx-swap-oob="true"`)
+	assert.Contains(t, body, "Anna is editing")
+	assert.Contains(t, body, `id="presence-post-2" hx-swap-oob="true"`)
+	assert.Contains(t, body, "Boris and 2 others are editing")
+}
@@ -0,0 +1,182 @@
+package presence
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/presence"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// Handler handles the heartbeats of open edit forms and renders who is editing
+type Handler struct {
+	tracker   Tracker
+	posts     PostService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new presence handler
+func New(tracker Tracker, posts PostService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		tracker:   tracker,
+		posts:     posts,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// editingView is the data of the presence part of the edit form. Others are
+// the names of everyone else editing the post.
+type editingView struct {
+	PostID   string
+	Session  string
+	Name     string
+	MaxName  int
+	Others   []string
+	Interval string
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Heartbeat handles the periodic report of an open edit form. The first
+// heartbeat of a form comes without a session and is given one. Only posts
+// that exist are tracked, as every tracked post is broadcast to all readers.
+func (h *Handler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "id")
+	if _, err := primitive.ObjectIDFromHex(postID); err != nil {
+		h.handleError(w, err, ErrInvalidPostID, http.StatusBadRequest)
+		return
+	}
+	if _, err := h.posts.GetByID(r.Context(), postID); err != nil {
+		if errors.Is(err, domain.ErrPostNotFound) {
+			h.handleError(w, err, ErrPostNotFound, http.StatusNotFound)
+		} else {
+			h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	session := r.PostForm.Get("session")
+	if session == "" {
+		var err error
+		if session, err = newSession(); err != nil {
+			h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+			return
+		}
+	} else if !validSession(session) {
+		h.handleError(w, errors.New("malformed session"), ErrInvalidSession, http.StatusBadRequest)
+		return
+	}
+
+	var name string
+	if r.PostForm.Has("name") {
+		name = cleanName(r.PostForm.Get("name"))
+		h.rememberName(w, r, name)
+	} else if c, err := r.Cookie(NameCookie); err == nil {
+		if value, err := url.QueryUnescape(c.Value); err == nil {
+			name = cleanName(value)
+		}
+	}
+
+	displayName := name
+	if displayName == "" {
+		displayName = DefaultName
+	}
+	editors := h.tracker.Heartbeat(postID, session, displayName)
+
+	view := editingView{
+		PostID:   postID,
+		Session:  session,
+		Name:     name,
+		MaxName:  maxNameLength,
+		Others:   editors.Names(session),
+		Interval: presence.HeartbeatInterval.String(),
+	}
+	if err := h.templates.ExecuteTemplate(w, "post/presence", view); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Leave handles the closing of an edit form
+func (h *Handler) Leave(w http.ResponseWriter, r *http.Request) {
+	session := r.URL.Query().Get("session")
+	if !validSession(session) {
+		h.handleError(w, errors.New("malformed session"), ErrInvalidSession, http.StatusBadRequest)
+		return
+	}
+	h.tracker.Leave(chi.URLParam(r, "id"), session)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Badges renders the editing badges of every post that is being edited as
+// out-of-band swaps, for a page that was just loaded.
+func (h *Handler) Badges(w http.ResponseWriter, r *http.Request) {
+	if err := h.templates.ExecuteTemplate(w, "post/presence-badges", h.tracker.All()); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// rememberName stores name in a cookie when it changed, or removes the
+// cookie when the name was cleared.
+func (h *Handler) rememberName(w http.ResponseWriter, r *http.Request, name string) {
+	if c, err := r.Cookie(NameCookie); err == nil && c.Value == url.QueryEscape(name) {
+		return
+	}
+	cookie := &http.Cookie{
+		Name:     NameCookie,
+		Value:    url.QueryEscape(name),
+		Path:     "/",
+		MaxAge:   int(nameCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if name == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+func newSession() (string, error) {
+	b := make([]byte, sessionBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validSession(session string) bool {
+	if len(session) != 2*sessionBytes {
+		return false
+	}
+	_, err := hex.DecodeString(session)
+	return err == nil
+}
+
+// cleanName collapses whitespace and cuts the name to maxNameLength runes.
+func cleanName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = strings.TrimSpace(string(runes[:maxNameLength]))
+	}
+	return name
+}
@@ -0,0 +1,18 @@
+package presence
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/presence"
+)
+
+type Tracker interface {
+	Heartbeat(postID, session, name string) presence.PostEditors
+	Leave(postID, session string)
+	All() []presence.PostEditors
+}
+
+type PostService interface {
+	GetByID(ctx context.Context, id string) (*domain.Post, error)
+}
@@ -0,0 +1,19 @@
+package presence
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockPostService is a mock implementation of PostService
+type MockPostService struct {
+	GetByIDFunc func(ctx context.Context, id string) (*domain.Post, error)
+}
+
+func (m *MockPostService) GetByID(ctx context.Context, id string) (*domain.Post, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
@@ -0,0 +1,12 @@
+package presence
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the presence handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/presence", h.Badges)
+	r.Post("/posts/{id}/presence", h.Heartbeat)
+	r.Delete("/posts/{id}/presence", h.Leave)
+}
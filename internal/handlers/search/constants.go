@@ -0,0 +1,6 @@
+package search
+
+// HTMX headers
+const (
+	HXErrorHeader = "HX-Error-Message"
+)
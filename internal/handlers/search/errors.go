@@ -0,0 +1,7 @@
+package search
+
+// Error messages
+const (
+	ErrFailedToLoadSuggestions = "Failed to load suggestions"
+	ErrInternalServer          = "Internal server error"
+)
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func setupTestHandler() (*Handler, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	handler := New(mockService, tmpl, logger)
+	return handler, mockService
+}
+
+func TestHandler_Suggest(t *testing.T) {
+	handler, mockService := setupTestHandler()
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSuggest    func(ctx context.Context, text string, limit int) (*domain.Suggestions, error)
+		expectedStatus int
+		expectedBody   []string
+		unexpectedBody []string
+	}{
+		{
+			name:  "renders suggestions",
+			query: "/search/suggest?search=elec",
+			mockSuggest: func(ctx context.Context, text string, limit int) (*domain.Suggestions, error) {
+				assert.Equal(t, "elec", text)
+				assert.Equal(t, suggestLimit, limit)
+				return &domain.Suggestions{
+					Query:   text,
+					Titles:  []domain.TitleSuggestion{{ID: "abc", Title: "Election <night>"}},
+					Popular: []domain.PopularQuery{{Term: "election results", Count: 3}},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`hx-get="/posts/abc"`, "Election &lt;night&gt;", `data-term="election results"`, "Popular searches"},
+		},
+		{
+			name:  "empty suggestions render nothing",
+			query: "/search/suggest?search=zzz",
+			mockSuggest: func(ctx context.Context, text string, limit int) (*domain.Suggestions, error) {
+				return &domain.Suggestions{Query: text}, nil
+			},
+			expectedStatus: http.StatusOK,
+			unexpectedBody: []string{"Posts", "Popular searches"},
+		},
+		{
+			name:  "service error",
+			query: "/search/suggest?search=elec",
+			mockSuggest: func(ctx context.Context, text string, limit int) (*domain.Suggestions, error) {
+				return nil, assert.AnError
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.SuggestFunc = tt.mockSuggest
+
+			req := httptest.NewRequest(http.MethodGet, tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.Suggest(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for _, s := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.unexpectedBody {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, ErrFailedToLoadSuggestions, w.Header().Get(HXErrorHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package search
+
+import (
+	"html/template"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// suggestLimit is the number of suggestions of each kind shown under the search box.
+const suggestLimit = 5
+
+// Handler handles HTTP requests for search suggestions
+type Handler struct {
+	service   SuggestService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new search handler
+func New(service SuggestService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Suggest renders title completions and popular queries for the typed text
+func (h *Handler) Suggest(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := h.service.Suggest(r.Context(), r.URL.Query().Get("search"), suggestLimit)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadSuggestions, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "search/suggestions", suggestions); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type SuggestService interface {
+	Suggest(ctx context.Context, text string, limit int) (*domain.Suggestions, error)
+}
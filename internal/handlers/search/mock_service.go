@@ -0,0 +1,19 @@
+package search
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService implements SuggestService interface for testing
+type MockService struct {
+	SuggestFunc func(ctx context.Context, text string, limit int) (*domain.Suggestions, error)
+}
+
+func (m *MockService) Suggest(ctx context.Context, text string, limit int) (*domain.Suggestions, error) {
+	if m.SuggestFunc != nil {
+		return m.SuggestFunc(ctx, text, limit)
+	}
+	return &domain.Suggestions{}, nil
+}
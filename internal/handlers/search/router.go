@@ -0,0 +1,10 @@
+package search
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the search handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/search/suggest", h.Suggest)
+}
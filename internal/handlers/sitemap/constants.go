@@ -0,0 +1,13 @@
+package sitemap
+
+// Response headers
+const (
+	ContentTypeXML = "application/xml; charset=utf-8"
+	CacheControl   = "public, max-age=3600"
+)
+
+// Google News publication
+const (
+	publicationName     = "News Portal"
+	publicationLanguage = "en"
+)
@@ -0,0 +1,8 @@
+package sitemap
+
+// Error messages
+const (
+	ErrFailedToLoadSitemap   = "Failed to load sitemap"
+	ErrFailedToRenderSitemap = "Failed to render sitemap"
+	ErrSitemapNotFound       = "Sitemap not found"
+)
@@ -0,0 +1,146 @@
+package sitemap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/sitemap"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func setupTestHandler() (http.Handler, *MockService) {
+	mockService := &MockService{}
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, "https://news.example/", logger))
+	return r, mockService
+}
+
+func serve(router http.Handler, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w
+}
+
+func TestHandler_Index(t *testing.T) {
+	router, mockService := setupTestHandler()
+
+	tests := []struct {
+		name      string
+		count     int64
+		wantPages int
+	}{
+		{name: "empty site", count: 0, wantPages: 1},
+		{name: "exactly one sitemap", count: sitemap.MaxURLs, wantPages: 1},
+		{name: "split", count: 2*sitemap.MaxURLs + 1, wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.CountPublishedFunc = func(ctx context.Context) (int64, error) {
+				return tt.count, nil
+			}
+
+			w := serve(router, "/sitemap.xml")
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, ContentTypeXML, w.Header().Get("Content-Type"))
+			body := w.Body.String()
+			assert.Contains(t, body, "<sitemapindex")
+			assert.Equal(t, tt.wantPages, strings.Count(body, "/sitemaps/posts-"))
+			assert.Contains(t, body, "<loc>https://news.example/sitemaps/posts-1.xml</loc>")
+			assert.Contains(t, body, "<loc>https://news.example/sitemaps/news.xml</loc>")
+		})
+	}
+}
+
+func TestHandler_Posts(t *testing.T) {
+	router, mockService := setupTestHandler()
+	created := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	posts := []*domain.Post{
+		{ID: primitive.NewObjectID(), Title: "Updated", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Legacy", CreatedAt: created},
+	}
+
+	var gotQuery domain.SitemapQuery
+	mockService.CountPublishedFunc = func(ctx context.Context) (int64, error) {
+		return sitemap.MaxURLs + 2, nil
+	}
+	mockService.EachPublishedFunc = func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+		gotQuery = query
+		for _, p := range posts {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := serve(router, "/sitemaps/posts-2.xml")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.SitemapQuery{Offset: sitemap.MaxURLs, Limit: sitemap.MaxURLs}, gotQuery)
+	body := w.Body.String()
+	assert.Contains(t, body, "<loc>https://news.example/posts/"+posts[0].ID.Hex()+"</loc><lastmod>2025-03-14T10:00:00Z</lastmod>")
+	assert.Contains(t, body, "<loc>https://news.example/posts/"+posts[1].ID.Hex()+"</loc><lastmod>2025-03-14T09:00:00Z</lastmod>")
+	assert.NotContains(t, body, "news:news")
+
+	for _, url := range []string{"/sitemaps/posts-3.xml", "/sitemaps/posts-0.xml", "/sitemaps/posts-x.xml"} {
+		assert.Equal(t, http.StatusNotFound, serve(router, url).Code, url)
+	}
+}
+
+func TestHandler_News(t *testing.T) {
+	router, mockService := setupTestHandler()
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget vote", CreatedAt: time.Now()}
+
+	var gotQuery domain.SitemapQuery
+	mockService.EachPublishedFunc = func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+		gotQuery = query
+		return fn(post)
+	}
+
+	w := serve(router, "/sitemaps/news.xml")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, gotQuery.NewestFirst)
+	assert.Equal(t, int64(sitemap.MaxNewsURLs), gotQuery.Limit)
+	assert.WithinDuration(t, time.Now().Add(-sitemap.NewsWindow), gotQuery.Since, time.Minute)
+	assert.Contains(t, w.Body.String(), "<news:name>News Portal</news:name>")
+	assert.Contains(t, w.Body.String(), "<news:title>Budget vote</news:title>")
+}
+
+func TestHandler_StreamErrors(t *testing.T) {
+	router, mockService := setupTestHandler()
+
+	mockService.EachPublishedFunc = func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+		return assert.AnError
+	}
+	w := serve(router, "/sitemaps/news.xml")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+
+	mockService.EachPublishedFunc = func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+		if err := fn(&domain.Post{ID: primitive.NewObjectID(), Title: "First"}); err != nil {
+			return err
+		}
+		return assert.AnError
+	}
+	w = serve(router, "/sitemaps/news.xml")
+	assert.Equal(t, http.StatusOK, w.Code, "the status is sent with the first entry")
+	assert.NotContains(t, w.Body.String(), "</urlset>")
+
+	mockService.CountPublishedFunc = func(ctx context.Context) (int64, error) {
+		return 0, assert.AnError
+	}
+	assert.Equal(t, http.StatusInternalServerError, serve(router, "/sitemap.xml").Code)
+}
@@ -0,0 +1,157 @@
+package sitemap
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/feed"
+	"github.com/kir/news-app/internal/sitemap"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler serves the sitemaps of published posts
+type Handler struct {
+	service SitemapService
+	urls    feed.URLs
+	logger  *zap.Logger
+}
+
+// New creates a new sitemap handler. Sitemap URLs are built on baseURL.
+func New(service SitemapService, baseURL string, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		urls:    feed.URLs{BaseURL: strings.TrimRight(baseURL, "/")},
+		logger:  logger,
+	}
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Del("Cache-Control")
+	http.Error(w, message, status)
+}
+
+// Index serves the sitemap index listing one post sitemap per MaxURLs posts
+// and the news sitemap.
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	pages, err := h.pages(r)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadSitemap, http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]sitemap.IndexEntry, 0, pages+1)
+	for page := 1; page <= pages; page++ {
+		entries = append(entries, sitemap.IndexEntry{Loc: h.urls.Absolute(fmt.Sprintf("/sitemaps/posts-%d.xml", page))})
+	}
+	entries = append(entries, sitemap.IndexEntry{Loc: h.urls.Absolute("/sitemaps/news.xml")})
+
+	var buf bytes.Buffer
+	if err := sitemap.WriteIndex(&buf, entries); err != nil {
+		h.handleError(w, err, ErrFailedToRenderSitemap, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeXML)
+	w.Header().Set("Cache-Control", CacheControl)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.logger.Error("failed to write sitemap index", zap.Error(err))
+	}
+}
+
+// Posts serves one post sitemap. Page n lists the n-th block of MaxURLs
+// published posts, oldest first.
+func (h *Handler) Posts(w http.ResponseWriter, r *http.Request) {
+	page, err := strconv.Atoi(chi.URLParam(r, "page"))
+	if err != nil || page < 1 {
+		h.handleError(w, err, ErrSitemapNotFound, http.StatusNotFound)
+		return
+	}
+
+	pages, err := h.pages(r)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadSitemap, http.StatusInternalServerError)
+		return
+	}
+	if page > pages {
+		h.handleError(w, nil, ErrSitemapNotFound, http.StatusNotFound)
+		return
+	}
+
+	query := domain.SitemapQuery{
+		Offset: int64(page-1) * sitemap.MaxURLs,
+		Limit:  sitemap.MaxURLs,
+	}
+	h.stream(w, r, query, false)
+}
+
+// News serves the Google News sitemap of posts published within NewsWindow.
+func (h *Handler) News(w http.ResponseWriter, r *http.Request) {
+	query := domain.SitemapQuery{
+		Since:       time.Now().Add(-sitemap.NewsWindow),
+		Limit:       sitemap.MaxNewsURLs,
+		NewestFirst: true,
+	}
+	h.stream(w, r, query, true)
+}
+
+// pages returns the number of post sitemaps; an empty site still has one.
+func (h *Handler) pages(r *http.Request) (int, error) {
+	count, err := h.service.CountPublished(r.Context())
+	if err != nil {
+		return 0, err
+	}
+	pages := int((count + sitemap.MaxURLs - 1) / sitemap.MaxURLs)
+	return max(pages, 1), nil
+}
+
+// stream writes posts straight from the storage cursor to the response.
+// Errors before the first entry still get an error status; later ones can
+// only be logged because the response has already started.
+func (h *Handler) stream(w http.ResponseWriter, r *http.Request, query domain.SitemapQuery, news bool) {
+	w.Header().Set("Content-Type", ContentTypeXML)
+	w.Header().Set("Cache-Control", CacheControl)
+
+	sw := sitemap.NewWriter(w, news)
+	err := h.service.EachPublished(r.Context(), query, func(p *domain.Post) error {
+		return sw.Add(h.entry(p, news))
+	})
+	if err != nil {
+		if !sw.Started() {
+			h.handleError(w, err, ErrFailedToLoadSitemap, http.StatusInternalServerError)
+			return
+		}
+		h.logger.Error("failed to stream sitemap", zap.Error(err))
+		return
+	}
+
+	if err := sw.Close(); err != nil {
+		h.logger.Error("failed to finish sitemap", zap.Error(err))
+	}
+}
+
+// entry builds the sitemap entry of a post. Posts never updated have no
+// UpdatedAt and fall back to their creation time.
+func (h *Handler) entry(p *domain.Post, news bool) sitemap.URL {
+	u := sitemap.URL{Loc: h.urls.Post(p), LastMod: p.UpdatedAt}
+	if u.LastMod.IsZero() {
+		u.LastMod = p.CreatedAt
+	}
+	if news {
+		u.News = &sitemap.News{
+			PublicationName: publicationName,
+			Language:        publicationLanguage,
+			PublicationDate: p.CreatedAt,
+			Title:           p.Title,
+		}
+	}
+	return u
+}
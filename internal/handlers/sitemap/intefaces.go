@@ -0,0 +1,12 @@
+package sitemap
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type SitemapService interface {
+	CountPublished(ctx context.Context) (int64, error)
+	EachPublished(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error
+}
@@ -0,0 +1,27 @@
+package sitemap
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService implements SitemapService interface for testing
+type MockService struct {
+	CountPublishedFunc func(ctx context.Context) (int64, error)
+	EachPublishedFunc  func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error
+}
+
+func (m *MockService) CountPublished(ctx context.Context) (int64, error) {
+	if m.CountPublishedFunc != nil {
+		return m.CountPublishedFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockService) EachPublished(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+	if m.EachPublishedFunc != nil {
+		return m.EachPublishedFunc(ctx, query, fn)
+	}
+	return nil
+}
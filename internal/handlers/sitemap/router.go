@@ -0,0 +1,12 @@
+package sitemap
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the sitemap handler
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/sitemap.xml", h.Index)
+	r.Get("/sitemaps/posts-{page}.xml", h.Posts)
+	r.Get("/sitemaps/news.xml", h.News)
+}
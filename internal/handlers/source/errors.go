@@ -0,0 +1,9 @@
+package source
+
+// Error messages
+const (
+	ErrInvalidRequestBody   = "Invalid request body"
+	ErrInvalidInterval      = "Invalid interval, use a duration such as 15m or 1h"
+	ErrFailedToLoadSources  = "Failed to load sources"
+	ErrFailedToCreateSource = "Failed to create source"
+)
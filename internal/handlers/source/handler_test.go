@@ -0,0 +1,179 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// apiKeys authenticates the tokens "reader" and "writer" with keys granted
+// posts:read and posts:read plus posts:write.
+type apiKeys struct{}
+
+func (apiKeys) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	switch token {
+	case "reader":
+		return &domain.APIKey{OwnerID: "owner", Scopes: []domain.APIScope{domain.ScopePostsRead}}, nil
+	case "writer":
+		return &domain.APIKey{OwnerID: "owner", Scopes: []domain.APIScope{domain.ScopePostsRead, domain.ScopePostsWrite}}, nil
+	}
+	return nil, domain.ErrInvalidAPIKey
+}
+
+// setupTestHandler returns the routes with every request sent with a
+// posts:write key, unless it already has one.
+func setupTestHandler() (http.Handler, *MockService) {
+	mockService := &MockService{}
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, logger), apiKeys{})
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") == "" {
+			req.Header.Set("Authorization", "Bearer writer")
+		}
+		r.ServeHTTP(w, req)
+	}), mockService
+}
+
+func TestHandler_Scopes(t *testing.T) {
+	mockService := &MockService{}
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Source, error) {
+		return nil, nil
+	}
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, logger), apiKeys{})
+
+	tests := []struct {
+		method string
+		token  string
+		status int
+	}{
+		{http.MethodGet, "", http.StatusUnauthorized},
+		{http.MethodPost, "", http.StatusUnauthorized},
+		{http.MethodGet, "unknown", http.StatusUnauthorized},
+		{http.MethodGet, "reader", http.StatusForbidden},
+		{http.MethodPost, "reader", http.StatusForbidden},
+		{http.MethodGet, "writer", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" as "+tt.token, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/sources", strings.NewReader(`{}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	router, mockService := setupTestHandler()
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "valid source",
+			body:           `{"name":"Wire","url":"https://wire.example/rss","category":"world","publish":true,"interval":"30m"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "malformed body",
+			body:           `{"name":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrInvalidRequestBody,
+		},
+		{
+			name:           "invalid interval",
+			body:           `{"name":"Wire","url":"https://wire.example/rss","interval":"often"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  ErrInvalidInterval,
+		},
+		{
+			name:           "validation error",
+			body:           `{"name":"Wire","url":"wire"}`,
+			serviceErr:     fmt.Errorf("failed to create source: %w", domain.ErrInvalidSourceURL),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  domain.ErrInvalidSourceURL.Error(),
+		},
+		{
+			name:           "duplicate",
+			body:           `{"name":"Wire","url":"https://wire.example/rss"}`,
+			serviceErr:     fmt.Errorf("failed to save source: %w", domain.ErrDuplicateSource),
+			expectedStatus: http.StatusConflict,
+			expectedError:  domain.ErrDuplicateSource.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotInput domain.SourceInput
+			mockService.CreateFunc = func(ctx context.Context, in domain.SourceInput) (*domain.Source, error) {
+				gotInput = in
+				if tt.serviceErr != nil {
+					return nil, tt.serviceErr
+				}
+				return &domain.Source{Name: in.Name, URL: in.URL, Interval: in.Interval}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/sources", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, body["error"])
+				return
+			}
+			assert.Equal(t, domain.SourceInput{Name: "Wire", URL: "https://wire.example/rss", Category: "world", Publish: true, Interval: 30 * time.Minute}, gotInput)
+			assert.Equal(t, "30m0s", body["interval"])
+			assert.Equal(t, "Wire", body["name"])
+		})
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	router, mockService := setupTestHandler()
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Source, error) {
+		return []*domain.Source{{Name: "Wire", URL: "https://wire.example/rss", Interval: time.Hour, ETag: `"abc"`, LastError: "timeout"}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sources", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body []map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body, 1)
+	assert.Equal(t, "1h0m0s", body[0]["interval"])
+	assert.Equal(t, "timeout", body[0]["last_error"])
+	assert.NotContains(t, body[0], "etag")
+
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Source, error) {
+		return nil, assert.AnError
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sources", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
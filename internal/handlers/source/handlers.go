@@ -0,0 +1,128 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Handler handles the JSON API managing ingested feed sources
+type Handler struct {
+	service SourceService
+	logger  *zap.Logger
+}
+
+// New creates a new source handler
+func New(service SourceService, logger *zap.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// apiError is the JSON body of API error responses.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// sourceRequest is the JSON body creating a source. Interval is a Go duration
+// such as "15m"; it defaults to domain.DefaultSourceInterval.
+type sourceRequest struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Category string `json:"category"`
+	Publish  bool   `json:"publish"`
+	Interval string `json:"interval"`
+}
+
+// sourceResponse renders a source with a readable interval.
+type sourceResponse struct {
+	*domain.Source
+	Interval string `json:"interval"`
+}
+
+// List returns all sources with their last fetch state
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	sources, err := h.service.List(r.Context())
+	if err != nil {
+		h.logger.Error(ErrFailedToLoadSources, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToLoadSources})
+		return
+	}
+
+	response := make([]sourceResponse, 0, len(sources))
+	for _, s := range sources {
+		response = append(response, newSourceResponse(s))
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// Create registers a new source
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req sourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidRequestBody})
+		return
+	}
+
+	in := domain.SourceInput{
+		Name:     req.Name,
+		URL:      req.URL,
+		Category: req.Category,
+		Publish:  req.Publish,
+	}
+	if req.Interval != "" {
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			h.writeJSON(w, http.StatusBadRequest, apiError{Error: ErrInvalidInterval})
+			return
+		}
+		in.Interval = interval
+	}
+
+	source, err := h.service.Create(r.Context(), in)
+	if invalid := validationError(err); invalid != nil {
+		h.writeJSON(w, http.StatusBadRequest, apiError{Error: invalid.Error()})
+		return
+	}
+	switch {
+	case errors.Is(err, domain.ErrDuplicateSource):
+		h.writeJSON(w, http.StatusConflict, apiError{Error: domain.ErrDuplicateSource.Error()})
+		return
+	case err != nil:
+		h.logger.Error(ErrFailedToCreateSource, zap.Error(err))
+		h.writeJSON(w, http.StatusInternalServerError, apiError{Error: ErrFailedToCreateSource})
+		return
+	}
+
+	h.logger.Info("created source", zap.String("name", source.Name), zap.String("url", source.URL))
+	h.writeJSON(w, http.StatusCreated, newSourceResponse(source))
+}
+
+func newSourceResponse(s *domain.Source) sourceResponse {
+	return sourceResponse{Source: s, Interval: s.Interval.String()}
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidSourceName, domain.ErrInvalidSourceURL, domain.ErrInvalidSourceInterval} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
+
+// writeJSON encodes v as the JSON response body.
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Error("failed to encode response", zap.Error(err))
+	}
+}
@@ -0,0 +1,12 @@
+package source
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type SourceService interface {
+	Create(ctx context.Context, in domain.SourceInput) (*domain.Source, error)
+	List(ctx context.Context) ([]*domain.Source, error)
+}
@@ -0,0 +1,27 @@
+package source
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService implements SourceService interface for testing
+type MockService struct {
+	CreateFunc func(ctx context.Context, in domain.SourceInput) (*domain.Source, error)
+	ListFunc   func(ctx context.Context) ([]*domain.Source, error)
+}
+
+func (m *MockService) Create(ctx context.Context, in domain.SourceInput) (*domain.Source, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, in)
+	}
+	return nil, nil
+}
+
+func (m *MockService) List(ctx context.Context) ([]*domain.Source, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
@@ -0,0 +1,18 @@
+package source
+
+import (
+	"github.com/kir/news-app/internal/apiauth"
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up all routes for the source handler. Sources publish
+// posts, so every route needs an API key granted posts:write.
+func RegisterRoutes(r chi.Router, h *Handler, auth apiauth.Authenticator) {
+	r.Route("/api/sources", func(r chi.Router) {
+		r.Use(apiauth.Require(auth, domain.ScopePostsWrite, h.logger))
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+	})
+}
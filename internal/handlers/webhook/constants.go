@@ -0,0 +1,13 @@
+package webhook
+
+// HTMX headers
+const (
+	HXErrorHeader   = "HX-Error-Message"
+	HXRefreshHeader = "HX-Refresh"
+)
+
+// Admin page paths
+const (
+	webhooksPath   = "/admin/webhooks"
+	deliveriesPath = webhooksPath + "/deliveries/"
+)
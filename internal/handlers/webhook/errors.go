@@ -0,0 +1,13 @@
+package webhook
+
+// Error messages
+const (
+	ErrInvalidFormData       = "Invalid form data"
+	ErrWebhookNotFound       = "Webhook not found"
+	ErrDeliveryNotFound      = "Delivery not found"
+	ErrFailedToLoadWebhooks  = "Failed to load webhooks"
+	ErrFailedToCreateWebhook = "Failed to create webhook"
+	ErrFailedToDeleteWebhook = "Failed to delete webhook"
+	ErrFailedToRedeliver     = "Failed to redeliver"
+	ErrInternalServer        = "Internal server error"
+)
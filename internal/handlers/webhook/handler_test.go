@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const adminToken = "admin-secret"
+
+// setupTestHandler returns the routes with every request sent as the admin.
+func setupTestHandler() (http.Handler, *MockService) {
+	mockService := &MockService{}
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	r := chi.NewRouter()
+	RegisterRoutes(r, New(mockService, tmpl, logger), adminToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.SetBasicAuth("admin", adminToken)
+		r.ServeHTTP(w, req)
+	}), mockService
+}
+
+func TestRegisterRoutes_AdminToken(t *testing.T) {
+	tmpl := template.Must(templates.Parse("../../../templates"))
+	logger, _ := zap.NewDevelopment()
+	handler := New(&MockService{}, tmpl, logger)
+
+	r := chi.NewRouter()
+	RegisterRoutes(r, handler, adminToken)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil),
+		httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader("url=https%3A%2F%2Fhooks.example")),
+		httptest.NewRequest(http.MethodGet, "/admin/webhooks/deliveries/"+primitive.NewObjectID().Hex(), nil),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "%s %s needs the admin token", req.Method, req.URL.Path)
+	}
+
+	r = chi.NewRouter()
+	RegisterRoutes(r, handler, "")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "the pages are not mounted without a token")
+}
+
+func TestHandler_Index(t *testing.T) {
+	router, mockService := setupTestHandler()
+	hook := &domain.Webhook{
+		ID:        primitive.NewObjectID(),
+		URL:       "https://hooks.example/news",
+		Secret:    "s3cret",
+		Events:    []domain.PostEventType{domain.PostPublished, domain.PostDeleted},
+		CreatedAt: time.Now(),
+	}
+	delivery := &domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		URL:            hook.URL,
+		Event:          domain.PostPublished,
+		Status:         domain.DeliveryFailed,
+		Attempts:       domain.MaxDeliveryAttempts,
+		ResponseStatus: http.StatusBadGateway,
+		CreatedAt:      time.Now(),
+	}
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Webhook, error) {
+		return []*domain.Webhook{hook}, nil
+	}
+	mockService.RecentDeliveriesFunc = func(ctx context.Context) ([]*domain.WebhookDelivery, error) {
+		return []*domain.WebhookDelivery{delivery}, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "https://hooks.example/news")
+	assert.NotContains(t, body, "s3cret", "secrets are only shown on creation")
+	assert.Contains(t, body, "post.published, post.deleted")
+	assert.Contains(t, body, `hx-delete="/admin/webhooks/`+hook.ID.Hex()+`"`)
+	assert.Contains(t, body, `href="/admin/webhooks/deliveries/`+delivery.ID.Hex()+`"`)
+	assert.Contains(t, body, "failed")
+	assert.Contains(t, body, "(502)")
+	for _, e := range domain.WebhookEvents {
+		assert.Contains(t, body, `value="`+string(e)+`"`)
+	}
+
+	mockService.ListFunc = func(ctx context.Context) ([]*domain.Webhook, error) {
+		return nil, assert.AnError
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandler_Create(t *testing.T) {
+	router, mockService := setupTestHandler()
+
+	tests := []struct {
+		name           string
+		form           string
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "valid webhook",
+			form:           "url=https%3A%2F%2Fhooks.example%2Fnews&events=post.published&events=post.deleted",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "n3w-s3cret",
+		},
+		{
+			name:           "invalid URL",
+			form:           "url=hooks.example",
+			serviceErr:     fmt.Errorf("failed to create webhook: %w", domain.ErrInvalidWebhookURL),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidWebhookURL.Error(),
+		},
+		{
+			name:           "repository error",
+			form:           "url=https%3A%2F%2Fhooks.example%2Fnews",
+			serviceErr:     assert.AnError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   ErrFailedToCreateWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.WebhookInput
+			mockService.CreateFunc = func(ctx context.Context, in domain.WebhookInput) (*domain.Webhook, error) {
+				got = in
+				if tt.serviceErr != nil {
+					return nil, tt.serviceErr
+				}
+				return &domain.Webhook{ID: primitive.NewObjectID(), URL: in.URL, Secret: "n3w-s3cret", Events: in.Events}, nil
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(tt.form))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.name == "valid webhook" {
+				assert.Equal(t, []domain.PostEventType{domain.PostPublished, domain.PostDeleted}, got.Events)
+			}
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	router, mockService := setupTestHandler()
+	id := primitive.NewObjectID().Hex()
+
+	mockService.DeleteFunc = func(ctx context.Context, got string) error {
+		if got != id {
+			return domain.ErrWebhookNotFound
+		}
+		return nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/webhooks/"+id, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "true", w.Header().Get(HXRefreshHeader))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/webhooks/other", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ErrWebhookNotFound, w.Header().Get(HXErrorHeader))
+}
+
+func TestHandler_Delivery(t *testing.T) {
+	router, mockService := setupTestHandler()
+	delivery := &domain.WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		URL:           "https://hooks.example/news",
+		Event:         domain.PostCreated,
+		PostID:        "post-1",
+		Payload:       `{"event":"post.created","post_id":"post-1"}`,
+		Signature:     "sha256=abc",
+		Status:        domain.DeliveryPending,
+		Attempts:      1,
+		LastError:     "unexpected status 500",
+		CreatedAt:     time.Now(),
+		LastAttemptAt: time.Now(),
+		NextAttemptAt: time.Now().Add(time.Minute),
+	}
+	mockService.GetDeliveryFunc = func(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+		if id != delivery.ID.Hex() {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return delivery, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks/deliveries/"+delivery.ID.Hex(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "&#34;event&#34;: &#34;post.created&#34;,\n  &#34;post_id&#34;")
+	assert.Contains(t, body, "unexpected status 500")
+	assert.Contains(t, body, "sha256=abc")
+	assert.Contains(t, body, `action="/admin/webhooks/deliveries/`+delivery.ID.Hex()+`/redeliver"`)
+	assert.Contains(t, body, "Next attempt")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks/deliveries/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_Redeliver(t *testing.T) {
+	router, mockService := setupTestHandler()
+	original := primitive.NewObjectID().Hex()
+	redelivery := &domain.WebhookDelivery{ID: primitive.NewObjectID()}
+
+	mockService.RedeliverFunc = func(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+		if id != original {
+			return nil, fmt.Errorf("failed to get webhook delivery: %w", domain.ErrDeliveryNotFound)
+		}
+		return redelivery, nil
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks/deliveries/"+original+"/redeliver", nil))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/admin/webhooks/deliveries/"+redelivery.ID.Hex(), w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks/deliveries/missing/redeliver", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
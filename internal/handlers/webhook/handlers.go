@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Handler handles the admin pages managing webhooks and their deliveries
+type Handler struct {
+	service   WebhookService
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// New creates a new webhook handler
+func New(service WebhookService, templates *template.Template, logger *zap.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// webhooksPage is the data of the webhook admin page. URL and Error refill
+// the subscription form after a rejected submission; Created is the webhook
+// just added, whose secret is shown this once.
+type webhooksPage struct {
+	Webhooks   []*domain.Webhook
+	Deliveries []*domain.WebhookDelivery
+	Events     []domain.PostEventType
+	URL        string
+	Error      string
+	Created    *domain.Webhook
+}
+
+// deliveryPage is the data of the delivery detail page. Payload is indented for reading.
+type deliveryPage struct {
+	Delivery *domain.WebhookDelivery
+	Payload  string
+}
+
+// handleError is a helper function to handle errors consistently
+func (h *Handler) handleError(w http.ResponseWriter, err error, message string, status int) {
+	h.logger.Error(message, zap.Error(err))
+	w.Header().Set(HXErrorHeader, message)
+	http.Error(w, message, status)
+}
+
+// Index handles the page listing webhooks and the latest deliveries
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	h.renderIndex(w, r, http.StatusOK, webhooksPage{})
+}
+
+// Create handles the subscription form
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	in := domain.WebhookInput{URL: r.PostForm.Get("url")}
+	for _, e := range r.PostForm["events"] {
+		in.Events = append(in.Events, domain.PostEventType(e))
+	}
+
+	hook, err := h.service.Create(r.Context(), in)
+	if invalid := validationError(err); invalid != nil {
+		h.renderIndex(w, r, http.StatusBadRequest, webhooksPage{URL: in.URL, Error: invalid.Error()})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToCreateWebhook, http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("created webhook", zap.String("url", hook.URL))
+	h.renderIndex(w, r, http.StatusCreated, webhooksPage{Created: hook})
+}
+
+// Delete handles the removal of a webhook and reloads the page
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	err := h.service.Delete(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		h.handleError(w, err, ErrWebhookNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToDeleteWebhook, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(HXRefreshHeader, "true")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delivery handles the page showing one delivery with its payload
+func (h *Handler) Delivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.GetDelivery(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrDeliveryNotFound) {
+		h.handleError(w, err, ErrDeliveryNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+
+	page := deliveryPage{Delivery: delivery, Payload: delivery.Payload}
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(delivery.Payload), "", "  ") == nil {
+		page.Payload = indented.String()
+	}
+
+	if err := h.templates.ExecuteTemplate(w, "admin/webhook-delivery", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+	}
+}
+
+// Redeliver handles queueing a delivery again and shows the new delivery
+func (h *Handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.Redeliver(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, domain.ErrDeliveryNotFound) {
+		h.handleError(w, err, ErrDeliveryNotFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.handleError(w, err, ErrFailedToRedeliver, http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, deliveriesPath+delivery.ID.Hex(), http.StatusSeeOther)
+}
+
+// renderIndex loads the webhooks and deliveries into page and renders it with status.
+func (h *Handler) renderIndex(w http.ResponseWriter, r *http.Request, status int, page webhooksPage) {
+	ctx := r.Context()
+
+	hooks, err := h.service.List(ctx)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadWebhooks, http.StatusInternalServerError)
+		return
+	}
+	deliveries, err := h.service.RecentDeliveries(ctx)
+	if err != nil {
+		h.handleError(w, err, ErrFailedToLoadWebhooks, http.StatusInternalServerError)
+		return
+	}
+
+	page.Webhooks = hooks
+	page.Deliveries = deliveries
+	page.Events = domain.WebhookEvents
+
+	var buf bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&buf, "admin/webhooks", page); err != nil {
+		h.handleError(w, err, ErrInternalServer, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// validationError returns the domain validation error wrapped in err, if any.
+func validationError(err error) error {
+	for _, target := range []error{domain.ErrInvalidWebhookURL, domain.ErrInvalidWebhookEvent} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+	return nil
+}
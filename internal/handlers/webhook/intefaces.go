@@ -0,0 +1,16 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, in domain.WebhookInput) (*domain.Webhook, error)
+	List(ctx context.Context) ([]*domain.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	RecentDeliveries(ctx context.Context) ([]*domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockService implements WebhookService interface for testing
+type MockService struct {
+	CreateFunc           func(ctx context.Context, in domain.WebhookInput) (*domain.Webhook, error)
+	ListFunc             func(ctx context.Context) ([]*domain.Webhook, error)
+	DeleteFunc           func(ctx context.Context, id string) error
+	RecentDeliveriesFunc func(ctx context.Context) ([]*domain.WebhookDelivery, error)
+	GetDeliveryFunc      func(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	RedeliverFunc        func(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+}
+
+func (m *MockService) Create(ctx context.Context, in domain.WebhookInput) (*domain.Webhook, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, in)
+	}
+	return nil, nil
+}
+
+func (m *MockService) List(ctx context.Context) ([]*domain.Webhook, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockService) RecentDeliveries(ctx context.Context) ([]*domain.WebhookDelivery, error) {
+	if m.RecentDeliveriesFunc != nil {
+		return m.RecentDeliveriesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockService) GetDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	if m.GetDeliveryFunc != nil {
+		return m.GetDeliveryFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockService) Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	if m.RedeliverFunc != nil {
+		return m.RedeliverFunc(ctx, id)
+	}
+	return nil, nil
+}
@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"github.com/kir/news-app/internal/adminauth"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes sets up the webhook admin pages, which manage where post
+// events are sent, behind the admin token. Without a token they are not mounted.
+func RegisterRoutes(r chi.Router, h *Handler, token string) {
+	if token == "" {
+		return
+	}
+	r.Route("/admin/webhooks", func(r chi.Router) {
+		r.Use(adminauth.Require(token))
+		r.Get("/", h.Index)
+		r.Post("/", h.Create)
+		r.Delete("/{id}", h.Delete)
+		r.Get("/deliveries/{id}", h.Delivery)
+		r.Post("/deliveries/{id}/redeliver", h.Redeliver)
+	})
+}
@@ -0,0 +1,279 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxFeedSize caps how much of a feed response is read.
+	maxFeedSize = 10 << 20
+	// maxTags caps how many item categories become post tags.
+	maxTags = 10
+	// maxTitleBytes mirrors the post title limit.
+	maxTitleBytes = 200
+
+	userAgent = "NewsPortal-Ingest/1.0"
+)
+
+// PostImporter creates posts from feed items.
+type PostImporter interface {
+	Import(ctx context.Context, in domain.PostInput, origin domain.PostOrigin) (*domain.Post, error)
+}
+
+// Result summarizes one fetch of a source.
+type Result struct {
+	NotModified bool
+	Items       int
+	Created     int
+	Duplicates  int
+	Skipped     int
+}
+
+// Fetcher polls feed sources and imports their items as posts. Every source
+// is fetched on its own interval; the fetcher wakes up every tick to look
+// for sources that are due.
+type Fetcher struct {
+	sources domain.SourceRepository
+	posts   PostImporter
+	client  *http.Client
+	tick    time.Duration
+	now     func() time.Time
+	logger  *zap.Logger
+}
+
+// Option configures optional Fetcher settings.
+type Option func(*Fetcher)
+
+// WithHTTPClient sets the client used to download feeds.
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *Fetcher) {
+		f.client = client
+	}
+}
+
+// WithTick sets how often the fetcher looks for due sources.
+func WithTick(tick time.Duration) Option {
+	return func(f *Fetcher) {
+		f.tick = tick
+	}
+}
+
+// WithLogger sets the logger used to report fetch failures.
+func WithLogger(logger *zap.Logger) Option {
+	return func(f *Fetcher) {
+		f.logger = logger
+	}
+}
+
+func NewFetcher(sources domain.SourceRepository, posts PostImporter, opts ...Option) *Fetcher {
+	f := &Fetcher{
+		sources: sources,
+		posts:   posts,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		tick:    time.Minute,
+		now:     time.Now,
+		logger:  zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Run polls due sources every tick until ctx is cancelled.
+func (f *Fetcher) Run(ctx context.Context) {
+	f.logger.Info("starting feed ingestion", zap.Duration("tick", f.tick))
+	ticker := time.NewTicker(f.tick)
+	defer ticker.Stop()
+
+	for {
+		f.PollDue(ctx)
+		select {
+		case <-ctx.Done():
+			f.logger.Info("feed ingestion stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollDue fetches every source whose next fetch time has passed. Failures
+// are recorded on the source and logged; they do not stop other sources.
+func (f *Fetcher) PollDue(ctx context.Context) {
+	due, err := f.sources.GetDue(ctx, f.now())
+	if err != nil {
+		f.logger.Error("failed to load due sources", zap.Error(err))
+		return
+	}
+
+	for _, source := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		result, err := f.Fetch(ctx, source)
+		if err != nil {
+			f.logger.Error("failed to fetch source",
+				zap.String("source", source.Name),
+				zap.String("url", source.URL),
+				zap.Error(err),
+			)
+			continue
+		}
+		f.logger.Info("fetched source",
+			zap.String("source", source.Name),
+			zap.Bool("not_modified", result.NotModified),
+			zap.Int("items", result.Items),
+			zap.Int("created", result.Created),
+			zap.Int("duplicates", result.Duplicates),
+			zap.Int("skipped", result.Skipped),
+		)
+	}
+}
+
+// Fetch downloads a source once, imports its new items and saves the fetch
+// state that schedules the next fetch.
+func (f *Fetcher) Fetch(ctx context.Context, source *domain.Source) (Result, error) {
+	result, err := f.fetch(ctx, source)
+	source.Fetched(f.now(), err)
+	if saveErr := f.sources.SaveFetchState(ctx, source); saveErr != nil {
+		return result, errors.Join(err, saveErr)
+	}
+	return result, err
+}
+
+func (f *Fetcher) fetch(ctx context.Context, source *domain.Source) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if source.ETag != "" {
+		req.Header.Set("If-None-Match", source.ETag)
+	}
+	if source.LastModified != "" {
+		req.Header.Set("If-Modified-Since", source.LastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to download feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return Result{NotModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	items, err := Parse(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Items: len(items)}
+	for _, item := range items {
+		in, origin, ok := postFromItem(source, item)
+		if !ok {
+			result.Skipped++
+			continue
+		}
+
+		_, err := f.posts.Import(ctx, in, origin)
+		switch {
+		case errors.Is(err, domain.ErrDuplicatePost):
+			result.Duplicates++
+		case err != nil:
+			f.logger.Warn("failed to import feed item",
+				zap.String("source", source.Name),
+				zap.String("guid", origin.GUID),
+				zap.Error(err),
+			)
+			result.Skipped++
+		default:
+			result.Created++
+		}
+	}
+
+	// Validators are only kept once the items behind them have been imported.
+	source.ETag = resp.Header.Get("ETag")
+	source.LastModified = resp.Header.Get("Last-Modified")
+	return result, nil
+}
+
+// postFromItem maps a feed item to post input attributed to its source.
+// Items without a title or any identifier are skipped.
+func postFromItem(source *domain.Source, item Item) (domain.PostInput, domain.PostOrigin, bool) {
+	guid := item.GUID
+	if guid == "" {
+		guid = item.Link
+	}
+	title := truncate(PlainText(item.Title), maxTitleBytes)
+	if guid == "" || len(title) < 3 {
+		return domain.PostInput{}, domain.PostOrigin{}, false
+	}
+
+	content := PlainText(item.Content)
+	if len(content) < 10 {
+		content = strings.TrimSpace(title + "\n\n" + item.Link)
+	}
+
+	category := source.Category
+	if category == "" && len(item.Categories) > 0 {
+		category = item.Categories[0]
+	}
+	tags := item.Categories
+	if len(tags) > maxTags {
+		tags = tags[:maxTags]
+	}
+	author := item.Author
+	if author == "" {
+		author = source.Name
+	}
+	status := domain.PostStatusDraft
+	if source.Publish {
+		status = domain.PostStatusPublished
+	}
+
+	in := domain.PostInput{
+		Title:    title,
+		Content:  content,
+		Category: category,
+		Tags:     tags,
+		Author:   author,
+		Status:   status,
+	}
+	origin := domain.PostOrigin{
+		SourceID: source.ID,
+		Name:     source.Name,
+		GUID:     guid,
+		Link:     item.Link,
+	}
+	return in, origin, true
+}
+
+// truncate shortens s to at most max bytes on a rune boundary, marking the cut.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	const ellipsis = "…"
+	cut := max - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(s[:cut]) + ellipsis
+}
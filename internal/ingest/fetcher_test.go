@@ -0,0 +1,211 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memorySources is an in-memory domain.SourceRepository.
+type memorySources struct {
+	mu      sync.Mutex
+	sources []*domain.Source
+	saved   int
+}
+
+func (m *memorySources) Create(ctx context.Context, s *domain.Source) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = append(m.sources, s)
+	return nil
+}
+
+func (m *memorySources) GetAll(ctx context.Context) ([]*domain.Source, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sources, nil
+}
+
+func (m *memorySources) GetDue(ctx context.Context, now time.Time) ([]*domain.Source, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []*domain.Source
+	for _, s := range m.sources {
+		if !s.NextFetchAt.After(now) {
+			due = append(due, s)
+		}
+	}
+	return due, nil
+}
+
+func (m *memorySources) SaveFetchState(ctx context.Context, s *domain.Source) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved++
+	return nil
+}
+
+// memoryPosts imports posts and de-duplicates them like the post service.
+type memoryPosts struct {
+	mu    sync.Mutex
+	posts []*domain.Post
+}
+
+func (m *memoryPosts) Import(ctx context.Context, in domain.PostInput, origin domain.PostOrigin) (*domain.Post, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.posts {
+		if p.Origin.GUID == origin.GUID || (origin.Link != "" && p.Origin.Link == origin.Link) {
+			return nil, domain.ErrDuplicatePost
+		}
+	}
+	post, err := domain.NewPostFromInput(in)
+	if err != nil {
+		return nil, err
+	}
+	post.Origin = &origin
+	m.posts = append(m.posts, post)
+	return post, nil
+}
+
+// feedServer serves body with an ETag and answers matching conditional requests with 304.
+func feedServer(t *testing.T, body *string, requests *[]*http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r)
+		sum := sha256.Sum256([]byte(*body))
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Fri, 14 Mar 2025 09:00:00 GMT")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(*body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	body := rssFeed
+	var requests []*http.Request
+	server := feedServer(t, &body, &requests)
+
+	source, err := domain.NewSource(domain.SourceInput{Name: "Wire", URL: server.URL, Category: "wire", Interval: 10 * time.Minute})
+	require.NoError(t, err)
+	sources := &memorySources{sources: []*domain.Source{source}}
+	posts := &memoryPosts{}
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	fetcher := NewFetcher(sources, posts)
+	fetcher.now = func() time.Time { return now }
+
+	result, err := fetcher.Fetch(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Items: 2, Created: 2}, result)
+	assert.Equal(t, userAgent, requests[0].Header.Get("User-Agent"))
+	assert.Empty(t, requests[0].Header.Get("If-None-Match"))
+	assert.NotEmpty(t, source.ETag)
+	assert.Equal(t, "Fri, 14 Mar 2025 09:00:00 GMT", source.LastModified)
+	assert.Equal(t, now.Add(10*time.Minute), source.NextFetchAt)
+	assert.Equal(t, 1, sources.saved)
+
+	require.Len(t, posts.posts, 2)
+	post := posts.posts[0]
+	assert.Equal(t, "Budget & taxes", post.Title)
+	assert.Equal(t, "Parliament passed the budget.\n\nNext paragraph", post.Content)
+	assert.Equal(t, "wire", post.Category, "the source category wins over item categories")
+	assert.Equal(t, []string{"politics", "budget"}, post.Tags)
+	assert.Equal(t, "Anna", post.Author)
+	assert.Equal(t, domain.PostStatusDraft, post.Status)
+	assert.Equal(t, domain.PostOrigin{SourceID: source.ID, Name: "Wire", GUID: "wire-1", Link: "https://wire.example/budget"}, *post.Origin)
+
+	derby := posts.posts[1]
+	assert.Equal(t, "https://wire.example/derby", derby.Origin.GUID, "items without guid are keyed by link")
+	assert.Equal(t, "Wire", derby.Author, "items without author are attributed to the source")
+
+	// An unchanged feed is not downloaded again.
+	result, err = fetcher.Fetch(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, Result{NotModified: true}, result)
+	assert.Equal(t, source.ETag, requests[1].Header.Get("If-None-Match"))
+	assert.Equal(t, "Fri, 14 Mar 2025 09:00:00 GMT", requests[1].Header.Get("If-Modified-Since"))
+
+	// A changed feed only adds the new items.
+	body = strings.Replace(rssFeed, "<item>", `<item><title>Breaking</title><guid>wire-3</guid><description>Something new happened</description></item><item>`, 1)
+	result, err = fetcher.Fetch(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Items: 3, Created: 1, Duplicates: 2}, result)
+	assert.Len(t, posts.posts, 3)
+}
+
+func TestFetcher_FetchErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			_, _ = w.Write([]byte("<html>not a feed</html>"))
+		}
+	}))
+	defer server.Close()
+
+	sources := &memorySources{}
+	fetcher := NewFetcher(sources, &memoryPosts{})
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	fetcher.now = func() time.Time { return now }
+
+	for _, path := range []string{"/missing", "/html"} {
+		source := &domain.Source{ID: primitive.NewObjectID(), Name: "Broken", URL: server.URL + path, Interval: time.Hour, ETag: `"old"`}
+
+		_, err := fetcher.Fetch(context.Background(), source)
+
+		assert.Error(t, err, path)
+		assert.NotEmpty(t, source.LastError, path)
+		assert.Equal(t, `"old"`, source.ETag, "validators are kept after a failed fetch")
+		assert.Equal(t, now.Add(time.Hour), source.NextFetchAt, "failed sources are retried on their interval")
+	}
+}
+
+func TestFetcher_PollDue(t *testing.T) {
+	body := atomFeed
+	var requests []*http.Request
+	server := feedServer(t, &body, &requests)
+
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+	due := &domain.Source{ID: primitive.NewObjectID(), Name: "Agency", URL: server.URL, Publish: true, Interval: time.Hour, NextFetchAt: now.Add(-time.Minute)}
+	later := &domain.Source{ID: primitive.NewObjectID(), Name: "Later", URL: server.URL + "/later", Interval: time.Hour, NextFetchAt: now.Add(time.Minute)}
+	posts := &memoryPosts{}
+	fetcher := NewFetcher(&memorySources{sources: []*domain.Source{due, later}}, posts)
+	fetcher.now = func() time.Time { return now }
+
+	fetcher.PollDue(context.Background())
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, "/", requests[0].URL.Path)
+	require.Len(t, posts.posts, 2)
+	assert.Equal(t, domain.PostStatusPublished, posts.posts[0].Status)
+	assert.Equal(t, "weather", posts.posts[0].Category)
+	assert.Equal(t, now.Add(time.Hour), due.NextFetchAt)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abcdef…", truncate("abcdefghijklmnop", 9))
+	got := truncate(strings.Repeat("я", 10), 9)
+	assert.Equal(t, "яяя…", got)
+	assert.LessOrEqual(t, len(got), 9)
+}
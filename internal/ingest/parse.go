@@ -0,0 +1,214 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat is returned for documents that are neither RSS nor Atom.
+var ErrUnknownFormat = errors.New("document is not an RSS or Atom feed")
+
+// Item is a feed entry normalized across RSS and Atom. Content is HTML as
+// published by the feed.
+type Item struct {
+	GUID       string
+	Link       string
+	Title      string
+	Content    string
+	Author     string
+	Categories []string
+	Published  time.Time
+}
+
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	// RSS 1.0 (RDF) keeps items next to the channel.
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        string   `xml:"guid"`
+	Link        string   `xml:"link"`
+	Title       string   `xml:"title"`
+	Description string   `xml:"description"`
+	Encoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Author      string   `xml:"author"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomDocument struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Content string `xml:"content"`
+	Summary string `xml:"summary"`
+	Authors []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// Parse reads an RSS 0.9x/1.0/2.0 or Atom 1.0 document.
+func Parse(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse rss: %w", err)
+		}
+		items := make([]Item, 0, len(doc.Channel.Items)+len(doc.Items))
+		for _, it := range append(doc.Channel.Items, doc.Items...) {
+			items = append(items, it.item())
+		}
+		return items, nil
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse atom: %w", err)
+		}
+		items := make([]Item, 0, len(doc.Entries))
+		for _, e := range doc.Entries {
+			items = append(items, e.item())
+		}
+		return items, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// rootElement returns the local name of the document element.
+func rootElement(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", ErrUnknownFormat
+			}
+			return "", fmt.Errorf("failed to parse feed: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func (it rssItem) item() Item {
+	content := it.Encoded
+	if strings.TrimSpace(content) == "" {
+		content = it.Description
+	}
+	author := it.Creator
+	if author == "" {
+		author = it.Author
+	}
+	published := parseDate(it.PubDate)
+	if published.IsZero() {
+		published = parseDate(it.Date)
+	}
+
+	return Item{
+		GUID:       strings.TrimSpace(it.GUID),
+		Link:       strings.TrimSpace(it.Link),
+		Title:      strings.TrimSpace(it.Title),
+		Content:    content,
+		Author:     strings.TrimSpace(author),
+		Categories: it.Categories,
+		Published:  published,
+	}
+}
+
+func (e atomEntry) item() Item {
+	// The alternate link is the entry's page; a link without rel is alternate too.
+	var link string
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			link = l.Href
+			break
+		}
+	}
+	content := e.Content
+	if strings.TrimSpace(content) == "" {
+		content = e.Summary
+	}
+	var author string
+	if len(e.Authors) > 0 {
+		author = e.Authors[0].Name
+	}
+	var categories []string
+	for _, c := range e.Categories {
+		categories = append(categories, c.Term)
+	}
+	published := parseDate(e.Published)
+	if published.IsZero() {
+		published = parseDate(e.Updated)
+	}
+
+	return Item{
+		GUID:       strings.TrimSpace(e.ID),
+		Link:       strings.TrimSpace(link),
+		Title:      strings.TrimSpace(e.Title),
+		Content:    content,
+		Author:     strings.TrimSpace(author),
+		Categories: categories,
+		Published:  published,
+	}
+}
+
+// dateLayouts are the date formats found in the wild, RFC 822 variants first.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseDate returns the zero time for missing or unrecognized dates.
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
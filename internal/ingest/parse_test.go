@@ -0,0 +1,135 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Wire</title>
+  <atom:link href="https://wire.example/rss" rel="self"/>
+  <item>
+    <title>Budget &amp; taxes</title>
+    <link>https://wire.example/budget</link>
+    <guid isPermaLink="false">wire-1</guid>
+    <description>Short summary</description>
+    <content:encoded><![CDATA[<p>Parliament passed the <b>budget</b>.</p><p>Next paragraph</p>]]></content:encoded>
+    <dc:creator>Anna</dc:creator>
+    <category>Politics</category>
+    <category>Budget</category>
+    <pubDate>Fri, 14 Mar 2025 09:00:00 +0000</pubDate>
+  </item>
+  <item>
+    <title>Derby result</title>
+    <link>https://wire.example/derby</link>
+    <description>&lt;p&gt;The home side won&lt;/p&gt;</description>
+    <pubDate>Thu, 13 Mar 2025 18:30:00 GMT</pubDate>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Agency</title>
+  <entry>
+    <id>tag:agency.example,2025:1</id>
+    <title>Storm warning</title>
+    <link rel="self" href="https://agency.example/api/1"/>
+    <link rel="alternate" href="https://agency.example/storm"/>
+    <summary>Short</summary>
+    <content type="html">&lt;p&gt;Heavy rain expected tonight&lt;/p&gt;</content>
+    <author><name>Boris</name></author>
+    <category term="weather"/>
+    <published>2025-03-14T09:00:00+03:00</published>
+    <updated>2025-03-14T10:00:00Z</updated>
+  </entry>
+  <entry>
+    <id>tag:agency.example,2025:2</id>
+    <title>Update only</title>
+    <link href="https://agency.example/update"/>
+    <summary>Summary text only</summary>
+    <updated>2025-03-14T10:00:00Z</updated>
+  </entry>
+</feed>`
+
+const rdfFeed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel><title>Old</title></channel>
+  <item>
+    <title>Legacy item</title>
+    <link>https://old.example/1</link>
+    <description>Legacy description</description>
+    <dc:date>2025-03-14T09:00:00Z</dc:date>
+  </item>
+</rdf:RDF>`
+
+func TestParse_RSS(t *testing.T) {
+	items, err := Parse(strings.NewReader(rssFeed))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, Item{
+		GUID:       "wire-1",
+		Link:       "https://wire.example/budget",
+		Title:      "Budget & taxes",
+		Content:    "<p>Parliament passed the <b>budget</b>.</p><p>Next paragraph</p>",
+		Author:     "Anna",
+		Categories: []string{"Politics", "Budget"},
+		Published:  time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC),
+	}, normalizeTime(items[0]))
+
+	assert.Empty(t, items[1].GUID)
+	assert.Equal(t, "<p>The home side won</p>", items[1].Content)
+	assert.Equal(t, time.Date(2025, 3, 13, 18, 30, 0, 0, time.UTC), items[1].Published.UTC())
+}
+
+func TestParse_Atom(t *testing.T) {
+	items, err := Parse(strings.NewReader(atomFeed))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	assert.Equal(t, Item{
+		GUID:       "tag:agency.example,2025:1",
+		Link:       "https://agency.example/storm",
+		Title:      "Storm warning",
+		Content:    "<p>Heavy rain expected tonight</p>",
+		Author:     "Boris",
+		Categories: []string{"weather"},
+		Published:  time.Date(2025, 3, 14, 6, 0, 0, 0, time.UTC),
+	}, normalizeTime(items[0]))
+
+	assert.Equal(t, "https://agency.example/update", items[1].Link)
+	assert.Equal(t, "Summary text only", items[1].Content)
+	assert.Equal(t, time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC), items[1].Published.UTC())
+}
+
+func TestParse_RDF(t *testing.T) {
+	items, err := Parse(strings.NewReader(rdfFeed))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Legacy item", items[0].Title)
+	assert.Equal(t, "https://old.example/1", items[0].Link)
+	assert.Equal(t, time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC), items[0].Published)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<html><body>Not a feed</body></html>`))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = Parse(strings.NewReader(``))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = Parse(strings.NewReader(`<rss><channel><item>`))
+	assert.Error(t, err)
+}
+
+func normalizeTime(item Item) Item {
+	item.Published = item.Published.UTC()
+	return item
+}
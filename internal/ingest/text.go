@@ -0,0 +1,34 @@
+package ingest
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// blockTags end a paragraph, line break tags a line.
+	blockTags   = regexp.MustCompile(`(?i)</?(p|div|h[1-6]|ul|ol|blockquote|pre|table|section|article)\b[^>]*>`)
+	breakTags   = regexp.MustCompile(`(?i)<(br|li|tr)\b[^>]*>`)
+	droppedTags = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>`)
+	anyTag      = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText converts feed HTML to the plain text posts are stored as. Block
+// elements become blank-line separated paragraphs, matching how post content
+// is rendered back to HTML.
+func PlainText(s string) string {
+	s = droppedTags.ReplaceAllString(s, "")
+	s = blockTags.ReplaceAllString(s, "\n\n")
+	s = breakTags.ReplaceAllString(s, "\n")
+	s = anyTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	s = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
@@ -0,0 +1,28 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{name: "plain", html: "  Just text  ", want: "Just text"},
+		{name: "paragraphs", html: "<p>First <b>bold</b></p>\n<p>Second</p>", want: "First bold\n\nSecond"},
+		{name: "line breaks", html: "One<br>Two<br/>Three", want: "One\nTwo\nThree"},
+		{name: "entities", html: "Fish &amp; chips &quot;today&quot;", want: `Fish & chips "today"`},
+		{name: "scripts dropped", html: "<script>alert(1)</script>Safe<style>p{}</style>", want: "Safe"},
+		{name: "list", html: "<ul><li>a</li><li>b</li></ul>", want: "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PlainText(tt.html))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package live
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// PostEvent is the name of the server-sent events carrying post changes.
+const PostEvent = "post"
+
+// PostBroadcaster implements domain.PostEventHandler. It renders every post
+// change as an HTML fragment with htmx out-of-band swaps and publishes it to
+// the hub, so that open pages insert, replace or remove the post card.
+type PostBroadcaster struct {
+	hub       *Hub
+	templates *template.Template
+}
+
+// NewPostBroadcaster creates a broadcaster rendering the "post/live-*" templates.
+func NewPostBroadcaster(hub *Hub, templates *template.Template) *PostBroadcaster {
+	return &PostBroadcaster{
+		hub:       hub,
+		templates: templates,
+	}
+}
+
+// HandlePostEvent implements domain.PostEventHandler
+func (b *PostBroadcaster) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	var name string
+	var data any
+	switch event.Type {
+	case domain.PostCreated:
+		name, data = "post/live-created", event.Post
+	case domain.PostUpdated:
+		name, data = "post/live-updated", event.Post
+	case domain.PostDeleted:
+		name, data = "post/live-deleted", event.PostID
+	case domain.PostBreaking:
+		name, data = "post/live-breaking", event.Post
+	default:
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := b.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	b.hub.Publish(PostEvent, buf.String())
+	return nil
+}
@@ -0,0 +1,76 @@
+package live
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPostBroadcaster(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("")
+	defer sub.Close()
+	broadcaster := NewPostBroadcaster(hub, template.Must(templates.Parse("../../templates")))
+	ctx := context.Background()
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved", Content: "The council approved the budget."}
+	id := post.ID.Hex()
+
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostCreated, PostID: id, Post: post}))
+	msg := <-sub.C
+	assert.Equal(t, PostEvent, msg.Event)
+	assert.Contains(t, msg.Data, `hx-swap-oob="afterbegin:#posts-grid[data-live]"`)
+	assert.Contains(t, msg.Data, `id="post-`+id+`"`)
+	assert.Contains(t, msg.Data, "Budget approved")
+
+	post.Title = "Budget approved after debate"
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: id, Post: post}))
+	msg = <-sub.C
+	assert.Contains(t, msg.Data, `id="post-`+id+`" hx-swap-oob="true"`)
+	assert.Contains(t, msg.Data, "Budget approved after debate")
+
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostPublished, PostID: id, Post: post}))
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostDeleted, PostID: id}))
+	msg = <-sub.C
+	assert.Contains(t, msg.Data, `<div id="post-`+id+`" hx-swap-oob="delete"></div>`)
+	assert.Contains(t, msg.Data, `<div id="breaking-`+id+`" hx-swap-oob="delete"></div>`)
+	assert.Empty(t, sub.C, "published events are not broadcast")
+}
+
+func TestPostBroadcaster_Breaking(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("")
+	defer sub.Close()
+	broadcaster := NewPostBroadcaster(hub, template.Must(templates.Parse("../../templates")))
+	ctx := context.Background()
+
+	breaking, err := domain.NewBreaking(time.Now(), time.Hour)
+	require.NoError(t, err)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Earthquake hits the coast", Content: "A strong earthquake was felt.", Breaking: breaking}
+	id := post.ID.Hex()
+
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostBreaking, PostID: id, Post: post}))
+	msg := <-sub.C
+	assert.Contains(t, msg.Data, `<div id="breaking-`+id+`" hx-swap-oob="delete"></div>`)
+	assert.Contains(t, msg.Data, `hx-swap-oob="afterbegin:#breaking-items"`)
+	assert.Contains(t, msg.Data, "Earthquake hits the coast")
+
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: id, Post: post}))
+	msg = <-sub.C
+	assert.Contains(t, msg.Data, "Breaking</span>", "the card shows the badge")
+	assert.Contains(t, msg.Data, `hx-swap-oob="afterbegin:#breaking-items"`, "the banner item follows the new title")
+
+	post.Breaking = nil
+	require.NoError(t, broadcaster.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostBreaking, PostID: id, Post: post}))
+	msg = <-sub.C
+	assert.Contains(t, msg.Data, `<div id="breaking-`+id+`" hx-swap-oob="delete"></div>`)
+	assert.NotContains(t, msg.Data, "afterbegin")
+}
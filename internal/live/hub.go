@@ -0,0 +1,168 @@
+package live
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is one server-sent event. ID is unique across restarts of the
+// process, so that a client reconnecting with an ID from an earlier process
+// does not get unrelated messages replayed.
+type Message struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// Hub is an in-process publish/subscribe hub. It keeps the latest messages so
+// that reconnecting subscribers can catch up on what they missed.
+type Hub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []Message
+	subs    map[*Subscription]struct{}
+	closed  bool
+
+	historySize int
+	bufferSize  int
+}
+
+// Option configures optional Hub settings.
+type Option func(*Hub)
+
+// WithHistory sets how many recent messages are kept for replay.
+func WithHistory(n int) Option {
+	return func(h *Hub) {
+		h.historySize = n
+	}
+}
+
+// WithSubscriberBuffer sets how many messages a subscriber may fall behind
+// before it is dropped.
+func WithSubscriberBuffer(n int) Option {
+	return func(h *Hub) {
+		h.bufferSize = n
+	}
+}
+
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:        make(map[*Subscription]struct{}),
+		historySize: 100,
+		bufferSize:  32,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Subscription receives the messages published after it was created. C is
+// closed when the subscription is closed, when the subscriber fell too far
+// behind or when the hub shuts down.
+type Subscription struct {
+	C <-chan Message
+
+	c   chan Message
+	hub *Hub
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s)
+}
+
+// Subscribe registers a subscriber. It also returns the kept messages
+// published after lastEventID, the ID of the last message a reconnecting
+// client received. It returns a nil subscription once the hub is closed.
+func (h *Hub) Subscribe(lastEventID string) (*Subscription, []Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, nil
+	}
+
+	c := make(chan Message, h.bufferSize)
+	sub := &Subscription{C: c, c: c, hub: h}
+	h.subs[sub] = struct{}{}
+	return sub, h.since(lastEventID)
+}
+
+// since returns the kept messages after the message with the given ID.
+func (h *Hub) since(lastEventID string) []Message {
+	epoch, seq, ok := strings.Cut(lastEventID, "-")
+	if !ok || epoch != h.epoch {
+		return nil
+	}
+	last, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || last >= h.seq {
+		return nil
+	}
+
+	// history holds the messages h.seq-len(history)+1 through h.seq.
+	skip := len(h.history) - int(h.seq-last)
+	if skip < 0 {
+		skip = 0
+	}
+	return append([]Message(nil), h.history[skip:]...)
+}
+
+// Publish sends a message to every subscriber and returns it. Subscribers
+// whose buffer is full are dropped; they catch up when they reconnect.
+func (h *Hub) Publish(event, data string) Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	msg := Message{
+		ID:    h.epoch + "-" + strconv.FormatUint(h.seq, 10),
+		Event: event,
+		Data:  data,
+	}
+	h.history = append(h.history, msg)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subs {
+		select {
+		case sub.c <- msg:
+		default:
+			h.drop(sub)
+		}
+	}
+	return msg
+}
+
+// Len returns the number of subscribers.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close closes every subscription and rejects new ones, so that open streams
+// end and the HTTP server can shut down.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		h.drop(sub)
+	}
+}
+
+// drop removes sub and closes its channel. h.mu must be held.
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	close(sub.c)
+}
@@ -0,0 +1,90 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := NewHub()
+	sub, replay := hub.Subscribe("")
+	require.NotNil(t, sub)
+	assert.Empty(t, replay)
+	assert.Equal(t, 1, hub.Len())
+
+	first := hub.Publish("post", "one")
+	second := hub.Publish("post", "two")
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, first, <-sub.C)
+	assert.Equal(t, second, <-sub.C)
+
+	sub.Close()
+	sub.Close()
+	assert.Zero(t, hub.Len())
+	_, ok := <-sub.C
+	assert.False(t, ok)
+}
+
+func TestHub_Replay(t *testing.T) {
+	hub := NewHub(WithHistory(3))
+	var ids []string
+	for _, data := range []string{"a", "b", "c", "d", "e"} {
+		ids = append(ids, hub.Publish("post", data).ID)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{name: "no ID", lastEventID: "", want: nil},
+		{name: "up to date", lastEventID: ids[4], want: nil},
+		{name: "missed two", lastEventID: ids[2], want: []string{"d", "e"}},
+		{name: "older than history", lastEventID: ids[0], want: []string{"c", "d", "e"}},
+		{name: "earlier process", lastEventID: "abc-3", want: nil},
+		{name: "malformed", lastEventID: "garbage", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, replay := hub.Subscribe(tt.lastEventID)
+			defer sub.Close()
+			var got []string
+			for _, msg := range replay {
+				got = append(got, msg.Data)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(WithSubscriberBuffer(1))
+	slow, _ := hub.Subscribe("")
+	fast, _ := hub.Subscribe("")
+
+	hub.Publish("post", "one")
+	<-fast.C
+	hub.Publish("post", "two")
+
+	assert.Equal(t, 1, hub.Len())
+	assert.Equal(t, "one", (<-slow.C).Data)
+	_, ok := <-slow.C
+	assert.False(t, ok, "the slow subscriber is closed")
+	assert.Equal(t, "two", (<-fast.C).Data)
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("")
+
+	hub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	sub.Close()
+
+	late, _ := hub.Subscribe("")
+	assert.Nil(t, late)
+	hub.Publish("post", "after close")
+}
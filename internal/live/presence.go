@@ -0,0 +1,38 @@
+package live
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/kir/news-app/internal/presence"
+
+	"go.uber.org/zap"
+)
+
+// PresenceBroadcaster implements presence.Listener. It publishes the editing
+// badge of a post whenever its editors change, so that open pages show who
+// is editing which post.
+type PresenceBroadcaster struct {
+	hub       *Hub
+	templates *template.Template
+	logger    *zap.Logger
+}
+
+// NewPresenceBroadcaster creates a broadcaster rendering the "post/presence-badge" template.
+func NewPresenceBroadcaster(hub *Hub, templates *template.Template, logger *zap.Logger) *PresenceBroadcaster {
+	return &PresenceBroadcaster{
+		hub:       hub,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// EditorsChanged implements presence.Listener
+func (b *PresenceBroadcaster) EditorsChanged(editors presence.PostEditors) {
+	var buf bytes.Buffer
+	if err := b.templates.ExecuteTemplate(&buf, "post/presence-badge", editors); err != nil {
+		b.logger.Error("failed to render presence badge", zap.String("post_id", editors.PostID), zap.Error(err))
+		return
+	}
+	b.hub.Publish(PostEvent, buf.String())
+}
@@ -0,0 +1,34 @@
+package live
+
+import (
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/presence"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPresenceBroadcaster(t *testing.T) {
+	hub := NewHub()
+	sub, _ := hub.Subscribe("")
+	defer sub.Close()
+	broadcaster := NewPresenceBroadcaster(hub, template.Must(templates.Parse("../../templates")), zap.NewNop())
+
+	now := time.Now()
+	broadcaster.EditorsChanged(presence.PostEditors{PostID: "post-1", Editors: []presence.Editor{
+		{Session: "s1", Name: "Anna", Since: now},
+		{Session: "s2", Name: "Boris", Since: now},
+	}})
+	msg := <-sub.C
+	assert.Equal(t, PostEvent, msg.Event)
+	assert.Contains(t, msg.Data, `id="presence-post-1" hx-swap-oob="true"`)
+	assert.Contains(t, msg.Data, "Anna and Boris are editing")
+
+	broadcaster.EditorsChanged(presence.PostEditors{PostID: "post-1"})
+	msg = <-sub.C
+	assert.Contains(t, msg.Data, `class="empty:hidden mb-3"></div>`, "the badge is emptied once nobody edits")
+}
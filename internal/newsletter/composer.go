@@ -0,0 +1,97 @@
+package newsletter
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// Composer renders the emails sent to subscribers. Every email has an HTML
+// and a plain-text template of the same name.
+type Composer struct {
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+	baseURL string
+}
+
+// NewComposer creates a composer linking to the site at baseURL.
+func NewComposer(html *htmltemplate.Template, text *texttemplate.Template, baseURL string) *Composer {
+	return &Composer{
+		html:    html,
+		text:    text,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// emailView is the data of the email templates.
+type emailView struct {
+	Subscriber     *domain.Subscriber
+	Posts          []*domain.Post
+	Since          time.Time
+	BaseURL        string
+	ConfirmURL     string
+	UnsubscribeURL string
+}
+
+// PostURL returns the absolute URL of a post.
+func (v emailView) PostURL(p *domain.Post) string {
+	return v.BaseURL + "/posts/" + p.ID.Hex()
+}
+
+func (c *Composer) view(sub *domain.Subscriber) emailView {
+	token := url.QueryEscape(sub.Token)
+	return emailView{
+		Subscriber:     sub,
+		BaseURL:        c.baseURL,
+		ConfirmURL:     c.baseURL + "/newsletter/confirm?token=" + token,
+		UnsubscribeURL: c.baseURL + "/newsletter/unsubscribe?token=" + token,
+	}
+}
+
+// Confirmation renders the email asking a new subscriber to confirm its address.
+func (c *Composer) Confirmation(sub *domain.Subscriber) (*domain.EmailMessage, error) {
+	msg := &domain.EmailMessage{
+		To:      sub.Email,
+		Subject: "Confirm your News Portal subscription",
+	}
+	return msg, c.render(msg, "newsletter/confirm-email", c.view(sub))
+}
+
+// Digest renders the digest of posts, newest first, for sub.
+func (c *Composer) Digest(sub *domain.Subscriber, posts []*domain.Post) (*domain.EmailMessage, error) {
+	view := c.view(sub)
+	view.Posts = posts
+	view.Since = sub.LastDigestAt
+
+	subject := "Your daily News Portal digest"
+	if sub.Frequency == domain.DigestWeekly {
+		subject = "Your weekly News Portal digest"
+	}
+	if len(posts) > 0 {
+		subject += ": " + posts[0].Title
+	}
+	msg := &domain.EmailMessage{
+		To:          sub.Email,
+		Subject:     subject,
+		Unsubscribe: view.UnsubscribeURL,
+	}
+	return msg, c.render(msg, "newsletter/digest-email", view)
+}
+
+func (c *Composer) render(msg *domain.EmailMessage, name string, view emailView) error {
+	var html, text bytes.Buffer
+	if err := c.html.ExecuteTemplate(&html, name, view); err != nil {
+		return err
+	}
+	if err := c.text.ExecuteTemplate(&text, name, view); err != nil {
+		return err
+	}
+	msg.HTML = html.String()
+	msg.Text = strings.TrimSpace(text.String()) + "\n"
+	return nil
+}
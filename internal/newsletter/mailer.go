@@ -0,0 +1,175 @@
+package newsletter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// SMTPMailer implements domain.Mailer by handing messages to an SMTP server.
+// It upgrades the connection with STARTTLS when the server offers it.
+type SMTPMailer struct {
+	addr    string
+	from    *mail.Address
+	auth    smtp.Auth
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// MailerOption configures optional SMTPMailer settings.
+type MailerOption func(*SMTPMailer)
+
+// WithAuth sets the credentials used when the server supports AUTH.
+func WithAuth(auth smtp.Auth) MailerOption {
+	return func(m *SMTPMailer) {
+		m.auth = auth
+	}
+}
+
+// WithTimeout bounds each message exchange with the server.
+func WithTimeout(timeout time.Duration) MailerOption {
+	return func(m *SMTPMailer) {
+		m.timeout = timeout
+	}
+}
+
+// NewSMTPMailer creates a mailer sending from the address from, e.g.
+// "News Portal <newsletter@news.example>", through the server at addr.
+func NewSMTPMailer(addr, from string, opts ...MailerOption) (*SMTPMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	m := &SMTPMailer{
+		addr:    addr,
+		from:    sender,
+		timeout: 30 * time.Second,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Send implements domain.Mailer
+func (m *SMTPMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	body, err := m.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(m.addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if ok, _ := c.Extension("AUTH"); ok && m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("sender rejected: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("recipient rejected: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+	return c.Quit()
+}
+
+// compose renders msg as a multipart/alternative MIME message with CRLF line
+// endings and quoted-printable bodies.
+func (m *SMTPMailer) compose(msg *domain.EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	domainPart := m.from.Address[strings.LastIndex(m.from.Address, "@")+1:]
+
+	header := []string{
+		"From: " + m.from.String(),
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + m.now().Format(time.RFC1123Z),
+		"Message-ID: <" + hex.EncodeToString(id) + "@" + domainPart + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	if msg.Unsubscribe != "" {
+		header = append(header,
+			"List-Unsubscribe: <"+msg.Unsubscribe+">",
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose message: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		qp.Write([]byte(strings.ReplaceAll(part.body, "\n", "\r\n")))
+		qp.Close()
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compose message: %w", err)
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
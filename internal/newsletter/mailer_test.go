@@ -0,0 +1,159 @@
+package newsletter
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one session, answers every command with success
+// unless it is listed in reject, and records the envelope and message.
+type fakeSMTPServer struct {
+	addr   string
+	reject map[string]string
+	done   chan struct{}
+
+	from, to string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T, reject map[string]string) *fakeSMTPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTPServer{addr: ln.Addr().String(), reject: reject, done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.serve(conn)
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.Fields(line + " ")[0])
+		if msg, ok := s.reject[verb]; ok {
+			reply(msg)
+			continue
+		}
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.to = line
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.data = data.String()
+			reply("250 OK: queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	mailer, err := NewSMTPMailer(server.addr, "News Portal <newsletter@news.example>")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), &domain.EmailMessage{
+		To:          "anna@example.com",
+		Subject:     "Your daily News Portal digest: Bürgermeister gewählt",
+		HTML:        "<p>Hello</p>\n",
+		Text:        "Hello\n",
+		Unsubscribe: "https://news.example/newsletter/unsubscribe?token=abc",
+	})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Equal(t, "MAIL FROM:<newsletter@news.example> BODY=8BITMIME", server.from)
+	assert.Equal(t, "RCPT TO:<anna@example.com>", server.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(server.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Your daily News Portal digest: Bürgermeister gewählt", subject)
+	assert.Equal(t, `"News Portal" <newsletter@news.example>`, msg.Header.Get("From"))
+	assert.Equal(t, "<https://news.example/newsletter/unsubscribe?token=abc>", msg.Header.Get("List-Unsubscribe"))
+	assert.NotEmpty(t, msg.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8: Hello\r\n",
+		"text/html; charset=utf-8: <p>Hello</p>\r\n",
+	}, bodies)
+}
+
+func TestSMTPMailer_SendRejected(t *testing.T) {
+	server := newFakeSMTPServer(t, map[string]string{"RCPT": "550 No such user"})
+	mailer, err := NewSMTPMailer(server.addr, "newsletter@news.example")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), &domain.EmailMessage{To: "nobody@example.com", Subject: "Hi", Text: "Hi", HTML: "Hi"})
+	assert.ErrorContains(t, err, "recipient rejected")
+	assert.ErrorContains(t, err, "No such user")
+}
+
+func TestNewSMTPMailer_InvalidSender(t *testing.T) {
+	_, err := NewSMTPMailer("localhost:25", "not an address")
+	assert.Error(t, err)
+}
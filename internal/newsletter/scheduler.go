@@ -0,0 +1,152 @@
+package newsletter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// lease is how long a claimed subscriber stays hidden from other schedulers.
+// A scheduler that dies while sending leaves the digest to be sent after it.
+const lease = 5 * time.Minute
+
+// PostSource provides the posts of a digest.
+type PostSource interface {
+	GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+}
+
+// Scheduler sends every confirmed subscriber a digest of the published posts
+// created since its previous one, once a day or once a week. A subscriber
+// without new posts gets no email; its next digest is simply scheduled.
+type Scheduler struct {
+	subscribers domain.SubscriberRepository
+	sends       domain.NewsletterSendRepository
+	posts       PostSource
+	composer    *Composer
+	mailer      domain.Mailer
+	tick        time.Duration
+	now         func() time.Time
+	logger      *zap.Logger
+}
+
+// Option configures optional Scheduler settings.
+type Option func(*Scheduler)
+
+// WithTick sets how often the scheduler looks for due digests.
+func WithTick(tick time.Duration) Option {
+	return func(s *Scheduler) {
+		s.tick = tick
+	}
+}
+
+// WithLogger sets the logger used to report failed digests.
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = logger
+	}
+}
+
+func NewScheduler(subscribers domain.SubscriberRepository, sends domain.NewsletterSendRepository, posts PostSource, composer *Composer, mailer domain.Mailer, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		subscribers: subscribers,
+		sends:       sends,
+		posts:       posts,
+		composer:    composer,
+		mailer:      mailer,
+		tick:        time.Minute,
+		now:         time.Now,
+		logger:      zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run sends due digests every tick until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.Info("starting newsletter scheduler", zap.Duration("tick", s.tick))
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && s.SendNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("newsletter scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendNext claims one subscriber whose digest is due and sends it. It reports
+// whether a subscriber was claimed.
+func (s *Scheduler) SendNext(ctx context.Context) bool {
+	sub, err := s.subscribers.ClaimDue(ctx, s.now(), lease)
+	if err != nil {
+		s.logger.Error("failed to claim subscriber", zap.Error(err))
+		return false
+	}
+	if sub == nil {
+		return false
+	}
+
+	if err := s.send(ctx, sub); err != nil {
+		s.logger.Warn("failed to send digest",
+			zap.String("subscriber_id", sub.ID.Hex()),
+			zap.Time("next_digest_at", sub.NextDigestAt),
+			zap.Error(err),
+		)
+	}
+	if err := s.subscribers.Save(ctx, sub); err != nil {
+		s.logger.Error("failed to save subscriber", zap.String("subscriber_id", sub.ID.Hex()), zap.Error(err))
+	}
+	return true
+}
+
+// send sends the digest of sub and schedules the next one. Only emails
+// handed to the mailer are logged.
+func (s *Scheduler) send(ctx context.Context, sub *domain.Subscriber) error {
+	now := s.now()
+	list, err := s.posts.GetPaginated(ctx, domain.PostQuery{
+		Page:      1,
+		PageSize:  domain.MaxDigestPosts,
+		SkipCount: true,
+		Sort:      domain.SortNewest,
+		Status:    domain.PostStatusPublished,
+		From:      sub.LastDigestAt,
+		To:        now,
+	})
+	if err != nil {
+		sub.DigestFailed(now)
+		return fmt.Errorf("failed to get posts: %w", err)
+	}
+	if len(list.Posts) == 0 {
+		sub.DigestSent(now)
+		return nil
+	}
+
+	msg, err := s.composer.Digest(sub, list.Posts)
+	if err != nil {
+		sub.DigestFailed(now)
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	sendErr := s.mailer.Send(ctx, msg)
+	if err := s.sends.Create(ctx, domain.NewNewsletterSend(sub, domain.EmailDigest, msg, len(list.Posts), now, sendErr)); err != nil {
+		s.logger.Error("failed to log newsletter send", zap.String("subscriber_id", sub.ID.Hex()), zap.Error(err))
+	}
+	if sendErr != nil {
+		sub.DigestFailed(now)
+		return sendErr
+	}
+	sub.DigestSent(now)
+	return nil
+}
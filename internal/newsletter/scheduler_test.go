@@ -0,0 +1,164 @@
+package newsletter
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memorySubscribers is an in-memory domain.SubscriberRepository holding one
+// subscriber, enough for the scheduler.
+type memorySubscribers struct {
+	domain.SubscriberRepository
+	sub   *domain.Subscriber
+	saved []domain.Subscriber
+}
+
+func (m *memorySubscribers) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.Subscriber, error) {
+	if m.sub == nil || !m.sub.Confirmed() || m.sub.NextDigestAt.After(now) {
+		return nil, nil
+	}
+	m.sub.NextDigestAt = now.Add(lease)
+	claimed := *m.sub
+	return &claimed, nil
+}
+
+func (m *memorySubscribers) Save(ctx context.Context, sub *domain.Subscriber) error {
+	*m.sub = *sub
+	m.saved = append(m.saved, *sub)
+	return nil
+}
+
+type memorySends struct {
+	domain.NewsletterSendRepository
+	sends []*domain.NewsletterSend
+}
+
+func (m *memorySends) Create(ctx context.Context, send *domain.NewsletterSend) error {
+	m.sends = append(m.sends, send)
+	return nil
+}
+
+type fakePosts struct {
+	posts []*domain.Post
+	query domain.PostQuery
+}
+
+func (f *fakePosts) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	f.query = query
+	return &domain.PostList{Posts: f.posts}, nil
+}
+
+type fakeMailer struct {
+	err  error
+	sent []*domain.EmailMessage
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func newTestComposer(t *testing.T) *Composer {
+	text, err := templates.ParseText("../../templates")
+	require.NoError(t, err)
+	return NewComposer(template.Must(templates.Parse("../../templates")), text, "https://news.example/")
+}
+
+func confirmedSubscriber(t *testing.T, confirmedAt time.Time) *domain.Subscriber {
+	sub, err := domain.NewSubscriber("anna@example.com", domain.DigestDaily)
+	require.NoError(t, err)
+	sub.Confirm(confirmedAt)
+	return sub
+}
+
+func TestComposer(t *testing.T) {
+	composer := newTestComposer(t)
+	sub := confirmedSubscriber(t, time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+
+	msg, err := composer.Confirmation(sub)
+	require.NoError(t, err)
+	assert.Equal(t, "anna@example.com", msg.To)
+	assert.Contains(t, msg.HTML, `href="https://news.example/newsletter/confirm?token=`+sub.Token+`"`)
+	assert.Contains(t, msg.Text, "https://news.example/newsletter/confirm?token="+sub.Token)
+	assert.Empty(t, msg.Unsubscribe)
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Tom & Jerry return", Content: "The classic cartoon is back on screens.", Category: "culture"}
+	msg, err = composer.Digest(sub, []*domain.Post{post})
+	require.NoError(t, err)
+	assert.Equal(t, "Your daily News Portal digest: Tom & Jerry return", msg.Subject)
+	assert.Equal(t, "https://news.example/newsletter/unsubscribe?token="+sub.Token, msg.Unsubscribe)
+	assert.Contains(t, msg.HTML, "Tom &amp; Jerry return")
+	assert.Contains(t, msg.HTML, `href="https://news.example/posts/`+post.ID.Hex()+`"`)
+	assert.Contains(t, msg.HTML, "New since March 1, 2026 08:00")
+	assert.Contains(t, msg.Text, "Tom & Jerry return\nThe classic cartoon is back on screens.\nhttps://news.example/posts/"+post.ID.Hex())
+	assert.Contains(t, msg.Text, "Unsubscribe: https://news.example/newsletter/unsubscribe?token="+sub.Token)
+}
+
+func newTestScheduler(t *testing.T, sub *domain.Subscriber, posts []*domain.Post) (*Scheduler, *memorySubscribers, *memorySends, *fakePosts, *fakeMailer) {
+	subscribers := &memorySubscribers{sub: sub}
+	sends := &memorySends{}
+	source := &fakePosts{posts: posts}
+	mailer := &fakeMailer{}
+	return NewScheduler(subscribers, sends, source, newTestComposer(t), mailer), subscribers, sends, source, mailer
+}
+
+func TestScheduler_SendNext(t *testing.T) {
+	confirmedAt := time.Now().Add(-25 * time.Hour)
+	sub := confirmedSubscriber(t, confirmedAt)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved", Content: "The council approved the budget."}
+	scheduler, subscribers, sends, source, mailer := newTestScheduler(t, sub, []*domain.Post{post})
+	now := time.Now()
+	scheduler.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.True(t, scheduler.SendNext(ctx))
+	assert.Equal(t, confirmedAt, source.query.From)
+	assert.Equal(t, now, source.query.To)
+	assert.Equal(t, domain.PostStatusPublished, source.query.Status)
+	require.Len(t, mailer.sent, 1)
+	assert.Contains(t, mailer.sent[0].Text, "Budget approved")
+
+	require.Len(t, sends.sends, 1)
+	assert.Equal(t, domain.EmailDigest, sends.sends[0].Kind)
+	assert.Equal(t, 1, sends.sends[0].PostCount)
+	assert.False(t, sends.sends[0].Failed())
+
+	assert.Equal(t, now, subscribers.sub.LastDigestAt)
+	assert.Equal(t, now.Add(24*time.Hour), subscribers.sub.NextDigestAt)
+	assert.False(t, scheduler.SendNext(ctx), "the next digest is not due yet")
+}
+
+func TestScheduler_SendNextWithoutPosts(t *testing.T) {
+	sub := confirmedSubscriber(t, time.Now().Add(-25*time.Hour))
+	scheduler, subscribers, sends, _, mailer := newTestScheduler(t, sub, nil)
+
+	require.True(t, scheduler.SendNext(context.Background()))
+	assert.Empty(t, mailer.sent)
+	assert.Empty(t, sends.sends)
+	assert.True(t, subscribers.sub.NextDigestAt.After(time.Now().Add(23*time.Hour)))
+}
+
+func TestScheduler_SendNextFailure(t *testing.T) {
+	confirmedAt := time.Now().Add(-25 * time.Hour)
+	sub := confirmedSubscriber(t, confirmedAt)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved", Content: "The council approved the budget."}
+	scheduler, subscribers, sends, _, mailer := newTestScheduler(t, sub, []*domain.Post{post})
+	mailer.err = assert.AnError
+	now := time.Now()
+	scheduler.now = func() time.Time { return now }
+
+	require.True(t, scheduler.SendNext(context.Background()))
+	require.Len(t, sends.sends, 1)
+	assert.True(t, sends.sends[0].Failed())
+	assert.Equal(t, confirmedAt, subscribers.sub.LastDigestAt, "the posts are sent again")
+	assert.Equal(t, now.Add(domain.DigestRetryDelay), subscribers.sub.NextDigestAt)
+}
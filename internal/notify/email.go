@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// Composer renders the notification emails. Every email has an HTML and a
+// plain-text template of the same name.
+type Composer struct {
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+	baseURL string
+}
+
+// NewComposer creates a composer linking to the site at baseURL.
+func NewComposer(html *htmltemplate.Template, text *texttemplate.Template, baseURL string) *Composer {
+	return &Composer{
+		html:    html,
+		text:    text,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// emailView is the data of the notification email templates.
+type emailView struct {
+	Settings       *domain.NotificationSettings
+	Notification   *domain.Notification
+	BaseURL        string
+	PostURL        string
+	InboxURL       string
+	ConfirmURL     string
+	UnsubscribeURL string
+}
+
+func (c *Composer) view(s *domain.NotificationSettings) emailView {
+	token := url.QueryEscape(s.Token)
+	return emailView{
+		Settings:       s,
+		BaseURL:        c.baseURL,
+		InboxURL:       c.baseURL + "/notifications",
+		ConfirmURL:     c.baseURL + "/notifications/email/confirm?token=" + token,
+		UnsubscribeURL: c.baseURL + "/notifications/email/unsubscribe?token=" + token,
+	}
+}
+
+// Confirmation renders the email asking a reader to confirm the address
+// notifications are emailed to.
+func (c *Composer) Confirmation(s *domain.NotificationSettings) (*domain.EmailMessage, error) {
+	msg := &domain.EmailMessage{
+		To:      s.Email,
+		Subject: "Confirm your News Portal notification emails",
+	}
+	return msg, c.render(msg, "notifications/confirm-email", c.view(s))
+}
+
+// Notification renders the email telling a reader about n.
+func (c *Composer) Notification(s *domain.NotificationSettings, n *domain.Notification) (*domain.EmailMessage, error) {
+	view := c.view(s)
+	view.Notification = n
+	view.PostURL = c.baseURL + "/posts/" + n.PostID.Hex()
+
+	subject := "New in " + n.Reason.Value + ": " + n.Title
+	switch n.Reason.Kind {
+	case domain.FollowAuthor:
+		subject = "New from " + n.Reason.Value + ": " + n.Title
+	case domain.FollowTag:
+		subject = "New in #" + n.Reason.Value + ": " + n.Title
+	}
+	msg := &domain.EmailMessage{
+		To:          s.Email,
+		Subject:     subject,
+		Unsubscribe: view.UnsubscribeURL,
+	}
+	return msg, c.render(msg, "notifications/email", view)
+}
+
+func (c *Composer) render(msg *domain.EmailMessage, name string, view emailView) error {
+	var html, text bytes.Buffer
+	if err := c.html.ExecuteTemplate(&html, name, view); err != nil {
+		return err
+	}
+	if err := c.text.ExecuteTemplate(&text, name, view); err != nil {
+		return err
+	}
+	msg.HTML = html.String()
+	msg.Text = strings.TrimSpace(text.String()) + "\n"
+	return nil
+}
+
+// EmailNotifier implements domain.Notifier by emailing notifications to the
+// readers who confirmed an address. Readers without one are skipped.
+type EmailNotifier struct {
+	settings domain.NotificationSettingsRepository
+	composer *Composer
+	mailer   domain.Mailer
+}
+
+// NewEmailNotifier creates a notifier sending through mailer.
+func NewEmailNotifier(settings domain.NotificationSettingsRepository, composer *Composer, mailer domain.Mailer) *EmailNotifier {
+	return &EmailNotifier{
+		settings: settings,
+		composer: composer,
+		mailer:   mailer,
+	}
+}
+
+// Notify implements domain.Notifier
+func (e *EmailNotifier) Notify(ctx context.Context, n *domain.Notification) error {
+	s, err := e.settings.Get(ctx, n.ReaderID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if !s.EmailConfirmed() {
+		return nil
+	}
+
+	msg, err := e.composer.Notification(s, n)
+	if err != nil {
+		return fmt.Errorf("failed to render notification email: %w", err)
+	}
+	return e.mailer.Send(ctx, msg)
+}
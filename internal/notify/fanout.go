@@ -0,0 +1,169 @@
+// Package notify turns published posts into notifications for the readers
+// following their category or author.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// FanOut writes a notification to the inbox of every follower of a post when
+// it is published, and hands the new notifications to notifiers such as
+// email from a pool of workers. Fanning out the same event again notifies
+// nobody twice, as a reader has at most one notification per post.
+type FanOut struct {
+	follows       domain.FollowRepository
+	notifications domain.NotificationRepository
+	notifiers     []domain.Notifier
+	workers       int
+	queue         chan *domain.Notification
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// Option configures optional FanOut settings.
+type Option func(*FanOut)
+
+// WithNotifiers adds notifiers that deliver every new notification.
+func WithNotifiers(notifiers ...domain.Notifier) Option {
+	return func(f *FanOut) {
+		f.notifiers = append(f.notifiers, notifiers...)
+	}
+}
+
+// WithWorkers sets the number of notifications delivered concurrently.
+func WithWorkers(n int) Option {
+	return func(f *FanOut) {
+		f.workers = n
+	}
+}
+
+// WithLogger sets the logger used to report failed deliveries.
+func WithLogger(logger *zap.Logger) Option {
+	return func(f *FanOut) {
+		f.logger = logger
+	}
+}
+
+func NewFanOut(follows domain.FollowRepository, notifications domain.NotificationRepository, opts ...Option) *FanOut {
+	f := &FanOut{
+		follows:       follows,
+		notifications: notifications,
+		workers:       2,
+		queue:         make(chan *domain.Notification, 256),
+		now:           time.Now,
+		logger:        zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.workers < 1 {
+		f.workers = 1
+	}
+	return f
+}
+
+// Publish implements domain.EventPublisher so that the outbox relay feeds the
+// fan-out. A failed fan-out is retried with the event; notifications of a
+// deleted post are removed from every inbox.
+func (f *FanOut) Publish(ctx context.Context, event domain.PostEvent) error {
+	switch event.Type {
+	case domain.PostPublished:
+		if event.Post == nil {
+			return nil
+		}
+		return f.fanOut(ctx, event.Post)
+	case domain.PostDeleted:
+		return f.notifications.DeleteByPost(ctx, event.PostID)
+	}
+	return nil
+}
+
+// fanOut notifies the followers of post. A reader following both its
+// category and its author is notified once, for the category.
+func (f *FanOut) fanOut(ctx context.Context, post *domain.Post) error {
+	targets := domain.PostFollowTargets(post)
+	follows, err := f.follows.GetByTargets(ctx, targets)
+	if err != nil {
+		return fmt.Errorf("failed to load followers: %w", err)
+	}
+
+	reasons := make(map[string]domain.FollowTarget, len(follows))
+	for _, target := range targets {
+		for _, follow := range follows {
+			if _, ok := reasons[follow.ReaderID]; !ok && follow.FollowTarget == target {
+				reasons[follow.ReaderID] = target
+			}
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	now := f.now()
+	notifications := make([]*domain.Notification, 0, len(reasons))
+	for readerID, reason := range reasons {
+		notifications = append(notifications, domain.NewNotification(readerID, post, reason, now))
+	}
+	added, err := f.notifications.Add(ctx, notifications)
+	if err != nil {
+		return fmt.Errorf("failed to add notifications: %w", err)
+	}
+	f.logger.Info("notified followers", zap.String("post_id", post.ID.Hex()), zap.Int("readers", len(added)))
+
+	if len(f.notifiers) == 0 {
+		return nil
+	}
+	for _, n := range added {
+		select {
+		case f.queue <- n:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Run starts the workers delivering new notifications to the notifiers and
+// blocks until ctx is cancelled and every worker has finished its delivery.
+func (f *FanOut) Run(ctx context.Context) {
+	f.logger.Info("starting notification delivery", zap.Int("workers", f.workers), zap.Int("notifiers", len(f.notifiers)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < f.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case n := <-f.queue:
+					f.deliver(ctx, n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	f.logger.Info("notification delivery stopped")
+}
+
+// deliver hands n to every notifier. Failed deliveries are not retried; the
+// notification stays in the inbox.
+func (f *FanOut) deliver(ctx context.Context, n *domain.Notification) {
+	for _, notifier := range f.notifiers {
+		if err := notifier.Notify(ctx, n); err != nil {
+			f.logger.Warn("failed to deliver notification",
+				zap.String("notification_id", n.ID.Hex()),
+				zap.String("post_id", n.PostID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+}
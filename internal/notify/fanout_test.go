@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"context"
+	"html/template"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/templates"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFollows struct {
+	domain.FollowRepository
+	follows []*domain.Follow
+}
+
+func (m *memoryFollows) GetByTargets(ctx context.Context, targets []domain.FollowTarget) ([]*domain.Follow, error) {
+	var matched []*domain.Follow
+	for _, f := range m.follows {
+		for _, t := range targets {
+			if f.FollowTarget == t {
+				matched = append(matched, f)
+			}
+		}
+	}
+	return matched, nil
+}
+
+// memoryNotifications keeps one notification per reader and post, like the
+// unique index of the MongoDB repository.
+type memoryNotifications struct {
+	domain.NotificationRepository
+	notifications []*domain.Notification
+	deleted       []string
+}
+
+func (m *memoryNotifications) Add(ctx context.Context, notifications []*domain.Notification) ([]*domain.Notification, error) {
+	var added []*domain.Notification
+outer:
+	for _, n := range notifications {
+		for _, existing := range m.notifications {
+			if existing.ReaderID == n.ReaderID && existing.PostID == n.PostID {
+				continue outer
+			}
+		}
+		m.notifications = append(m.notifications, n)
+		added = append(added, n)
+	}
+	return added, nil
+}
+
+func (m *memoryNotifications) DeleteByPost(ctx context.Context, postID string) error {
+	m.deleted = append(m.deleted, postID)
+	return nil
+}
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	notified []*domain.Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n *domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notified = append(r.notified, n)
+	return nil
+}
+
+func (r *recordingNotifier) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.notified)
+}
+
+func follow(readerID string, kind domain.FollowKind, value string) *domain.Follow {
+	return domain.NewFollow(readerID, domain.FollowTarget{Kind: kind, Value: value})
+}
+
+func TestFanOut_Publish(t *testing.T) {
+	follows := &memoryFollows{follows: []*domain.Follow{
+		follow("anna", domain.FollowCategory, "Politics"),
+		follow("anna", domain.FollowAuthor, "Boris"),
+		follow("clara", domain.FollowAuthor, "Boris"),
+		follow("dmitri", domain.FollowCategory, "Sports"),
+	}}
+	notifications := &memoryNotifications{}
+	notifier := &recordingNotifier{}
+	f := NewFanOut(follows, notifications, WithNotifiers(notifier))
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved", Category: "Politics", Author: "Boris"}
+	event := domain.PostEvent{Type: domain.PostPublished, PostID: post.ID.Hex(), Post: post}
+	require.NoError(t, f.Publish(context.Background(), event))
+
+	reasons := make(map[string]domain.FollowTarget)
+	for _, n := range notifications.notifications {
+		assert.Equal(t, post.ID, n.PostID)
+		assert.Equal(t, "Budget approved", n.Title)
+		assert.False(t, n.Read())
+		reasons[n.ReaderID] = n.Reason
+	}
+	assert.Equal(t, map[string]domain.FollowTarget{
+		"anna":  {Kind: domain.FollowCategory, Value: "Politics"},
+		"clara": {Kind: domain.FollowAuthor, Value: "Boris"},
+	}, reasons)
+	assert.Len(t, f.queue, 2)
+
+	require.NoError(t, f.Publish(context.Background(), event), "a redelivered event")
+	assert.Len(t, notifications.notifications, 2)
+	assert.Len(t, f.queue, 2, "nobody is notified twice")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return notifier.count() == 2 }, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestFanOut_IgnoresOtherEvents(t *testing.T) {
+	follows := &memoryFollows{follows: []*domain.Follow{follow("anna", domain.FollowCategory, "Politics")}}
+	notifications := &memoryNotifications{}
+	f := NewFanOut(follows, notifications)
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved", Category: "Politics"}
+	for _, typ := range []domain.PostEventType{domain.PostCreated, domain.PostUpdated, domain.PostBreaking} {
+		require.NoError(t, f.Publish(context.Background(), domain.PostEvent{Type: typ, PostID: post.ID.Hex(), Post: post}))
+	}
+	assert.Empty(t, notifications.notifications)
+
+	require.NoError(t, f.Publish(context.Background(), domain.PostEvent{Type: domain.PostDeleted, PostID: post.ID.Hex()}))
+	assert.Equal(t, []string{post.ID.Hex()}, notifications.deleted)
+}
+
+type memorySettings struct {
+	domain.NotificationSettingsRepository
+	settings map[string]*domain.NotificationSettings
+}
+
+func (m *memorySettings) Get(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+	if s, ok := m.settings[readerID]; ok {
+		return s, nil
+	}
+	return &domain.NotificationSettings{ReaderID: readerID}, nil
+}
+
+type fakeMailer struct {
+	sent []*domain.EmailMessage
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestEmailNotifier(t *testing.T) {
+	text, err := templates.ParseText("../../templates")
+	require.NoError(t, err)
+	composer := NewComposer(template.Must(templates.Parse("../../templates")), text, "https://news.example/")
+
+	confirmed := &domain.NotificationSettings{ReaderID: "anna"}
+	_, err = confirmed.SetEmail("anna@example.com")
+	require.NoError(t, err)
+	confirmed.ConfirmEmail(time.Now())
+	unconfirmed := &domain.NotificationSettings{ReaderID: "boris"}
+	_, err = unconfirmed.SetEmail("boris@example.com")
+	require.NoError(t, err)
+
+	settings := &memorySettings{settings: map[string]*domain.NotificationSettings{"anna": confirmed, "boris": unconfirmed}}
+	mailer := &fakeMailer{}
+	notifier := NewEmailNotifier(settings, composer, mailer)
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved"}
+	reason := domain.FollowTarget{Kind: domain.FollowAuthor, Value: "Clara"}
+	for _, readerID := range []string{"anna", "boris", "dmitri"} {
+		require.NoError(t, notifier.Notify(context.Background(), domain.NewNotification(readerID, post, reason, time.Now())))
+	}
+
+	require.Len(t, mailer.sent, 1, "only confirmed addresses get emails")
+	msg := mailer.sent[0]
+	assert.Equal(t, "anna@example.com", msg.To)
+	assert.Equal(t, "New from Clara: Budget approved", msg.Subject)
+	assert.Equal(t, "https://news.example/notifications/email/unsubscribe?token="+confirmed.Token, msg.Unsubscribe)
+	assert.Contains(t, msg.HTML, `href="https://news.example/posts/`+post.ID.Hex()+`"`)
+	assert.Contains(t, msg.Text, "New from Clara:\n\nBudget approved\nhttps://news.example/posts/"+post.ID.Hex())
+}
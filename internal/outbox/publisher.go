@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// HandlerPublisher publishes events to in-process event handlers. Unlike
+// handlers registered on the post service, they receive events that were
+// committed before a crash once the server is back.
+type HandlerPublisher struct {
+	handlers []domain.PostEventHandler
+}
+
+// NewHandlerPublisher creates a publisher calling handlers in order.
+func NewHandlerPublisher(handlers ...domain.PostEventHandler) *HandlerPublisher {
+	return &HandlerPublisher{handlers: handlers}
+}
+
+// Publish implements domain.EventPublisher
+func (p *HandlerPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	var errs []error
+	for _, h := range p.handlers {
+		if err := h.HandlePostEvent(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SubjectPrefix is prepended to the event type to form the subject a message
+// is published on, e.g. "news.post.published".
+const SubjectPrefix = "news."
+
+// Message is the JSON body published to a Bus. ID is the same for every
+// redelivery of an event and lets subscribers drop duplicates.
+type Message struct {
+	ID         string               `json:"id"`
+	Event      domain.PostEventType `json:"event"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	PostID     string               `json:"post_id"`
+	Post       *domain.Post         `json:"post,omitempty"`
+}
+
+// Bus is a subject based message broker. Its method set matches a NATS
+// connection, so one can be plugged in where MemoryBus stands in today.
+type Bus interface {
+	Publish(subject string, data []byte) error
+}
+
+// BusPublisher publishes events as JSON messages to a Bus.
+type BusPublisher struct {
+	bus Bus
+}
+
+// NewBusPublisher creates a publisher sending to bus.
+func NewBusPublisher(bus Bus) *BusPublisher {
+	return &BusPublisher{bus: bus}
+}
+
+// Publish implements domain.EventPublisher
+func (p *BusPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	data, err := json.Marshal(Message{
+		ID:         event.ID,
+		Event:      event.Type,
+		OccurredAt: event.OccurredAt,
+		PostID:     event.PostID,
+		Post:       event.Post,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	subject := SubjectPrefix + string(event.Type)
+	if err := p.bus.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// MemoryBus is an in-process Bus. Subscribers are called synchronously in
+// the publishing goroutine.
+type MemoryBus struct {
+	mu   sync.RWMutex
+	subs map[string][]func(data []byte)
+}
+
+// NewMemoryBus creates a bus without subscribers.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subs: make(map[string][]func(data []byte))}
+}
+
+// Subscribe registers fn for messages on subject. A subject ending in ".>"
+// matches every subject with that prefix, as in NATS.
+func (b *MemoryBus) Subscribe(subject string, fn func(data []byte)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[subject] = append(b.subs[subject], fn)
+}
+
+// Publish implements Bus
+func (b *MemoryBus) Publish(subject string, data []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for pattern, subs := range b.subs {
+		if !matchSubject(pattern, subject) {
+			continue
+		}
+		for _, fn := range subs {
+			fn(data)
+		}
+	}
+	return nil
+}
+
+func matchSubject(pattern, subject string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, ">"); ok {
+		return strings.HasPrefix(subject, prefix)
+	}
+	return pattern == subject
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// lease is how long a claimed entry stays hidden from other relays. A relay
+// that dies while publishing leaves the entry to be published again after it.
+const lease = time.Minute
+
+// Relay publishes outbox entries to every publisher and marks them sent.
+// An entry is sent only once all publishers accepted it; when one of them
+// fails the entry is published again to all of them later, so publishers
+// see every event at least once.
+type Relay struct {
+	outbox     domain.OutboxRepository
+	publishers []domain.EventPublisher
+	poll       time.Duration
+	wake       chan struct{}
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// Option configures optional Relay settings.
+type Option func(*Relay)
+
+// WithPublishers adds publishers that receive every relayed event.
+func WithPublishers(publishers ...domain.EventPublisher) Option {
+	return func(r *Relay) {
+		r.publishers = append(r.publishers, publishers...)
+	}
+}
+
+// WithPollInterval sets how often an idle relay looks for entries that became due.
+func WithPollInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.poll = interval
+	}
+}
+
+// WithLogger sets the logger used to report publish failures.
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Relay) {
+		r.logger = logger
+	}
+}
+
+func NewRelay(outbox domain.OutboxRepository, opts ...Option) *Relay {
+	r := &Relay{
+		outbox: outbox,
+		poll:   5 * time.Second,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+		logger: zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Notify wakes the relay to look for new entries, typically after a
+// transaction that recorded some has committed.
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays entries until ctx is cancelled. Between batches it sleeps until
+// it is notified or the poll interval has passed.
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("starting outbox relay", zap.Int("publishers", len(r.publishers)))
+
+	ticker := time.NewTicker(r.poll)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && r.RelayNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopped")
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayNext claims one due entry and publishes it. It reports whether an
+// entry was claimed.
+func (r *Relay) RelayNext(ctx context.Context) bool {
+	entry, err := r.outbox.ClaimDue(ctx, r.now(), lease)
+	if err != nil {
+		r.logger.Error("failed to claim outbox entry", zap.Error(err))
+		return false
+	}
+	if entry == nil {
+		return false
+	}
+
+	publishErr := r.publish(ctx, entry.PostEvent())
+	entry.Relayed(r.now(), publishErr)
+	if err := r.outbox.Save(ctx, entry); err != nil {
+		r.logger.Error("failed to save outbox entry", zap.String("entry_id", entry.ID.Hex()), zap.Error(err))
+	}
+
+	if publishErr != nil {
+		r.logger.Warn("failed to publish outbox entry",
+			zap.String("entry_id", entry.ID.Hex()),
+			zap.String("event", string(entry.Event)),
+			zap.String("post_id", entry.PostID),
+			zap.Int("attempt", entry.Attempts),
+			zap.Time("next_attempt_at", entry.NextAttemptAt),
+			zap.Error(publishErr),
+		)
+	}
+	return true
+}
+
+// publish hands event to every publisher, even when an earlier one failed.
+func (r *Relay) publish(ctx context.Context, event domain.PostEvent) error {
+	var errs []error
+	for i, p := range r.publishers {
+		if err := p.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("publisher %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
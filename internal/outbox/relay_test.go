@@ -0,0 +1,217 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryOutbox is an in-memory domain.OutboxRepository that stores copies,
+// like a database would.
+type memoryOutbox struct {
+	mu      sync.Mutex
+	entries map[primitive.ObjectID]domain.OutboxEntry
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{entries: make(map[primitive.ObjectID]domain.OutboxEntry)}
+}
+
+func (m *memoryOutbox) Add(ctx context.Context, entries ...*domain.OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range entries {
+		m.entries[e.ID] = *e
+	}
+	return nil
+}
+
+func (m *memoryOutbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.OutboxEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due *domain.OutboxEntry
+	for _, e := range m.entries {
+		if e.Sent() || e.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || e.ID.Hex() < due.ID.Hex() {
+			e := e
+			due = &e
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	due.NextAttemptAt = now.Add(lease)
+	m.entries[due.ID] = *due
+	return due, nil
+}
+
+func (m *memoryOutbox) Save(ctx context.Context, e *domain.OutboxEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[e.ID] = *e
+	return nil
+}
+
+func (m *memoryOutbox) get(id primitive.ObjectID) domain.OutboxEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries[id]
+}
+
+// recordingPublisher records published events and fails while err is set.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	err    error
+	events []domain.PostEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.PostEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func (p *recordingPublisher) published() []domain.PostEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.PostEvent(nil), p.events...)
+}
+
+func TestRelay_RelayNext(t *testing.T) {
+	store := newMemoryOutbox()
+	healthy := &recordingPublisher{}
+	flaky := &recordingPublisher{err: errors.New("broker unavailable")}
+	relay := NewRelay(store, WithPublishers(healthy, flaky))
+	ctx := context.Background()
+
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+	first := domain.NewOutboxEntry(domain.PostEvent{Type: domain.PostCreated, PostID: "post-1", OccurredAt: now})
+	second := domain.NewOutboxEntry(domain.PostEvent{Type: domain.PostPublished, PostID: "post-1", OccurredAt: now})
+	require.NoError(t, store.Add(ctx, first, second))
+
+	assert.True(t, relay.RelayNext(ctx))
+	failed := store.get(first.ID)
+	assert.False(t, failed.Sent())
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Contains(t, failed.LastError, "broker unavailable")
+	assert.Equal(t, now.Add(domain.OutboxRetryDelay), failed.NextAttemptAt)
+
+	flaky.err = nil
+	assert.True(t, relay.RelayNext(ctx), "the second entry is due while the first waits")
+	relayed := store.get(second.ID)
+	assert.True(t, relayed.Sent())
+	assert.False(t, relay.RelayNext(ctx))
+
+	now = now.Add(domain.OutboxRetryDelay)
+	assert.True(t, relay.RelayNext(ctx))
+	sent := store.get(first.ID)
+	assert.True(t, sent.Sent())
+	assert.Equal(t, 2, sent.Attempts)
+	assert.Empty(t, sent.LastError)
+	assert.False(t, relay.RelayNext(ctx))
+
+	events := healthy.published()
+	require.Len(t, events, 3, "a failed entry is published to every publisher again")
+	assert.Equal(t, first.ID.Hex(), events[0].ID)
+	assert.Equal(t, second.ID.Hex(), events[1].ID)
+	assert.Equal(t, events[0], events[2])
+	assert.Len(t, flaky.published(), 3)
+}
+
+func TestRelay_Run(t *testing.T) {
+	store := newMemoryOutbox()
+	received := make(chan domain.PostEvent, 10)
+	relay := NewRelay(store,
+		WithPublishers(NewHandlerPublisher(handlerFunc(func(ctx context.Context, event domain.PostEvent) error {
+			received <- event
+			return nil
+		}))),
+		WithPollInterval(time.Hour),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	entry := domain.NewOutboxEntry(domain.PostEvent{Type: domain.PostDeleted, PostID: "post-1", OccurredAt: time.Now()})
+	require.NoError(t, store.Add(context.Background(), entry))
+	relay.Notify()
+
+	select {
+	case event := <-received:
+		assert.Equal(t, domain.PostDeleted, event.Type)
+		assert.Equal(t, entry.ID.Hex(), event.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry was not relayed")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
+
+type handlerFunc func(ctx context.Context, event domain.PostEvent) error
+
+func (f handlerFunc) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	return f(ctx, event)
+}
+
+func TestBusPublisher(t *testing.T) {
+	bus := NewMemoryBus()
+	var published, all [][]byte
+	bus.Subscribe("news.post.published", func(data []byte) { published = append(published, data) })
+	bus.Subscribe("news.>", func(data []byte) { all = append(all, data) })
+	bus.Subscribe("other.post.published", func(data []byte) { t.Error("unexpected message on other subject") })
+
+	publisher := NewBusPublisher(bus)
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved"}
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, domain.PostEvent{ID: "entry-1", Type: domain.PostPublished, PostID: post.ID.Hex(), Post: post}))
+	require.NoError(t, publisher.Publish(ctx, domain.PostEvent{ID: "entry-2", Type: domain.PostDeleted, PostID: post.ID.Hex()}))
+
+	require.Len(t, published, 1)
+	assert.Len(t, all, 2)
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(published[0], &msg))
+	assert.Equal(t, "entry-1", msg.ID)
+	assert.Equal(t, domain.PostPublished, msg.Event)
+	assert.Equal(t, post.ID.Hex(), msg.PostID)
+	require.NotNil(t, msg.Post)
+	assert.Equal(t, "Budget approved", msg.Post.Title)
+}
+
+func TestHandlerPublisher(t *testing.T) {
+	var calls int
+	ok := handlerFunc(func(ctx context.Context, event domain.PostEvent) error {
+		calls++
+		return nil
+	})
+	failing := handlerFunc(func(ctx context.Context, event domain.PostEvent) error {
+		calls++
+		return assert.AnError
+	})
+
+	err := NewHandlerPublisher(failing, ok).Publish(context.Background(), domain.PostEvent{Type: domain.PostCreated})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 2, calls, "later handlers run after a failure")
+}
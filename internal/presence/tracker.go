@@ -0,0 +1,238 @@
+package presence
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// HeartbeatInterval is how often an open edit form reports that it is still
+// open. DefaultTTL leaves room for two missed heartbeats.
+const (
+	HeartbeatInterval = 10 * time.Second
+	DefaultTTL        = 3 * HeartbeatInterval
+)
+
+// Editor is someone with the edit form of a post open. Session identifies the
+// open form, so the same person editing in two tabs counts twice.
+type Editor struct {
+	Session string
+	Name    string
+	Since   time.Time
+	SeenAt  time.Time
+}
+
+// PostEditors lists the editors of one post in the order they started editing.
+type PostEditors struct {
+	PostID  string
+	Editors []Editor
+}
+
+// Names returns the editor names, leaving out the editor with the session
+// except, so that a form does not report its own editor.
+func (p PostEditors) Names(except string) []string {
+	names := make([]string, 0, len(p.Editors))
+	for _, e := range p.Editors {
+		if e.Session != except {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// Listener is told about every change to the editors of a post: someone
+// started or stopped editing, timed out or changed their name.
+type Listener interface {
+	EditorsChanged(editors PostEditors)
+}
+
+// Tracker keeps track of who is editing which post. It is in-memory: editors
+// report themselves with heartbeats and are forgotten once they stop.
+type Tracker struct {
+	mu    sync.Mutex
+	posts map[string]map[string]*Editor
+
+	ttl       time.Duration
+	listeners []Listener
+	now       func() time.Time
+}
+
+// Option configures optional Tracker settings.
+type Option func(*Tracker)
+
+// WithTTL sets how long an editor is kept after their last heartbeat.
+func WithTTL(ttl time.Duration) Option {
+	return func(t *Tracker) {
+		t.ttl = ttl
+	}
+}
+
+// WithListeners adds listeners that are told about changes.
+func WithListeners(listeners ...Listener) Option {
+	return func(t *Tracker) {
+		t.listeners = append(t.listeners, listeners...)
+	}
+}
+
+func NewTracker(opts ...Option) *Tracker {
+	t := &Tracker{
+		posts: make(map[string]map[string]*Editor),
+		ttl:   DefaultTTL,
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Heartbeat records that the editor with session is editing the post and
+// returns the post's editors.
+func (t *Tracker) Heartbeat(postID, session, name string) PostEditors {
+	t.mu.Lock()
+	now := t.now()
+	editors := t.posts[postID]
+	if editors == nil {
+		editors = make(map[string]*Editor)
+		t.posts[postID] = editors
+	}
+	e, ok := editors[session]
+	changed := !ok || e.Name != name
+	if !ok {
+		e = &Editor{Session: session, Since: now}
+		editors[session] = e
+	}
+	e.Name = name
+	e.SeenAt = now
+	current := t.editors(postID)
+	t.mu.Unlock()
+
+	if changed {
+		t.notify(current)
+	}
+	return current
+}
+
+// Leave removes the editor with session from the post, typically because the
+// edit form was closed.
+func (t *Tracker) Leave(postID, session string) {
+	t.mu.Lock()
+	_, ok := t.posts[postID][session]
+	if ok {
+		t.remove(postID, session)
+	}
+	current := t.editors(postID)
+	t.mu.Unlock()
+
+	if ok {
+		t.notify(current)
+	}
+}
+
+// Editors returns the editors of the post.
+func (t *Tracker) Editors(postID string) PostEditors {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.editors(postID)
+}
+
+// All returns the editors of every post that is being edited, ordered by post ID.
+func (t *Tracker) All() []PostEditors {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	all := make([]PostEditors, 0, len(t.posts))
+	for postID := range t.posts {
+		all = append(all, t.editors(postID))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].PostID < all[j].PostID })
+	return all
+}
+
+// Expire forgets editors whose last heartbeat is older than the TTL.
+func (t *Tracker) Expire() {
+	t.mu.Lock()
+	cutoff := t.now().Add(-t.ttl)
+	var changed []PostEditors
+	for postID, editors := range t.posts {
+		expired := false
+		for session, e := range editors {
+			if e.SeenAt.Before(cutoff) {
+				t.remove(postID, session)
+				expired = true
+			}
+		}
+		if expired {
+			changed = append(changed, t.editors(postID))
+		}
+	}
+	t.mu.Unlock()
+
+	for _, c := range changed {
+		t.notify(c)
+	}
+}
+
+// Run expires editors until ctx is cancelled.
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Expire()
+		}
+	}
+}
+
+// HandlePostEvent implements domain.PostEventHandler. An update re-renders the
+// post card without its editors, so they are announced again; the tracker
+// must therefore be registered after the handler rendering the card. Editors
+// of a deleted post are forgotten.
+func (t *Tracker) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	switch event.Type {
+	case domain.PostUpdated, domain.PostBreaking:
+		if current := t.Editors(event.PostID); len(current.Editors) > 0 {
+			t.notify(current)
+		}
+	case domain.PostDeleted:
+		t.mu.Lock()
+		delete(t.posts, event.PostID)
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// editors returns the editors of the post sorted by Since. t.mu must be held.
+func (t *Tracker) editors(postID string) PostEditors {
+	current := PostEditors{PostID: postID}
+	for _, e := range t.posts[postID] {
+		current.Editors = append(current.Editors, *e)
+	}
+	sort.Slice(current.Editors, func(i, j int) bool {
+		a, b := current.Editors[i], current.Editors[j]
+		if !a.Since.Equal(b.Since) {
+			return a.Since.Before(b.Since)
+		}
+		return a.Session < b.Session
+	})
+	return current
+}
+
+// remove deletes an editor and the post once it has none. t.mu must be held.
+func (t *Tracker) remove(postID, session string) {
+	delete(t.posts[postID], session)
+	if len(t.posts[postID]) == 0 {
+		delete(t.posts, postID)
+	}
+}
+
+func (t *Tracker) notify(editors PostEditors) {
+	for _, l := range t.listeners {
+		l.EditorsChanged(editors)
+	}
+}
@@ -0,0 +1,102 @@
+package presence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingListener struct {
+	changes []PostEditors
+}
+
+func (l *recordingListener) EditorsChanged(editors PostEditors) {
+	l.changes = append(l.changes, editors)
+}
+
+func newTestTracker() (*Tracker, *recordingListener, *time.Time) {
+	listener := &recordingListener{}
+	tracker := NewTracker(WithTTL(30*time.Second), WithListeners(listener))
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	return tracker, listener, &now
+}
+
+func TestTracker_Heartbeat(t *testing.T) {
+	tracker, listener, now := newTestTracker()
+
+	current := tracker.Heartbeat("post-1", "s1", "Anna")
+	require.Len(t, current.Editors, 1)
+	assert.Equal(t, "Anna", current.Editors[0].Name)
+	require.Len(t, listener.changes, 1)
+
+	*now = now.Add(time.Second)
+	current = tracker.Heartbeat("post-1", "s2", "Boris")
+	assert.Equal(t, []string{"Anna", "Boris"}, current.Names(""))
+	assert.Equal(t, []string{"Boris"}, current.Names("s1"))
+	require.Len(t, listener.changes, 2)
+
+	tracker.Heartbeat("post-1", "s1", "Anna")
+	assert.Len(t, listener.changes, 2, "a repeated heartbeat is not a change")
+
+	tracker.Heartbeat("post-1", "s1", "Anna K.")
+	require.Len(t, listener.changes, 3)
+	assert.Equal(t, []string{"Anna K.", "Boris"}, listener.changes[2].Names(""))
+}
+
+func TestTracker_Leave(t *testing.T) {
+	tracker, listener, _ := newTestTracker()
+	tracker.Heartbeat("post-1", "s1", "Anna")
+	tracker.Heartbeat("post-2", "s2", "Boris")
+
+	tracker.Leave("post-1", "s1")
+	require.Len(t, listener.changes, 3)
+	assert.Equal(t, PostEditors{PostID: "post-1"}, listener.changes[2])
+	assert.Empty(t, tracker.Editors("post-1").Editors)
+
+	tracker.Leave("post-1", "s1")
+	assert.Len(t, listener.changes, 3, "leaving twice is not a change")
+
+	all := tracker.All()
+	require.Len(t, all, 1)
+	assert.Equal(t, "post-2", all[0].PostID)
+}
+
+func TestTracker_Expire(t *testing.T) {
+	tracker, listener, now := newTestTracker()
+	tracker.Heartbeat("post-1", "s1", "Anna")
+	*now = now.Add(20 * time.Second)
+	tracker.Heartbeat("post-1", "s2", "Boris")
+
+	*now = now.Add(20 * time.Second)
+	tracker.Expire()
+	require.Len(t, listener.changes, 3)
+	assert.Equal(t, []string{"Boris"}, listener.changes[2].Names(""))
+
+	*now = now.Add(time.Minute)
+	tracker.Expire()
+	require.Len(t, listener.changes, 4)
+	assert.Empty(t, listener.changes[3].Editors)
+	assert.Empty(t, tracker.All())
+}
+
+func TestTracker_HandlePostEvent(t *testing.T) {
+	tracker, listener, _ := newTestTracker()
+	ctx := context.Background()
+	tracker.Heartbeat("post-1", "s1", "Anna")
+
+	require.NoError(t, tracker.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: "post-1"}))
+	require.Len(t, listener.changes, 2, "editors are announced again after an update")
+	assert.Equal(t, []string{"Anna"}, listener.changes[1].Names(""))
+
+	require.NoError(t, tracker.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: "post-2"}))
+	assert.Len(t, listener.changes, 2, "posts nobody edits are not announced")
+
+	require.NoError(t, tracker.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostDeleted, PostID: "post-1"}))
+	assert.Empty(t, tracker.All())
+}
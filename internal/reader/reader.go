@@ -0,0 +1,76 @@
+// Package reader identifies the visitors of the site. There are no accounts:
+// every browser is given a random reader ID in a long-lived cookie, which
+// keys everything a reader keeps, such as follows and notifications.
+package reader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	// CookieName is the cookie holding the reader ID.
+	CookieName = "reader_id"
+	// cookieMaxAge is renewed on every visit.
+	cookieMaxAge = 365 * 24 * time.Hour
+	idBytes      = 16
+)
+
+type contextKey struct{}
+
+// Middleware puts the reader ID from the cookie into the request context and
+// gives new readers one. The cookie is renewed so that regular readers keep
+// their ID.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := ""
+		if c, err := r.Cookie(CookieName); err == nil && Valid(c.Value) {
+			id = c.Value
+		} else if id, err = NewID(); err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     CookieName,
+			Value:    id,
+			Path:     "/",
+			MaxAge:   int(cookieMaxAge.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+	})
+}
+
+// WithID returns a copy of ctx carrying the reader ID.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the reader ID put into ctx by Middleware, or "" outside of it.
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// NewID returns a random reader ID.
+func NewID() (string, error) {
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Valid reports whether id is a well-formed reader ID.
+func Valid(id string) bool {
+	if len(id) != 2*idBytes {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
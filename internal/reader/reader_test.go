@@ -0,0 +1,50 @@
+package reader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ID(r.Context())
+	}))
+
+	t.Run("new reader", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.True(t, Valid(got))
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, CookieName, cookies[0].Name)
+		assert.Equal(t, got, cookies[0].Value)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("returning reader", func(t *testing.T) {
+		id, err := NewID()
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: id})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, id, got)
+		assert.Equal(t, id, rec.Result().Cookies()[0].Value)
+	})
+
+	t.Run("malformed cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: "../admin"})
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.True(t, Valid(got))
+		assert.NotEqual(t, "../admin", got)
+	})
+}
@@ -0,0 +1,113 @@
+package apikeyrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository implements domain.APIKeyRepository using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB API key repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("api_keys"),
+	}
+}
+
+// EnsureIndexes makes key prefixes unique and indexes the keys of a reader.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "prefix", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API key indexes: %w", err)
+	}
+	return nil
+}
+
+// Create implements APIKeyRepository.Create
+func (r *MongoRepository) Create(ctx context.Context, k *domain.APIKey) error {
+	if _, err := r.collection.InsertOne(ctx, k); err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// GetByPrefix implements APIKeyRepository.GetByPrefix
+func (r *MongoRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"prefix": prefix}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return &key, nil
+}
+
+// GetByOwner implements APIKeyRepository.GetByOwner
+func (r *MongoRepository) GetByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"owner_id": ownerID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*domain.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke implements APIKeyRepository.Revoke
+func (r *MongoRepository) Revoke(ctx context.Context, ownerID, id string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "owner_id": ownerID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// RecordUsage implements APIKeyRepository.RecordUsage
+func (r *MongoRepository) RecordUsage(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$max": bson.M{"last_used_at": at}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record API key usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package authorrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository implements domain.AuthorRepository using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB author repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("authors"),
+	}
+}
+
+// EnsureIndexes keeps one profile per handle.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "handle", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create author indexes: %w", err)
+	}
+	return nil
+}
+
+// GetByHandle implements AuthorRepository.GetByHandle
+func (r *MongoRepository) GetByHandle(ctx context.Context, handle string) (*domain.Author, error) {
+	var author domain.Author
+	err := r.collection.FindOne(ctx, bson.M{"handle": handle}).Decode(&author)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrAuthorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find author: %w", err)
+	}
+	return &author, nil
+}
+
+// Save implements AuthorRepository.Save
+func (r *MongoRepository) Save(ctx context.Context, a *domain.Author) error {
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"handle": a.Handle},
+		a,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save author: %w", err)
+	}
+	return nil
+}
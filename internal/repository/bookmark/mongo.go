@@ -0,0 +1,147 @@
+package bookmarkrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository implements domain.BookmarkRepository using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB bookmark repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("bookmarks"),
+	}
+}
+
+// EnsureIndexes makes bookmarks unique per reader and post, indexes the
+// reading lists and the bookmarks removed with a post.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "reader_id", Value: 1}, {Key: "post_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "reader_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "post_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create bookmark indexes: %w", err)
+	}
+	return nil
+}
+
+// Add implements BookmarkRepository.Add
+func (r *MongoRepository) Add(ctx context.Context, b *domain.Bookmark) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"reader_id": b.ReaderID, "post_id": b.PostID},
+		bson.M{"$setOnInsert": b},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request inserted the same bookmark first.
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to add bookmark: %w", err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// Remove implements BookmarkRepository.Remove
+func (r *MongoRepository) Remove(ctx context.Context, readerID, postID string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return false, nil
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"reader_id": readerID, "post_id": objID})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// GetByReader implements BookmarkRepository.GetByReader
+func (r *MongoRepository) GetByReader(ctx context.Context, readerID string, page, pageSize int) ([]*domain.Bookmark, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	filter := bson.M{"reader_id": readerID}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count bookmarks: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find bookmarks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var bookmarks []*domain.Bookmark
+	if err := cursor.All(ctx, &bookmarks); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode bookmarks: %w", err)
+	}
+	return bookmarks, total, nil
+}
+
+// Bookmarked implements BookmarkRepository.Bookmarked. Malformed IDs are
+// skipped.
+func (r *MongoRepository) Bookmarked(ctx context.Context, readerID string, postIDs []string) ([]string, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(postIDs))
+	for _, id := range postIDs {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	if len(objIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"reader_id": readerID, "post_id": bson.M{"$in": objIDs}},
+		options.Find().SetProjection(bson.M{"post_id": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bookmarks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var bookmarks []*domain.Bookmark
+	if err := cursor.All(ctx, &bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to decode bookmarks: %w", err)
+	}
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.PostID.Hex()
+	}
+	return ids, nil
+}
+
+// DeleteByPost implements BookmarkRepository.DeleteByPost
+func (r *MongoRepository) DeleteByPost(ctx context.Context, postID string) error {
+	objID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"post_id": objID}); err != nil {
+		return fmt.Errorf("failed to delete bookmarks: %w", err)
+	}
+	return nil
+}
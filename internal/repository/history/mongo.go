@@ -0,0 +1,81 @@
+package historyrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// retention is how long reads shape the "For you" feed before MongoDB
+// expires them.
+const retention = 90 * 24 * time.Hour
+
+// MongoRepository implements domain.ReadingHistoryRepository using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB reading history repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("reads"),
+	}
+}
+
+// EnsureIndexes keeps one read per reader and post, indexes the latest reads
+// of a reader and expires old reads.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "reader_id", Value: 1}, {Key: "post_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "reader_id", Value: 1}, {Key: "read_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "read_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reading history indexes: %w", err)
+	}
+	return nil
+}
+
+// Record implements ReadingHistoryRepository.Record
+func (r *MongoRepository) Record(ctx context.Context, read *domain.PostRead) error {
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"reader_id": read.ReaderID, "post_id": read.PostID},
+		read,
+		options.Replace().SetUpsert(true),
+	)
+	// A concurrent read of the same post won the insert; either one will do.
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to record read: %w", err)
+	}
+	return nil
+}
+
+// GetByReader implements ReadingHistoryRepository.GetByReader
+func (r *MongoRepository) GetByReader(ctx context.Context, readerID string, limit int) ([]*domain.PostRead, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"reader_id": readerID},
+		options.Find().SetSort(bson.D{{Key: "read_at", Value: -1}}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reads: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reads []*domain.PostRead
+	if err := cursor.All(ctx, &reads); err != nil {
+		return nil, fmt.Errorf("failed to decode reads: %w", err)
+	}
+	return reads, nil
+}
@@ -0,0 +1,208 @@
+package newsletterrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SubscriberRepository implements domain.SubscriberRepository using MongoDB
+type SubscriberRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSubscriberRepository creates a new MongoDB subscriber repository
+func NewSubscriberRepository(db *mongo.Database) *SubscriberRepository {
+	return &SubscriberRepository{
+		collection: db.Collection("subscribers"),
+	}
+}
+
+// EnsureIndexes makes emails and tokens unique and indexes the digest schedule.
+func (r *SubscriberRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "next_digest_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create subscriber indexes: %w", err)
+	}
+	return nil
+}
+
+// Create implements SubscriberRepository.Create
+func (r *SubscriberRepository) Create(ctx context.Context, sub *domain.Subscriber) error {
+	if _, err := r.collection.InsertOne(ctx, sub); err != nil {
+		return fmt.Errorf("failed to insert subscriber: %w", err)
+	}
+	return nil
+}
+
+// GetByID implements SubscriberRepository.GetByID
+func (r *SubscriberRepository) GetByID(ctx context.Context, id string) (*domain.Subscriber, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrSubscriberNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+// GetByEmail implements SubscriberRepository.GetByEmail
+func (r *SubscriberRepository) GetByEmail(ctx context.Context, email string) (*domain.Subscriber, error) {
+	return r.findOne(ctx, bson.M{"email": email})
+}
+
+// GetByToken implements SubscriberRepository.GetByToken
+func (r *SubscriberRepository) GetByToken(ctx context.Context, token string) (*domain.Subscriber, error) {
+	if token == "" {
+		return nil, domain.ErrSubscriberNotFound
+	}
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *SubscriberRepository) findOne(ctx context.Context, filter bson.M) (*domain.Subscriber, error) {
+	var sub domain.Subscriber
+	if err := r.collection.FindOne(ctx, filter).Decode(&sub); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSubscriberNotFound
+		}
+		return nil, fmt.Errorf("failed to find subscriber: %w", err)
+	}
+	return &sub, nil
+}
+
+// GetAll implements SubscriberRepository.GetAll. Subscribers are ordered by sign-up.
+func (r *SubscriberRepository) GetAll(ctx context.Context) ([]*domain.Subscriber, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscribers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var subs []*domain.Subscriber
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, fmt.Errorf("failed to decode subscribers: %w", err)
+	}
+	return subs, nil
+}
+
+// Save implements SubscriberRepository.Save. The email and token never change.
+func (r *SubscriberRepository) Save(ctx context.Context, sub *domain.Subscriber) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": sub.ID},
+		bson.M{"$set": bson.M{
+			"frequency":      sub.Frequency,
+			"confirmed_at":   sub.ConfirmedAt,
+			"last_digest_at": sub.LastDigestAt,
+			"next_digest_at": sub.NextDigestAt,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrSubscriberNotFound
+	}
+	return nil
+}
+
+// Delete implements SubscriberRepository.Delete. The send log is kept.
+func (r *SubscriberRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete subscriber: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrSubscriberNotFound
+	}
+	return nil
+}
+
+// ClaimDue implements SubscriberRepository.ClaimDue. The digest due the
+// longest is claimed first.
+func (r *SubscriberRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.Subscriber, error) {
+	var sub domain.Subscriber
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"confirmed_at": bson.M{"$ne": nil}, "next_digest_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_digest_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_digest_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&sub)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim subscriber: %w", err)
+	}
+	return &sub, nil
+}
+
+// SendRepository implements domain.NewsletterSendRepository using MongoDB
+type SendRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSendRepository creates a new MongoDB newsletter send log repository
+func NewSendRepository(db *mongo.Database) *SendRepository {
+	return &SendRepository{
+		collection: db.Collection("newsletter_sends"),
+	}
+}
+
+// EnsureIndexes indexes the log order, overall and per subscriber.
+func (r *SendRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sent_at", Value: -1}}},
+		{Keys: bson.D{{Key: "subscriber_id", Value: 1}, {Key: "sent_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create newsletter send indexes: %w", err)
+	}
+	return nil
+}
+
+// Create implements NewsletterSendRepository.Create
+func (r *SendRepository) Create(ctx context.Context, send *domain.NewsletterSend) error {
+	if _, err := r.collection.InsertOne(ctx, send); err != nil {
+		return fmt.Errorf("failed to insert newsletter send: %w", err)
+	}
+	return nil
+}
+
+// GetRecent implements NewsletterSendRepository.GetRecent
+func (r *SendRepository) GetRecent(ctx context.Context, limit int) ([]*domain.NewsletterSend, error) {
+	return r.find(ctx, bson.M{}, limit)
+}
+
+// GetBySubscriber implements NewsletterSendRepository.GetBySubscriber
+func (r *SendRepository) GetBySubscriber(ctx context.Context, subscriberID primitive.ObjectID, limit int) ([]*domain.NewsletterSend, error) {
+	return r.find(ctx, bson.M{"subscriber_id": subscriberID}, limit)
+}
+
+// find returns the newest sends matching filter first.
+func (r *SendRepository) find(ctx context.Context, filter bson.M, limit int) ([]*domain.NewsletterSend, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sent_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find newsletter sends: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sends []*domain.NewsletterSend
+	if err := cursor.All(ctx, &sends); err != nil {
+		return nil, fmt.Errorf("failed to decode newsletter sends: %w", err)
+	}
+	return sends, nil
+}
@@ -0,0 +1,301 @@
+package notificationrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FollowRepository implements domain.FollowRepository using MongoDB
+type FollowRepository struct {
+	collection *mongo.Collection
+}
+
+// NewFollowRepository creates a new MongoDB follow repository
+func NewFollowRepository(db *mongo.Database) *FollowRepository {
+	return &FollowRepository{
+		collection: db.Collection("follows"),
+	}
+}
+
+// EnsureIndexes makes follows unique per reader and indexes the followers of
+// a target for the fan-out.
+func (r *FollowRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "reader_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "value", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "value", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create follow indexes: %w", err)
+	}
+	return nil
+}
+
+// Add implements FollowRepository.Add
+func (r *FollowRepository) Add(ctx context.Context, f *domain.Follow) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"reader_id": f.ReaderID, "kind": f.Kind, "value": f.Value},
+		bson.M{"$setOnInsert": f},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to add follow: %w", err)
+	}
+	return nil
+}
+
+// Remove implements FollowRepository.Remove. Removing a follow that does not
+// exist is not an error.
+func (r *FollowRepository) Remove(ctx context.Context, readerID string, target domain.FollowTarget) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"reader_id": readerID, "kind": target.Kind, "value": target.Value})
+	if err != nil {
+		return fmt.Errorf("failed to remove follow: %w", err)
+	}
+	return nil
+}
+
+// GetByReader implements FollowRepository.GetByReader. Follows are ordered by
+// kind and name.
+func (r *FollowRepository) GetByReader(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+	return r.find(ctx, bson.M{"reader_id": readerID}, options.Find().SetSort(bson.D{{Key: "kind", Value: 1}, {Key: "value", Value: 1}}))
+}
+
+// GetByTargets implements FollowRepository.GetByTargets
+func (r *FollowRepository) GetByTargets(ctx context.Context, targets []domain.FollowTarget) ([]*domain.Follow, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	or := make(bson.A, len(targets))
+	for i, t := range targets {
+		or[i] = bson.M{"kind": t.Kind, "value": t.Value}
+	}
+	return r.find(ctx, bson.M{"$or": or}, options.Find())
+}
+
+func (r *FollowRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*domain.Follow, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find follows: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var follows []*domain.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		return nil, fmt.Errorf("failed to decode follows: %w", err)
+	}
+	return follows, nil
+}
+
+// NotificationRepository implements domain.NotificationRepository using MongoDB
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewNotificationRepository creates a new MongoDB notification repository
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+// EnsureIndexes allows one notification per reader and post, which makes
+// fanning out the same event again harmless, and indexes the inboxes.
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "reader_id", Value: 1}, {Key: "post_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "reader_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "post_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+	return nil
+}
+
+// Add implements NotificationRepository.Add. The notifications are inserted
+// unordered; the ones rejected as duplicates are left out of the result.
+func (r *NotificationRepository) Add(ctx context.Context, notifications []*domain.Notification) ([]*domain.Notification, error) {
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+	docs := make([]interface{}, len(notifications))
+	for i, n := range notifications {
+		docs[i] = n
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return notifications, nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return nil, fmt.Errorf("failed to insert notifications: %w", err)
+	}
+	rejected := make(map[int]bool, len(bulkErr.WriteErrors))
+	for _, we := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we) {
+			return nil, fmt.Errorf("failed to insert notifications: %w", err)
+		}
+		rejected[we.Index] = true
+	}
+	var added []*domain.Notification
+	for i, n := range notifications {
+		if !rejected[i] {
+			added = append(added, n)
+		}
+	}
+	return added, nil
+}
+
+// GetByReader implements NotificationRepository.GetByReader. The newest
+// notifications come first.
+func (r *NotificationRepository) GetByReader(ctx context.Context, readerID string, limit int) ([]*domain.Notification, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"reader_id": readerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*domain.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// CountUnread implements NotificationRepository.CountUnread
+func (r *NotificationRepository) CountUnread(ctx context.Context, readerID string) (int64, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"reader_id": readerID, "read_at": nil})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return n, nil
+}
+
+// MarkRead implements NotificationRepository.MarkRead. A notification read
+// before keeps its read time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, readerID, id string, now time.Time) (*domain.Notification, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrNotificationNotFound
+	}
+	filter := bson.M{"_id": objID, "reader_id": readerID}
+	if _, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "reader_id": readerID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": now}},
+	); err != nil {
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	var n domain.Notification
+	if err := r.collection.FindOne(ctx, filter).Decode(&n); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+	return &n, nil
+}
+
+// MarkAllRead implements NotificationRepository.MarkAllRead
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, readerID string, now time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"reader_id": readerID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": now}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// DeleteByPost implements NotificationRepository.DeleteByPost
+func (r *NotificationRepository) DeleteByPost(ctx context.Context, postID string) error {
+	objID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return nil
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"post_id": objID}); err != nil {
+		return fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	return nil
+}
+
+// SettingsRepository implements domain.NotificationSettingsRepository using MongoDB
+type SettingsRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSettingsRepository creates a new MongoDB notification settings repository
+func NewSettingsRepository(db *mongo.Database) *SettingsRepository {
+	return &SettingsRepository{
+		collection: db.Collection("notification_settings"),
+	}
+}
+
+// EnsureIndexes makes tokens unique. Settings without an email have no token.
+func (r *SettingsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification settings indexes: %w", err)
+	}
+	return nil
+}
+
+// Get implements NotificationSettingsRepository.Get
+func (r *SettingsRepository) Get(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+	s, err := r.findOne(ctx, bson.M{"_id": readerID})
+	if errors.Is(err, domain.ErrSettingsNotFound) {
+		return &domain.NotificationSettings{ReaderID: readerID}, nil
+	}
+	return s, err
+}
+
+// GetByToken implements NotificationSettingsRepository.GetByToken
+func (r *SettingsRepository) GetByToken(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	if token == "" {
+		return nil, domain.ErrSettingsNotFound
+	}
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *SettingsRepository) findOne(ctx context.Context, filter bson.M) (*domain.NotificationSettings, error) {
+	var s domain.NotificationSettings
+	if err := r.collection.FindOne(ctx, filter).Decode(&s); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSettingsNotFound
+		}
+		return nil, fmt.Errorf("failed to find notification settings: %w", err)
+	}
+	return &s, nil
+}
+
+// Save implements NotificationSettingsRepository.Save
+func (r *SettingsRepository) Save(ctx context.Context, s *domain.NotificationSettings) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": s.ReaderID}, s, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package outboxrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sentRetention is how long published entries are kept for inspection
+// before MongoDB expires them.
+const sentRetention = 7 * 24 * time.Hour
+
+// MongoRepository implements OutboxRepository interface using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB outbox repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("outbox"),
+	}
+}
+
+// EnsureIndexes indexes unsent entries in relay order and expires published ones.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "sent_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(sentRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+	return nil
+}
+
+// Add implements OutboxRepository.Add
+func (r *MongoRepository) Add(ctx context.Context, entries ...*domain.OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(entries))
+	for i, e := range entries {
+		docs[i] = e
+	}
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert outbox entries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue implements OutboxRepository.ClaimDue
+func (r *MongoRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.OutboxEntry, error) {
+	var e domain.OutboxEntry
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"sent_at": nil, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "_id", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&e)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox entry: %w", err)
+	}
+	return &e, nil
+}
+
+// Save implements OutboxRepository.Save. Only the relay state is updated;
+// sent_at is written once the entry is published so that it starts to expire.
+func (r *MongoRepository) Save(ctx context.Context, e *domain.OutboxEntry) error {
+	set := bson.M{
+		"attempts":        e.Attempts,
+		"next_attempt_at": e.NextAttemptAt,
+		"last_error":      e.LastError,
+	}
+	if e.Sent() {
+		set["sent_at"] = e.SentAt
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": e.ID}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to save outbox entry: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("outbox entry %s not found", e.ID.Hex())
+	}
+	return nil
+}
+
+// Transactor implements domain.Transactor with MongoDB multi-document
+// transactions. Transactions need a replica set or sharded cluster; on a
+// standalone server fn runs without one and its writes are not atomic.
+type Transactor struct {
+	client    *mongo.Client
+	supported bool
+}
+
+// NewTransactor creates a transactor. supported is usually the result of
+// SupportsTransactions.
+func NewTransactor(client *mongo.Client, supported bool) *Transactor {
+	return &Transactor{
+		client:    client,
+		supported: supported,
+	}
+}
+
+// SupportsTransactions reports whether the deployment client is connected to
+// is a replica set or sharded cluster.
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("failed to inspect deployment: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// WithTransaction implements Transactor.WithTransaction. fn may be run more
+// than once when the transaction hits a transient error.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.supported {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
@@ -3,6 +3,7 @@ package postrepo
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/kir/news-app/internal/domain"
@@ -32,6 +33,9 @@ func (r *MongoRepository) Create(ctx context.Context, p *domain.Post) error {
 	}
 
 	res, err := r.collection.InsertOne(ctx, p)
+	if p.Origin != nil && mongo.IsDuplicateKeyError(err) {
+		return domain.ErrDuplicatePost
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert post: %w", err)
 	}
@@ -63,19 +67,47 @@ func (r *MongoRepository) GetAll(ctx context.Context) ([]*domain.Post, error) {
 func (r *MongoRepository) GetByID(ctx context.Context, id string) (*domain.Post, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id format: %w", err)
+		return nil, fmt.Errorf("invalid id format: %w", domain.ErrPostNotFound)
 	}
 
 	var p domain.Post
 	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&p); err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("post not found")
+			return nil, domain.ErrPostNotFound
 		}
 		return nil, fmt.Errorf("failed to find post: %w", err)
 	}
 	return &p, nil
 }
 
+// GetByIDs implements Repository.GetByIDs. Unknown or malformed ids are skipped
+// and the result order is unspecified.
+func (r *MongoRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objIDs = append(objIDs, objID)
+	}
+	if len(objIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": objIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find posts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var posts []*domain.Post
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, fmt.Errorf("failed to decode posts: %w", err)
+	}
+	return posts, nil
+}
+
 // Update implements Repository.Update
 func (r *MongoRepository) Update(ctx context.Context, p *domain.Post) error {
 	if err := p.Validate(); err != nil {
@@ -88,9 +120,18 @@ func (r *MongoRepository) Update(ctx context.Context, p *domain.Post) error {
 		bson.M{"_id": p.ID},
 		bson.M{
 			"$set": bson.M{
-				"title":      p.Title,
-				"content":    p.Content,
-				"updated_at": p.UpdatedAt,
+				"title":           p.Title,
+				"content":         p.Content,
+				"category":        p.Category,
+				"tags":            p.Tags,
+				"author":          p.Author,
+				"contributors":    p.Contributors,
+				"image_url":       p.ImageURL,
+				"seo_title":       p.SEOTitle,
+				"seo_description": p.SEODescription,
+				"fingerprint":     p.Fingerprint,
+				"status":          p.Status,
+				"updated_at":      p.UpdatedAt,
 			},
 		},
 	)
@@ -121,35 +162,55 @@ func (r *MongoRepository) Delete(ctx context.Context, id string) error {
 }
 
 // GetPaginated implements Repository.GetPaginated
-func (r *MongoRepository) GetPaginated(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
-	if page < 1 {
-		page = 1
+func (r *MongoRepository) GetPaginated(ctx context.Context, q domain.PostQuery) (*domain.PostList, error) {
+	if q.Page < 1 {
+		q.Page = 1
 	}
-	if pageSize < 1 {
-		pageSize = 9
+	if q.PageSize < 1 {
+		q.PageSize = 9
 	}
 
-	skip := (page - 1) * pageSize
-	filter := bson.M{}
+	filter := buildFilter(q, "")
 
-	if search != "" {
-		filter = bson.M{
-			"$or": []bson.M{
-				{"title": bson.M{"$regex": search, "$options": "i"}},
-				{"content": bson.M{"$regex": search, "$options": "i"}},
-			},
+	var total int64
+	if !q.SkipCount {
+		count, err := r.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count documents: %w", err)
 		}
+		total = count
 	}
 
-	total, err := r.collection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count documents: %w", err)
+	// Relevance needs the search index; matching by regex has no ranking.
+	sort := q.EffectiveSort()
+	if sort == domain.SortRelevance {
+		sort = domain.SortNewest
+	}
+	spec, ok := sortSpecs[sort]
+	if !ok {
+		return nil, fmt.Errorf("failed to sort posts: %w", domain.ErrInvalidSort)
 	}
 
+	// One extra post is fetched to find out whether another page follows.
 	findOptions := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(pageSize)).
-		SetSort(bson.M{"created_at": -1})
+		SetLimit(int64(q.PageSize + 1)).
+		SetSort(spec.order())
+	if spec.collation != nil {
+		findOptions.SetCollation(spec.collation)
+	}
+
+	if q.Cursor != "" {
+		cursor, err := domain.ParsePostCursor(q.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cursor: %w", err)
+		}
+		if cursor.Sort != sort {
+			return nil, fmt.Errorf("cursor taken in %s order: %w", cursor.Sort, domain.ErrInvalidCursor)
+		}
+		filter = bson.M{"$and": bson.A{filter, spec.after(cursor)}}
+	} else {
+		findOptions.SetSkip(int64((q.Page - 1) * q.PageSize))
+	}
 
 	cursor, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -162,12 +223,262 @@ func (r *MongoRepository) GetPaginated(ctx context.Context, page, pageSize int,
 		return nil, fmt.Errorf("failed to decode posts: %w", err)
 	}
 
-	return &domain.PostList{
-		Posts:      posts,
+	list := &domain.PostList{
 		TotalCount: total,
-		Page:       page,
-		PageSize:   pageSize,
-	}, nil
+		Page:       q.Page,
+		PageSize:   q.PageSize,
+	}
+	if len(posts) > q.PageSize {
+		posts = posts[:q.PageSize]
+		list.NextCursor = domain.CursorAfter(posts[len(posts)-1], sort).Encode()
+	}
+	list.Posts = posts
+	return list, nil
+}
+
+// sortSpec maps a listing order onto a field and direction. Ties are broken
+// by _id in the same direction, keeping the order total as keyset pagination requires.
+// A collation is set on both the query and the index of the order, which
+// MongoDB only uses together.
+type sortSpec struct {
+	field     string
+	direction int
+	collation *options.Collation
+}
+
+// caseInsensitive compares strings ignoring case, like the lowercased titles
+// the search index sorts by.
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+var sortSpecs = map[domain.PostSort]sortSpec{
+	domain.SortNewest:  {field: "created_at", direction: -1},
+	domain.SortOldest:  {field: "created_at", direction: 1},
+	domain.SortUpdated: {field: "updated_at", direction: -1},
+	domain.SortViews:   {field: "view_count", direction: -1},
+	domain.SortTitle:   {field: "title", direction: 1, collation: caseInsensitive},
+}
+
+func (s sortSpec) order() bson.D {
+	return bson.D{{Key: s.field, Value: s.direction}, {Key: "_id", Value: s.direction}}
+}
+
+// after matches the posts following c in this order.
+func (s sortSpec) after(c domain.PostCursor) bson.M {
+	op := "$gt"
+	if s.direction < 0 {
+		op = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{s.field: bson.M{op: c.Value}},
+		bson.M{s.field: c.Value, "_id": bson.M{op: c.ID}},
+	}}
+}
+
+// EnsureIndexes creates one index per listing order, the indexes used to
+// de-duplicate ingested posts and the index of contributors, and backfills
+// the counters of posts stored before they existed: keyset comparisons skip
+// missing fields. Posts stored before contributors existed get their author
+// as contributor.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	var models []mongo.IndexModel
+	seen := make(map[string]bool)
+	for _, spec := range sortSpecs {
+		// An index serves both directions, so oldest reuses the newest index.
+		if seen[spec.field] {
+			continue
+		}
+		seen[spec.field] = true
+		model := mongo.IndexModel{Keys: spec.order()}
+		if spec.collation != nil {
+			model.Options = options.Index().SetCollation(spec.collation)
+		}
+		models = append(models, model)
+	}
+	// Ingested posts are de-duplicated by the GUID and link of their feed
+	// item within its source. The unique GUID index also rejects an item
+	// imported twice concurrently.
+	models = append(models,
+		mongo.IndexModel{
+			Keys: bson.D{{Key: "origin.source_id", Value: 1}, {Key: "origin.guid", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"origin.guid": bson.M{"$exists": true}}),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "origin.source_id", Value: 1}, {Key: "origin.link", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		// Few posts are ever flagged as breaking news.
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "breaking.until", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	)
+	models = append(models, mongo.IndexModel{Keys: bson.D{{Key: "contributors.name", Value: 1}}})
+	if _, err := r.collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("failed to create post indexes: %w", err)
+	}
+
+	for _, field := range []string{"view_count", "bookmark_count"} {
+		_, err := r.collection.UpdateMany(ctx,
+			bson.M{field: bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{field: int64(0)}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to backfill %s: %w", field, err)
+		}
+	}
+
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"contributors": bson.M{"$exists": false}, "author": bson.M{"$nin": bson.A{nil, ""}}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"contributors": bson.A{bson.M{"name": "$author", "role": domain.RoleAuthor}},
+		}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill contributors: %w", err)
+	}
+	return nil
+}
+
+// GetFacets implements Repository.GetFacets. Every facet is counted with all
+// other active filters applied. Single-valued dimensions ignore their own filter
+// so that alternative values stay visible; tags keep it because tags combine.
+// Posts stored before statuses existed are counted as published, as statusFilter
+// matches them.
+func (r *MongoRepository) GetFacets(ctx context.Context, q domain.PostQuery) (*domain.PostFacets, error) {
+	groupBy := func(value interface{}) bson.A {
+		return bson.A{
+			bson.M{"$group": bson.M{"_id": value, "count": bson.M{"$sum": 1}}},
+			bson.M{"$match": bson.M{"_id": bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": domain.MaxFacetValues},
+		}
+	}
+	group := func(field string) bson.A {
+		return groupBy("$" + field)
+	}
+	facet := func(dimension, field string, unwind bool) bson.A {
+		stages := bson.A{bson.M{"$match": buildFilter(q, dimension)}}
+		if unwind {
+			stages = append(stages, bson.M{"$unwind": "$" + field})
+		}
+		return append(stages, group(field)...)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$facet", Value: bson.M{
+			"categories": facet("category", "category", false),
+			"tags":       facet("", "tags", true),
+			"authors": append(bson.A{
+				bson.M{"$match": buildFilter(q, "author")},
+				// A contributor credited in several roles counts once per post.
+				bson.M{"$project": bson.M{"name": bson.M{"$setUnion": bson.A{"$contributors.name", bson.A{}}}}},
+				bson.M{"$unwind": "$name"},
+			}, group("name")...),
+			"statuses": append(bson.A{bson.M{"$match": buildFilter(q, "status")}},
+				groupBy(bson.M{"$ifNull": bson.A{"$status", domain.PostStatusPublished}})...),
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate facets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Categories []domain.FacetValue `bson:"categories"`
+		Tags       []domain.FacetValue `bson:"tags"`
+		Authors    []domain.FacetValue `bson:"authors"`
+		Statuses   []domain.FacetValue `bson:"statuses"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode facets: %w", err)
+	}
+
+	facets := &domain.PostFacets{}
+	if len(results) > 0 {
+		facets.Categories = results[0].Categories
+		facets.Tags = results[0].Tags
+		facets.Authors = results[0].Authors
+		facets.Statuses = results[0].Statuses
+	}
+	return facets, nil
+}
+
+// buildFilter translates a query into a MongoDB filter. The filter on the
+// dimension named by skip is left out.
+func buildFilter(q domain.PostQuery, skip string) bson.M {
+	filter := bson.M{}
+
+	if q.Search != "" {
+		pattern := regexp.QuoteMeta(q.Search)
+		filter["$or"] = []bson.M{
+			{"title": bson.M{"$regex": pattern, "$options": "i"}},
+			{"content": bson.M{"$regex": pattern, "$options": "i"}},
+		}
+	}
+	if q.Category != "" && skip != "category" {
+		filter["category"] = q.Category
+	}
+	if len(q.Tags) > 0 && skip != "tags" {
+		filter["tags"] = bson.M{"$all": q.Tags}
+	}
+	// Any contributor matches the author filter, whatever their role.
+	if q.Author != "" && skip != "author" {
+		filter["contributors.name"] = q.Author
+	}
+	if q.Status != "" && skip != "status" {
+		filter["status"] = statusFilter(q.Status)
+	}
+
+	createdAt := bson.M{}
+	if !q.From.IsZero() {
+		createdAt["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		createdAt["$lte"] = q.To
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+
+	return filter
+}
+
+// statusFilter matches a status; posts stored before statuses existed count as published.
+func statusFilter(status domain.PostStatus) interface{} {
+	if status == domain.PostStatusPublished {
+		return bson.M{"$in": bson.A{domain.PostStatusPublished, nil}}
+	}
+	return status
+}
+
+// GetArchive implements Repository.GetArchive. Months are returned newest first.
+func (r *MongoRepository) GetArchive(ctx context.Context) ([]domain.ArchiveMonth, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"year":  bson.M{"$year": "$created_at"},
+				"month": bson.M{"$month": "$created_at"},
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.year", Value: -1}, {Key: "_id.month", Value: -1}}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "year": "$_id.year", "month": "$_id.month", "count": 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate archive: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var months []domain.ArchiveMonth
+	if err := cursor.All(ctx, &months); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	return months, nil
 }
 
 // GetRecent implements Repository.GetRecent
@@ -178,7 +489,7 @@ func (r *MongoRepository) GetRecent(ctx context.Context, limit int) ([]*domain.P
 
 	findOptions := options.Find().
 		SetLimit(int64(limit)).
-		SetSort(bson.M{"created_at": -1})
+		SetSort(sortSpecs[domain.SortNewest].order())
 
 	cursor, err := r.collection.Find(ctx, bson.M{}, findOptions)
 	if err != nil {
@@ -193,3 +504,197 @@ func (r *MongoRepository) GetRecent(ctx context.Context, limit int) ([]*domain.P
 
 	return posts, nil
 }
+
+// IncrementViews implements Repository.IncrementViews
+func (r *MongoRepository) IncrementViews(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$inc": bson.M{"view_count": 1}})
+	if err != nil {
+		return fmt.Errorf("failed to increment views: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// IncrementBookmarks implements Repository.IncrementBookmarks
+func (r *MongoRepository) IncrementBookmarks(ctx context.Context, id string, delta int) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$inc": bson.M{"bookmark_count": delta}})
+	if err != nil {
+		return fmt.Errorf("failed to increment bookmarks: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// CountPublished implements Repository.CountPublished
+func (r *MongoRepository) CountPublished(ctx context.Context) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"status": statusFilter(domain.PostStatusPublished)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count published posts: %w", err)
+	}
+	return count, nil
+}
+
+// GetAuthors implements Repository.GetAuthors. Every contributor counts,
+// whatever their role.
+func (r *MongoRepository) GetAuthors(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "contributors.name", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+	authors := make([]string, 0, len(values))
+	for _, v := range values {
+		if name, ok := v.(string); ok {
+			authors = append(authors, name)
+		}
+	}
+	return authors, nil
+}
+
+// EachPublished implements Repository.EachPublished. Posts are decoded one at a
+// time from the cursor with only the fields sitemaps need; an error returned by
+// fn stops the iteration and is returned unchanged.
+func (r *MongoRepository) EachPublished(ctx context.Context, q domain.SitemapQuery, fn func(*domain.Post) error) error {
+	filter := bson.M{"status": statusFilter(domain.PostStatusPublished)}
+	if !q.Since.IsZero() {
+		filter["created_at"] = bson.M{"$gte": q.Since}
+	}
+
+	order := 1
+	if q.NewestFirst {
+		order = -1
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: order}}).
+		SetProjection(bson.M{"title": 1, "status": 1, "created_at": 1, "updated_at": 1}).
+		SetSkip(q.Offset).
+		SetBatchSize(1000)
+	if q.Limit > 0 {
+		findOptions.SetLimit(q.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find published posts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var p domain.Post
+		if err := cursor.Decode(&p); err != nil {
+			return fmt.Errorf("failed to decode post: %w", err)
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate published posts: %w", err)
+	}
+	return nil
+}
+
+// ExistsByOrigin implements Repository.ExistsByOrigin. A post matches when its
+// origin is the same source and has the same GUID or, if link is not empty,
+// the same link. GUIDs are only unique within one feed.
+func (r *MongoRepository) ExistsByOrigin(ctx context.Context, sourceID primitive.ObjectID, guid, link string) (bool, error) {
+	conditions := bson.A{bson.M{"origin.guid": guid}}
+	if link != "" {
+		conditions = append(conditions, bson.M{"origin.link": link})
+	}
+
+	filter := bson.M{"origin.source_id": sourceID, "$or": conditions}
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to look up imported post: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetFingerprints implements Repository.GetFingerprints. Posts saved before
+// fingerprints were introduced are left out until they are backfilled.
+func (r *MongoRepository) GetFingerprints(ctx context.Context) ([]domain.PostFingerprint, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "fingerprint": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"fingerprint": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fingerprints: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var fingerprints []domain.PostFingerprint
+	if err := cursor.All(ctx, &fingerprints); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprints: %w", err)
+	}
+	return fingerprints, nil
+}
+
+// SetFingerprint implements Repository.SetFingerprint without touching updated_at.
+func (r *MongoRepository) SetFingerprint(ctx context.Context, id string, fingerprint int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{"fingerprint": fingerprint}})
+	if err != nil {
+		return fmt.Errorf("failed to set fingerprint: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// SetBreaking implements Repository.SetBreaking
+func (r *MongoRepository) SetBreaking(ctx context.Context, id string, breaking *domain.Breaking) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id format: %w", err)
+	}
+
+	update := bson.M{"$unset": bson.M{"breaking": ""}}
+	if breaking != nil {
+		update = bson.M{"$set": bson.M{"breaking": breaking}}
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to set breaking news flag: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("post not found")
+	}
+	return nil
+}
+
+// GetBreaking implements Repository.GetBreaking
+func (r *MongoRepository) GetBreaking(ctx context.Context, now time.Time) ([]*domain.Post, error) {
+	filter := bson.M{
+		"status":         statusFilter(domain.PostStatusPublished),
+		"breaking.until": bson.M{"$gt": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "breaking.since", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find breaking news: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var posts []*domain.Post
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, fmt.Errorf("failed to decode breaking news: %w", err)
+	}
+	return posts, nil
+}
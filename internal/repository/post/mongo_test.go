@@ -2,6 +2,7 @@ package postrepo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -109,7 +111,28 @@ func TestMongoRepository_GetByID(t *testing.T) {
 
 	// Test retrieval of non-existent post
 	_, err = testRepo.GetByID(ctx, "nonexistent")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+	_, err = testRepo.GetByID(ctx, primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+}
+
+func TestMongoRepository_GetByIDs(t *testing.T) {
+	ctx := context.Background()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		post, err := domain.NewPost(fmt.Sprintf("Batch Title %d", i), "Batch content with more than 10 characters")
+		require.NoError(t, err)
+		require.NoError(t, testRepo.Create(ctx, post))
+		ids = append(ids, post.ID.Hex())
+	}
+
+	found, err := testRepo.GetByIDs(ctx, append(ids[:2], "invalid", primitive.NewObjectID().Hex()))
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	found, err = testRepo.GetByIDs(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
 }
 
 func TestMongoRepository_Update(t *testing.T) {
@@ -227,13 +250,312 @@ func TestMongoRepository_GetPaginated(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := testRepo.GetPaginated(ctx, tt.page, tt.pageSize, tt.search)
+			result, err := testRepo.GetPaginated(ctx, domain.PostQuery{Page: tt.page, PageSize: tt.pageSize, Search: tt.search})
 			assert.NoError(t, err)
 			assert.Len(t, result.Posts, tt.want)
 		})
 	}
 }
 
+func TestMongoRepository_GetPaginatedCursor(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+	require.NoError(t, NewMongoRepository(testDB).EnsureIndexes(ctx))
+
+	// Posts sharing a creation time exercise the _id tiebreak.
+	createdAt := time.Now().Truncate(time.Millisecond)
+	for i := 0; i < 7; i++ {
+		post, err := domain.NewPost(
+			fmt.Sprintf("Test Title %d", i),
+			fmt.Sprintf("Test content %d with more than 10 characters", i),
+		)
+		require.NoError(t, err)
+		post.CreatedAt = createdAt.Add(-time.Duration(i/2) * time.Minute)
+		require.NoError(t, testRepo.Create(ctx, post))
+	}
+
+	offset, err := testRepo.GetPaginated(ctx, domain.PostQuery{Page: 1, PageSize: 7})
+	require.NoError(t, err)
+	assert.Empty(t, offset.NextCursor)
+
+	var seen []primitive.ObjectID
+	query := domain.PostQuery{PageSize: 3, SkipCount: true}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		result, err := testRepo.GetPaginated(ctx, query)
+		require.NoError(t, err)
+		assert.Zero(t, result.TotalCount)
+		for _, p := range result.Posts {
+			seen = append(seen, p.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		query.Cursor = result.NextCursor
+	}
+
+	want := make([]primitive.ObjectID, len(offset.Posts))
+	for i, p := range offset.Posts {
+		want[i] = p.ID
+	}
+	assert.Equal(t, want, seen)
+
+	_, err = testRepo.GetPaginated(ctx, domain.PostQuery{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestMongoRepository_GetPaginatedSort(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	var ids []primitive.ObjectID
+	for i, title := range []string{"Charlie", "alpha", "Bravo"} {
+		post, err := domain.NewPost(title, "Test content with more than 10 characters")
+		require.NoError(t, err)
+		post.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		require.NoError(t, testRepo.Create(ctx, post))
+		ids = append(ids, post.ID)
+	}
+	for n, id := range ids {
+		for i := 0; i <= n*2; i++ {
+			require.NoError(t, testRepo.IncrementViews(ctx, id.Hex()))
+		}
+	}
+	// A post stored before counters existed is backfilled on startup.
+	legacy := primitive.NewObjectID()
+	_, err = testDB.Collection("posts").InsertOne(ctx, bson.M{"_id": legacy, "title": "Delta", "content": "Legacy content without counters", "created_at": time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, NewMongoRepository(testDB).EnsureIndexes(ctx))
+
+	tests := []struct {
+		sort domain.PostSort
+		want []primitive.ObjectID
+	}{
+		{sort: domain.SortNewest, want: []primitive.ObjectID{ids[2], ids[1], ids[0], legacy}},
+		{sort: domain.SortOldest, want: []primitive.ObjectID{legacy, ids[0], ids[1], ids[2]}},
+		{sort: domain.SortViews, want: []primitive.ObjectID{ids[2], ids[1], ids[0], legacy}},
+		// Titles are compared ignoring case.
+		{sort: domain.SortTitle, want: []primitive.ObjectID{ids[1], ids[2], ids[0], legacy}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			var got []primitive.ObjectID
+			query := domain.PostQuery{PageSize: 1, Sort: tt.sort, SkipCount: true}
+			for pages := 0; pages < len(tt.want); pages++ {
+				result, err := testRepo.GetPaginated(ctx, query)
+				require.NoError(t, err)
+				for _, p := range result.Posts {
+					got = append(got, p.ID)
+				}
+				if result.NextCursor == "" {
+					break
+				}
+				query.Cursor = result.NextCursor
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	first, err := testRepo.GetPaginated(ctx, domain.PostQuery{PageSize: 1, Sort: domain.SortViews})
+	require.NoError(t, err)
+	_, err = testRepo.GetPaginated(ctx, domain.PostQuery{PageSize: 1, Sort: domain.SortTitle, Cursor: first.NextCursor})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor, "cursors are bound to their order")
+}
+
+func TestMongoRepository_Filters(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	inputs := []domain.PostInput{
+		{Title: "Budget vote", Content: "Parliament passed the budget", Category: "politics", Tags: []string{"budget", "parliament"}, Author: "anna"},
+		{Title: "Cabinet reshuffle", Content: "Two ministers were replaced", Category: "politics", Tags: []string{"parliament"}, Author: "ivan", Status: domain.PostStatusDraft},
+		{Title: "Derby result", Content: "The home side won two-nil", Category: "sport", Tags: []string{"football"}, Author: "anna"},
+	}
+	for _, in := range inputs {
+		post, err := domain.NewPostFromInput(in)
+		require.NoError(t, err)
+		require.NoError(t, testRepo.Create(ctx, post))
+	}
+
+	tests := []struct {
+		name  string
+		query domain.PostQuery
+		want  int64
+	}{
+		{name: "category", query: domain.PostQuery{Category: "politics"}, want: 2},
+		{name: "single tag", query: domain.PostQuery{Tags: []string{"parliament"}}, want: 2},
+		{name: "all tags", query: domain.PostQuery{Tags: []string{"parliament", "budget"}}, want: 1},
+		{name: "author", query: domain.PostQuery{Author: "anna"}, want: 2},
+		{name: "status", query: domain.PostQuery{Status: domain.PostStatusDraft}, want: 1},
+		{name: "combined", query: domain.PostQuery{Category: "politics", Status: domain.PostStatusPublished}, want: 1},
+		{name: "date range", query: domain.PostQuery{From: time.Now().Add(time.Hour)}, want: 0},
+		{name: "regex characters are literal", query: domain.PostQuery{Search: "two-nil ("}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := testRepo.GetPaginated(ctx, tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.TotalCount)
+		})
+	}
+
+	facets, err := testRepo.GetFacets(ctx, domain.PostQuery{Category: "politics"})
+	require.NoError(t, err)
+	// The category facet ignores the category filter itself.
+	assert.Equal(t, []domain.FacetValue{{Value: "politics", Count: 2}, {Value: "sport", Count: 1}}, facets.Categories)
+	assert.Equal(t, []domain.FacetValue{{Value: "parliament", Count: 2}, {Value: "budget", Count: 1}}, facets.Tags)
+	assert.Equal(t, []domain.FacetValue{{Value: "anna", Count: 1}, {Value: "ivan", Count: 1}}, facets.Authors)
+	assert.Equal(t, []domain.FacetValue{{Value: "draft", Count: 1}, {Value: "published", Count: 1}}, facets.Statuses)
+}
+
+func TestMongoRepository_Contributors(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	post, err := domain.NewPostFromInput(domain.PostInput{
+		Title:   "Flood aftermath",
+		Content: "The river is back in its bed",
+		Contributors: []domain.Contributor{
+			{Name: "anna", Role: domain.RoleAuthor},
+			{Name: "boris", Role: domain.RolePhotographer},
+			{Name: "anna", Role: domain.RoleEditor},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Create(ctx, post))
+	// Posts stored before contributors existed only name their author.
+	_, err = testDB.Collection("posts").InsertOne(ctx, bson.M{"_id": primitive.NewObjectID(), "title": "Legacy", "content": "Written before contributors", "author": "ivan", "created_at": time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, NewMongoRepository(testDB).EnsureIndexes(ctx))
+
+	for name, want := range map[string]int64{"anna": 1, "boris": 1, "ivan": 1} {
+		result, err := testRepo.GetPaginated(ctx, domain.PostQuery{Author: name})
+		require.NoError(t, err)
+		assert.Equal(t, want, result.TotalCount, name)
+	}
+
+	facets, err := testRepo.GetFacets(ctx, domain.PostQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FacetValue{{Value: "anna", Count: 1}, {Value: "boris", Count: 1}, {Value: "ivan", Count: 1}}, facets.Authors,
+		"a contributor credited twice counts once")
+
+	authors, err := testRepo.GetAuthors(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"anna", "boris", "ivan"}, authors)
+}
+
+func TestMongoRepository_GetArchive(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	for _, createdAt := range []time.Time{
+		time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 31, 23, 59, 0, 0, time.UTC),
+		time.Date(2025, time.April, 2, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, time.December, 24, 18, 0, 0, 0, time.UTC),
+	} {
+		post, err := domain.NewPost("Archived post", "Test content with more than 10 characters")
+		require.NoError(t, err)
+		post.CreatedAt = createdAt
+		require.NoError(t, testRepo.Create(ctx, post))
+	}
+
+	months, err := testRepo.GetArchive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ArchiveMonth{
+		{Year: 2025, Month: time.April, Count: 1},
+		{Year: 2025, Month: time.March, Count: 2},
+		{Year: 2024, Month: time.December, Count: 1},
+	}, months)
+}
+
+func TestMongoRepository_EachPublished(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	var published []primitive.ObjectID
+	for i, createdAt := range []time.Time{now.Add(-72 * time.Hour), now.Add(-24 * time.Hour), now.Add(-time.Hour)} {
+		post, err := domain.NewPost(fmt.Sprintf("Published %d", i), "Test content with more than 10 characters")
+		require.NoError(t, err)
+		post.CreatedAt = createdAt
+		require.NoError(t, testRepo.Create(ctx, post))
+		published = append(published, post.ID)
+	}
+	draft, err := domain.NewPostFromInput(domain.PostInput{Title: "Draft", Content: "Test content with more than 10 characters", Status: domain.PostStatusDraft})
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Create(ctx, draft))
+
+	count, err := testRepo.CountPublished(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	collect := func(q domain.SitemapQuery) []primitive.ObjectID {
+		var ids []primitive.ObjectID
+		require.NoError(t, testRepo.EachPublished(ctx, q, func(p *domain.Post) error {
+			assert.Empty(t, p.Content, "content must not be loaded")
+			ids = append(ids, p.ID)
+			return nil
+		}))
+		return ids
+	}
+
+	assert.Equal(t, published, collect(domain.SitemapQuery{}))
+	assert.Equal(t, published[1:2], collect(domain.SitemapQuery{Offset: 1, Limit: 1}))
+	assert.Equal(t, []primitive.ObjectID{published[2], published[1]}, collect(domain.SitemapQuery{Since: now.Add(-48 * time.Hour), NewestFirst: true}))
+
+	stop := errors.New("stop")
+	err = testRepo.EachPublished(ctx, domain.SitemapQuery{}, func(p *domain.Post) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestMongoRepository_Fingerprints(t *testing.T) {
+	ctx := context.Background()
+
+	err := testDB.Collection("posts").Drop(ctx)
+	require.NoError(t, err)
+
+	fingerprinted, err := domain.NewPost("Fingerprinted", "Test content with more than 10 characters")
+	require.NoError(t, err)
+	// The top bit is set to check that unsigned fingerprints survive the round trip.
+	fingerprinted.Fingerprint = -42
+	require.NoError(t, testRepo.Create(ctx, fingerprinted))
+
+	legacy, err := domain.NewPost("Legacy", "Test content with more than 10 characters")
+	require.NoError(t, err)
+	require.NoError(t, testRepo.Create(ctx, legacy))
+
+	fingerprints, err := testRepo.GetFingerprints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PostFingerprint{{ID: fingerprinted.ID, Fingerprint: -42}}, fingerprints)
+
+	require.NoError(t, testRepo.SetFingerprint(ctx, legacy.ID.Hex(), 7))
+	fingerprints, err = testRepo.GetFingerprints(ctx)
+	require.NoError(t, err)
+	assert.Len(t, fingerprints, 2)
+
+	got, err := testRepo.GetByID(ctx, legacy.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.Fingerprint)
+	assert.Equal(t, legacy.UpdatedAt.Unix(), got.UpdatedAt.Unix())
+
+	assert.Error(t, testRepo.SetFingerprint(ctx, primitive.NewObjectID().Hex(), 1))
+}
+
 func TestMongoRepository_GetRecent(t *testing.T) {
 	ctx := context.Background()
 
@@ -344,7 +666,7 @@ func TestMongoRepository_ContextTimeout(t *testing.T) {
 	err = testRepo.Delete(ctx, "test")
 	assert.Error(t, err)
 
-	_, err = testRepo.GetPaginated(ctx, 1, 10, "")
+	_, err = testRepo.GetPaginated(ctx, domain.PostQuery{Page: 1, PageSize: 10})
 	assert.Error(t, err)
 
 	_, err = testRepo.GetRecent(ctx, 5)
@@ -0,0 +1,97 @@
+package searchlogrepo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// retention is how long individual search log entries are kept.
+const retention = 30 * 24 * time.Hour
+
+// entry is a single logged search.
+type entry struct {
+	Term       string    `bson:"term"`
+	SearchedAt time.Time `bson:"searched_at"`
+}
+
+// MongoRepository implements SearchLogRepository interface using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB search log repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("search_log"),
+	}
+}
+
+// EnsureIndexes creates the term index used for prefix lookups and a TTL
+// index expiring old entries.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "term", Value: 1}, {Key: "searched_at", Value: -1}}},
+		{Keys: bson.D{{Key: "searched_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds()))},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create search log indexes: %w", err)
+	}
+	return nil
+}
+
+// Record implements SearchLogRepository.Record. Terms are stored lowercased
+// with collapsed whitespace so that variants are counted together.
+func (r *MongoRepository) Record(ctx context.Context, term string, at time.Time) error {
+	term = normalizeTerm(term)
+	if term == "" {
+		return nil
+	}
+	if _, err := r.collection.InsertOne(ctx, entry{Term: term, SearchedAt: at}); err != nil {
+		return fmt.Errorf("failed to record search term: %w", err)
+	}
+	return nil
+}
+
+// Popular implements SearchLogRepository.Popular
+func (r *MongoRepository) Popular(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+	if limit < 1 {
+		limit = 5
+	}
+
+	match := bson.M{"searched_at": bson.M{"$gte": since}}
+	if prefix = normalizeTerm(prefix); prefix != "" {
+		match["term"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$term", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate popular queries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var queries []domain.PopularQuery
+	if err := cursor.All(ctx, &queries); err != nil {
+		return nil, fmt.Errorf("failed to decode popular queries: %w", err)
+	}
+	return queries, nil
+}
+
+func normalizeTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
@@ -0,0 +1,103 @@
+package sourcerepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository implements SourceRepository interface using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB source repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("sources"),
+	}
+}
+
+// EnsureIndexes makes source URLs unique and indexes the polling schedule.
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "url", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "next_fetch_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create source indexes: %w", err)
+	}
+	return nil
+}
+
+// Create implements SourceRepository.Create
+func (r *MongoRepository) Create(ctx context.Context, s *domain.Source) error {
+	if _, err := r.collection.InsertOne(ctx, s); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDuplicateSource
+		}
+		return fmt.Errorf("failed to insert source: %w", err)
+	}
+	return nil
+}
+
+// GetAll implements SourceRepository.GetAll. Sources are ordered by name.
+func (r *MongoRepository) GetAll(ctx context.Context) ([]*domain.Source, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sources: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sources []*domain.Source
+	if err := cursor.All(ctx, &sources); err != nil {
+		return nil, fmt.Errorf("failed to decode sources: %w", err)
+	}
+	return sources, nil
+}
+
+// GetDue implements SourceRepository.GetDue. Sources overdue the longest come first.
+func (r *MongoRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.Source, error) {
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"next_fetch_at": bson.M{"$lte": now}},
+		options.Find().SetSort(bson.D{{Key: "next_fetch_at", Value: 1}}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due sources: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sources []*domain.Source
+	if err := cursor.All(ctx, &sources); err != nil {
+		return nil, fmt.Errorf("failed to decode due sources: %w", err)
+	}
+	return sources, nil
+}
+
+// SaveFetchState implements SourceRepository.SaveFetchState. Only the fields
+// written by the fetcher are updated.
+func (r *MongoRepository) SaveFetchState(ctx context.Context, s *domain.Source) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": s.ID},
+		bson.M{"$set": bson.M{
+			"etag":            s.ETag,
+			"last_modified":   s.LastModified,
+			"last_fetched_at": s.LastFetchedAt,
+			"next_fetch_at":   s.NextFetchAt,
+			"last_error":      s.LastError,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save source fetch state: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("source not found")
+	}
+	return nil
+}
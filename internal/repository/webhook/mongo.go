@@ -0,0 +1,187 @@
+package webhookrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository implements WebhookRepository interface using MongoDB
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRepository creates a new MongoDB webhook repository
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("webhooks"),
+	}
+}
+
+// Create implements WebhookRepository.Create
+func (r *MongoRepository) Create(ctx context.Context, hook *domain.Webhook) error {
+	if _, err := r.collection.InsertOne(ctx, hook); err != nil {
+		return fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	return nil
+}
+
+// GetAll implements WebhookRepository.GetAll. Webhooks are ordered by creation.
+func (r *MongoRepository) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var hooks []*domain.Webhook
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// Delete implements WebhookRepository.Delete. The delivery log is kept.
+func (r *MongoRepository) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrWebhookNotFound
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// DeliveryRepository implements WebhookDeliveryRepository interface using MongoDB
+type DeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewDeliveryRepository creates a new MongoDB webhook delivery repository
+func NewDeliveryRepository(db *mongo.Database) *DeliveryRepository {
+	return &DeliveryRepository{
+		collection: db.Collection("webhook_deliveries"),
+	}
+}
+
+// EnsureIndexes indexes the retry schedule and the delivery log order, and
+// makes the delivery of an event to a webhook unique.
+func (r *DeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "event_id", Value: 1}, {Key: "webhook_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"event_id": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+	return nil
+}
+
+// Create implements WebhookDeliveryRepository.Create
+func (r *DeliveryRepository) Create(ctx context.Context, d *domain.WebhookDelivery) error {
+	_, err := r.collection.InsertOne(ctx, d)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrDuplicateDelivery
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetByID implements WebhookDeliveryRepository.GetByID
+func (r *DeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	var d domain.WebhookDelivery
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&d); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// GetRecent implements WebhookDeliveryRepository.GetRecent. Payloads are not loaded.
+func (r *DeliveryRepository) GetRecent(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*domain.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDue implements WebhookDeliveryRepository.ClaimDue. The delivery due
+// the longest is claimed first.
+func (r *DeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// Save implements WebhookDeliveryRepository.Save. Only the attempt state is updated.
+func (r *DeliveryRepository) Save(ctx context.Context, d *domain.WebhookDelivery) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": d.ID},
+		bson.M{"$set": bson.M{
+			"status":          d.Status,
+			"attempts":        d.Attempts,
+			"next_attempt_at": d.NextAttemptAt,
+			"last_attempt_at": d.LastAttemptAt,
+			"response_status": d.ResponseStatus,
+			"last_error":      d.LastError,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDeliveryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// Facets implements domain.SearchIndex.Facets. Counts are taken over the
+// documents containing every term of text, so they agree with the hits of
+// Query. As in the post repository, single-valued dimensions ignore their own
+// filter, tags keep it, and posts without a status count as published.
+func (i *Index) Facets(ctx context.Context, text string, filters domain.SearchFilters) (*domain.PostFacets, error) {
+	terms := uniqueTerms(Analyze(text))
+	if len(terms) == 0 {
+		return &domain.PostFacets{}, nil
+	}
+
+	anyCategory, anyAuthor, anyStatus := filters, filters, filters
+	anyCategory.Category = ""
+	anyAuthor.Author = ""
+	anyStatus.Status = ""
+
+	categories, tags, authors, statuses := facetCounter{}, facetCounter{}, facetCounter{}, facetCounter{}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for id := range i.candidates(terms) {
+		doc := i.docs[id]
+		if !matchesAll(doc, terms) {
+			continue
+		}
+		if matchesFilters(doc, anyCategory) {
+			categories.add(doc.category)
+		}
+		if matchesFilters(doc, filters) {
+			tags.add(doc.tags...)
+		}
+		if matchesFilters(doc, anyAuthor) {
+			authors.add(doc.authors...)
+		}
+		if matchesFilters(doc, anyStatus) {
+			status := doc.status
+			if status == "" {
+				status = domain.PostStatusPublished
+			}
+			statuses.add(string(status))
+		}
+	}
+
+	return &domain.PostFacets{
+		Categories: categories.top(),
+		Tags:       tags.top(),
+		Authors:    authors.top(),
+		Statuses:   statuses.top(),
+	}, nil
+}
+
+// facetCounter counts the documents carrying each value of a dimension.
+type facetCounter map[string]int64
+
+// add counts one document with the given values. Empty values are skipped and
+// a value repeated within the document counts once.
+func (c facetCounter) add(values ...string) {
+	for n, v := range values {
+		if v == "" || slices.Contains(values[:n], v) {
+			continue
+		}
+		c[v]++
+	}
+}
+
+// top returns the domain.MaxFacetValues most frequent values, ordered by count
+// and then by value.
+func (c facetCounter) top() []domain.FacetValue {
+	values := make([]domain.FacetValue, 0, len(c))
+	for v, count := range c {
+		values = append(values, domain.FacetValue{Value: v, Count: count})
+	}
+	sort.Slice(values, func(a, b int) bool {
+		if values[a].Count != values[b].Count {
+			return values[a].Count > values[b].Count
+		}
+		return values[a].Value < values[b].Value
+	})
+	if len(values) > domain.MaxFacetValues {
+		values = values[:domain.MaxFacetValues]
+	}
+	return values
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_Facets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	budget := newTestPost("Budget vote", "Parliament passed the budget", now)
+	budget.Category, budget.Author, budget.Tags = "politics", "anna", []string{"budget", "parliament"}
+	draft := newTestPost("Budget leak", "Draft budgets leaked", now)
+	draft.Category, draft.Status = "politics", domain.PostStatusDraft
+	draft.Contributors = []domain.Contributor{
+		{Name: "ivan", Role: domain.RoleAuthor},
+		{Name: "ivan", Role: domain.RolePhotographer},
+		{Name: "boris", Role: domain.RoleEditor},
+	}
+	sport := newTestPost("Budget cuts hit clubs", "Football clubs face cuts", now)
+	sport.Category, sport.Author, sport.Tags = "sport", "anna", []string{"budget"}
+	weather := newTestPost("Weather", "Sunny skies all week", now)
+	weather.Category = "weather"
+
+	idx := NewIndex()
+	require.NoError(t, idx.Rebuild(ctx, []*domain.Post{budget, draft, sport, weather}))
+
+	facets, err := idx.Facets(ctx, "budgeting", domain.SearchFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FacetValue{{Value: "politics", Count: 2}, {Value: "sport", Count: 1}}, facets.Categories)
+	assert.Equal(t, []domain.FacetValue{{Value: "budget", Count: 2}, {Value: "parliament", Count: 1}}, facets.Tags)
+	assert.Equal(t, []domain.FacetValue{{Value: "anna", Count: 2}, {Value: "boris", Count: 1}, {Value: "ivan", Count: 1}}, facets.Authors,
+		"a contributor credited twice counts once")
+	assert.Equal(t, []domain.FacetValue{{Value: "published", Count: 2}, {Value: "draft", Count: 1}}, facets.Statuses,
+		"posts without a status count as published")
+
+	facets, err = idx.Facets(ctx, "budget", domain.SearchFilters{Category: "politics", Status: domain.PostStatusPublished})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FacetValue{{Value: "politics", Count: 1}, {Value: "sport", Count: 1}}, facets.Categories,
+		"the category facet ignores the category filter")
+	assert.Equal(t, []domain.FacetValue{{Value: "draft", Count: 1}, {Value: "published", Count: 1}}, facets.Statuses)
+	assert.Equal(t, []domain.FacetValue{{Value: "anna", Count: 1}}, facets.Authors)
+
+	facets, err = idx.Facets(ctx, "the", domain.SearchFilters{})
+	require.NoError(t, err)
+	assert.Empty(t, facets.Categories)
+}
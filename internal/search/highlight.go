@@ -0,0 +1,157 @@
+package search
+
+import "github.com/kir/news-app/internal/domain"
+
+// snippetWords is the number of words shown in a search snippet.
+const snippetWords = 30
+
+// Highlighter marks occurrences of query terms in text. Words match when their
+// stems equal a stem of the query, so "elections" highlights "election".
+type Highlighter struct {
+	terms map[string]struct{}
+}
+
+// NewHighlighter creates a highlighter for the given query text.
+func NewHighlighter(query string) *Highlighter {
+	h := &Highlighter{terms: make(map[string]struct{})}
+	for _, t := range Analyze(query) {
+		h.terms[t] = struct{}{}
+	}
+	return h
+}
+
+// Highlight builds the title and snippet highlights for a post.
+func (h *Highlighter) Highlight(title, content string) *domain.Highlight {
+	return &domain.Highlight{
+		Title:   h.Fragments(title),
+		Snippet: h.Snippet(content, snippetWords),
+	}
+}
+
+// Fragments splits text into alternating plain and matched fragments.
+func (h *Highlighter) Fragments(text string) []domain.TextFragment {
+	tokens := Tokenize(text)
+	return h.fragments(text, tokens, 0, len(text))
+}
+
+// Snippet returns a window of at most words words around the densest cluster
+// of matches. Without matches the snippet is the beginning of the text.
+func (h *Highlighter) Snippet(text string, words int) []domain.TextFragment {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	if len(tokens) <= words {
+		return h.fragments(text, tokens, 0, len(text))
+	}
+
+	first, last := h.bestWindow(tokens, words)
+	start, end := tokens[first].Start, tokens[last].End
+	if first == 0 {
+		start = 0
+	}
+	if last == len(tokens)-1 {
+		end = len(text)
+	}
+
+	fragments := h.fragments(text, tokens[first:last+1], start, end)
+	if first > 0 {
+		fragments = append([]domain.TextFragment{{Text: "… "}}, fragments...)
+	}
+	if last < len(tokens)-1 {
+		fragments = append(fragments, domain.TextFragment{Text: " …"})
+	}
+	return mergeFragments(fragments)
+}
+
+// bestWindow returns the first and last token index of the window of the given
+// size containing the most matches. The window is shifted so that the first
+// match is preceded by a little context.
+func (h *Highlighter) bestWindow(tokens []Token, size int) (int, int) {
+	const leadingContext = 5
+
+	matched := make([]bool, len(tokens))
+	for i, t := range tokens {
+		matched[i] = h.matches(t.Term)
+	}
+
+	best, bestCount, count := 0, 0, 0
+	for i := range tokens {
+		if matched[i] {
+			count++
+		}
+		if i >= size && matched[i-size] {
+			count--
+		}
+		if count > bestCount {
+			bestCount = count
+			best = i - size + 1
+		}
+	}
+	if bestCount == 0 {
+		return 0, size - 1
+	}
+
+	if best < 0 {
+		best = 0
+	}
+	for best < len(tokens) && !matched[best] {
+		best++
+	}
+	best -= leadingContext
+	if best < 0 {
+		best = 0
+	}
+	last := best + size - 1
+	if last >= len(tokens) {
+		last = len(tokens) - 1
+		best = last - size + 1
+	}
+	return best, last
+}
+
+// fragments converts text[start:end] into fragments using the given tokens,
+// which must lie within that range.
+func (h *Highlighter) fragments(text string, tokens []Token, start, end int) []domain.TextFragment {
+	var fragments []domain.TextFragment
+	pos := start
+	for _, t := range tokens {
+		if !h.matches(t.Term) {
+			continue
+		}
+		if t.Start > pos {
+			fragments = append(fragments, domain.TextFragment{Text: text[pos:t.Start]})
+		}
+		fragments = append(fragments, domain.TextFragment{Text: text[t.Start:t.End], Match: true})
+		pos = t.End
+	}
+	if pos < end {
+		fragments = append(fragments, domain.TextFragment{Text: text[pos:end]})
+	}
+	return mergeFragments(fragments)
+}
+
+func (h *Highlighter) matches(term string) bool {
+	if len(h.terms) == 0 {
+		return false
+	}
+	stem, ok := normalize(term)
+	if !ok {
+		return false
+	}
+	_, found := h.terms[stem]
+	return found
+}
+
+// mergeFragments joins adjacent fragments with the same match state.
+func mergeFragments(fragments []domain.TextFragment) []domain.TextFragment {
+	out := fragments[:0]
+	for _, f := range fragments {
+		if n := len(out); n > 0 && out[n-1].Match == f.Match {
+			out[n-1].Text += f.Text
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
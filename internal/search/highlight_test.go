@@ -0,0 +1,71 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func matchedTexts(fragments []domain.TextFragment) []string {
+	var out []string
+	for _, f := range fragments {
+		if f.Match {
+			out = append(out, f.Text)
+		}
+	}
+	return out
+}
+
+func joinFragments(fragments []domain.TextFragment) string {
+	var b strings.Builder
+	for _, f := range fragments {
+		b.WriteString(f.Text)
+	}
+	return b.String()
+}
+
+func TestHighlighter_Fragments(t *testing.T) {
+	h := NewHighlighter("elections result")
+	title := "Election Results: the results are in"
+	fragments := h.Fragments(title)
+
+	assert.Equal(t, title, joinFragments(fragments))
+	assert.Equal(t, []string{"Election", "Results", "results"}, matchedTexts(fragments))
+	assert.Equal(t, []domain.TextFragment{{Text: "Title"}}, h.Fragments("Title"))
+}
+
+func TestHighlighter_Snippet(t *testing.T) {
+	filler := strings.Repeat("lorem ipsum dolor sit amet ", 20)
+	content := filler + "the central bank raised interest rates again " + filler
+
+	t.Run("window around deep match", func(t *testing.T) {
+		fragments := NewHighlighter("interest rates").Snippet(content, 12)
+		text := joinFragments(fragments)
+
+		assert.True(t, strings.HasPrefix(text, "… "))
+		assert.True(t, strings.HasSuffix(text, " …"))
+		assert.Contains(t, text, "central bank raised interest rates again")
+		assert.Equal(t, []string{"interest", "rates"}, matchedTexts(fragments))
+		assert.LessOrEqual(t, len(strings.Fields(text)), 12+2)
+	})
+
+	t.Run("no match falls back to the beginning", func(t *testing.T) {
+		fragments := NewHighlighter("weather").Snippet(content, 5)
+		assert.Equal(t, "lorem ipsum dolor sit amet …", joinFragments(fragments))
+		assert.Empty(t, matchedTexts(fragments))
+	})
+
+	t.Run("short text is returned whole", func(t *testing.T) {
+		fragments := NewHighlighter("news").Snippet("Breaking news today.", 30)
+		assert.Equal(t, "Breaking news today.", joinFragments(fragments))
+		assert.Equal(t, []string{"news"}, matchedTexts(fragments))
+	})
+
+	t.Run("russian", func(t *testing.T) {
+		fragments := NewHighlighter("выборы").Snippet("Итоги выборов объявлены", 30)
+		assert.Equal(t, []string{"выборов"}, matchedTexts(fragments))
+	})
+}
@@ -0,0 +1,348 @@
+package search
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// BM25 tuning parameters.
+const (
+	bm25K1     = 1.2
+	bm25B      = 0.75
+	titleBoost = 3
+)
+
+// document holds the per-post data needed for ranking, filtering and highlighting.
+type document struct {
+	id        string
+	title     string
+	content   string
+	length    int
+	terms     map[string]int
+	category  string
+	tags      []string
+	authors   []string
+	status    domain.PostStatus
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// Index is an in-memory inverted index implementing domain.SearchIndex.
+// Title terms are counted titleBoost times so that title matches rank higher.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string]int
+	totalLen int
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Index implements domain.SearchIndex.Index. Re-indexing a post replaces its previous entry.
+func (i *Index) Index(ctx context.Context, post *domain.Post) error {
+	doc := newDocument(post)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(doc.id)
+	i.add(doc)
+	return nil
+}
+
+// Remove implements domain.SearchIndex.Remove
+func (i *Index) Remove(ctx context.Context, id string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(id)
+	return nil
+}
+
+// Rebuild implements domain.SearchIndex.Rebuild. The index is swapped atomically,
+// so queries running during a rebuild see either the old or the new contents.
+func (i *Index) Rebuild(ctx context.Context, posts []*domain.Post) error {
+	fresh := NewIndex()
+	for _, p := range posts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fresh.add(newDocument(p))
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.docs = fresh.docs
+	i.postings = fresh.postings
+	i.totalLen = fresh.totalLen
+	return nil
+}
+
+// Len returns the number of indexed documents.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+// Query implements domain.SearchIndex.Query. A document matches when it contains
+// every query term; matches are ranked by BM25 and then by creation time unless
+// the query asks for a date or title order. View counts are not indexed, so
+// that order ranks by relevance.
+// Hits on the requested page carry title and snippet highlights.
+func (i *Index) Query(ctx context.Context, q domain.SearchQuery) (*domain.SearchResult, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = 9
+	}
+
+	terms := uniqueTerms(Analyze(q.Text))
+	if len(terms) == 0 {
+		return &domain.SearchResult{}, nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	candidates := i.candidates(terms)
+	hits := make([]domain.SearchHit, 0, len(candidates))
+	for id := range candidates {
+		doc := i.docs[id]
+		if !matchesAll(doc, terms) || !matchesFilters(doc, q.Filters) {
+			continue
+		}
+		hits = append(hits, domain.SearchHit{ID: id, Score: i.score(doc, terms)})
+	}
+
+	less := i.relevanceOrder(hits)
+	switch q.Sort {
+	case domain.SortNewest:
+		less = i.dateOrder(hits, func(d *document) time.Time { return d.createdAt }, true)
+	case domain.SortOldest:
+		less = i.dateOrder(hits, func(d *document) time.Time { return d.createdAt }, false)
+	case domain.SortUpdated:
+		less = i.dateOrder(hits, func(d *document) time.Time { return d.updatedAt }, true)
+	case domain.SortTitle:
+		less = func(a, b int) bool {
+			ta, tb := strings.ToLower(i.docs[hits[a].ID].title), strings.ToLower(i.docs[hits[b].ID].title)
+			if ta != tb {
+				return ta < tb
+			}
+			return hits[a].ID < hits[b].ID
+		}
+	}
+	sort.Slice(hits, less)
+
+	total := len(hits)
+	start := (q.Page - 1) * q.PageSize
+	if start > total {
+		start = total
+	}
+	end := start + q.PageSize
+	if end > total {
+		end = total
+	}
+
+	page := hits[start:end]
+	highlighter := NewHighlighter(q.Text)
+	for n := range page {
+		doc := i.docs[page[n].ID]
+		page[n].Highlight = highlighter.Highlight(doc.title, doc.content)
+	}
+
+	return &domain.SearchResult{
+		Hits:  page,
+		Total: total,
+	}, nil
+}
+
+// candidates returns the postings of the rarest of terms, which hold every
+// document that can contain all of them; the caller must hold the read lock.
+func (i *Index) candidates(terms []string) map[string]int {
+	candidates := i.postings[terms[0]]
+	for _, t := range terms[1:] {
+		if len(i.postings[t]) < len(candidates) {
+			candidates = i.postings[t]
+		}
+	}
+	return candidates
+}
+
+// relevanceOrder ranks hits by score, newer posts first among equal scores;
+// the caller must hold the read lock.
+func (i *Index) relevanceOrder(hits []domain.SearchHit) func(a, b int) bool {
+	return func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return i.docs[hits[a].ID].createdAt.After(i.docs[hits[b].ID].createdAt)
+	}
+}
+
+// dateOrder orders hits by a document date, breaking ties by score;
+// the caller must hold the read lock.
+func (i *Index) dateOrder(hits []domain.SearchHit, date func(*document) time.Time, desc bool) func(a, b int) bool {
+	return func(a, b int) bool {
+		da, db := date(i.docs[hits[a].ID]), date(i.docs[hits[b].ID])
+		if !da.Equal(db) {
+			return da.After(db) == desc
+		}
+		return hits[a].Score > hits[b].Score
+	}
+}
+
+// HandlePostEvent implements domain.PostEventHandler, keeping the index in sync
+// with post mutations.
+func (i *Index) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	switch event.Type {
+	case domain.PostCreated, domain.PostUpdated:
+		if event.Post != nil {
+			return i.Index(ctx, event.Post)
+		}
+	case domain.PostDeleted:
+		return i.Remove(ctx, event.PostID)
+	}
+	return nil
+}
+
+func newDocument(p *domain.Post) *document {
+	doc := &document{
+		id:        p.ID.Hex(),
+		title:     p.Title,
+		content:   p.Content,
+		terms:     make(map[string]int),
+		category:  p.Category,
+		tags:      p.Tags,
+		status:    p.Status,
+		createdAt: p.CreatedAt,
+		updatedAt: p.UpdatedAt,
+	}
+	// Any contributor matches the author filter, as in the post repository.
+	for _, c := range p.Credits() {
+		doc.authors = append(doc.authors, c.Name)
+	}
+	for _, t := range Analyze(p.Title) {
+		doc.terms[t] += titleBoost
+		doc.length += titleBoost
+	}
+	for _, t := range Analyze(p.Content) {
+		doc.terms[t]++
+		doc.length++
+	}
+	return doc
+}
+
+// add inserts doc; the caller must hold the write lock.
+func (i *Index) add(doc *document) {
+	i.docs[doc.id] = doc
+	i.totalLen += doc.length
+	for term, tf := range doc.terms {
+		postings, ok := i.postings[term]
+		if !ok {
+			postings = make(map[string]int)
+			i.postings[term] = postings
+		}
+		postings[doc.id] = tf
+	}
+}
+
+// remove deletes the document with the given id; the caller must hold the write lock.
+func (i *Index) remove(id string) {
+	doc, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	i.totalLen -= doc.length
+	delete(i.docs, id)
+}
+
+// score computes the BM25 score of doc for terms; the caller must hold the read lock.
+func (i *Index) score(doc *document, terms []string) float64 {
+	n := float64(len(i.docs))
+	avgLen := float64(i.totalLen) / n
+	var score float64
+	for _, t := range terms {
+		tf := float64(doc.terms[t])
+		if tf == 0 {
+			continue
+		}
+		df := float64(len(i.postings[t]))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.length)/avgLen))
+		score += idf * norm
+	}
+	return score
+}
+
+func matchesAll(doc *document, terms []string) bool {
+	for _, t := range terms {
+		if doc.terms[t] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesFilters(doc *document, f domain.SearchFilters) bool {
+	if f.Category != "" && doc.category != f.Category {
+		return false
+	}
+	if f.Author != "" && !slices.Contains(doc.authors, f.Author) {
+		return false
+	}
+	if f.Status != "" && !statusMatches(doc.status, f.Status) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.Contains(doc.tags, tag) {
+			return false
+		}
+	}
+	if !f.From.IsZero() && doc.createdAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && doc.createdAt.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// statusMatches treats posts without a status as published, like the repository does.
+func statusMatches(status, want domain.PostStatus) bool {
+	if status == "" {
+		status = domain.PostStatusPublished
+	}
+	return status == want
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
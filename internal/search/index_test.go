@@ -0,0 +1,214 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestPost(title, content string, createdAt time.Time) *domain.Post {
+	return &domain.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     title,
+		Content:   content,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func hitIDs(result *domain.SearchResult) []string {
+	ids := make([]string, len(result.Hits))
+	for i, h := range result.Hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestIndex_Query(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	inTitle := newTestPost("Election results announced", "The commission published the final numbers today.", now.Add(-3*time.Hour))
+	inBody := newTestPost("Weekly roundup", "Among other things the election drew record turnout and results were close.", now.Add(-2*time.Hour))
+	unrelated := newTestPost("Weather forecast", "Rain is expected across the region tomorrow.", now.Add(-1*time.Hour))
+	russian := newTestPost("Итоги выборов", "Комиссия опубликовала результаты выборов в Москве.", now)
+
+	idx := NewIndex()
+	require.NoError(t, idx.Rebuild(ctx, []*domain.Post{inTitle, inBody, unrelated, russian}))
+	assert.Equal(t, 4, idx.Len())
+
+	tests := []struct {
+		name    string
+		query   domain.SearchQuery
+		want    []string
+		wantTot int
+	}{
+		{
+			name:    "title match ranks first",
+			query:   domain.SearchQuery{Text: "election results"},
+			want:    []string{inTitle.ID.Hex(), inBody.ID.Hex()},
+			wantTot: 2,
+		},
+		{
+			name:    "stemmed match",
+			query:   domain.SearchQuery{Text: "announcing"},
+			want:    []string{inTitle.ID.Hex()},
+			wantTot: 1,
+		},
+		{
+			name:    "all terms required",
+			query:   domain.SearchQuery{Text: "election rain"},
+			want:    []string{},
+			wantTot: 0,
+		},
+		{
+			name:    "russian morphology",
+			query:   domain.SearchQuery{Text: "выборы"},
+			want:    []string{russian.ID.Hex()},
+			wantTot: 1,
+		},
+		{
+			name:    "stop words only",
+			query:   domain.SearchQuery{Text: "the and"},
+			want:    []string{},
+			wantTot: 0,
+		},
+		{
+			name: "date filter",
+			query: domain.SearchQuery{
+				Text:    "results",
+				Filters: domain.SearchFilters{From: now.Add(-150 * time.Minute)},
+			},
+			want:    []string{inBody.ID.Hex()},
+			wantTot: 1,
+		},
+		{
+			name:    "pagination",
+			query:   domain.SearchQuery{Text: "election results", Page: 2, PageSize: 1},
+			want:    []string{inBody.ID.Hex()},
+			wantTot: 2,
+		},
+		{
+			name:    "page past the end",
+			query:   domain.SearchQuery{Text: "election", Page: 5, PageSize: 10},
+			want:    []string{},
+			wantTot: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := idx.Query(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hitIDs(result))
+			assert.Equal(t, tt.wantTot, result.Total)
+		})
+	}
+}
+
+func TestIndex_QueryFilters(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	budget := newTestPost("Budget vote", "Parliament passed the budget", now)
+	budget.Category, budget.Author, budget.Tags = "politics", "anna", []string{"budget", "parliament"}
+	draft := newTestPost("Budget leak", "Draft budget figures leaked", now)
+	draft.Category, draft.Author, draft.Status = "politics", "ivan", domain.PostStatusDraft
+	draft.Contributors = []domain.Contributor{{Name: "ivan", Role: domain.RoleAuthor}, {Name: "boris", Role: domain.RolePhotographer}}
+	sport := newTestPost("Budget cuts hit clubs", "Football clubs face budget cuts", now)
+	sport.Category, sport.Author, sport.Tags = "sport", "anna", []string{"budget"}
+
+	idx := NewIndex()
+	require.NoError(t, idx.Rebuild(ctx, []*domain.Post{budget, draft, sport}))
+
+	tests := []struct {
+		name    string
+		filters domain.SearchFilters
+		want    int
+	}{
+		{name: "no filters", want: 3},
+		{name: "category", filters: domain.SearchFilters{Category: "politics"}, want: 2},
+		{name: "author", filters: domain.SearchFilters{Author: "anna"}, want: 2},
+		{name: "any contributor", filters: domain.SearchFilters{Author: "boris"}, want: 1},
+		{name: "all tags", filters: domain.SearchFilters{Tags: []string{"budget", "parliament"}}, want: 1},
+		{name: "published includes legacy posts", filters: domain.SearchFilters{Status: domain.PostStatusPublished}, want: 2},
+		{name: "draft", filters: domain.SearchFilters{Status: domain.PostStatusDraft}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := idx.Query(ctx, domain.SearchQuery{Text: "budget", Filters: tt.filters})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Total)
+		})
+	}
+}
+
+func TestIndex_QuerySort(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	old := newTestPost("Budget talks stall", "Budget budget budget negotiations stalled", now.Add(-2*time.Hour))
+	mid := newTestPost("Annual budget", "The budget passed", now.Add(-time.Hour))
+	mid.UpdatedAt = now.Add(time.Hour)
+	recent := newTestPost("City plans", "The city budget grows", now)
+
+	idx := NewIndex()
+	require.NoError(t, idx.Rebuild(ctx, []*domain.Post{old, mid, recent}))
+
+	tests := []struct {
+		sort domain.PostSort
+		want []string
+	}{
+		{sort: domain.SortNewest, want: []string{recent.ID.Hex(), mid.ID.Hex(), old.ID.Hex()}},
+		{sort: domain.SortOldest, want: []string{old.ID.Hex(), mid.ID.Hex(), recent.ID.Hex()}},
+		{sort: domain.SortUpdated, want: []string{mid.ID.Hex(), recent.ID.Hex(), old.ID.Hex()}},
+		{sort: domain.SortTitle, want: []string{mid.ID.Hex(), old.ID.Hex(), recent.ID.Hex()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			result, err := idx.Query(ctx, domain.SearchQuery{Text: "budget", Sort: tt.sort})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hitIDs(result))
+		})
+	}
+
+	relevance, err := idx.Query(ctx, domain.SearchQuery{Text: "budget"})
+	require.NoError(t, err)
+	byViews, err := idx.Query(ctx, domain.SearchQuery{Text: "budget", Sort: domain.SortViews})
+	require.NoError(t, err)
+	assert.Equal(t, hitIDs(relevance), hitIDs(byViews), "counter orders fall back to relevance")
+}
+
+func TestIndex_HandlePostEvent(t *testing.T) {
+	ctx := context.Background()
+	idx := NewIndex()
+	post := newTestPost("Markets rally", "Stocks climbed for the third day in a row.", time.Now())
+
+	require.NoError(t, idx.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostCreated, PostID: post.ID.Hex(), Post: post}))
+	result, err := idx.Query(ctx, domain.SearchQuery{Text: "stocks"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+
+	post.Content = "Bonds slipped while investors waited for the central bank."
+	require.NoError(t, idx.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: post.ID.Hex(), Post: post}))
+	result, err = idx.Query(ctx, domain.SearchQuery{Text: "stocks"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	result, err = idx.Query(ctx, domain.SearchQuery{Text: "bonds"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+
+	require.NoError(t, idx.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostDeleted, PostID: post.ID.Hex()}))
+	result, err = idx.Query(ctx, domain.SearchQuery{Text: "bonds"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	assert.Equal(t, 0, idx.Len())
+}
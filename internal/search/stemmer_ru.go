@@ -0,0 +1,172 @@
+package search
+
+import "sort"
+
+// stemRussian implements the Snowball stemming algorithm for Russian.
+// The word is expected to be lowercase with ё already replaced by е.
+func stemRussian(word string) string {
+	w := []rune(word)
+	rv, r2 := russianRegions(w)
+	if rv >= len(w) {
+		return word
+	}
+
+	// Step 1
+	if n := matchRussianGrouped(w, rv, ruPerfectiveGerund1, ruPerfectiveGerund2); n > 0 {
+		w = w[:len(w)-n]
+	} else {
+		if n := matchRussian(w, rv, ruReflexive, false); n > 0 {
+			w = w[:len(w)-n]
+		}
+		if n := matchRussianAdjectival(w, rv); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := matchRussianVerb(w, rv); n > 0 {
+			w = w[:len(w)-n]
+		} else if n := matchRussian(w, rv, ruNoun, false); n > 0 {
+			w = w[:len(w)-n]
+		}
+	}
+
+	// Step 2
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3
+	if n := matchRussian(w, r2, ruDerivational, false); n > 0 {
+		w = w[:len(w)-n]
+	}
+
+	// Step 4
+	if n := matchRussian(w, rv, ruSuperlative, false); n > 0 {
+		w = w[:len(w)-n]
+	}
+	switch {
+	case endsWithRunes(w, rv, []rune("нн")):
+		w = w[:len(w)-1]
+	case len(w) > rv && w[len(w)-1] == 'ь':
+		w = w[:len(w)-1]
+	}
+
+	return string(w)
+}
+
+var (
+	ruPerfectiveGerund1 = russianEndings("в", "вши", "вшись")
+	ruPerfectiveGerund2 = russianEndings("ив", "ивши", "ившись", "ыв", "ывши", "ывшись")
+	ruAdjective         = russianEndings("ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею")
+	ruParticiple1       = russianEndings("ем", "нн", "вш", "ющ", "щ")
+	ruParticiple2       = russianEndings("ивш", "ывш", "ующ")
+	ruReflexive         = russianEndings("ся", "сь")
+	ruVerb1             = russianEndings("ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно")
+	ruVerb2             = russianEndings("ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю")
+	ruNoun              = russianEndings("а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я")
+	ruSuperlative       = russianEndings("ейш", "ейше")
+	ruDerivational      = russianEndings("ост", "ость")
+)
+
+// russianEndings converts endings to runes ordered longest first.
+func russianEndings(endings ...string) [][]rune {
+	out := make([][]rune, len(endings))
+	for i, e := range endings {
+		out[i] = []rune(e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return out
+}
+
+func isRussianVowel(r rune) bool {
+	switch r {
+	case 'а', 'е', 'и', 'о', 'у', 'ы', 'э', 'ю', 'я':
+		return true
+	}
+	return false
+}
+
+// russianRegions returns the start of the RV and R2 regions.
+func russianRegions(w []rune) (rv, r2 int) {
+	rv, r1 := len(w), len(w)
+	for i, r := range w {
+		if isRussianVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	for i := 1; i < len(w); i++ {
+		if !isRussianVowel(w[i]) && isRussianVowel(w[i-1]) {
+			r1 = i + 1
+			break
+		}
+	}
+	r2 = len(w)
+	for i := r1 + 1; i < len(w); i++ {
+		if !isRussianVowel(w[i]) && isRussianVowel(w[i-1]) {
+			r2 = i + 1
+			break
+		}
+	}
+	return rv, r2
+}
+
+func endsWithRunes(w []rune, region int, suffix []rune) bool {
+	if len(w)-len(suffix) < region {
+		return false
+	}
+	for i, r := range suffix {
+		if w[len(w)-len(suffix)+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRussian returns the length of the longest ending found inside the region,
+// or 0. When afterAYa is set the ending must be preceded by а or я within the region.
+func matchRussian(w []rune, region int, endings [][]rune, afterAYa bool) int {
+	for _, e := range endings {
+		if !endsWithRunes(w, region, e) {
+			continue
+		}
+		if afterAYa {
+			i := len(w) - len(e) - 1
+			if i < region || (w[i] != 'а' && w[i] != 'я') {
+				return 0
+			}
+		}
+		return len(e)
+	}
+	return 0
+}
+
+// matchRussianGrouped picks the longest match across a group-1 (preceded by а/я)
+// and a group-2 ending list.
+func matchRussianGrouped(w []rune, region int, group1, group2 [][]rune) int {
+	n1 := longestEnding(w, region, group1)
+	n2 := longestEnding(w, region, group2)
+	if n2 >= n1 {
+		return n2
+	}
+	return matchRussian(w, region, group1, true)
+}
+
+func longestEnding(w []rune, region int, endings [][]rune) int {
+	for _, e := range endings {
+		if endsWithRunes(w, region, e) {
+			return len(e)
+		}
+	}
+	return 0
+}
+
+func matchRussianAdjectival(w []rune, rv int) int {
+	n := matchRussian(w, rv, ruAdjective, false)
+	if n == 0 {
+		return 0
+	}
+	rest := w[:len(w)-n]
+	return n + matchRussianGrouped(rest, rv, ruParticiple1, ruParticiple2)
+}
+
+func matchRussianVerb(w []rune, rv int) int {
+	return matchRussianGrouped(w, rv, ruVerb1, ruVerb2)
+}
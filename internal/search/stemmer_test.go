@@ -0,0 +1,58 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "caresses", want: "caress"},
+		{word: "ponies", want: "poni"},
+		{word: "hopping", want: "hop"},
+		{word: "running", want: "run"},
+		{word: "connections", want: "connect"},
+		{word: "connected", want: "connect"},
+		{word: "relational", want: "relat"},
+		{word: "generalizations", want: "gener"},
+		{word: "happy", want: "happi"},
+		{word: "go", want: "go"},
+		{word: "covid19", want: "covid19"},
+		{word: "новости", want: "новост"},
+		{word: "новостей", want: "новост"},
+		{word: "красивая", want: "красив"},
+		{word: "красивые", want: "красив"},
+		{word: "выборы", want: "выбор"},
+		{word: "выборах", want: "выбор"},
+		{word: "прочитавшись", want: "прочита"},
+		{word: "я", want: "я"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			assert.Equal(t, tt.want, Stem(tt.word))
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	assert.Equal(t, []string{"elect", "result", "announc"}, Analyze("The Election results are announced!"))
+	assert.Equal(t, []string{"новост", "москв"}, Analyze("Новости и Москвы"))
+	assert.Equal(t, []string{"еж"}, Analyze("Ёж"))
+	assert.Empty(t, Analyze(" , . "))
+}
+
+func TestTokenize_Offsets(t *testing.T) {
+	text := "Hi, Мир!"
+	tokens := Tokenize(text)
+	if assert.Len(t, tokens, 2) {
+		assert.Equal(t, "hi", tokens[0].Term)
+		assert.Equal(t, "Hi", text[tokens[0].Start:tokens[0].End])
+		assert.Equal(t, "мир", tokens[1].Term)
+		assert.Equal(t, "Мир", text[tokens[1].Start:tokens[1].End])
+	}
+}
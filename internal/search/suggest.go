@@ -0,0 +1,243 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// trieNode is a node of the word prefix tree. ids holds the titles containing
+// the word that ends at this node.
+type trieNode struct {
+	children map[rune]*trieNode
+	ids      map[string]struct{}
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// suggestion is an indexed title.
+type suggestion struct {
+	id        string
+	title     string
+	lower     string
+	words     map[string]struct{}
+	createdAt time.Time
+}
+
+// Suggester completes partially typed queries against post titles.
+// It keeps an in-memory prefix tree of title words.
+type Suggester struct {
+	mu     sync.RWMutex
+	root   *trieNode
+	titles map[string]*suggestion
+}
+
+// NewSuggester creates an empty suggester
+func NewSuggester() *Suggester {
+	return &Suggester{
+		root:   newTrieNode(),
+		titles: make(map[string]*suggestion),
+	}
+}
+
+// Add indexes or re-indexes the title of a post.
+func (s *Suggester) Add(post *domain.Post) {
+	sg := newSuggestion(post)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(sg.id)
+	s.add(sg)
+}
+
+// Remove drops a post from the suggester.
+func (s *Suggester) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(id)
+}
+
+// Rebuild implements domain.PostRebuilder, replacing the suggester contents with the given posts.
+func (s *Suggester) Rebuild(ctx context.Context, posts []*domain.Post) error {
+	fresh := NewSuggester()
+	for _, p := range posts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fresh.add(newSuggestion(p))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.root = fresh.root
+	s.titles = fresh.titles
+	return nil
+}
+
+// Complete returns up to limit titles matching the typed text. Every complete
+// word must appear in the title and the last, possibly partial, word must
+// prefix one of its words. Titles starting with the text rank first, then
+// newer posts.
+func (s *Suggester) Complete(text string, limit int) []domain.TitleSuggestion {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 || limit < 1 {
+		return nil
+	}
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.Term
+	}
+	prefix := words[len(words)-1]
+	if strings.HasSuffix(text, " ") {
+		prefix = ""
+	} else {
+		words = words[:len(words)-1]
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var candidates map[string]struct{}
+	if prefix != "" {
+		candidates = s.withPrefix(prefix)
+	} else {
+		candidates = s.withPrefix(words[0])
+	}
+
+	lowerText := strings.ToLower(strings.TrimSpace(text))
+	matches := make([]*suggestion, 0, len(candidates))
+	for id := range candidates {
+		sg := s.titles[id]
+		if hasWords(sg, words) {
+			matches = append(matches, sg)
+		}
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		pa := strings.HasPrefix(matches[a].lower, lowerText)
+		pb := strings.HasPrefix(matches[b].lower, lowerText)
+		if pa != pb {
+			return pa
+		}
+		return matches[a].createdAt.After(matches[b].createdAt)
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]domain.TitleSuggestion, len(matches))
+	for i, sg := range matches {
+		out[i] = domain.TitleSuggestion{ID: sg.id, Title: sg.title}
+	}
+	return out
+}
+
+// HandlePostEvent implements domain.PostEventHandler, refreshing titles on post changes.
+func (s *Suggester) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	switch event.Type {
+	case domain.PostCreated, domain.PostUpdated:
+		if event.Post != nil {
+			s.Add(event.Post)
+		}
+	case domain.PostDeleted:
+		s.Remove(event.PostID)
+	}
+	return nil
+}
+
+func newSuggestion(p *domain.Post) *suggestion {
+	sg := &suggestion{
+		id:        p.ID.Hex(),
+		title:     p.Title,
+		lower:     strings.ToLower(p.Title),
+		words:     make(map[string]struct{}),
+		createdAt: p.CreatedAt,
+	}
+	for _, t := range Tokenize(p.Title) {
+		sg.words[t.Term] = struct{}{}
+	}
+	return sg
+}
+
+// add inserts sg; the caller must hold the write lock.
+func (s *Suggester) add(sg *suggestion) {
+	s.titles[sg.id] = sg
+	for word := range sg.words {
+		node := s.root
+		for _, r := range word {
+			child, ok := node.children[r]
+			if !ok {
+				child = newTrieNode()
+				node.children[r] = child
+			}
+			node = child
+		}
+		if node.ids == nil {
+			node.ids = make(map[string]struct{})
+		}
+		node.ids[sg.id] = struct{}{}
+	}
+}
+
+// remove deletes the title with the given id; the caller must hold the write lock.
+// Emptied trie branches are left in place and disappear on the next rebuild.
+func (s *Suggester) remove(id string) {
+	sg, ok := s.titles[id]
+	if !ok {
+		return
+	}
+	for word := range sg.words {
+		if node := s.find(word); node != nil {
+			delete(node.ids, id)
+		}
+	}
+	delete(s.titles, id)
+}
+
+func (s *Suggester) find(word string) *trieNode {
+	node := s.root
+	for _, r := range word {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// withPrefix collects the ids of titles having a word that starts with prefix.
+func (s *Suggester) withPrefix(prefix string) map[string]struct{} {
+	ids := make(map[string]struct{})
+	node := s.find(prefix)
+	if node == nil {
+		return ids
+	}
+
+	stack := []*trieNode{node}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for id := range n.ids {
+			ids[id] = struct{}{}
+		}
+		for _, child := range n.children {
+			stack = append(stack, child)
+		}
+	}
+	return ids
+}
+
+func hasWords(sg *suggestion, words []string) bool {
+	for _, w := range words {
+		if _, ok := sg.words[w]; !ok {
+			return false
+		}
+	}
+	return true
+}
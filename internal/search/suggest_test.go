@@ -0,0 +1,101 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func suggestionTitles(suggestions []domain.TitleSuggestion) []string {
+	titles := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		titles[i] = s.Title
+	}
+	return titles
+}
+
+func TestSuggester_Complete(t *testing.T) {
+	now := time.Now()
+	posts := []*domain.Post{
+		newTestPost("Election results announced", "", now.Add(-3*time.Hour)),
+		newTestPost("Local elections postponed", "", now.Add(-2*time.Hour)),
+		newTestPost("Electric cars sales grow", "", now.Add(-1*time.Hour)),
+		newTestPost("Итоги выборов в Москве", "", now),
+	}
+
+	s := NewSuggester()
+	require.NoError(t, s.Rebuild(context.Background(), posts))
+
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "prefix ranks titles starting with the text first",
+			text:  "elect",
+			limit: 5,
+			want:  []string{"Electric cars sales grow", "Election results announced", "Local elections postponed"},
+		},
+		{
+			name:  "complete words must match",
+			text:  "local elec",
+			limit: 5,
+			want:  []string{"Local elections postponed"},
+		},
+		{
+			name:  "trailing space completes the last word",
+			text:  "election ",
+			limit: 5,
+			want:  []string{"Election results announced"},
+		},
+		{
+			name:  "limit",
+			text:  "elect",
+			limit: 1,
+			want:  []string{"Electric cars sales grow"},
+		},
+		{
+			name:  "cyrillic",
+			text:  "Выбо",
+			limit: 5,
+			want:  []string{"Итоги выборов в Москве"},
+		},
+		{
+			name:  "no match",
+			text:  "weather",
+			limit: 5,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, suggestionTitles(s.Complete(tt.text, tt.limit)))
+		})
+	}
+}
+
+func TestSuggester_HandlePostEvent(t *testing.T) {
+	ctx := context.Background()
+	post := newTestPost("Budget vote delayed", "", time.Now())
+
+	s := NewSuggester()
+	require.NoError(t, s.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostCreated, PostID: post.ID.Hex(), Post: post}))
+	assert.Equal(t, []string{"Budget vote delayed"}, suggestionTitles(s.Complete("budg", 5)))
+
+	updated := *post
+	updated.Title = "Budget approved"
+	require.NoError(t, s.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostUpdated, PostID: post.ID.Hex(), Post: &updated}))
+	assert.Equal(t, []string{"Budget approved"}, suggestionTitles(s.Complete("budg", 5)))
+	assert.Empty(t, s.Complete("vote", 5))
+
+	require.NoError(t, s.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostDeleted, PostID: post.ID.Hex()}))
+	assert.Empty(t, s.Complete("budg", 5))
+}
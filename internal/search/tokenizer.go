@@ -0,0 +1,93 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a normalized word together with its byte offsets in the source text.
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text into lowercase word tokens. Letters and digits form words,
+// everything else is treated as a separator.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+	return tokens
+}
+
+// Analyze tokenizes text, drops stop words and reduces every word to its stem.
+func Analyze(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if term, ok := normalize(t.Term); ok {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// normalize stems a single lowercase word. It reports false for stop words.
+func normalize(word string) (string, bool) {
+	if isStopWord(word) {
+		return "", false
+	}
+	return Stem(word), true
+}
+
+// Stem reduces a lowercase word to its stem, choosing the stemmer by script.
+func Stem(word string) string {
+	if isCyrillic(word) {
+		return stemRussian(word)
+	}
+	return stemEnglish(word)
+}
+
+func newToken(text string, start, end int) Token {
+	term := strings.ToLower(text[start:end])
+	term = strings.ReplaceAll(term, "ё", "е")
+	return Token{Term: term, Start: start, End: end}
+}
+
+func isCyrillic(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.Is(unicode.Cyrillic, r)
+}
+
+var stopWords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a an and are as at be but by for from has have in is it its of on or
+		that the this to was were will with
+		а без в во да для до же за и из или к как ко на не ни но о об от по
+		при с со то у что это`) {
+		stopWords[w] = struct{}{}
+	}
+}
+
+func isStopWord(word string) bool {
+	_, ok := stopWords[word]
+	return ok
+}
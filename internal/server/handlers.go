@@ -1,54 +1,252 @@
 package server
 
 import (
+	"context"
 	"html/template"
+	"net"
+	"net/smtp"
+	texttemplate "text/template"
+	"time"
 
+	"github.com/kir/news-app/internal/domain"
+	apikeyhandler "github.com/kir/news-app/internal/handlers/apikey"
+	authorhandler "github.com/kir/news-app/internal/handlers/author"
+	bookmarkhandler "github.com/kir/news-app/internal/handlers/bookmark"
+	eventshandler "github.com/kir/news-app/internal/handlers/events"
+	feedhandler "github.com/kir/news-app/internal/handlers/feed"
+	newsletterhandler "github.com/kir/news-app/internal/handlers/newsletter"
+	notificationhandler "github.com/kir/news-app/internal/handlers/notification"
 	posthandler "github.com/kir/news-app/internal/handlers/post"
+	presencehandler "github.com/kir/news-app/internal/handlers/presence"
+	searchhandler "github.com/kir/news-app/internal/handlers/search"
+	sitemaphandler "github.com/kir/news-app/internal/handlers/sitemap"
+	sourcehandler "github.com/kir/news-app/internal/handlers/source"
+	webhookhandler "github.com/kir/news-app/internal/handlers/webhook"
+	"github.com/kir/news-app/internal/ingest"
+	"github.com/kir/news-app/internal/live"
+	"github.com/kir/news-app/internal/newsletter"
+	"github.com/kir/news-app/internal/notify"
+	"github.com/kir/news-app/internal/outbox"
+	"github.com/kir/news-app/internal/presence"
+	"github.com/kir/news-app/internal/reader"
+	apikeyrepo "github.com/kir/news-app/internal/repository/apikey"
+	authorrepo "github.com/kir/news-app/internal/repository/author"
+	bookmarkrepo "github.com/kir/news-app/internal/repository/bookmark"
+	historyrepo "github.com/kir/news-app/internal/repository/history"
+	newsletterrepo "github.com/kir/news-app/internal/repository/newsletter"
+	notificationrepo "github.com/kir/news-app/internal/repository/notification"
+	outboxrepo "github.com/kir/news-app/internal/repository/outbox"
 	postrepo "github.com/kir/news-app/internal/repository/post"
+	searchlogrepo "github.com/kir/news-app/internal/repository/searchlog"
+	sourcerepo "github.com/kir/news-app/internal/repository/source"
+	webhookrepo "github.com/kir/news-app/internal/repository/webhook"
+	"github.com/kir/news-app/internal/search"
+	apikeyservice "github.com/kir/news-app/internal/services/apikey"
+	authorservice "github.com/kir/news-app/internal/services/author"
+	bookmarkservice "github.com/kir/news-app/internal/services/bookmark"
+	foryouservice "github.com/kir/news-app/internal/services/foryou"
+	newsletterservice "github.com/kir/news-app/internal/services/newsletter"
+	notificationservice "github.com/kir/news-app/internal/services/notification"
 	postservice "github.com/kir/news-app/internal/services/post"
+	searchservice "github.com/kir/news-app/internal/services/search"
+	sourceservice "github.com/kir/news-app/internal/services/source"
+	webhookservice "github.com/kir/news-app/internal/services/webhook"
+	"github.com/kir/news-app/internal/templates"
+	"github.com/kir/news-app/internal/webhook"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
 func (s *Server) Handlers() {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
+	r.Use(reader.Middleware)
 
-	tmpl := template.New("")
-
-	tmpl.Funcs(template.FuncMap{
-		"add": func(a, b int) int {
-			return a + b
-		},
-		"subtract": func(a, b int) int {
-			return a - b
-		},
-		"multiply": func(a, b int) int {
-			return a * b
-		},
-		"sequence": func(start, end int) []int {
-			var result []int
-			for i := start; i <= end; i++ {
-				result = append(result, i)
-			}
-			return result
-		},
-		"objectIDToString": func(id primitive.ObjectID) string {
-			return id.Hex()
-		},
-	})
-
-	tmpl = template.Must(tmpl.ParseGlob("templates/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("templates/post/*.html"))
-	tmpl = template.Must(tmpl.ParseGlob("templates/modals/*.html"))
-
-	repo := postrepo.NewMongoRepository(s.mongo.Client.Database("newsdb"))
-	service := postservice.NewService(repo)
-	handler := posthandler.New(service, tmpl, s.logger)
+	tmpl := template.Must(templates.Parse("templates"))
+	text := texttemplate.Must(templates.ParseText("templates"))
+
+	db := s.mongo.Client.Database("newsdb")
+	repo := postrepo.NewMongoRepository(db)
+	searchLog := searchlogrepo.NewMongoRepository(db)
+	sources := sourcerepo.NewMongoRepository(db)
+	webhooks := webhookrepo.NewMongoRepository(db)
+	deliveries := webhookrepo.NewDeliveryRepository(db)
+	outboxEntries := outboxrepo.NewMongoRepository(db)
+	subscribers := newsletterrepo.NewSubscriberRepository(db)
+	sends := newsletterrepo.NewSendRepository(db)
+	follows := notificationrepo.NewFollowRepository(db)
+	notifications := notificationrepo.NewNotificationRepository(db)
+	notificationSettings := notificationrepo.NewSettingsRepository(db)
+	bookmarks := bookmarkrepo.NewMongoRepository(db)
+	reads := historyrepo.NewMongoRepository(db)
+	authors := authorrepo.NewMongoRepository(db)
+	apiKeys := apikeyrepo.NewMongoRepository(db)
+	index := search.NewIndex()
+	suggester := search.NewSuggester()
+	s.hub = live.NewHub()
+	s.presence = presence.NewTracker(presence.WithListeners(live.NewPresenceBroadcaster(s.hub, tmpl, s.logger)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	transactions, err := outboxrepo.SupportsTransactions(ctx, s.mongo.Client)
+	if err != nil {
+		s.logger.Error("failed to detect transaction support", zap.Error(err))
+	} else if !transactions {
+		s.logger.Warn("MongoDB is not a replica set; posts and outbox entries are written without a transaction")
+	}
+
+	s.dispatcher = webhook.NewDispatcher(webhooks, deliveries,
+		webhook.WithWorkers(s.cfg.Webhook.Workers),
+		webhook.WithLogger(s.logger),
+	)
+	mailer := s.newMailer()
+	fanOutOpts := []notify.Option{notify.WithWorkers(s.cfg.Notification.Workers), notify.WithLogger(s.logger)}
+	notificationOpts := []notificationservice.Option{}
+	if mailer != nil {
+		composer := notify.NewComposer(tmpl, text, s.cfg.PublicBaseURL)
+		fanOutOpts = append(fanOutOpts, notify.WithNotifiers(notify.NewEmailNotifier(notificationSettings, composer, mailer)))
+		notificationOpts = append(notificationOpts, notificationservice.WithEmail(mailer, composer))
+	}
+	s.fanOut = notify.NewFanOut(follows, notifications, fanOutOpts...)
+	bookmarkService := bookmarkservice.NewService(bookmarks, repo)
+	s.relay = outbox.NewRelay(outboxEntries,
+		// Bookmarks of deleted posts are removed from relayed events, so a
+		// crash right after a deletion does not leave them behind.
+		outbox.WithPublishers(s.dispatcher, s.fanOut, outbox.NewHandlerPublisher(bookmarkService)),
+		outbox.WithLogger(s.logger),
+	)
+	service := postservice.NewService(repo,
+		postservice.WithSearchIndex(index),
+		postservice.WithSearchLog(searchLog),
+		// The tracker re-announces editors after the broadcaster re-rendered a card.
+		postservice.WithEventHandlers(index, suggester, live.NewPostBroadcaster(s.hub, tmpl), s.presence),
+		postservice.WithOutbox(outboxEntries, outboxrepo.NewTransactor(s.mongo.Client, transactions), s.relay),
+		postservice.WithLogger(s.logger),
+	)
+
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create post indexes", zap.Error(err))
+	}
+	if err := searchLog.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create search log indexes", zap.Error(err))
+	}
+	if err := sources.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create source indexes", zap.Error(err))
+	}
+	if err := deliveries.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create webhook delivery indexes", zap.Error(err))
+	}
+	if err := outboxEntries.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create outbox indexes", zap.Error(err))
+	}
+	if err := subscribers.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create subscriber indexes", zap.Error(err))
+	}
+	if err := sends.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create newsletter send indexes", zap.Error(err))
+	}
+	if err := follows.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create follow indexes", zap.Error(err))
+	}
+	if err := notifications.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create notification indexes", zap.Error(err))
+	}
+	if err := notificationSettings.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create notification settings indexes", zap.Error(err))
+	}
+	if err := bookmarks.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create bookmark indexes", zap.Error(err))
+	}
+	if err := reads.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create reading history indexes", zap.Error(err))
+	}
+	if err := authors.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create author indexes", zap.Error(err))
+	}
+	if err := apiKeys.EnsureIndexes(ctx); err != nil {
+		s.logger.Error("failed to create API key indexes", zap.Error(err))
+	}
+	if err := service.RebuildIndexes(ctx); err != nil {
+		s.logger.Error("failed to rebuild search index", zap.Error(err))
+	} else {
+		s.logger.Info("search index rebuilt", zap.Int("documents", index.Len()))
+	}
+	if n, err := service.BackfillFingerprints(ctx); err != nil {
+		s.logger.Error("failed to backfill post fingerprints", zap.Error(err))
+	} else if n > 0 {
+		s.logger.Info("post fingerprints backfilled", zap.Int("posts", n))
+	}
+
+	suggestService := searchservice.NewService(suggester, searchLog)
+	forYou := foryouservice.NewService(follows, reads, repo)
+	apiKeyService := apikeyservice.NewService(apiKeys, apikeyservice.WithLogger(s.logger))
+	handler := posthandler.New(service, tmpl, s.cfg.PublicBaseURL, s.logger,
+		posthandler.WithPersonalFeed(forYou),
+		posthandler.WithAPIKeys(apiKeyService),
+	)
 
 	posthandler.RegisterRoutes(r, handler, s.logger)
+	searchhandler.RegisterRoutes(r, searchhandler.New(suggestService, tmpl, s.logger))
+	feedhandler.RegisterRoutes(r, feedhandler.New(service, s.cfg.PublicBaseURL, s.logger))
+	sitemaphandler.RegisterRoutes(r, sitemaphandler.New(service, s.cfg.PublicBaseURL, s.logger))
+	sourcehandler.RegisterRoutes(r, sourcehandler.New(sourceservice.NewService(sources), s.logger), apiKeyService)
+	webhookService := webhookservice.NewService(webhooks, deliveries, webhookservice.WithNotifier(s.dispatcher))
+	if s.cfg.Admin.Token == "" {
+		s.logger.Info("ADMIN_TOKEN not set; webhook admin pages disabled")
+	}
+	webhookhandler.RegisterRoutes(r, webhookhandler.New(webhookService, tmpl, s.logger), s.cfg.Admin.Token)
+	presencehandler.RegisterRoutes(r, presencehandler.New(s.presence, service, tmpl, s.logger))
+	notificationService := notificationservice.NewService(follows, notifications, notificationSettings, notificationOpts...)
+	notificationhandler.RegisterRoutes(r, notificationhandler.New(notificationService, tmpl, s.logger))
+	bookmarkhandler.RegisterRoutes(r, bookmarkhandler.New(bookmarkService, tmpl, s.logger))
+	authorhandler.RegisterRoutes(r, authorhandler.New(authorservice.NewService(authors, repo), tmpl, s.logger))
+	apikeyhandler.RegisterRoutes(r, apikeyhandler.New(apiKeyService, tmpl, s.cfg.Admin.Token, s.logger))
+
+	if mailer != nil {
+		composer := newsletter.NewComposer(tmpl, text, s.cfg.PublicBaseURL)
+		newsletterService := newsletterservice.NewService(subscribers, sends, mailer, composer)
+		newsletterHandler := newsletterhandler.New(newsletterService, tmpl, s.logger)
+		newsletterhandler.RegisterRoutes(r, newsletterHandler)
+		if s.cfg.Admin.Token == "" {
+			s.logger.Info("ADMIN_TOKEN not set; newsletter admin pages disabled")
+		}
+		newsletterhandler.RegisterAdminRoutes(r, newsletterHandler, s.cfg.Admin.Token)
+		s.newsletter = newsletter.NewScheduler(subscribers, sends, service, composer, mailer,
+			newsletter.WithTick(s.cfg.Newsletter.Tick),
+			newsletter.WithLogger(s.logger),
+		)
+	}
+
+	if s.cfg.Ingest.Enabled {
+		s.fetcher = ingest.NewFetcher(sources, service,
+			ingest.WithTick(s.cfg.Ingest.Tick),
+			ingest.WithLogger(s.logger),
+		)
+	}
+
+	// The event stream is kept out of r, whose middleware times requests out.
+	root := chi.NewRouter()
+	eventshandler.RegisterRoutes(root, eventshandler.New(s.hub, s.logger))
+	root.Mount("/", r)
+
+	s.http.Handler = root
+}
 
-	s.http.Handler = r
+// newMailer creates the mailer of newsletter and notification emails, or
+// returns nil when the SMTP settings are unusable.
+func (s *Server) newMailer() domain.Mailer {
+	var opts []newsletter.MailerOption
+	if s.cfg.SMTP.Username != "" {
+		host, _, _ := net.SplitHostPort(s.cfg.SMTP.Addr)
+		opts = append(opts, newsletter.WithAuth(smtp.PlainAuth("", s.cfg.SMTP.Username, s.cfg.SMTP.Password, host)))
+	}
+	mailer, err := newsletter.NewSMTPMailer(s.cfg.SMTP.Addr, s.cfg.SMTP.From, opts...)
+	if err != nil {
+		s.logger.Error("failed to set up mailer; newsletter and notification emails disabled", zap.Error(err))
+		return nil
+	}
+	return mailer
 }
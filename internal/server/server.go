@@ -5,6 +5,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/kir/news-app/internal/ingest"
+	"github.com/kir/news-app/internal/live"
+	"github.com/kir/news-app/internal/newsletter"
+	"github.com/kir/news-app/internal/notify"
+	"github.com/kir/news-app/internal/outbox"
+	"github.com/kir/news-app/internal/presence"
+	"github.com/kir/news-app/internal/webhook"
 	"github.com/kir/news-app/pkg/config"
 	"github.com/kir/news-app/pkg/mongo"
 
@@ -19,6 +26,23 @@ type Server struct {
 	mongo  *mongo.Client
 	http   *http.Server
 	router chi.Router
+
+	// fetcher ingests external feeds while the server runs; nil when disabled.
+	fetcher *ingest.Fetcher
+	// dispatcher sends webhook deliveries while the server runs.
+	dispatcher *webhook.Dispatcher
+	// relay publishes post events recorded in the outbox while the server runs.
+	relay *outbox.Relay
+	// hub feeds the /events streams; closing it ends them on shutdown.
+	hub *live.Hub
+	// presence tracks who is editing which post and expires stale editors.
+	presence *presence.Tracker
+	// newsletter sends digests to subscribers while the server runs; nil
+	// when no mailer could be set up.
+	newsletter *newsletter.Scheduler
+	// fanOut delivers notifications of published posts to notifiers while
+	// the server runs.
+	fanOut *notify.FanOut
 }
 
 func New(cfg *config.Config, logger *zap.Logger, mongo *mongo.Client) *Server {
@@ -88,6 +112,25 @@ func (s *Server) Run(ctx context.Context) error {
 
 	errChan := make(chan error, 1)
 
+	if s.fetcher != nil {
+		go s.fetcher.Run(ctx)
+	}
+	if s.dispatcher != nil {
+		go s.dispatcher.Run(ctx)
+	}
+	if s.relay != nil {
+		go s.relay.Run(ctx)
+	}
+	if s.presence != nil {
+		go s.presence.Run(ctx)
+	}
+	if s.newsletter != nil {
+		go s.newsletter.Run(ctx)
+	}
+	if s.fanOut != nil {
+		go s.fanOut.Run(ctx)
+	}
+
 	go func() {
 		if err := s.http.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			s.logger.Error("Server failed to start", zap.Error(err))
@@ -98,6 +141,11 @@ func (s *Server) Run(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		s.logger.Info("Shutting down server...")
+		// Open event streams never become idle; end them so that Shutdown
+		// does not wait for its timeout.
+		if s.hub != nil {
+			s.hub.Close()
+		}
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
@@ -0,0 +1,54 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockAPIKeyRepository is a mock implementation of domain.APIKeyRepository
+type MockAPIKeyRepository struct {
+	CreateFunc      func(ctx context.Context, k *domain.APIKey) error
+	GetByPrefixFunc func(ctx context.Context, prefix string) (*domain.APIKey, error)
+	GetByOwnerFunc  func(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	RevokeFunc      func(ctx context.Context, ownerID, id string, at time.Time) error
+	RecordUsageFunc func(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, k *domain.APIKey) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, k)
+	}
+	return nil
+}
+
+func (m *MockAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	if m.GetByPrefixFunc != nil {
+		return m.GetByPrefixFunc(ctx, prefix)
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeyRepository) GetByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	if m.GetByOwnerFunc != nil {
+		return m.GetByOwnerFunc(ctx, ownerID)
+	}
+	return nil, nil
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, ownerID, id string, at time.Time) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(ctx, ownerID, id, at)
+	}
+	return nil
+}
+
+func (m *MockAPIKeyRepository) RecordUsage(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	if m.RecordUsageFunc != nil {
+		return m.RecordUsageFunc(ctx, id, at)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	keys   domain.APIKeyRepository
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithLogger sets the logger used to report failures to record key usage.
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+func NewService(keys domain.APIKeyRepository, opts ...Option) *Service {
+	s := &Service{
+		keys:   keys,
+		now:    time.Now,
+		logger: zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create creates an API key of the reader and returns it with its token,
+// which is not stored and cannot be shown again.
+func (s *Service) Create(ctx context.Context, ownerID string, in domain.APIKeyInput) (*domain.APIKey, string, error) {
+	key, token, err := domain.NewAPIKey(ownerID, in, s.now())
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.keys.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to save API key: %w", err)
+	}
+	return key, token, nil
+}
+
+// List returns the keys of the reader, newest first, revoked ones included.
+func (s *Service) List(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	keys, err := s.keys.GetByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke revokes a key of the reader. Requests with its token are refused
+// from then on.
+func (s *Service) Revoke(ctx context.Context, ownerID, id string) error {
+	return s.keys.Revoke(ctx, ownerID, id, s.now())
+}
+
+// Authenticate returns the key of token. It returns domain.ErrInvalidAPIKey
+// for tokens of no key, and domain.ErrAPIKeyExpired or
+// domain.ErrAPIKeyRevoked for keys that can no longer be used. The use is
+// recorded at most once per domain.APIKeyUsageResolution.
+func (s *Service) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	prefix, err := domain.ParseAPIToken(token)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.keys.GetByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	now := s.now()
+	if err := key.Verify(token, now); err != nil {
+		return nil, err
+	}
+	if key.UsageDue(now) {
+		// A missed record only makes the last use look older; the request
+		// goes ahead.
+		if err := s.keys.RecordUsage(ctx, key.ID, now); err != nil {
+			s.logger.Error("failed to record API key usage", zap.String("prefix", key.Prefix), zap.Error(err))
+		} else {
+			key.LastUsedAt = now
+		}
+	}
+	return key, nil
+}
@@ -0,0 +1,161 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps keys by prefix, like the MongoDB repository.
+func memoryRepository() (*MockAPIKeyRepository, map[string]*domain.APIKey) {
+	keys := map[string]*domain.APIKey{}
+	return &MockAPIKeyRepository{
+		CreateFunc: func(ctx context.Context, k *domain.APIKey) error {
+			stored := *k
+			keys[k.Prefix] = &stored
+			return nil
+		},
+		GetByPrefixFunc: func(ctx context.Context, prefix string) (*domain.APIKey, error) {
+			k, ok := keys[prefix]
+			if !ok {
+				return nil, domain.ErrAPIKeyNotFound
+			}
+			found := *k
+			return &found, nil
+		},
+		RecordUsageFunc: func(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+			for _, k := range keys {
+				if k.ID == id {
+					k.LastUsedAt = at
+				}
+			}
+			return nil
+		},
+	}, keys
+}
+
+func TestService_Create(t *testing.T) {
+	repo, keys := memoryRepository()
+	service := NewService(repo)
+
+	key, token, err := service.Create(context.Background(), "reader", domain.APIKeyInput{Name: "Script", Scopes: []domain.APIScope{domain.ScopePostsRead}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	require.Contains(t, keys, key.Prefix)
+	assert.Equal(t, "reader", keys[key.Prefix].OwnerID)
+
+	_, _, err = service.Create(context.Background(), "reader", domain.APIKeyInput{Name: "Script"})
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIScope)
+	assert.Len(t, keys, 1, "invalid keys are not saved")
+}
+
+func TestService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	repo, keys := memoryRepository()
+	service := NewService(repo)
+	service.now = func() time.Time { return now }
+
+	key, token, err := service.Create(context.Background(), "reader", domain.APIKeyInput{
+		Name:      "Script",
+		Scopes:    []domain.APIScope{domain.ScopePostsRead},
+		ExpiresIn: 24 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		found, err := service.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, now, keys[key.Prefix].LastUsedAt)
+	})
+
+	t.Run("usage is recorded once a minute", func(t *testing.T) {
+		recorded := 0
+		repo.RecordUsageFunc = func(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+			recorded++
+			keys[key.Prefix].LastUsedAt = at
+			return nil
+		}
+		now = now.Add(30 * time.Second)
+		_, err := service.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, 0, recorded)
+
+		now = now.Add(30 * time.Second)
+		_, err = service.Authenticate(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recorded)
+	})
+
+	t.Run("usage failure", func(t *testing.T) {
+		repo.RecordUsageFunc = func(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+			return errors.New("connection reset")
+		}
+		now = now.Add(time.Hour)
+		_, err := service.Authenticate(context.Background(), token)
+		assert.NoError(t, err, "the request goes ahead")
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		last := byte('0')
+		if token[len(token)-1] == last {
+			last = '1'
+		}
+		forged := token[:len(token)-1] + string(last)
+		_, err := service.Authenticate(context.Background(), forged)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		_, other, err := domain.NewAPIKey("reader", domain.APIKeyInput{Name: "Other", Scopes: []domain.APIScope{domain.ScopePostsRead}}, now)
+		require.NoError(t, err)
+		_, err = service.Authenticate(context.Background(), other)
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := service.Authenticate(context.Background(), "s3cret")
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = key.ExpiresAt
+		_, err := service.Authenticate(context.Background(), token)
+		assert.ErrorIs(t, err, domain.ErrAPIKeyExpired)
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		repo.GetByPrefixFunc = func(ctx context.Context, prefix string) (*domain.APIKey, error) {
+			return nil, errors.New("connection reset")
+		}
+		_, err := service.Authenticate(context.Background(), token)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+}
+
+func TestService_Revoke(t *testing.T) {
+	now := time.Now()
+	var owner, id string
+	var at time.Time
+	repo := &MockAPIKeyRepository{
+		RevokeFunc: func(ctx context.Context, ownerID, keyID string, revokedAt time.Time) error {
+			owner, id, at = ownerID, keyID, revokedAt
+			return nil
+		},
+	}
+	service := NewService(repo)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.Revoke(context.Background(), "reader", "key"))
+	assert.Equal(t, "reader", owner)
+	assert.Equal(t, "key", id)
+	assert.Equal(t, now, at)
+}
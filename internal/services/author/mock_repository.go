@@ -0,0 +1,47 @@
+package author
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockAuthorRepository is a mock implementation of domain.AuthorRepository
+type MockAuthorRepository struct {
+	GetByHandleFunc func(ctx context.Context, handle string) (*domain.Author, error)
+	SaveFunc        func(ctx context.Context, a *domain.Author) error
+}
+
+func (m *MockAuthorRepository) GetByHandle(ctx context.Context, handle string) (*domain.Author, error) {
+	if m.GetByHandleFunc != nil {
+		return m.GetByHandleFunc(ctx, handle)
+	}
+	return nil, domain.ErrAuthorNotFound
+}
+
+func (m *MockAuthorRepository) Save(ctx context.Context, a *domain.Author) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, a)
+	}
+	return nil
+}
+
+// MockPostRepository is a mock implementation of PostRepository
+type MockPostRepository struct {
+	GetPaginatedFunc func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetAuthorsFunc   func(ctx context.Context) ([]string, error)
+}
+
+func (m *MockPostRepository) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	if m.GetPaginatedFunc != nil {
+		return m.GetPaginatedFunc(ctx, query)
+	}
+	return &domain.PostList{Page: query.Page, PageSize: query.PageSize}, nil
+}
+
+func (m *MockPostRepository) GetAuthors(ctx context.Context) ([]string, error) {
+	if m.GetAuthorsFunc != nil {
+		return m.GetAuthorsFunc(ctx)
+	}
+	return nil, nil
+}
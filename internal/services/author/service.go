@@ -0,0 +1,103 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// PostRepository is the part of the post storage author pages need.
+type PostRepository interface {
+	GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetAuthors(ctx context.Context) ([]string, error)
+}
+
+type Service struct {
+	authors domain.AuthorRepository
+	posts   PostRepository
+	now     func() time.Time
+}
+
+func NewService(authors domain.AuthorRepository, posts PostRepository) *Service {
+	return &Service{
+		authors: authors,
+		posts:   posts,
+		now:     time.Now,
+	}
+}
+
+// Get returns the profile of the author with handle. Authors of posts who
+// have no profile yet get an empty one.
+func (s *Service) Get(ctx context.Context, handle string) (*domain.Author, error) {
+	author, err := s.authors.GetByHandle(ctx, handle)
+	if err == nil {
+		return author, nil
+	}
+	if !errors.Is(err, domain.ErrAuthorNotFound) {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	names, err := s.posts.GetAuthors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+	for _, name := range names {
+		if domain.AuthorHandle(name) == handle {
+			return domain.NewAuthor(name), nil
+		}
+	}
+	return nil, domain.ErrAuthorNotFound
+}
+
+// Names returns the names of everyone credited on a post, sorted.
+func (s *Service) Names(ctx context.Context) ([]string, error) {
+	names, err := s.posts.GetAuthors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Page returns the profile of the author with handle and a page of their
+// published posts, newest first.
+func (s *Service) Page(ctx context.Context, handle string, page int) (*domain.Author, *domain.PostList, error) {
+	author, err := s.Get(ctx, handle)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	posts, err := s.posts.GetPaginated(ctx, domain.PostQuery{
+		Page:     page,
+		PageSize: domain.AuthorPageSize,
+		Sort:     domain.SortNewest,
+		Status:   domain.PostStatusPublished,
+		Author:   author.Name,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get author posts: %w", err)
+	}
+	return author, posts, nil
+}
+
+// Update changes the profile of the author with handle, creating it on the
+// first edit.
+func (s *Service) Update(ctx context.Context, handle string, in domain.AuthorInput) (*domain.Author, error) {
+	author, err := s.Get(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	if err := author.Update(in, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.authors.Save(ctx, author); err != nil {
+		return nil, fmt.Errorf("failed to save author: %w", err)
+	}
+	return author, nil
+}
@@ -0,0 +1,128 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Get(t *testing.T) {
+	stored := &domain.Author{Handle: "anna-smith", Name: "Anna Smith", Bio: "Covers politics."}
+	authors := &MockAuthorRepository{
+		GetByHandleFunc: func(ctx context.Context, handle string) (*domain.Author, error) {
+			if handle == stored.Handle {
+				return stored, nil
+			}
+			return nil, domain.ErrAuthorNotFound
+		},
+	}
+	posts := &MockPostRepository{
+		GetAuthorsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"Anna Smith", "Jürgen Ölz"}, nil
+		},
+	}
+	service := NewService(authors, posts)
+
+	t.Run("profile", func(t *testing.T) {
+		author, err := service.Get(context.Background(), "anna-smith")
+		require.NoError(t, err)
+		assert.Same(t, stored, author)
+	})
+
+	t.Run("post author without a profile", func(t *testing.T) {
+		author, err := service.Get(context.Background(), "jürgen-ölz")
+		require.NoError(t, err)
+		assert.Equal(t, "Jürgen Ölz", author.Name)
+		assert.Empty(t, author.Bio)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := service.Get(context.Background(), "nobody")
+		assert.ErrorIs(t, err, domain.ErrAuthorNotFound)
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		failing := NewService(&MockAuthorRepository{
+			GetByHandleFunc: func(ctx context.Context, handle string) (*domain.Author, error) {
+				return nil, errors.New("connection reset")
+			},
+		}, posts)
+		_, err := failing.Get(context.Background(), "anna-smith")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrAuthorNotFound)
+	})
+}
+
+func TestService_Names(t *testing.T) {
+	posts := &MockPostRepository{
+		GetAuthorsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"Boris", "Anna Smith", "Clara"}, nil
+		},
+	}
+	names, err := NewService(&MockAuthorRepository{}, posts).Names(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Anna Smith", "Boris", "Clara"}, names)
+}
+
+func TestService_Page(t *testing.T) {
+	var asked domain.PostQuery
+	posts := &MockPostRepository{
+		GetAuthorsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"Anna Smith"}, nil
+		},
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			asked = query
+			return &domain.PostList{Page: query.Page, PageSize: query.PageSize}, nil
+		},
+	}
+	service := NewService(&MockAuthorRepository{}, posts)
+
+	author, list, err := service.Page(context.Background(), "anna-smith", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Anna Smith", author.Name)
+	assert.Equal(t, 1, list.Page)
+	assert.Equal(t, domain.PostQuery{
+		Page:     1,
+		PageSize: domain.AuthorPageSize,
+		Sort:     domain.SortNewest,
+		Status:   domain.PostStatusPublished,
+		Author:   "Anna Smith",
+	}, asked)
+}
+
+func TestService_Update(t *testing.T) {
+	var saved *domain.Author
+	authors := &MockAuthorRepository{
+		SaveFunc: func(ctx context.Context, a *domain.Author) error {
+			saved = a
+			return nil
+		},
+	}
+	posts := &MockPostRepository{
+		GetAuthorsFunc: func(ctx context.Context) ([]string, error) {
+			return []string{"Anna Smith"}, nil
+		},
+	}
+	service := NewService(authors, posts)
+
+	author, err := service.Update(context.Background(), "anna-smith", domain.AuthorInput{
+		Bio:   " Covers politics. ",
+		Links: []string{"https://x.com/anna", ""},
+	})
+	require.NoError(t, err)
+	assert.Same(t, author, saved)
+	assert.Equal(t, "anna-smith", saved.Handle)
+	assert.Equal(t, "Covers politics.", saved.Bio)
+	assert.Equal(t, []string{"https://x.com/anna"}, saved.Links)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	saved = nil
+	_, err = service.Update(context.Background(), "anna-smith", domain.AuthorInput{AvatarURL: "avatar.png"})
+	assert.ErrorIs(t, err, domain.ErrInvalidAvatar)
+	assert.Nil(t, saved, "invalid profiles are not saved")
+}
@@ -0,0 +1,79 @@
+package bookmark
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockBookmarkRepository is a mock implementation of domain.BookmarkRepository
+type MockBookmarkRepository struct {
+	AddFunc          func(ctx context.Context, b *domain.Bookmark) (bool, error)
+	RemoveFunc       func(ctx context.Context, readerID, postID string) (bool, error)
+	GetByReaderFunc  func(ctx context.Context, readerID string, page, pageSize int) ([]*domain.Bookmark, int64, error)
+	BookmarkedFunc   func(ctx context.Context, readerID string, postIDs []string) ([]string, error)
+	DeleteByPostFunc func(ctx context.Context, postID string) error
+}
+
+func (m *MockBookmarkRepository) Add(ctx context.Context, b *domain.Bookmark) (bool, error) {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, b)
+	}
+	return true, nil
+}
+
+func (m *MockBookmarkRepository) Remove(ctx context.Context, readerID, postID string) (bool, error) {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(ctx, readerID, postID)
+	}
+	return true, nil
+}
+
+func (m *MockBookmarkRepository) GetByReader(ctx context.Context, readerID string, page, pageSize int) ([]*domain.Bookmark, int64, error) {
+	if m.GetByReaderFunc != nil {
+		return m.GetByReaderFunc(ctx, readerID, page, pageSize)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockBookmarkRepository) Bookmarked(ctx context.Context, readerID string, postIDs []string) ([]string, error) {
+	if m.BookmarkedFunc != nil {
+		return m.BookmarkedFunc(ctx, readerID, postIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockBookmarkRepository) DeleteByPost(ctx context.Context, postID string) error {
+	if m.DeleteByPostFunc != nil {
+		return m.DeleteByPostFunc(ctx, postID)
+	}
+	return nil
+}
+
+// MockPostRepository is a mock implementation of PostRepository
+type MockPostRepository struct {
+	GetByIDFunc            func(ctx context.Context, id string) (*domain.Post, error)
+	GetByIDsFunc           func(ctx context.Context, ids []string) ([]*domain.Post, error)
+	IncrementBookmarksFunc func(ctx context.Context, id string, delta int) error
+}
+
+func (m *MockPostRepository) GetByID(ctx context.Context, id string) (*domain.Post, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockPostRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	if m.GetByIDsFunc != nil {
+		return m.GetByIDsFunc(ctx, ids)
+	}
+	return nil, nil
+}
+
+func (m *MockPostRepository) IncrementBookmarks(ctx context.Context, id string, delta int) error {
+	if m.IncrementBookmarksFunc != nil {
+		return m.IncrementBookmarksFunc(ctx, id, delta)
+	}
+	return nil
+}
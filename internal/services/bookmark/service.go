@@ -0,0 +1,175 @@
+package bookmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// maxMarked caps the number of posts one Marked call looks up, which is
+// more than a page of posts.
+const maxMarked = 100
+
+// PostRepository is the part of the post storage bookmarks need.
+type PostRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.Post, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error)
+	IncrementBookmarks(ctx context.Context, id string, delta int) error
+}
+
+type Service struct {
+	bookmarks domain.BookmarkRepository
+	posts     PostRepository
+	now       func() time.Time
+}
+
+func NewService(bookmarks domain.BookmarkRepository, posts PostRepository) *Service {
+	return &Service{
+		bookmarks: bookmarks,
+		posts:     posts,
+		now:       time.Now,
+	}
+}
+
+// Get returns a post with Bookmarked telling whether the reader bookmarked it.
+func (s *Service) Get(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	post, err := s.post(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	marked, err := s.bookmarks.Bookmarked(ctx, readerID, []string{postID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	post.Bookmarked = len(marked) > 0
+	return post, nil
+}
+
+// Add bookmarks a post for the reader and returns it with its new count.
+// Bookmarking twice is not an error. Drafts are not found.
+func (s *Service) Add(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	post, err := s.post(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !post.IsPublished() {
+		return nil, fmt.Errorf("failed to get post: %w", domain.ErrPostNotFound)
+	}
+	added, err := s.bookmarks.Add(ctx, domain.NewBookmark(readerID, post.ID, s.now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to bookmark post: %w", err)
+	}
+	if added {
+		if err := s.posts.IncrementBookmarks(ctx, postID, 1); err != nil {
+			return nil, fmt.Errorf("failed to count bookmark: %w", err)
+		}
+		post.BookmarkCount++
+	}
+	post.Bookmarked = true
+	return post, nil
+}
+
+// Remove takes a post off the reader's bookmarks and returns it with its new
+// count.
+func (s *Service) Remove(ctx context.Context, readerID, postID string) (*domain.Post, error) {
+	post, err := s.post(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.bookmarks.Remove(ctx, readerID, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	if removed {
+		if err := s.posts.IncrementBookmarks(ctx, postID, -1); err != nil {
+			return nil, fmt.Errorf("failed to count bookmark: %w", err)
+		}
+		post.BookmarkCount = max(post.BookmarkCount-1, 0)
+	}
+	post.Bookmarked = false
+	return post, nil
+}
+
+// Marked returns the published posts among postIDs the reader bookmarked,
+// with Bookmarked set.
+func (s *Service) Marked(ctx context.Context, readerID string, postIDs []string) ([]*domain.Post, error) {
+	if len(postIDs) > maxMarked {
+		postIDs = postIDs[:maxMarked]
+	}
+	marked, err := s.bookmarks.Bookmarked(ctx, readerID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	if len(marked) == 0 {
+		return nil, nil
+	}
+	posts, err := s.posts.GetByIDs(ctx, marked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarked posts: %w", err)
+	}
+	var published []*domain.Post
+	for _, p := range posts {
+		if p.IsPublished() {
+			p.Bookmarked = true
+			published = append(published, p)
+		}
+	}
+	return published, nil
+}
+
+// List returns a page of the reader's reading list, most recently bookmarked
+// first. Posts unpublished since they were bookmarked are left out.
+func (s *Service) List(ctx context.Context, readerID string, page int) (*domain.PostList, error) {
+	if page < 1 {
+		page = 1
+	}
+	bookmarks, total, err := s.bookmarks.GetByReader(ctx, readerID, page, domain.BookmarkPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.PostID.Hex()
+	}
+	posts, err := s.posts.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarked posts: %w", err)
+	}
+	byID := make(map[string]*domain.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID.Hex()] = p
+	}
+
+	list := &domain.PostList{TotalCount: total, Page: page, PageSize: domain.BookmarkPageSize}
+	for _, id := range ids {
+		// A post deleted since is skipped until its bookmarks are cleaned up.
+		if p, ok := byID[id]; ok && p.IsPublished() {
+			p.Bookmarked = true
+			list.Posts = append(list.Posts, p)
+		}
+	}
+	return list, nil
+}
+
+// HandlePostEvent implements domain.PostEventHandler by removing the
+// bookmarks of deleted posts.
+func (s *Service) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	if event.Type != domain.PostDeleted {
+		return nil
+	}
+	if err := s.bookmarks.DeleteByPost(ctx, event.PostID); err != nil {
+		return fmt.Errorf("failed to delete bookmarks of post %s: %w", event.PostID, err)
+	}
+	return nil
+}
+
+func (s *Service) post(ctx context.Context, postID string) (*domain.Post, error) {
+	post, err := s.posts.GetByID(ctx, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+	return post, nil
+}
@@ -0,0 +1,200 @@
+package bookmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestService_Add(t *testing.T) {
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget passes", BookmarkCount: 2}
+	posts := &MockPostRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			copied := *post
+			return &copied, nil
+		},
+	}
+	var deltas []int
+	posts.IncrementBookmarksFunc = func(ctx context.Context, id string, delta int) error {
+		assert.Equal(t, post.ID.Hex(), id)
+		deltas = append(deltas, delta)
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		added     bool
+		wantCount int64
+		wantDelta []int
+	}{
+		{name: "new bookmark", added: true, wantCount: 3, wantDelta: []int{1}},
+		{name: "already bookmarked", added: false, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas = nil
+			var saved *domain.Bookmark
+			bookmarks := &MockBookmarkRepository{
+				AddFunc: func(ctx context.Context, b *domain.Bookmark) (bool, error) {
+					saved = b
+					return tt.added, nil
+				},
+			}
+			service := NewService(bookmarks, posts)
+
+			got, err := service.Add(context.Background(), "reader", post.ID.Hex())
+			require.NoError(t, err)
+			assert.True(t, got.Bookmarked)
+			assert.Equal(t, tt.wantCount, got.BookmarkCount)
+			assert.Equal(t, tt.wantDelta, deltas)
+			require.NotNil(t, saved)
+			assert.Equal(t, "reader", saved.ReaderID)
+			assert.Equal(t, post.ID, saved.PostID)
+		})
+	}
+}
+
+func TestService_Remove(t *testing.T) {
+	post := &domain.Post{ID: primitive.NewObjectID(), BookmarkCount: 1}
+	var delta int
+	posts := &MockPostRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return post, nil
+		},
+		IncrementBookmarksFunc: func(ctx context.Context, id string, d int) error {
+			delta = d
+			return nil
+		},
+	}
+	service := NewService(&MockBookmarkRepository{}, posts)
+
+	got, err := service.Remove(context.Background(), "reader", post.ID.Hex())
+	require.NoError(t, err)
+	assert.False(t, got.Bookmarked)
+	assert.Equal(t, int64(0), got.BookmarkCount)
+	assert.Equal(t, -1, delta)
+}
+
+func TestService_AddMissingPost(t *testing.T) {
+	posts := &MockPostRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return nil, domain.ErrPostNotFound
+		},
+	}
+	bookmarks := &MockBookmarkRepository{
+		AddFunc: func(ctx context.Context, b *domain.Bookmark) (bool, error) {
+			t.Fatal("a missing post must not be bookmarked")
+			return false, nil
+		},
+	}
+	service := NewService(bookmarks, posts)
+
+	_, err := service.Add(context.Background(), "reader", primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+}
+
+func TestService_AddDraft(t *testing.T) {
+	posts := &MockPostRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return &domain.Post{ID: primitive.NewObjectID(), Status: domain.PostStatusDraft}, nil
+		},
+	}
+	bookmarks := &MockBookmarkRepository{
+		AddFunc: func(ctx context.Context, b *domain.Bookmark) (bool, error) {
+			t.Fatal("a draft must not be bookmarked")
+			return false, nil
+		},
+	}
+	service := NewService(bookmarks, posts)
+
+	_, err := service.Add(context.Background(), "reader", primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+}
+
+func TestService_List(t *testing.T) {
+	older := &domain.Post{ID: primitive.NewObjectID(), Title: "Older"}
+	newer := &domain.Post{ID: primitive.NewObjectID(), Title: "Newer"}
+	deleted := primitive.NewObjectID()
+	draft := &domain.Post{ID: primitive.NewObjectID(), Title: "Draft", Status: domain.PostStatusDraft}
+	now := time.Now()
+
+	bookmarks := &MockBookmarkRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string, page, pageSize int) ([]*domain.Bookmark, int64, error) {
+			assert.Equal(t, "reader", readerID)
+			assert.Equal(t, 2, page)
+			assert.Equal(t, domain.BookmarkPageSize, pageSize)
+			return []*domain.Bookmark{
+				domain.NewBookmark(readerID, newer.ID, now),
+				domain.NewBookmark(readerID, deleted, now.Add(-time.Minute)),
+				domain.NewBookmark(readerID, draft.ID, now.Add(-2*time.Minute)),
+				domain.NewBookmark(readerID, older.ID, now.Add(-time.Hour)),
+			}, 12, nil
+		},
+	}
+	posts := &MockPostRepository{
+		GetByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Post, error) {
+			assert.Len(t, ids, 4)
+			return []*domain.Post{older, draft, newer}, nil
+		},
+	}
+	service := NewService(bookmarks, posts)
+
+	list, err := service.List(context.Background(), "reader", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), list.TotalCount)
+	assert.Equal(t, 2, list.Page)
+	require.Len(t, list.Posts, 2, "the deleted post and the draft are skipped")
+	assert.Equal(t, "Newer", list.Posts[0].Title, "most recently bookmarked first")
+	assert.Equal(t, "Older", list.Posts[1].Title)
+	assert.True(t, list.Posts[0].Bookmarked)
+}
+
+func TestService_Marked(t *testing.T) {
+	marked := &domain.Post{ID: primitive.NewObjectID()}
+	draft := &domain.Post{ID: primitive.NewObjectID(), Status: domain.PostStatusDraft}
+	bookmarks := &MockBookmarkRepository{
+		BookmarkedFunc: func(ctx context.Context, readerID string, postIDs []string) ([]string, error) {
+			assert.Len(t, postIDs, maxMarked)
+			return []string{marked.ID.Hex(), draft.ID.Hex()}, nil
+		},
+	}
+	posts := &MockPostRepository{
+		GetByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Post, error) {
+			assert.Equal(t, []string{marked.ID.Hex(), draft.ID.Hex()}, ids)
+			return []*domain.Post{marked, draft}, nil
+		},
+	}
+	service := NewService(bookmarks, posts)
+
+	ids := make([]string, maxMarked+10)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID().Hex()
+	}
+	got, err := service.Marked(context.Background(), "reader", ids)
+	require.NoError(t, err)
+	require.Len(t, got, 1, "the draft is left out")
+	assert.Equal(t, marked.ID, got[0].ID)
+	assert.True(t, got[0].Bookmarked)
+}
+
+func TestService_HandlePostEvent(t *testing.T) {
+	var deleted []string
+	bookmarks := &MockBookmarkRepository{
+		DeleteByPostFunc: func(ctx context.Context, postID string) error {
+			deleted = append(deleted, postID)
+			return nil
+		},
+	}
+	service := NewService(bookmarks, &MockPostRepository{})
+
+	require.NoError(t, service.HandlePostEvent(context.Background(), domain.PostEvent{Type: domain.PostUpdated, PostID: "a"}))
+	require.NoError(t, service.HandlePostEvent(context.Background(), domain.PostEvent{Type: domain.PostDeleted, PostID: "b"}))
+	assert.Equal(t, []string{"b"}, deleted)
+}
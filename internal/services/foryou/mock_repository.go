@@ -0,0 +1,71 @@
+package foryou
+
+import (
+	"context"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockFollowRepository is a mock implementation of domain.FollowRepository
+type MockFollowRepository struct {
+	GetByReaderFunc func(ctx context.Context, readerID string) ([]*domain.Follow, error)
+}
+
+func (m *MockFollowRepository) Add(ctx context.Context, f *domain.Follow) error {
+	return nil
+}
+
+func (m *MockFollowRepository) Remove(ctx context.Context, readerID string, target domain.FollowTarget) error {
+	return nil
+}
+
+func (m *MockFollowRepository) GetByReader(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+	if m.GetByReaderFunc != nil {
+		return m.GetByReaderFunc(ctx, readerID)
+	}
+	return nil, nil
+}
+
+func (m *MockFollowRepository) GetByTargets(ctx context.Context, targets []domain.FollowTarget) ([]*domain.Follow, error) {
+	return nil, nil
+}
+
+// MockHistoryRepository is a mock implementation of domain.ReadingHistoryRepository
+type MockHistoryRepository struct {
+	RecordFunc      func(ctx context.Context, r *domain.PostRead) error
+	GetByReaderFunc func(ctx context.Context, readerID string, limit int) ([]*domain.PostRead, error)
+}
+
+func (m *MockHistoryRepository) Record(ctx context.Context, r *domain.PostRead) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, r)
+	}
+	return nil
+}
+
+func (m *MockHistoryRepository) GetByReader(ctx context.Context, readerID string, limit int) ([]*domain.PostRead, error) {
+	if m.GetByReaderFunc != nil {
+		return m.GetByReaderFunc(ctx, readerID, limit)
+	}
+	return nil, nil
+}
+
+// MockPostRepository is a mock implementation of PostRepository
+type MockPostRepository struct {
+	GetPaginatedFunc func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetByIDsFunc     func(ctx context.Context, ids []string) ([]*domain.Post, error)
+}
+
+func (m *MockPostRepository) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	if m.GetPaginatedFunc != nil {
+		return m.GetPaginatedFunc(ctx, query)
+	}
+	return &domain.PostList{Page: query.Page, PageSize: query.PageSize}, nil
+}
+
+func (m *MockPostRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	if m.GetByIDsFunc != nil {
+		return m.GetByIDsFunc(ctx, ids)
+	}
+	return nil, nil
+}
@@ -0,0 +1,276 @@
+package foryou
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// candidateCount is the number of latest published posts that are ranked.
+	candidateCount = 200
+	// historySize is the number of latest reads the interests are taken from.
+	historySize = 100
+	// halfLife is the age at which a post counts half as much as a new one.
+	halfLife = 24 * time.Hour
+	// historyWeight is the weight the whole reading history adds, shared
+	// among the reads.
+	historyWeight = 2.0
+	// readFactor scales down posts the reader already opened.
+	readFactor = 0.3
+
+	defaultCacheTTL = 5 * time.Minute
+	maxCached       = 10000
+)
+
+// followWeights is the weight a follow adds to the posts it matches. Posts
+// carry several tags, so each tag counts less.
+var followWeights = map[domain.FollowKind]float64{
+	domain.FollowCategory: 3,
+	domain.FollowAuthor:   3,
+	domain.FollowTag:      1.5,
+}
+
+// PostRepository is the part of the post storage the feed needs.
+type PostRepository interface {
+	GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error)
+}
+
+// cachedRanking is the ranking of a reader's feed, kept until expires. ids is
+// nil when nothing is known about the reader.
+type cachedRanking struct {
+	ids     []string
+	expires time.Time
+}
+
+// Service ranks the "For you" feed of a reader by the categories, authors
+// and tags the reader follows and reads, and by recency. Rankings are cached
+// per reader, so that pages do not shift while the reader pages through.
+type Service struct {
+	follows domain.FollowRepository
+	history domain.ReadingHistoryRepository
+	posts   PostRepository
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedRanking
+}
+
+// Option configures optional Service settings.
+type Option func(*Service)
+
+// WithCacheTTL sets how long a ranking is reused. Defaults to 5 minutes.
+func WithCacheTTL(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.ttl = d
+		}
+	}
+}
+
+func NewService(follows domain.FollowRepository, history domain.ReadingHistoryRepository, posts PostRepository, opts ...Option) *Service {
+	s := &Service{
+		follows: follows,
+		history: history,
+		posts:   posts,
+		ttl:     defaultCacheTTL,
+		now:     time.Now,
+		cache:   make(map[string]cachedRanking),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RecordRead adds p to the reading history of the reader.
+func (s *Service) RecordRead(ctx context.Context, readerID string, p *domain.Post) error {
+	if err := s.history.Record(ctx, domain.NewPostRead(readerID, p, s.now())); err != nil {
+		return fmt.Errorf("failed to record read: %w", err)
+	}
+	return nil
+}
+
+// ForYou returns a page of the reader's feed. Readers nothing is known about
+// get the latest published posts.
+func (s *Service) ForYou(ctx context.Context, readerID string, page int) (*domain.FeedPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	ids, err := s.ranking(ctx, readerID)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		list, err := s.posts.GetPaginated(ctx, domain.PostQuery{
+			Page:     page,
+			PageSize: domain.FeedPageSize,
+			Sort:     domain.SortNewest,
+			Status:   domain.PostStatusPublished,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest posts: %w", err)
+		}
+		list.NextCursor = ""
+		return &domain.FeedPage{PostList: *list}, nil
+	}
+
+	feed := &domain.FeedPage{
+		PostList:     domain.PostList{TotalCount: int64(len(ids)), Page: page, PageSize: domain.FeedPageSize},
+		Personalized: true,
+	}
+	start := (page - 1) * domain.FeedPageSize
+	if start >= len(ids) {
+		return feed, nil
+	}
+	ids = ids[start:min(start+domain.FeedPageSize, len(ids))]
+
+	posts, err := s.posts.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed posts: %w", err)
+	}
+	byID := make(map[string]*domain.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID.Hex()] = p
+	}
+	for _, id := range ids {
+		// Posts deleted since the ranking are skipped.
+		if p, ok := byID[id]; ok {
+			feed.Posts = append(feed.Posts, p)
+		}
+	}
+	return feed, nil
+}
+
+// ranking returns the ranked post IDs of the reader's feed, from the cache
+// when it is fresh.
+func (s *Service) ranking(ctx context.Context, readerID string) ([]string, error) {
+	now := s.now()
+	s.mu.Lock()
+	cached, ok := s.cache[readerID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.ids, nil
+	}
+
+	ids, err := s.rank(ctx, readerID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxCached {
+		for id, r := range s.cache {
+			if !now.Before(r.expires) {
+				delete(s.cache, id)
+			}
+		}
+		if len(s.cache) >= maxCached {
+			clear(s.cache)
+		}
+	}
+	s.cache[readerID] = cachedRanking{ids: ids, expires: now.Add(s.ttl)}
+	return ids, nil
+}
+
+// rank ranks the latest published posts for the reader, or returns nil when
+// the reader follows and read nothing.
+func (s *Service) rank(ctx context.Context, readerID string, now time.Time) ([]string, error) {
+	follows, err := s.follows.GetByReader(ctx, readerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follows: %w", err)
+	}
+	reads, err := s.history.GetByReader(ctx, readerID, historySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading history: %w", err)
+	}
+	if len(follows) == 0 && len(reads) == 0 {
+		return nil, nil
+	}
+	interests := newProfile(follows, reads)
+
+	candidates, err := s.posts.GetPaginated(ctx, domain.PostQuery{
+		Page:      1,
+		PageSize:  candidateCount,
+		SkipCount: true,
+		Sort:      domain.SortNewest,
+		Status:    domain.PostStatusPublished,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed candidates: %w", err)
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	ranked := make([]scored, len(candidates.Posts))
+	for i, p := range candidates.Posts {
+		ranked[i] = scored{id: p.ID.Hex(), score: interests.score(p, now)}
+	}
+	// The candidates come newest first, which a stable sort keeps among
+	// equal scores.
+	slices.SortStableFunc(ranked, func(a, b scored) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		}
+		return 0
+	})
+
+	ids := make([]string, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.id
+	}
+	return ids, nil
+}
+
+// profile is what the feed knows about the interests of a reader.
+type profile struct {
+	weights map[domain.FollowTarget]float64
+	read    map[primitive.ObjectID]bool
+}
+
+func newProfile(follows []*domain.Follow, reads []*domain.PostRead) profile {
+	p := profile{
+		weights: make(map[domain.FollowTarget]float64),
+		read:    make(map[primitive.ObjectID]bool, len(reads)),
+	}
+	for _, f := range follows {
+		p.weights[f.FollowTarget] += followWeights[f.Kind]
+	}
+	for _, r := range reads {
+		for _, t := range r.FollowTargets() {
+			p.weights[t] += historyWeight / float64(len(reads))
+		}
+		p.read[r.PostID] = true
+	}
+	return p
+}
+
+// score rates post for the reader at now: the weights of the interests it
+// matches, decayed by its age.
+func (p profile) score(post *domain.Post, now time.Time) float64 {
+	affinity := 0.0
+	for _, t := range domain.PostFollowTargets(post) {
+		affinity += p.weights[t]
+	}
+	age := max(now.Sub(post.CreatedAt), 0)
+	score := (1 + affinity) * math.Pow(0.5, age.Hours()/halfLife.Hours())
+	if p.read[post.ID] {
+		score *= readFactor
+	}
+	return score
+}
@@ -0,0 +1,174 @@
+package foryou
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func post(title, category, author string, age time.Duration, now time.Time) *domain.Post {
+	return &domain.Post{
+		ID:        primitive.NewObjectID(),
+		Title:     title,
+		Category:  category,
+		Author:    author,
+		Status:    domain.PostStatusPublished,
+		CreatedAt: now.Add(-age),
+	}
+}
+
+// postRepository serves posts, newest first, like the post repository.
+func postRepository(posts ...*domain.Post) *MockPostRepository {
+	return &MockPostRepository{
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			return &domain.PostList{Posts: posts, Page: query.Page, PageSize: query.PageSize}, nil
+		},
+		GetByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Post, error) {
+			var found []*domain.Post
+			for _, p := range posts {
+				for _, id := range ids {
+					if p.ID.Hex() == id {
+						found = append(found, p)
+					}
+				}
+			}
+			return found, nil
+		},
+	}
+}
+
+func titles(posts []*domain.Post) []string {
+	var t []string
+	for _, p := range posts {
+		t = append(t, p.Title)
+	}
+	return t
+}
+
+func TestService_ForYouRanks(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	unrelated := post("Unrelated", "Sports", "", time.Hour, now)
+	read := post("Read", "Politics", "", time.Hour, now)
+	followed := post("Followed", "Politics", "", 12*time.Hour, now)
+	byAuthor := post("By Anna", "", "Anna", 72*time.Hour, now)
+	posts := postRepository(unrelated, read, followed, byAuthor)
+
+	follows := &MockFollowRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+			return []*domain.Follow{
+				domain.NewFollow(readerID, domain.FollowTarget{Kind: domain.FollowCategory, Value: "Politics"}),
+				domain.NewFollow(readerID, domain.FollowTarget{Kind: domain.FollowAuthor, Value: "Anna"}),
+			}, nil
+		},
+	}
+	history := &MockHistoryRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string, limit int) ([]*domain.PostRead, error) {
+			return []*domain.PostRead{domain.NewPostRead(readerID, read, now)}, nil
+		},
+	}
+	service := NewService(follows, history, posts)
+	service.now = func() time.Time { return now }
+
+	feed, err := service.ForYou(context.Background(), "reader", 1)
+	require.NoError(t, err)
+	assert.True(t, feed.Personalized)
+	assert.Equal(t, int64(4), feed.TotalCount)
+	assert.Equal(t, []string{"Followed", "Read", "Unrelated", "By Anna"}, titles(feed.Posts),
+		"followed interests come first, read posts drop and old posts fade")
+}
+
+func TestService_ForYouFallback(t *testing.T) {
+	var asked domain.PostQuery
+	posts := &MockPostRepository{
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			asked = query
+			return &domain.PostList{Posts: []*domain.Post{{Title: "Latest"}}, TotalCount: 1, Page: query.Page, PageSize: query.PageSize}, nil
+		},
+	}
+	service := NewService(&MockFollowRepository{}, &MockHistoryRepository{}, posts)
+
+	feed, err := service.ForYou(context.Background(), "reader", 2)
+	require.NoError(t, err)
+	assert.False(t, feed.Personalized)
+	assert.Equal(t, []string{"Latest"}, titles(feed.Posts))
+	assert.Equal(t, 2, asked.Page)
+	assert.Equal(t, domain.PostStatusPublished, asked.Status)
+	assert.Equal(t, domain.SortNewest, asked.Sort)
+}
+
+func TestService_ForYouPages(t *testing.T) {
+	now := time.Now()
+	var all []*domain.Post
+	for i := range domain.FeedPageSize + 2 {
+		all = append(all, post("Post", "Politics", "", time.Duration(i)*time.Hour, now))
+	}
+	follows := &MockFollowRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+			return []*domain.Follow{domain.NewFollow(readerID, domain.FollowTarget{Kind: domain.FollowCategory, Value: "Politics"})}, nil
+		},
+	}
+	service := NewService(follows, &MockHistoryRepository{}, postRepository(all...))
+
+	feed, err := service.ForYou(context.Background(), "reader", 2)
+	require.NoError(t, err)
+	require.Len(t, feed.Posts, 2)
+	assert.Equal(t, all[domain.FeedPageSize].ID, feed.Posts[0].ID)
+
+	feed, err = service.ForYou(context.Background(), "reader", 3)
+	require.NoError(t, err)
+	assert.Empty(t, feed.Posts)
+}
+
+func TestService_ForYouCachesRanking(t *testing.T) {
+	now := time.Now()
+	ranked := 0
+	follows := &MockFollowRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+			ranked++
+			return []*domain.Follow{domain.NewFollow(readerID, domain.FollowTarget{Kind: domain.FollowTag, Value: "budget"})}, nil
+		},
+	}
+	service := NewService(follows, &MockHistoryRepository{}, postRepository(post("Post", "", "", time.Hour, now)), WithCacheTTL(time.Minute))
+	service.now = func() time.Time { return now }
+
+	for range 3 {
+		_, err := service.ForYou(context.Background(), "reader", 1)
+		require.NoError(t, err)
+	}
+	_, err := service.ForYou(context.Background(), "other", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ranked, "one ranking per reader")
+
+	now = now.Add(time.Minute)
+	_, err = service.ForYou(context.Background(), "reader", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, ranked, "an expired ranking is computed again")
+}
+
+func TestService_RecordRead(t *testing.T) {
+	var recorded *domain.PostRead
+	history := &MockHistoryRepository{
+		RecordFunc: func(ctx context.Context, r *domain.PostRead) error {
+			recorded = r
+			return nil
+		},
+	}
+	service := NewService(&MockFollowRepository{}, history, &MockPostRepository{})
+	p := &domain.Post{ID: primitive.NewObjectID(), Category: "Politics", Author: "Anna", Tags: []string{"budget"}}
+
+	require.NoError(t, service.RecordRead(context.Background(), "reader", p))
+	require.NotNil(t, recorded)
+	assert.Equal(t, "reader", recorded.ReaderID)
+	assert.Equal(t, p.ID, recorded.PostID)
+	assert.Equal(t, []domain.FollowTarget{
+		{Kind: domain.FollowCategory, Value: "Politics"},
+		{Kind: domain.FollowAuthor, Value: "Anna"},
+		{Kind: domain.FollowTag, Value: "budget"},
+	}, recorded.FollowTargets())
+}
@@ -0,0 +1,106 @@
+package newsletter
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockSubscriberRepository is a mock implementation of domain.SubscriberRepository
+type MockSubscriberRepository struct {
+	CreateFunc     func(ctx context.Context, sub *domain.Subscriber) error
+	GetByIDFunc    func(ctx context.Context, id string) (*domain.Subscriber, error)
+	GetByEmailFunc func(ctx context.Context, email string) (*domain.Subscriber, error)
+	GetByTokenFunc func(ctx context.Context, token string) (*domain.Subscriber, error)
+	GetAllFunc     func(ctx context.Context) ([]*domain.Subscriber, error)
+	SaveFunc       func(ctx context.Context, sub *domain.Subscriber) error
+	DeleteFunc     func(ctx context.Context, id primitive.ObjectID) error
+	ClaimDueFunc   func(ctx context.Context, now time.Time, lease time.Duration) (*domain.Subscriber, error)
+}
+
+func (m *MockSubscriberRepository) Create(ctx context.Context, sub *domain.Subscriber) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, sub)
+	}
+	return nil
+}
+
+func (m *MockSubscriberRepository) GetByID(ctx context.Context, id string) (*domain.Subscriber, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockSubscriberRepository) GetByEmail(ctx context.Context, email string) (*domain.Subscriber, error) {
+	if m.GetByEmailFunc != nil {
+		return m.GetByEmailFunc(ctx, email)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockSubscriberRepository) GetByToken(ctx context.Context, token string) (*domain.Subscriber, error) {
+	if m.GetByTokenFunc != nil {
+		return m.GetByTokenFunc(ctx, token)
+	}
+	return nil, domain.ErrSubscriberNotFound
+}
+
+func (m *MockSubscriberRepository) GetAll(ctx context.Context) ([]*domain.Subscriber, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockSubscriberRepository) Save(ctx context.Context, sub *domain.Subscriber) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, sub)
+	}
+	return nil
+}
+
+func (m *MockSubscriberRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockSubscriberRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.Subscriber, error) {
+	if m.ClaimDueFunc != nil {
+		return m.ClaimDueFunc(ctx, now, lease)
+	}
+	return nil, nil
+}
+
+// MockSendRepository is a mock implementation of domain.NewsletterSendRepository
+type MockSendRepository struct {
+	CreateFunc          func(ctx context.Context, send *domain.NewsletterSend) error
+	GetRecentFunc       func(ctx context.Context, limit int) ([]*domain.NewsletterSend, error)
+	GetBySubscriberFunc func(ctx context.Context, subscriberID primitive.ObjectID, limit int) ([]*domain.NewsletterSend, error)
+}
+
+func (m *MockSendRepository) Create(ctx context.Context, send *domain.NewsletterSend) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, send)
+	}
+	return nil
+}
+
+func (m *MockSendRepository) GetRecent(ctx context.Context, limit int) ([]*domain.NewsletterSend, error) {
+	if m.GetRecentFunc != nil {
+		return m.GetRecentFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockSendRepository) GetBySubscriber(ctx context.Context, subscriberID primitive.ObjectID, limit int) ([]*domain.NewsletterSend, error) {
+	if m.GetBySubscriberFunc != nil {
+		return m.GetBySubscriberFunc(ctx, subscriberID, limit)
+	}
+	return nil, nil
+}
@@ -0,0 +1,159 @@
+package newsletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+const (
+	// recentSends is the number of sends shown in the send log.
+	recentSends = 50
+	// subscriberSends is the number of sends shown for one subscriber.
+	subscriberSends = 100
+)
+
+// Composer renders the emails the service sends.
+type Composer interface {
+	Confirmation(sub *domain.Subscriber) (*domain.EmailMessage, error)
+}
+
+type Service struct {
+	subscribers domain.SubscriberRepository
+	sends       domain.NewsletterSendRepository
+	mailer      domain.Mailer
+	composer    Composer
+	now         func() time.Time
+}
+
+func NewService(subscribers domain.SubscriberRepository, sends domain.NewsletterSendRepository, mailer domain.Mailer, composer Composer) *Service {
+	return &Service{
+		subscribers: subscribers,
+		sends:       sends,
+		mailer:      mailer,
+		composer:    composer,
+		now:         time.Now,
+	}
+}
+
+// Subscribe signs email up for the digest and sends the confirmation email.
+// Signing up an unconfirmed address again changes its frequency and sends the
+// confirmation again. A confirmed address is left as is, so that nobody can
+// find out who subscribed or change their subscription.
+func (s *Service) Subscribe(ctx context.Context, email string, frequency domain.DigestFrequency) error {
+	sub, err := domain.NewSubscriber(email, frequency)
+	if err != nil {
+		return fmt.Errorf("failed to create subscriber: %w", err)
+	}
+
+	existing, err := s.subscribers.GetByEmail(ctx, sub.Email)
+	switch {
+	case errors.Is(err, domain.ErrSubscriberNotFound):
+		if err := s.subscribers.Create(ctx, sub); err != nil {
+			return fmt.Errorf("failed to save subscriber: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get subscriber: %w", err)
+	case existing.Confirmed():
+		return nil
+	default:
+		sub = existing
+		if err := sub.SetFrequency(frequency); err != nil {
+			return err
+		}
+		if err := s.subscribers.Save(ctx, sub); err != nil {
+			return fmt.Errorf("failed to save subscriber: %w", err)
+		}
+	}
+
+	return s.sendConfirmation(ctx, sub)
+}
+
+// sendConfirmation sends the confirmation email and logs it.
+func (s *Service) sendConfirmation(ctx context.Context, sub *domain.Subscriber) error {
+	msg, err := s.composer.Confirmation(sub)
+	if err != nil {
+		return fmt.Errorf("failed to render confirmation email: %w", err)
+	}
+	sendErr := s.mailer.Send(ctx, msg)
+	if err := s.sends.Create(ctx, domain.NewNewsletterSend(sub, domain.EmailConfirmation, msg, 0, s.now(), sendErr)); err != nil {
+		return fmt.Errorf("failed to log newsletter send: %w", err)
+	}
+	if sendErr != nil {
+		return fmt.Errorf("failed to send confirmation email: %w", sendErr)
+	}
+	return nil
+}
+
+// Confirm confirms the subscriber with the token from the confirmation email.
+// Confirming twice is not an error.
+func (s *Service) Confirm(ctx context.Context, token string) (*domain.Subscriber, error) {
+	sub, err := s.subscribers.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	if sub.Confirmed() {
+		return sub, nil
+	}
+
+	sub.Confirm(s.now())
+	if err := s.subscribers.Save(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to save subscriber: %w", err)
+	}
+	return sub, nil
+}
+
+// GetByToken returns the subscriber with the token from its emails.
+func (s *Service) GetByToken(ctx context.Context, token string) (*domain.Subscriber, error) {
+	sub, err := s.subscribers.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	return sub, nil
+}
+
+// Unsubscribe deletes the subscriber with the token from its emails and
+// returns it. The send log is kept.
+func (s *Service) Unsubscribe(ctx context.Context, token string) (*domain.Subscriber, error) {
+	sub, err := s.subscribers.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	if err := s.subscribers.Delete(ctx, sub.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete subscriber: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*domain.Subscriber, error) {
+	subs, err := s.subscribers.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	return subs, nil
+}
+
+// RecentSends returns the latest sends to any subscriber, newest first.
+func (s *Service) RecentSends(ctx context.Context) ([]*domain.NewsletterSend, error) {
+	sends, err := s.sends.GetRecent(ctx, recentSends)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter sends: %w", err)
+	}
+	return sends, nil
+}
+
+// GetSubscriber returns a subscriber together with its latest sends, newest first.
+func (s *Service) GetSubscriber(ctx context.Context, id string) (*domain.Subscriber, []*domain.NewsletterSend, error) {
+	sub, err := s.subscribers.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get subscriber: %w", err)
+	}
+	sends, err := s.sends.GetBySubscriber(ctx, sub.ID, subscriberSends)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get newsletter sends: %w", err)
+	}
+	return sub, sends, nil
+}
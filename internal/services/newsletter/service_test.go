@@ -0,0 +1,167 @@
+package newsletter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeMailer struct {
+	err  error
+	sent []*domain.EmailMessage
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+type fakeComposer struct{}
+
+func (fakeComposer) Confirmation(sub *domain.Subscriber) (*domain.EmailMessage, error) {
+	return &domain.EmailMessage{To: sub.Email, Subject: "Confirm", Text: sub.Token, HTML: sub.Token}, nil
+}
+
+func TestService_Subscribe(t *testing.T) {
+	var created *domain.Subscriber
+	var logged []*domain.NewsletterSend
+	subscribers := &MockSubscriberRepository{
+		CreateFunc: func(ctx context.Context, sub *domain.Subscriber) error {
+			created = sub
+			return nil
+		},
+	}
+	sends := &MockSendRepository{
+		CreateFunc: func(ctx context.Context, send *domain.NewsletterSend) error {
+			logged = append(logged, send)
+			return nil
+		},
+	}
+	mailer := &fakeMailer{}
+	service := NewService(subscribers, sends, mailer, fakeComposer{})
+
+	require.NoError(t, service.Subscribe(context.Background(), "Anna@Example.com", domain.DigestWeekly))
+	require.NotNil(t, created)
+	assert.Equal(t, "anna@example.com", created.Email)
+	assert.False(t, created.Confirmed())
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, created.Token, mailer.sent[0].Text)
+	require.Len(t, logged, 1)
+	assert.Equal(t, domain.EmailConfirmation, logged[0].Kind)
+	assert.Equal(t, created.ID, logged[0].SubscriberID)
+
+	err := service.Subscribe(context.Background(), "anna", domain.DigestWeekly)
+	assert.ErrorIs(t, err, domain.ErrInvalidEmail)
+}
+
+func TestService_SubscribeAgain(t *testing.T) {
+	existing, err := domain.NewSubscriber("anna@example.com", domain.DigestDaily)
+	require.NoError(t, err)
+
+	var saved *domain.Subscriber
+	subscribers := &MockSubscriberRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (*domain.Subscriber, error) {
+			return existing, nil
+		},
+		CreateFunc: func(ctx context.Context, sub *domain.Subscriber) error {
+			t.Error("an existing subscriber is not created again")
+			return nil
+		},
+		SaveFunc: func(ctx context.Context, sub *domain.Subscriber) error {
+			saved = sub
+			return nil
+		},
+	}
+	mailer := &fakeMailer{}
+	service := NewService(subscribers, &MockSendRepository{}, mailer, fakeComposer{})
+	ctx := context.Background()
+
+	require.NoError(t, service.Subscribe(ctx, "anna@example.com", domain.DigestWeekly))
+	require.NotNil(t, saved)
+	assert.Equal(t, domain.DigestWeekly, saved.Frequency)
+	require.Len(t, mailer.sent, 1, "the confirmation is sent again")
+
+	existing.Confirm(time.Now())
+	saved = nil
+	require.NoError(t, service.Subscribe(ctx, "anna@example.com", domain.DigestDaily))
+	assert.Nil(t, saved, "a confirmed subscription is left as is")
+	assert.Len(t, mailer.sent, 1)
+}
+
+func TestService_SubscribeMailFailure(t *testing.T) {
+	var logged *domain.NewsletterSend
+	sends := &MockSendRepository{
+		CreateFunc: func(ctx context.Context, send *domain.NewsletterSend) error {
+			logged = send
+			return nil
+		},
+	}
+	service := NewService(&MockSubscriberRepository{}, sends, &fakeMailer{err: assert.AnError}, fakeComposer{})
+
+	err := service.Subscribe(context.Background(), "anna@example.com", domain.DigestDaily)
+	assert.ErrorIs(t, err, assert.AnError)
+	require.NotNil(t, logged)
+	assert.True(t, logged.Failed())
+}
+
+func TestService_Confirm(t *testing.T) {
+	sub, err := domain.NewSubscriber("anna@example.com", domain.DigestDaily)
+	require.NoError(t, err)
+
+	var saves int
+	subscribers := &MockSubscriberRepository{
+		GetByTokenFunc: func(ctx context.Context, token string) (*domain.Subscriber, error) {
+			if token != sub.Token {
+				return nil, domain.ErrSubscriberNotFound
+			}
+			return sub, nil
+		},
+		SaveFunc: func(ctx context.Context, s *domain.Subscriber) error {
+			saves++
+			return nil
+		},
+	}
+	service := NewService(subscribers, &MockSendRepository{}, &fakeMailer{}, fakeComposer{})
+	ctx := context.Background()
+
+	confirmed, err := service.Confirm(ctx, sub.Token)
+	require.NoError(t, err)
+	assert.True(t, confirmed.Confirmed())
+	assert.False(t, confirmed.NextDigestAt.IsZero())
+	assert.Equal(t, 1, saves)
+
+	_, err = service.Confirm(ctx, sub.Token)
+	require.NoError(t, err)
+	assert.Equal(t, 1, saves, "confirming twice saves once")
+
+	_, err = service.Confirm(ctx, "unknown")
+	assert.ErrorIs(t, err, domain.ErrSubscriberNotFound)
+}
+
+func TestService_Unsubscribe(t *testing.T) {
+	sub, err := domain.NewSubscriber("anna@example.com", domain.DigestDaily)
+	require.NoError(t, err)
+
+	var deleted primitive.ObjectID
+	subscribers := &MockSubscriberRepository{
+		GetByTokenFunc: func(ctx context.Context, token string) (*domain.Subscriber, error) {
+			return sub, nil
+		},
+		DeleteFunc: func(ctx context.Context, id primitive.ObjectID) error {
+			deleted = id
+			return nil
+		},
+	}
+	service := NewService(subscribers, &MockSendRepository{}, &fakeMailer{}, fakeComposer{})
+
+	got, err := service.Unsubscribe(context.Background(), sub.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "anna@example.com", got.Email)
+	assert.Equal(t, sub.ID, deleted)
+}
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockFollowRepository is a mock implementation of domain.FollowRepository
+type MockFollowRepository struct {
+	AddFunc          func(ctx context.Context, f *domain.Follow) error
+	RemoveFunc       func(ctx context.Context, readerID string, target domain.FollowTarget) error
+	GetByReaderFunc  func(ctx context.Context, readerID string) ([]*domain.Follow, error)
+	GetByTargetsFunc func(ctx context.Context, targets []domain.FollowTarget) ([]*domain.Follow, error)
+}
+
+func (m *MockFollowRepository) Add(ctx context.Context, f *domain.Follow) error {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, f)
+	}
+	return nil
+}
+
+func (m *MockFollowRepository) Remove(ctx context.Context, readerID string, target domain.FollowTarget) error {
+	if m.RemoveFunc != nil {
+		return m.RemoveFunc(ctx, readerID, target)
+	}
+	return nil
+}
+
+func (m *MockFollowRepository) GetByReader(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+	if m.GetByReaderFunc != nil {
+		return m.GetByReaderFunc(ctx, readerID)
+	}
+	return nil, nil
+}
+
+func (m *MockFollowRepository) GetByTargets(ctx context.Context, targets []domain.FollowTarget) ([]*domain.Follow, error) {
+	if m.GetByTargetsFunc != nil {
+		return m.GetByTargetsFunc(ctx, targets)
+	}
+	return nil, nil
+}
+
+// MockNotificationRepository is a mock implementation of domain.NotificationRepository
+type MockNotificationRepository struct {
+	AddFunc          func(ctx context.Context, notifications []*domain.Notification) ([]*domain.Notification, error)
+	GetByReaderFunc  func(ctx context.Context, readerID string, limit int) ([]*domain.Notification, error)
+	CountUnreadFunc  func(ctx context.Context, readerID string) (int64, error)
+	MarkReadFunc     func(ctx context.Context, readerID, id string, now time.Time) (*domain.Notification, error)
+	MarkAllReadFunc  func(ctx context.Context, readerID string, now time.Time) error
+	DeleteByPostFunc func(ctx context.Context, postID string) error
+}
+
+func (m *MockNotificationRepository) Add(ctx context.Context, notifications []*domain.Notification) ([]*domain.Notification, error) {
+	if m.AddFunc != nil {
+		return m.AddFunc(ctx, notifications)
+	}
+	return notifications, nil
+}
+
+func (m *MockNotificationRepository) GetByReader(ctx context.Context, readerID string, limit int) ([]*domain.Notification, error) {
+	if m.GetByReaderFunc != nil {
+		return m.GetByReaderFunc(ctx, readerID, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, readerID string) (int64, error) {
+	if m.CountUnreadFunc != nil {
+		return m.CountUnreadFunc(ctx, readerID)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, readerID, id string, now time.Time) (*domain.Notification, error) {
+	if m.MarkReadFunc != nil {
+		return m.MarkReadFunc(ctx, readerID, id, now)
+	}
+	return nil, domain.ErrNotificationNotFound
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, readerID string, now time.Time) error {
+	if m.MarkAllReadFunc != nil {
+		return m.MarkAllReadFunc(ctx, readerID, now)
+	}
+	return nil
+}
+
+func (m *MockNotificationRepository) DeleteByPost(ctx context.Context, postID string) error {
+	if m.DeleteByPostFunc != nil {
+		return m.DeleteByPostFunc(ctx, postID)
+	}
+	return nil
+}
+
+// MockSettingsRepository is a mock implementation of domain.NotificationSettingsRepository
+type MockSettingsRepository struct {
+	GetFunc        func(ctx context.Context, readerID string) (*domain.NotificationSettings, error)
+	GetByTokenFunc func(ctx context.Context, token string) (*domain.NotificationSettings, error)
+	SaveFunc       func(ctx context.Context, s *domain.NotificationSettings) error
+}
+
+func (m *MockSettingsRepository) Get(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, readerID)
+	}
+	return &domain.NotificationSettings{ReaderID: readerID}, nil
+}
+
+func (m *MockSettingsRepository) GetByToken(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	if m.GetByTokenFunc != nil {
+		return m.GetByTokenFunc(ctx, token)
+	}
+	return nil, domain.ErrSettingsNotFound
+}
+
+func (m *MockSettingsRepository) Save(ctx context.Context, s *domain.NotificationSettings) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, s)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// inboxSize is the number of notifications shown in the inbox.
+const inboxSize = 50
+
+// Composer renders the emails the service sends.
+type Composer interface {
+	Confirmation(s *domain.NotificationSettings) (*domain.EmailMessage, error)
+}
+
+type Service struct {
+	follows       domain.FollowRepository
+	notifications domain.NotificationRepository
+	settings      domain.NotificationSettingsRepository
+	mailer        domain.Mailer
+	composer      Composer
+	now           func() time.Time
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithEmail lets readers have their notifications emailed. Without it,
+// notifications only reach the inbox.
+func WithEmail(mailer domain.Mailer, composer Composer) Option {
+	return func(s *Service) {
+		s.mailer = mailer
+		s.composer = composer
+	}
+}
+
+func NewService(follows domain.FollowRepository, notifications domain.NotificationRepository, settings domain.NotificationSettingsRepository, opts ...Option) *Service {
+	s := &Service{
+		follows:       follows,
+		notifications: notifications,
+		settings:      settings,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Follow makes the reader follow a category or an author. Following twice is
+// not an error.
+func (s *Service) Follow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+	target, err := domain.NewFollowTarget(kind, value)
+	if err != nil {
+		return domain.FollowState{}, err
+	}
+	if err := s.follows.Add(ctx, domain.NewFollow(readerID, target)); err != nil {
+		return domain.FollowState{}, fmt.Errorf("failed to follow: %w", err)
+	}
+	return domain.FollowState{FollowTarget: target, Following: true}, nil
+}
+
+// Unfollow stops the reader following a category or an author.
+func (s *Service) Unfollow(ctx context.Context, readerID string, kind domain.FollowKind, value string) (domain.FollowState, error) {
+	target, err := domain.NewFollowTarget(kind, value)
+	if err != nil {
+		return domain.FollowState{}, err
+	}
+	if err := s.follows.Remove(ctx, readerID, target); err != nil {
+		return domain.FollowState{}, fmt.Errorf("failed to unfollow: %w", err)
+	}
+	return domain.FollowState{FollowTarget: target}, nil
+}
+
+// Follows returns what the reader follows, by kind and name.
+func (s *Service) Follows(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+	follows, err := s.follows.GetByReader(ctx, readerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follows: %w", err)
+	}
+	return follows, nil
+}
+
+// FollowStates tells for each of targets whether the reader follows it.
+func (s *Service) FollowStates(ctx context.Context, readerID string, targets []domain.FollowTarget) ([]domain.FollowState, error) {
+	follows, err := s.Follows(ctx, readerID)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]domain.FollowState, len(targets))
+	for i, t := range targets {
+		states[i].FollowTarget = t
+		for _, f := range follows {
+			if f.FollowTarget == t {
+				states[i].Following = true
+				break
+			}
+		}
+	}
+	return states, nil
+}
+
+// Inbox returns the latest notifications of the reader, newest first.
+func (s *Service) Inbox(ctx context.Context, readerID string) ([]*domain.Notification, error) {
+	notifications, err := s.notifications.GetByReader(ctx, readerID, inboxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// UnreadCount returns the number of notifications the reader has not opened.
+func (s *Service) UnreadCount(ctx context.Context, readerID string) (int64, error) {
+	n, err := s.notifications.CountUnread(ctx, readerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return n, nil
+}
+
+// Open marks a notification of the reader as read and returns it.
+func (s *Service) Open(ctx context.Context, readerID, id string) (*domain.Notification, error) {
+	n, err := s.notifications.MarkRead(ctx, readerID, id, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification: %w", err)
+	}
+	return n, nil
+}
+
+// MarkAllRead marks every notification of the reader as read.
+func (s *Service) MarkAllRead(ctx context.Context, readerID string) error {
+	if err := s.notifications.MarkAllRead(ctx, readerID, s.now()); err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// EmailAvailable reports whether notifications can be emailed.
+func (s *Service) EmailAvailable() bool {
+	return s.mailer != nil
+}
+
+// Settings returns the notification settings of the reader.
+func (s *Service) Settings(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+	settings, err := s.settings.Get(ctx, readerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SetEmail changes the address the reader's notifications are emailed to and
+// sends the confirmation email to a new address. An empty address stops the
+// emails.
+func (s *Service) SetEmail(ctx context.Context, readerID, email string) (*domain.NotificationSettings, error) {
+	if !s.EmailAvailable() {
+		return nil, domain.ErrEmailUnavailable
+	}
+	settings, err := s.Settings(ctx, readerID)
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := settings.SetEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.settings.Save(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	if !confirm {
+		return settings, nil
+	}
+
+	msg, err := s.composer.Confirmation(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render confirmation email: %w", err)
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+	return settings, nil
+}
+
+// ConfirmEmail confirms the address with the token from the confirmation
+// email. Confirming twice is not an error.
+func (s *Service) ConfirmEmail(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	settings, err := s.settings.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if settings.EmailConfirmed() {
+		return settings, nil
+	}
+	settings.ConfirmEmail(s.now())
+	if err := s.settings.Save(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return settings, nil
+}
+
+// GetByToken returns the settings with the token from notification emails.
+func (s *Service) GetByToken(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+	settings, err := s.settings.GetByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	return settings, nil
+}
+
+// StopEmail stops emailing the reader with the token from notification
+// emails and returns the address that was used.
+func (s *Service) StopEmail(ctx context.Context, token string) (string, error) {
+	settings, err := s.GetByToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	email := settings.Email
+	settings.StopEmail()
+	if err := s.settings.Save(ctx, settings); err != nil {
+		return "", fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return email, nil
+}
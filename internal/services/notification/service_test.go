@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMailer struct {
+	sent []*domain.EmailMessage
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+type fakeComposer struct{}
+
+func (fakeComposer) Confirmation(s *domain.NotificationSettings) (*domain.EmailMessage, error) {
+	return &domain.EmailMessage{To: s.Email, Subject: "Confirm", Text: s.Token}, nil
+}
+
+func TestService_Follow(t *testing.T) {
+	var added *domain.Follow
+	var removed domain.FollowTarget
+	follows := &MockFollowRepository{
+		AddFunc: func(ctx context.Context, f *domain.Follow) error {
+			added = f
+			return nil
+		},
+		RemoveFunc: func(ctx context.Context, readerID string, target domain.FollowTarget) error {
+			assert.Equal(t, "reader", readerID)
+			removed = target
+			return nil
+		},
+	}
+	service := NewService(follows, &MockNotificationRepository{}, &MockSettingsRepository{})
+
+	state, err := service.Follow(context.Background(), "reader", domain.FollowAuthor, " Anna ")
+	require.NoError(t, err)
+	assert.True(t, state.Following)
+	require.NotNil(t, added)
+	assert.Equal(t, "reader", added.ReaderID)
+	assert.Equal(t, domain.FollowTarget{Kind: domain.FollowAuthor, Value: "Anna"}, added.FollowTarget)
+
+	state, err = service.Unfollow(context.Background(), "reader", domain.FollowAuthor, "Anna")
+	require.NoError(t, err)
+	assert.False(t, state.Following)
+	assert.Equal(t, added.FollowTarget, removed)
+
+	_, err = service.Follow(context.Background(), "reader", "source", "Reuters")
+	assert.ErrorIs(t, err, domain.ErrInvalidFollowKind)
+}
+
+func TestService_FollowStates(t *testing.T) {
+	politics := domain.FollowTarget{Kind: domain.FollowCategory, Value: "Politics"}
+	anna := domain.FollowTarget{Kind: domain.FollowAuthor, Value: "Anna"}
+	follows := &MockFollowRepository{
+		GetByReaderFunc: func(ctx context.Context, readerID string) ([]*domain.Follow, error) {
+			return []*domain.Follow{domain.NewFollow(readerID, anna)}, nil
+		},
+	}
+	service := NewService(follows, &MockNotificationRepository{}, &MockSettingsRepository{})
+
+	states, err := service.FollowStates(context.Background(), "reader", []domain.FollowTarget{politics, anna})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FollowState{
+		{FollowTarget: politics},
+		{FollowTarget: anna, Following: true},
+	}, states)
+}
+
+func TestService_SetEmail(t *testing.T) {
+	var saved *domain.NotificationSettings
+	settings := &MockSettingsRepository{
+		GetFunc: func(ctx context.Context, readerID string) (*domain.NotificationSettings, error) {
+			if saved != nil {
+				copied := *saved
+				return &copied, nil
+			}
+			return &domain.NotificationSettings{ReaderID: readerID}, nil
+		},
+		GetByTokenFunc: func(ctx context.Context, token string) (*domain.NotificationSettings, error) {
+			if saved == nil || saved.Token != token {
+				return nil, domain.ErrSettingsNotFound
+			}
+			copied := *saved
+			return &copied, nil
+		},
+		SaveFunc: func(ctx context.Context, s *domain.NotificationSettings) error {
+			saved = s
+			return nil
+		},
+	}
+
+	_, err := NewService(&MockFollowRepository{}, &MockNotificationRepository{}, settings).SetEmail(context.Background(), "reader", "anna@example.com")
+	assert.ErrorIs(t, err, domain.ErrEmailUnavailable)
+
+	mailer := &fakeMailer{}
+	service := NewService(&MockFollowRepository{}, &MockNotificationRepository{}, settings, WithEmail(mailer, fakeComposer{}))
+
+	s, err := service.SetEmail(context.Background(), "reader", "anna@example.com")
+	require.NoError(t, err)
+	assert.False(t, s.EmailConfirmed())
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "anna@example.com", mailer.sent[0].To)
+	assert.Equal(t, s.Token, mailer.sent[0].Text)
+
+	_, err = service.ConfirmEmail(context.Background(), "wrong")
+	assert.ErrorIs(t, err, domain.ErrSettingsNotFound)
+	s, err = service.ConfirmEmail(context.Background(), s.Token)
+	require.NoError(t, err)
+	assert.True(t, s.EmailConfirmed())
+
+	_, err = service.SetEmail(context.Background(), "reader", "anna@example.com")
+	require.NoError(t, err)
+	assert.Len(t, mailer.sent, 1, "a confirmed address is not confirmed again")
+
+	email, err := service.StopEmail(context.Background(), s.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "anna@example.com", email)
+	assert.Empty(t, saved.Email)
+	assert.False(t, saved.EmailConfirmed())
+}
+
+func TestService_Open(t *testing.T) {
+	var markedAt time.Time
+	notifications := &MockNotificationRepository{
+		MarkReadFunc: func(ctx context.Context, readerID, id string, now time.Time) (*domain.Notification, error) {
+			if readerID != "reader" || id != "n1" {
+				return nil, domain.ErrNotificationNotFound
+			}
+			markedAt = now
+			return &domain.Notification{ReaderID: readerID, ReadAt: &now}, nil
+		},
+	}
+	service := NewService(&MockFollowRepository{}, notifications, &MockSettingsRepository{})
+
+	n, err := service.Open(context.Background(), "reader", "n1")
+	require.NoError(t, err)
+	assert.True(t, n.Read())
+	assert.False(t, markedAt.IsZero())
+
+	_, err = service.Open(context.Background(), "someone-else", "n1")
+	assert.ErrorIs(t, err, domain.ErrNotificationNotFound)
+}
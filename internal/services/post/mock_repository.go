@@ -2,19 +2,35 @@ package post
 
 import (
 	"context"
+	"time"
 
 	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockRepository is a mock implementation of domain.Repository
 type MockRepository struct {
-	CreateFunc       func(ctx context.Context, post *domain.Post) error
-	GetAllFunc       func(ctx context.Context) ([]*domain.Post, error)
-	GetByIDFunc      func(ctx context.Context, id string) (*domain.Post, error)
-	UpdateFunc       func(ctx context.Context, post *domain.Post) error
-	DeleteFunc       func(ctx context.Context, id string) error
-	GetPaginatedFunc func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error)
-	GetRecentFunc    func(ctx context.Context, limit int) ([]*domain.Post, error)
+	CreateFunc             func(ctx context.Context, post *domain.Post) error
+	GetAllFunc             func(ctx context.Context) ([]*domain.Post, error)
+	GetByIDFunc            func(ctx context.Context, id string) (*domain.Post, error)
+	GetByIDsFunc           func(ctx context.Context, ids []string) ([]*domain.Post, error)
+	UpdateFunc             func(ctx context.Context, post *domain.Post) error
+	DeleteFunc             func(ctx context.Context, id string) error
+	GetPaginatedFunc       func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
+	GetFacetsFunc          func(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error)
+	GetArchiveFunc         func(ctx context.Context) ([]domain.ArchiveMonth, error)
+	GetRecentFunc          func(ctx context.Context, limit int) ([]*domain.Post, error)
+	IncrementViewsFunc     func(ctx context.Context, id string) error
+	IncrementBookmarksFunc func(ctx context.Context, id string, delta int) error
+	CountPublishedFunc     func(ctx context.Context) (int64, error)
+	EachPublishedFunc      func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error
+	ExistsByOriginFunc     func(ctx context.Context, sourceID primitive.ObjectID, guid, link string) (bool, error)
+	GetFingerprintsFunc    func(ctx context.Context) ([]domain.PostFingerprint, error)
+	SetFingerprintFunc     func(ctx context.Context, id string, fingerprint int64) error
+	SetBreakingFunc        func(ctx context.Context, id string, breaking *domain.Breaking) error
+	GetBreakingFunc        func(ctx context.Context, now time.Time) ([]*domain.Post, error)
+	GetAuthorsFunc         func(ctx context.Context) ([]string, error)
 }
 
 func (m *MockRepository) Create(ctx context.Context, post *domain.Post) error {
@@ -38,6 +54,13 @@ func (m *MockRepository) GetByID(ctx context.Context, id string) (*domain.Post,
 	return nil, nil
 }
 
+func (m *MockRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Post, error) {
+	if m.GetByIDsFunc != nil {
+		return m.GetByIDsFunc(ctx, ids)
+	}
+	return nil, nil
+}
+
 func (m *MockRepository) Update(ctx context.Context, post *domain.Post) error {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, post)
@@ -52,9 +75,16 @@ func (m *MockRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (m *MockRepository) GetPaginated(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+func (m *MockRepository) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 	if m.GetPaginatedFunc != nil {
-		return m.GetPaginatedFunc(ctx, page, pageSize, search)
+		return m.GetPaginatedFunc(ctx, query)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetFacets(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+	if m.GetFacetsFunc != nil {
+		return m.GetFacetsFunc(ctx, query)
 	}
 	return nil, nil
 }
@@ -65,3 +95,80 @@ func (m *MockRepository) GetRecent(ctx context.Context, limit int) ([]*domain.Po
 	}
 	return nil, nil
 }
+
+func (m *MockRepository) IncrementViews(ctx context.Context, id string) error {
+	if m.IncrementViewsFunc != nil {
+		return m.IncrementViewsFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockRepository) IncrementBookmarks(ctx context.Context, id string, delta int) error {
+	if m.IncrementBookmarksFunc != nil {
+		return m.IncrementBookmarksFunc(ctx, id, delta)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetArchive(ctx context.Context) ([]domain.ArchiveMonth, error) {
+	if m.GetArchiveFunc != nil {
+		return m.GetArchiveFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) CountPublished(ctx context.Context) (int64, error) {
+	if m.CountPublishedFunc != nil {
+		return m.CountPublishedFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockRepository) EachPublished(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+	if m.EachPublishedFunc != nil {
+		return m.EachPublishedFunc(ctx, query, fn)
+	}
+	return nil
+}
+
+func (m *MockRepository) ExistsByOrigin(ctx context.Context, sourceID primitive.ObjectID, guid, link string) (bool, error) {
+	if m.ExistsByOriginFunc != nil {
+		return m.ExistsByOriginFunc(ctx, sourceID, guid, link)
+	}
+	return false, nil
+}
+
+func (m *MockRepository) GetFingerprints(ctx context.Context) ([]domain.PostFingerprint, error) {
+	if m.GetFingerprintsFunc != nil {
+		return m.GetFingerprintsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) SetFingerprint(ctx context.Context, id string, fingerprint int64) error {
+	if m.SetFingerprintFunc != nil {
+		return m.SetFingerprintFunc(ctx, id, fingerprint)
+	}
+	return nil
+}
+
+func (m *MockRepository) SetBreaking(ctx context.Context, id string, breaking *domain.Breaking) error {
+	if m.SetBreakingFunc != nil {
+		return m.SetBreakingFunc(ctx, id, breaking)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetBreaking(ctx context.Context, now time.Time) ([]*domain.Post, error) {
+	if m.GetBreakingFunc != nil {
+		return m.GetBreakingFunc(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetAuthors(ctx context.Context) ([]string, error) {
+	if m.GetAuthorsFunc != nil {
+		return m.GetAuthorsFunc(ctx)
+	}
+	return nil, nil
+}
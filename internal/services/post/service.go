@@ -2,29 +2,214 @@ package post
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/simhash"
+
+	"go.uber.org/zap"
 )
 
+// maxSimilarPosts caps the number of near-duplicates reported for a new post.
+const maxSimilarPosts = 5
+
 type Service struct {
-	repo domain.Repository
+	repo      domain.Repository
+	index     domain.SearchIndex
+	searchLog domain.SearchLogRepository
+	handlers  []domain.PostEventHandler
+	outbox    domain.OutboxRepository
+	tx        domain.Transactor
+	relay     Notifier
+	logger    *zap.Logger
+}
+
+// Notifier is woken up after events have been recorded in the outbox.
+type Notifier interface {
+	Notify()
 }
 
-func NewService(repo domain.Repository) *Service {
-	return &Service{repo: repo}
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithSearchIndex routes text searches to index instead of the repository.
+// The caller is responsible for keeping the index in sync, usually by also
+// registering it with WithEventHandlers.
+func WithSearchIndex(index domain.SearchIndex) Option {
+	return func(s *Service) {
+		s.index = index
+	}
 }
 
-func (s *Service) Create(ctx context.Context, title, content string) (*domain.Post, error) {
-	post, err := domain.NewPost(title, content)
+// WithSearchLog records the text of every first-page search for popularity ranking.
+func WithSearchLog(log domain.SearchLogRepository) Option {
+	return func(s *Service) {
+		s.searchLog = log
+	}
+}
+
+// WithEventHandlers registers handlers notified after every successful mutation.
+func WithEventHandlers(handlers ...domain.PostEventHandler) Option {
+	return func(s *Service) {
+		s.handlers = append(s.handlers, handlers...)
+	}
+}
+
+// WithOutbox records the events of every mutation in outbox, in the same
+// transaction as the mutation, and wakes relay once it has committed. Handlers
+// registered with WithEventHandlers are still notified directly; they should
+// hold state that is rebuilt on startup, since they miss events on a crash.
+func WithOutbox(outbox domain.OutboxRepository, tx domain.Transactor, relay Notifier) Option {
+	return func(s *Service) {
+		s.outbox = outbox
+		s.tx = tx
+		s.relay = relay
+	}
+}
+
+// WithLogger sets the logger used to report event handler failures.
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+func NewService(repo domain.Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:   repo,
+		logger: zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// commit runs write, which persists a mutation and returns the events it
+// caused. With an outbox the events are recorded in the same transaction.
+// Event handlers are notified once the mutation has been persisted.
+func (s *Service) commit(ctx context.Context, write func(ctx context.Context) ([]domain.PostEvent, error)) error {
+	var events []domain.PostEvent
+	if s.outbox == nil {
+		var err error
+		if events, err = write(ctx); err != nil {
+			return err
+		}
+	} else {
+		err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if events, err = write(ctx); err != nil {
+				return err
+			}
+			entries := make([]*domain.OutboxEntry, len(events))
+			for i, e := range events {
+				entries[i] = domain.NewOutboxEntry(e)
+			}
+			if err := s.outbox.Add(ctx, entries...); err != nil {
+				return fmt.Errorf("failed to record post events: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if s.relay != nil {
+			s.relay.Notify()
+		}
+	}
+
+	for _, e := range events {
+		s.publish(ctx, e)
+	}
+	return nil
+}
+
+// newEvent creates an event of type t that occurred now.
+func newEvent(t domain.PostEventType, id string, post *domain.Post) domain.PostEvent {
+	return domain.PostEvent{
+		Type:       t,
+		PostID:     id,
+		Post:       post,
+		OccurredAt: time.Now(),
+	}
+}
+
+// createdEvents returns the events caused by creating post.
+func createdEvents(post *domain.Post) []domain.PostEvent {
+	events := []domain.PostEvent{newEvent(domain.PostCreated, post.ID.Hex(), post)}
+	if post.IsPublished() {
+		events = append(events, newEvent(domain.PostPublished, post.ID.Hex(), post))
+	}
+	return events
+}
+
+// publish notifies event handlers. Handler failures are logged and do not
+// affect the outcome of the mutation that has already been persisted.
+func (s *Service) publish(ctx context.Context, event domain.PostEvent) {
+	for _, h := range s.handlers {
+		if err := h.HandlePostEvent(ctx, event); err != nil {
+			s.logger.Error("post event handler failed",
+				zap.String("event", string(event.Type)),
+				zap.String("post_id", event.PostID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (s *Service) Create(ctx context.Context, in domain.PostInput) (*domain.Post, error) {
+	post, err := domain.NewPostFromInput(in)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
+	post.Fingerprint = int64(fingerprint(post.Content))
+
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.PostEvent, error) {
+		if err := s.repo.Create(ctx, post); err != nil {
+			return nil, fmt.Errorf("failed to save post: %w", err)
+		}
+		return createdEvents(post), nil
+	}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// Import creates a post from an ingested feed item and attributes it to origin.
+// It returns domain.ErrDuplicatePost when a post of the same source with the
+// same origin GUID or link already exists, also when the item is imported
+// concurrently and the repository rejects the second insert.
+func (s *Service) Import(ctx context.Context, in domain.PostInput, origin domain.PostOrigin) (*domain.Post, error) {
+	exists, err := s.repo.ExistsByOrigin(ctx, origin.SourceID, origin.GUID, origin.Link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for imported post: %w", err)
+	}
+	if exists {
+		return nil, domain.ErrDuplicatePost
+	}
 
-	if err := s.repo.Create(ctx, post); err != nil {
-		return nil, fmt.Errorf("failed to save post: %w", err)
+	post, err := domain.NewPostFromInput(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
+	post.Fingerprint = int64(fingerprint(post.Content))
+	post.Origin = &origin
 
+	if err := s.commit(ctx, func(ctx context.Context) ([]domain.PostEvent, error) {
+		err := s.repo.Create(ctx, post)
+		if errors.Is(err, domain.ErrDuplicatePost) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save post: %w", err)
+		}
+		return createdEvents(post), nil
+	}); err != nil {
+		return nil, err
+	}
 	return post, nil
 }
 
@@ -44,45 +229,245 @@ func (s *Service) GetByID(ctx context.Context, id string) (*domain.Post, error)
 	return post, nil
 }
 
-func (s *Service) Update(ctx context.Context, id, title, content string) error {
+func (s *Service) Update(ctx context.Context, id string, in domain.PostInput) error {
 	post, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get post for update: %w", err)
 	}
 
-	if err := post.Update(title, content); err != nil {
+	wasPublished := post.IsPublished()
+	if err := post.UpdateFromInput(in); err != nil {
 		return fmt.Errorf("failed to update post: %w", err)
 	}
+	post.Fingerprint = int64(fingerprint(post.Content))
+
+	return s.commit(ctx, func(ctx context.Context) ([]domain.PostEvent, error) {
+		if err := s.repo.Update(ctx, post); err != nil {
+			return nil, fmt.Errorf("failed to save updated post: %w", err)
+		}
+		events := []domain.PostEvent{newEvent(domain.PostUpdated, post.ID.Hex(), post)}
+		if !wasPublished && post.IsPublished() {
+			events = append(events, newEvent(domain.PostPublished, post.ID.Hex(), post))
+		}
+		return events, nil
+	})
+}
 
-	if err := s.repo.Update(ctx, post); err != nil {
-		return fmt.Errorf("failed to save updated post: %w", err)
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.commit(ctx, func(ctx context.Context) ([]domain.PostEvent, error) {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed to delete post: %w", err)
+		}
+		return []domain.PostEvent{newEvent(domain.PostDeleted, id, nil)}, nil
+	})
+}
+
+// SetBreaking flags the published post as breaking news for d, starting
+// now, or clears the flag when d is zero. It returns the updated post.
+func (s *Service) SetBreaking(ctx context.Context, id string, d time.Duration) (*domain.Post, error) {
+	post, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post for breaking news: %w", err)
 	}
 
-	return nil
+	var breaking *domain.Breaking
+	if d != 0 {
+		if !post.IsPublished() {
+			return nil, domain.ErrBreakingDraft
+		}
+		if breaking, err = domain.NewBreaking(time.Now(), d); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.commit(ctx, func(ctx context.Context) ([]domain.PostEvent, error) {
+		if err := s.repo.SetBreaking(ctx, id, breaking); err != nil {
+			return nil, fmt.Errorf("failed to save breaking news flag: %w", err)
+		}
+		post.Breaking = breaking
+		return []domain.PostEvent{newEvent(domain.PostBreaking, post.ID.Hex(), post)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return post, nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete post: %w", err)
+// GetBreaking returns the posts currently flagged as breaking news, most
+// recently flagged first.
+func (s *Service) GetBreaking(ctx context.Context) ([]*domain.Post, error) {
+	posts, err := s.repo.GetBreaking(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get breaking news: %w", err)
 	}
-	return nil
+	return posts, nil
 }
 
-func (s *Service) GetPaginated(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
-	if page < 1 {
-		page = 1
+func (s *Service) GetPaginated(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = 9
+	}
+
+	if query.Search != "" && query.Page == 1 && query.Cursor == "" && s.searchLog != nil {
+		if err := s.searchLog.Record(ctx, query.Search, time.Now()); err != nil {
+			s.logger.Error("failed to record search term", zap.Error(err))
+		}
 	}
-	if pageSize < 1 {
-		pageSize = 9
+
+	if _, err := domain.ParsePostSort(string(query.Sort)); err != nil {
+		return nil, fmt.Errorf("failed to get paginated posts: %w", err)
+	}
+
+	if query.Search != "" && s.index != nil {
+		return s.search(ctx, query)
 	}
 
-	posts, err := s.repo.GetPaginated(ctx, page, pageSize, search)
+	posts, err := s.repo.GetPaginated(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get paginated posts: %w", err)
 	}
 	return posts, nil
 }
 
+// search resolves a text query through the search index and loads the
+// matching posts in ranking order, attaching the highlights of every hit.
+// Ranked results are paged by number only, so cursors are rejected.
+func (s *Service) search(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+	if query.Cursor != "" {
+		return nil, fmt.Errorf("failed to search posts: %w", domain.ErrInvalidCursor)
+	}
+
+	result, err := s.index.Query(ctx, domain.SearchQuery{
+		Text:     query.Search,
+		Filters:  searchFilters(query),
+		Sort:     query.EffectiveSort(),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search posts: %w", err)
+	}
+
+	ids := make([]string, len(result.Hits))
+	for i, hit := range result.Hits {
+		ids[i] = hit.ID
+	}
+
+	found, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load search results: %w", err)
+	}
+
+	byID := make(map[string]*domain.Post, len(found))
+	for _, p := range found {
+		byID[p.ID.Hex()] = p
+	}
+
+	posts := make([]*domain.Post, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		if p, ok := byID[hit.ID]; ok {
+			p.Highlight = hit.Highlight
+			posts = append(posts, p)
+		}
+	}
+
+	return &domain.PostList{
+		Posts:      posts,
+		TotalCount: int64(result.Total),
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	}, nil
+}
+
+// searchFilters returns the filters of query for the search index.
+func searchFilters(query domain.PostQuery) domain.SearchFilters {
+	return domain.SearchFilters{
+		Category: query.Category,
+		Tags:     query.Tags,
+		Author:   query.Author,
+		Status:   query.Status,
+		From:     query.From,
+		To:       query.To,
+	}
+}
+
+// GetFacets returns the per-value post counts for the filter sidebar. Searches
+// are counted by the search index, like their results.
+func (s *Service) GetFacets(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+	if query.Search != "" && s.index != nil {
+		facets, err := s.index.Facets(ctx, query.Search, searchFilters(query))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get facets: %w", err)
+		}
+		return facets, nil
+	}
+
+	facets, err := s.repo.GetFacets(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get facets: %w", err)
+	}
+	return facets, nil
+}
+
+// RebuildIndexes reloads every post from the repository into the search index
+// and into every registered event handler implementing domain.PostRebuilder.
+func (s *Service) RebuildIndexes(ctx context.Context) error {
+	var rebuilders []domain.PostRebuilder
+	if s.index != nil {
+		rebuilders = append(rebuilders, s.index)
+	}
+	for _, h := range s.handlers {
+		if r, ok := h.(domain.PostRebuilder); ok && !sameRebuilder(rebuilders, r) {
+			rebuilders = append(rebuilders, r)
+		}
+	}
+	if len(rebuilders) == 0 {
+		return nil
+	}
+
+	posts, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load posts for indexing: %w", err)
+	}
+	for _, r := range rebuilders {
+		if err := r.Rebuild(ctx, posts); err != nil {
+			return fmt.Errorf("failed to rebuild index: %w", err)
+		}
+	}
+	return nil
+}
+
+// sameRebuilder reports whether r is already in rebuilders, so that an index
+// registered both as search index and as event handler is rebuilt once.
+func sameRebuilder(rebuilders []domain.PostRebuilder, r domain.PostRebuilder) bool {
+	for _, existing := range rebuilders {
+		if existing == r {
+			return true
+		}
+	}
+	return false
+}
+
+// GetArchive returns the number of posts per month, newest month first.
+func (s *Service) GetArchive(ctx context.Context) ([]domain.ArchiveMonth, error) {
+	months, err := s.repo.GetArchive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archive: %w", err)
+	}
+	return months, nil
+}
+
+// RecordView counts a view of the post with the given id.
+func (s *Service) RecordView(ctx context.Context, id string) error {
+	if err := s.repo.IncrementViews(ctx, id); err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+	return nil
+}
+
 func (s *Service) GetRecent(ctx context.Context, limit int) ([]*domain.Post, error) {
 	if limit < 1 {
 		limit = 5
@@ -94,3 +479,153 @@ func (s *Service) GetRecent(ctx context.Context, limit int) ([]*domain.Post, err
 	}
 	return posts, nil
 }
+
+// CountPublished returns the number of published posts.
+func (s *Service) CountPublished(ctx context.Context) (int64, error) {
+	count, err := s.repo.CountPublished(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count published posts: %w", err)
+	}
+	return count, nil
+}
+
+// EachPublished calls fn for every published post selected by query without
+// loading them all into memory.
+func (s *Service) EachPublished(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+	if err := s.repo.EachPublished(ctx, query, fn); err != nil {
+		return fmt.Errorf("failed to stream published posts: %w", err)
+	}
+	return nil
+}
+
+// fingerprint computes the near-duplicate fingerprint of a story. Headlines
+// are left out because rewrites of the same story rarely share them.
+func fingerprint(content string) uint64 {
+	return simhash.Fingerprint(content)
+}
+
+// FindSimilar returns the stored posts that are near-duplicates of in, closest
+// first, so that editors can be warned before publishing the same story twice.
+func (s *Service) FindSimilar(ctx context.Context, in domain.PostInput) ([]domain.SimilarPost, error) {
+	target := fingerprint(in.Content)
+	if target == 0 {
+		return nil, nil
+	}
+
+	fingerprints, err := s.repo.GetFingerprints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar posts: %w", err)
+	}
+
+	distances := make(map[string]int)
+	var ids []string
+	for _, f := range fingerprints {
+		if d := simhash.Distance(target, uint64(f.Fingerprint)); d <= simhash.MaxDistance {
+			id := f.ID.Hex()
+			distances[id] = d
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return distances[ids[i]] < distances[ids[j]]
+	})
+	if len(ids) > maxSimilarPosts {
+		ids = ids[:maxSimilarPosts]
+	}
+
+	posts, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load similar posts: %w", err)
+	}
+	similar := make([]domain.SimilarPost, 0, len(posts))
+	for _, p := range posts {
+		similar = append(similar, domain.SimilarPost{Post: p, Distance: distances[p.ID.Hex()]})
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Distance < similar[j].Distance
+	})
+	return similar, nil
+}
+
+// GetDuplicateClusters groups all stored near-duplicate posts. Clusters with
+// the most recent post come first.
+func (s *Service) GetDuplicateClusters(ctx context.Context) ([]domain.DuplicateCluster, error) {
+	fingerprints, err := s.repo.GetFingerprints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate clusters: %w", err)
+	}
+
+	values := make([]uint64, len(fingerprints))
+	for i, f := range fingerprints {
+		values[i] = uint64(f.Fingerprint)
+	}
+	groups := simhash.Cluster(values)
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	var ids []string
+	for _, group := range groups {
+		for _, i := range group {
+			ids = append(ids, fingerprints[i].ID.Hex())
+		}
+	}
+	posts, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load duplicate posts: %w", err)
+	}
+	byID := make(map[string]*domain.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID.Hex()] = p
+	}
+
+	clusters := make([]domain.DuplicateCluster, 0, len(groups))
+	for _, group := range groups {
+		var cluster domain.DuplicateCluster
+		for _, i := range group {
+			if p, ok := byID[fingerprints[i].ID.Hex()]; ok {
+				cluster.Posts = append(cluster.Posts, p)
+			}
+		}
+		if len(cluster.Posts) < 2 {
+			continue
+		}
+		sort.SliceStable(cluster.Posts, func(i, j int) bool {
+			return cluster.Posts[i].CreatedAt.Before(cluster.Posts[j].CreatedAt)
+		})
+		clusters = append(clusters, cluster)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := clusters[i].Posts, clusters[j].Posts
+		return a[len(a)-1].CreatedAt.After(b[len(b)-1].CreatedAt)
+	})
+	return clusters, nil
+}
+
+// BackfillFingerprints computes the fingerprint of every post saved without
+// one and returns how many posts were updated.
+func (s *Service) BackfillFingerprints(ctx context.Context) (int, error) {
+	posts, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load posts for fingerprinting: %w", err)
+	}
+
+	updated := 0
+	for _, p := range posts {
+		if p.Fingerprint != 0 {
+			continue
+		}
+		fp := fingerprint(p.Content)
+		if fp == 0 {
+			continue
+		}
+		if err := s.repo.SetFingerprint(ctx, p.ID.Hex(), int64(fp)); err != nil {
+			return updated, fmt.Errorf("failed to backfill fingerprint: %w", err)
+		}
+		updated++
+	}
+	return updated, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kir/news-app/internal/domain"
+	"github.com/kir/news-app/internal/search"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -80,7 +81,7 @@ func TestService_Create(t *testing.T) {
 			}
 			service := NewService(repo)
 
-			post, err := service.Create(context.Background(), tt.title, tt.content)
+			post, err := service.Create(context.Background(), domain.PostInput{Title: tt.title, Content: tt.content})
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -185,7 +186,7 @@ func TestService_Update(t *testing.T) {
 			}
 			service := NewService(repo)
 
-			err := service.Update(context.Background(), tt.id, tt.title, tt.content)
+			err := service.Update(context.Background(), tt.id, domain.PostInput{Title: tt.title, Content: tt.content})
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -203,7 +204,7 @@ func TestService_GetPaginated(t *testing.T) {
 		page             int
 		pageSize         int
 		search           string
-		mockGetPaginated func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error)
+		mockGetPaginated func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error)
 		expectedError    bool
 		expectedList     *domain.PostList
 	}{
@@ -212,7 +213,7 @@ func TestService_GetPaginated(t *testing.T) {
 			page:     1,
 			pageSize: 10,
 			search:   "test",
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return &domain.PostList{
 					Posts: []*domain.Post{
 						{
@@ -224,8 +225,8 @@ func TestService_GetPaginated(t *testing.T) {
 						},
 					},
 					TotalCount: 1,
-					Page:       page,
-					PageSize:   pageSize,
+					Page:       query.Page,
+					PageSize:   query.PageSize,
 				}, nil
 			},
 			expectedError: false,
@@ -246,10 +247,10 @@ func TestService_GetPaginated(t *testing.T) {
 			name:     "invalid page",
 			page:     0,
 			pageSize: 10,
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return &domain.PostList{
 					Page:     1,
-					PageSize: pageSize,
+					PageSize: query.PageSize,
 				}, nil
 			},
 			expectedError: false,
@@ -262,9 +263,9 @@ func TestService_GetPaginated(t *testing.T) {
 			name:     "invalid page size",
 			page:     1,
 			pageSize: 0,
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return &domain.PostList{
-					Page:     page,
+					Page:     query.Page,
 					PageSize: 9,
 				}, nil
 			},
@@ -278,7 +279,7 @@ func TestService_GetPaginated(t *testing.T) {
 			name:     "repository error",
 			page:     1,
 			pageSize: 10,
-			mockGetPaginated: func(ctx context.Context, page, pageSize int, search string) (*domain.PostList, error) {
+			mockGetPaginated: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
 				return nil, errors.New("repository error")
 			},
 			expectedError: true,
@@ -292,7 +293,7 @@ func TestService_GetPaginated(t *testing.T) {
 			}
 			service := NewService(repo)
 
-			list, err := service.GetPaginated(context.Background(), tt.page, tt.pageSize, tt.search)
+			list, err := service.GetPaginated(context.Background(), domain.PostQuery{Page: tt.page, PageSize: tt.pageSize, Search: tt.search})
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -576,3 +577,627 @@ func TestService_GetRecent(t *testing.T) {
 		})
 	}
 }
+
+type recordingHandler struct {
+	events []domain.PostEvent
+	err    error
+}
+
+func (h *recordingHandler) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	h.events = append(h.events, event)
+	return h.err
+}
+
+func TestService_PublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	existing := &domain.Post{
+		ID:      primitive.NewObjectID(),
+		Title:   "Original Title",
+		Content: "Original content",
+	}
+	repo := &MockRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return existing, nil
+		},
+	}
+	handler := &recordingHandler{err: errors.New("handler error")}
+	service := NewService(repo, WithEventHandlers(handler))
+
+	created, err := service.Create(ctx, domain.PostInput{Title: "Test Post", Content: "Test content with more than 10 characters"})
+	require.NoError(t, err)
+	require.NoError(t, service.Update(ctx, existing.ID.Hex(), domain.PostInput{Title: "Updated Title", Content: "Updated content with more than 10 characters"}))
+	require.NoError(t, service.Delete(ctx, existing.ID.Hex()))
+
+	require.Len(t, handler.events, 4)
+	assert.Equal(t, domain.PostCreated, handler.events[0].Type)
+	assert.Equal(t, created.ID.Hex(), handler.events[0].PostID)
+	assert.Same(t, created, handler.events[0].Post)
+	assert.Equal(t, domain.PostPublished, handler.events[1].Type)
+	assert.Same(t, created, handler.events[1].Post)
+	assert.Equal(t, domain.PostUpdated, handler.events[2].Type)
+	assert.Equal(t, "Updated Title", handler.events[2].Post.Title)
+	assert.Equal(t, domain.PostDeleted, handler.events[3].Type)
+	assert.Nil(t, handler.events[3].Post)
+}
+
+func TestService_PublishesPublishedOnTransition(t *testing.T) {
+	ctx := context.Background()
+	existing := &domain.Post{
+		ID:      primitive.NewObjectID(),
+		Title:   "Draft Title",
+		Content: "Draft content with more than 10 characters",
+		Status:  domain.PostStatusDraft,
+	}
+	repo := &MockRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return existing, nil
+		},
+	}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithEventHandlers(handler))
+
+	_, err := service.Create(ctx, domain.PostInput{Title: "Draft Post", Content: "Draft content with more than 10 characters", Status: domain.PostStatusDraft})
+	require.NoError(t, err)
+	draft := domain.PostInput{Title: "Draft Title", Content: "Draft content with more than 10 characters", Status: domain.PostStatusDraft}
+	require.NoError(t, service.Update(ctx, existing.ID.Hex(), draft))
+	published := draft
+	published.Status = domain.PostStatusPublished
+	require.NoError(t, service.Update(ctx, existing.ID.Hex(), published))
+	require.NoError(t, service.Update(ctx, existing.ID.Hex(), published))
+
+	var types []domain.PostEventType
+	for _, e := range handler.events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []domain.PostEventType{
+		domain.PostCreated,
+		domain.PostUpdated,
+		domain.PostUpdated, domain.PostPublished,
+		domain.PostUpdated,
+	}, types)
+}
+
+func TestService_EventsNotPublishedOnFailure(t *testing.T) {
+	repo := &MockRepository{
+		CreateFunc: func(ctx context.Context, p *domain.Post) error {
+			return errors.New("repository error")
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			return errors.New("repository error")
+		},
+	}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithEventHandlers(handler))
+
+	_, err := service.Create(context.Background(), domain.PostInput{Title: "Test Post", Content: "Test content with more than 10 characters"})
+	assert.Error(t, err)
+	assert.Error(t, service.Delete(context.Background(), primitive.NewObjectID().Hex()))
+	assert.Empty(t, handler.events)
+}
+
+type txKey struct{}
+
+// fakeTransactor marks the context of fn so that writes can be checked to
+// happen inside the transaction. A failed fn is counted as rolled back.
+type fakeTransactor struct {
+	rollbacks int
+}
+
+func (tx *fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(context.WithValue(ctx, txKey{}, true))
+	if err != nil {
+		tx.rollbacks++
+	}
+	return err
+}
+
+type fakeOutbox struct {
+	err     error
+	entries []*domain.OutboxEntry
+	inTx    bool
+}
+
+func (o *fakeOutbox) Add(ctx context.Context, entries ...*domain.OutboxEntry) error {
+	o.inTx = ctx.Value(txKey{}) != nil
+	if o.err != nil {
+		return o.err
+	}
+	o.entries = append(o.entries, entries...)
+	return nil
+}
+
+func (o *fakeOutbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.OutboxEntry, error) {
+	return nil, nil
+}
+
+func (o *fakeOutbox) Save(ctx context.Context, entry *domain.OutboxEntry) error {
+	return nil
+}
+
+type countingNotifier struct {
+	calls int
+}
+
+func (n *countingNotifier) Notify() {
+	n.calls++
+}
+
+func TestService_RecordsEventsInOutbox(t *testing.T) {
+	ctx := context.Background()
+	var createdInTx bool
+	repo := &MockRepository{
+		CreateFunc: func(ctx context.Context, p *domain.Post) error {
+			createdInTx = ctx.Value(txKey{}) != nil
+			p.ID = primitive.NewObjectID()
+			return nil
+		},
+	}
+	outbox := &fakeOutbox{}
+	tx := &fakeTransactor{}
+	relay := &countingNotifier{}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithOutbox(outbox, tx, relay), WithEventHandlers(handler))
+
+	created, err := service.Create(ctx, domain.PostInput{Title: "Test Post", Content: "Test content with more than 10 characters"})
+	require.NoError(t, err)
+
+	assert.True(t, createdInTx)
+	assert.True(t, outbox.inTx)
+	require.Len(t, outbox.entries, 2)
+	assert.Equal(t, domain.PostCreated, outbox.entries[0].Event)
+	assert.Equal(t, domain.PostPublished, outbox.entries[1].Event)
+	assert.Equal(t, created.ID.Hex(), outbox.entries[0].PostID)
+	assert.Same(t, created, outbox.entries[0].Post)
+	assert.Equal(t, 1, relay.calls)
+	assert.Len(t, handler.events, 2, "in-process handlers are still notified directly")
+}
+
+func TestService_OutboxFailureRollsBack(t *testing.T) {
+	repo := &MockRepository{}
+	outbox := &fakeOutbox{err: errors.New("outbox error")}
+	tx := &fakeTransactor{}
+	relay := &countingNotifier{}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithOutbox(outbox, tx, relay), WithEventHandlers(handler))
+
+	_, err := service.Create(context.Background(), domain.PostInput{Title: "Test Post", Content: "Test content with more than 10 characters"})
+	assert.ErrorIs(t, err, outbox.err)
+	assert.Error(t, service.Delete(context.Background(), primitive.NewObjectID().Hex()))
+
+	assert.Equal(t, 2, tx.rollbacks)
+	assert.Zero(t, relay.calls)
+	assert.Empty(t, handler.events)
+}
+
+func TestService_GetPaginatedWithSearchIndex(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	posts := []*domain.Post{
+		{ID: primitive.NewObjectID(), Title: "Weekly roundup", Content: "The election drew record turnout.", CreatedAt: now},
+		{ID: primitive.NewObjectID(), Title: "Election night", Content: "Polls closed at eight.", CreatedAt: now},
+		{ID: primitive.NewObjectID(), Title: "Weather", Content: "Sunny skies all week long.", CreatedAt: now},
+	}
+	byID := make(map[string]*domain.Post)
+	for _, p := range posts {
+		byID[p.ID.Hex()] = p
+	}
+
+	repo := &MockRepository{
+		GetAllFunc: func(ctx context.Context) ([]*domain.Post, error) {
+			return posts, nil
+		},
+		GetByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Post, error) {
+			var found []*domain.Post
+			// Return in reverse order to verify the service restores ranking order.
+			for i := len(ids) - 1; i >= 0; i-- {
+				if p, ok := byID[ids[i]]; ok {
+					found = append(found, p)
+				}
+			}
+			return found, nil
+		},
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			t.Fatal("repository search must not be used when an index is configured")
+			return nil, nil
+		},
+		GetFacetsFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostFacets, error) {
+			t.Fatal("search facets must be counted by the index")
+			return nil, nil
+		},
+	}
+	index := search.NewIndex()
+	suggester := search.NewSuggester()
+	service := NewService(repo, WithSearchIndex(index), WithEventHandlers(index, suggester))
+	require.NoError(t, service.RebuildIndexes(ctx))
+	assert.Equal(t, 3, index.Len())
+	assert.Len(t, suggester.Complete("elec", 5), 1)
+
+	list, err := service.GetPaginated(ctx, domain.PostQuery{Page: 1, PageSize: 10, Search: "elections"})
+	require.NoError(t, err)
+	require.Len(t, list.Posts, 2)
+	assert.Equal(t, int64(2), list.TotalCount)
+	assert.Equal(t, "Election night", list.Posts[0].Title)
+	assert.Equal(t, "Weekly roundup", list.Posts[1].Title)
+	require.NotNil(t, list.Posts[1].Highlight)
+	assert.Contains(t, list.Posts[1].Highlight.Snippet, domain.TextFragment{Text: "election", Match: true})
+
+	_, err = service.GetPaginated(ctx, domain.PostQuery{Search: "election", Cursor: "abc"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	facets, err := service.GetFacets(ctx, domain.PostQuery{Search: "elections"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FacetValue{{Value: string(domain.PostStatusPublished), Count: 2}}, facets.Statuses,
+		"facets count the stemmed matches listed above")
+
+	require.NoError(t, service.Delete(ctx, posts[1].ID.Hex()))
+	list, err = service.GetPaginated(ctx, domain.PostQuery{Page: 1, PageSize: 10, Search: "election"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), list.TotalCount)
+}
+
+func TestService_GetPaginatedRecordsSearches(t *testing.T) {
+	var recorded []string
+	log := &mockSearchLog{record: func(term string) { recorded = append(recorded, term) }}
+	repo := &MockRepository{
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			return &domain.PostList{Page: query.Page, PageSize: query.PageSize}, nil
+		},
+	}
+	service := NewService(repo, WithSearchLog(log))
+
+	for _, q := range []domain.PostQuery{
+		{Page: 1, Search: "budget"},
+		{Page: 2, Search: "budget"},
+		{Page: 1},
+	} {
+		_, err := service.GetPaginated(context.Background(), q)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"budget"}, recorded)
+}
+
+type mockSearchLog struct {
+	record func(term string)
+}
+
+func (m *mockSearchLog) Record(ctx context.Context, term string, at time.Time) error {
+	m.record(term)
+	return nil
+}
+
+func (m *mockSearchLog) Popular(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+	return nil, nil
+}
+
+func TestService_RecordView(t *testing.T) {
+	var incremented []string
+	repo := &MockRepository{
+		IncrementViewsFunc: func(ctx context.Context, id string) error {
+			if id == "missing" {
+				return errors.New("post not found")
+			}
+			incremented = append(incremented, id)
+			return nil
+		},
+	}
+	service := NewService(repo)
+
+	assert.NoError(t, service.RecordView(context.Background(), "abc"))
+	assert.Error(t, service.RecordView(context.Background(), "missing"))
+	assert.Equal(t, []string{"abc"}, incremented)
+}
+
+func TestService_GetPaginatedInvalidSort(t *testing.T) {
+	repo := &MockRepository{
+		GetPaginatedFunc: func(ctx context.Context, query domain.PostQuery) (*domain.PostList, error) {
+			t.Fatal("repository must not be queried with an invalid sort")
+			return nil, nil
+		},
+	}
+	service := NewService(repo)
+
+	_, err := service.GetPaginated(context.Background(), domain.PostQuery{Sort: "popular"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSort)
+}
+
+func TestService_GetArchive(t *testing.T) {
+	months := []domain.ArchiveMonth{{Year: 2025, Month: time.March, Count: 4}}
+	repo := &MockRepository{
+		GetArchiveFunc: func(ctx context.Context) ([]domain.ArchiveMonth, error) {
+			return months, nil
+		},
+	}
+
+	got, err := NewService(repo).GetArchive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, months, got)
+
+	repo.GetArchiveFunc = func(ctx context.Context) ([]domain.ArchiveMonth, error) {
+		return nil, errors.New("repository error")
+	}
+	_, err = NewService(repo).GetArchive(context.Background())
+	assert.Error(t, err)
+}
+
+func TestService_EachPublished(t *testing.T) {
+	posts := []*domain.Post{{Title: "First"}, {Title: "Second"}}
+	stop := errors.New("stop")
+	var gotQuery domain.SitemapQuery
+	repo := &MockRepository{
+		EachPublishedFunc: func(ctx context.Context, query domain.SitemapQuery, fn func(*domain.Post) error) error {
+			gotQuery = query
+			for _, p := range posts {
+				if err := fn(p); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	service := NewService(repo)
+
+	var titles []string
+	query := domain.SitemapQuery{Offset: 10, Limit: 5}
+	err := service.EachPublished(context.Background(), query, func(p *domain.Post) error {
+		titles = append(titles, p.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, titles)
+	assert.Equal(t, query, gotQuery)
+
+	err = service.EachPublished(context.Background(), query, func(p *domain.Post) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestService_Import(t *testing.T) {
+	ctx := context.Background()
+	origin := domain.PostOrigin{SourceID: primitive.NewObjectID(), Name: "Wire", GUID: "item-1", Link: "https://wire.example/1"}
+	input := domain.PostInput{Title: "Imported story", Content: "Content of the imported story", Status: domain.PostStatusDraft}
+
+	var created *domain.Post
+	var gotSource primitive.ObjectID
+	var gotGUID, gotLink string
+	existing := map[string]bool{}
+	repo := &MockRepository{
+		ExistsByOriginFunc: func(ctx context.Context, sourceID primitive.ObjectID, guid, link string) (bool, error) {
+			gotSource, gotGUID, gotLink = sourceID, guid, link
+			return existing[guid], nil
+		},
+		CreateFunc: func(ctx context.Context, p *domain.Post) error {
+			created = p
+			return nil
+		},
+	}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithEventHandlers(handler))
+
+	post, err := service.Import(ctx, input, origin)
+	require.NoError(t, err)
+	assert.Equal(t, origin.SourceID, gotSource)
+	assert.Equal(t, "item-1", gotGUID)
+	assert.Equal(t, "https://wire.example/1", gotLink)
+	assert.Same(t, created, post)
+	require.NotNil(t, post.Origin)
+	assert.Equal(t, origin, *post.Origin)
+	assert.Equal(t, domain.PostStatusDraft, post.Status)
+	require.Len(t, handler.events, 1)
+	assert.Equal(t, domain.PostCreated, handler.events[0].Type)
+
+	existing["item-1"] = true
+	created = nil
+	_, err = service.Import(ctx, input, origin)
+	assert.ErrorIs(t, err, domain.ErrDuplicatePost)
+	assert.Nil(t, created)
+	assert.Len(t, handler.events, 1)
+
+	_, err = service.Import(ctx, domain.PostInput{Title: "A"}, domain.PostOrigin{GUID: "item-2"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTitle)
+
+	// A concurrent import of the same item loses the race on the unique index.
+	repo.CreateFunc = func(ctx context.Context, p *domain.Post) error {
+		return domain.ErrDuplicatePost
+	}
+	_, err = service.Import(ctx, input, domain.PostOrigin{SourceID: origin.SourceID, GUID: "item-3"})
+	assert.ErrorIs(t, err, domain.ErrDuplicatePost)
+	assert.Len(t, handler.events, 1)
+}
+
+const (
+	budgetStory = "The city council approved a new budget on Tuesday that increases funding for public transport by 12 percent. " +
+		"The mayor said the extra money would be used to buy electric buses and extend night service on the busiest routes."
+	budgetRewrite = "The city council on Tuesday approved a new budget increasing public transport funding by 12 percent. " +
+		"The mayor said the extra money will buy electric buses and extend night service on the busiest routes."
+	frogStory = "Scientists have discovered a new species of frog in the rainforests of Ecuador. " +
+		"The tiny amphibian was found during an expedition last spring and is believed to be endangered."
+)
+
+// fingerprintRepo serves posts and their fingerprints from memory.
+func fingerprintRepo(posts ...*domain.Post) *MockRepository {
+	return &MockRepository{
+		GetFingerprintsFunc: func(ctx context.Context) ([]domain.PostFingerprint, error) {
+			fingerprints := make([]domain.PostFingerprint, len(posts))
+			for i, p := range posts {
+				fingerprints[i] = domain.PostFingerprint{ID: p.ID, Fingerprint: p.Fingerprint}
+			}
+			return fingerprints, nil
+		},
+		GetByIDsFunc: func(ctx context.Context, ids []string) ([]*domain.Post, error) {
+			var found []*domain.Post
+			for _, p := range posts {
+				for _, id := range ids {
+					if p.ID.Hex() == id {
+						found = append(found, p)
+					}
+				}
+			}
+			return found, nil
+		},
+	}
+}
+
+func storedPost(title, content string, createdAt time.Time) *domain.Post {
+	return &domain.Post{
+		ID:          primitive.NewObjectID(),
+		Title:       title,
+		Content:     content,
+		CreatedAt:   createdAt,
+		Fingerprint: int64(fingerprint(content)),
+	}
+}
+
+func TestService_SetsFingerprint(t *testing.T) {
+	ctx := context.Background()
+	var saved *domain.Post
+	repo := &MockRepository{
+		CreateFunc: func(ctx context.Context, p *domain.Post) error {
+			saved = p
+			return nil
+		},
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			return saved, nil
+		},
+		UpdateFunc: func(ctx context.Context, p *domain.Post) error {
+			saved = p
+			return nil
+		},
+	}
+	service := NewService(repo)
+
+	post, err := service.Create(ctx, domain.PostInput{Title: "Budget approved", Content: budgetStory})
+	require.NoError(t, err)
+	assert.NotZero(t, post.Fingerprint)
+	created := post.Fingerprint
+
+	require.NoError(t, service.Update(ctx, post.ID.Hex(), domain.PostInput{Title: "New frog species", Content: frogStory}))
+	assert.NotZero(t, saved.Fingerprint)
+	assert.NotEqual(t, created, saved.Fingerprint)
+}
+
+func TestService_FindSimilar(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	original := storedPost("Budget approved", budgetStory, now.Add(-time.Hour))
+	copied := storedPost("Budget approved", budgetStory, now)
+	frog := storedPost("New frog species", frogStory, now)
+	service := NewService(fingerprintRepo(frog, original, copied))
+
+	similar, err := service.FindSimilar(ctx, domain.PostInput{Title: "Council passes budget", Content: budgetRewrite})
+	require.NoError(t, err)
+	require.Len(t, similar, 2)
+	for _, s := range similar {
+		assert.Contains(t, []*domain.Post{original, copied}, s.Post)
+		assert.LessOrEqual(t, s.Distance, 6)
+		assert.Greater(t, s.Similarity(), 90)
+	}
+
+	similar, err = service.FindSimilar(ctx, domain.PostInput{Title: "New frog species", Content: frogStory})
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Same(t, frog, similar[0].Post)
+	assert.Equal(t, 0, similar[0].Distance)
+	assert.Equal(t, 100, similar[0].Similarity())
+
+	similar, err = service.FindSimilar(ctx, domain.PostInput{})
+	require.NoError(t, err)
+	assert.Empty(t, similar)
+
+	failing := &MockRepository{
+		GetFingerprintsFunc: func(ctx context.Context) ([]domain.PostFingerprint, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	_, err = NewService(failing).FindSimilar(ctx, domain.PostInput{Title: "Budget", Content: budgetStory})
+	assert.Error(t, err)
+}
+
+func TestService_GetDuplicateClusters(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	budgetOld := storedPost("Budget approved", budgetStory, now.Add(-3*time.Hour))
+	budgetNew := storedPost("Council passes budget", budgetRewrite, now.Add(-2*time.Hour))
+	frogOld := storedPost("New frog species", frogStory, now.Add(-time.Hour))
+	frogNew := storedPost("New frog species", frogStory, now)
+	single := storedPost("Weather", "Sunny skies are expected all week across the region", now)
+	service := NewService(fingerprintRepo(budgetNew, frogNew, single, budgetOld, frogOld))
+
+	clusters, err := service.GetDuplicateClusters(ctx)
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	assert.Equal(t, []*domain.Post{frogOld, frogNew}, clusters[0].Posts)
+	assert.Equal(t, []*domain.Post{budgetOld, budgetNew}, clusters[1].Posts)
+
+	clusters, err = NewService(fingerprintRepo(single)).GetDuplicateClusters(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, clusters)
+}
+
+func TestService_BackfillFingerprints(t *testing.T) {
+	ctx := context.Background()
+	done := storedPost("Budget approved", budgetStory, time.Now())
+	missing := &domain.Post{ID: primitive.NewObjectID(), Title: "New frog species", Content: frogStory}
+	set := map[string]int64{}
+	repo := &MockRepository{
+		GetAllFunc: func(ctx context.Context) ([]*domain.Post, error) {
+			return []*domain.Post{done, missing}, nil
+		},
+		SetFingerprintFunc: func(ctx context.Context, id string, fingerprint int64) error {
+			set[id] = fingerprint
+			return nil
+		},
+	}
+
+	updated, err := NewService(repo).BackfillFingerprints(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, map[string]int64{missing.ID.Hex(): int64(fingerprint(missing.Content))}, set)
+}
+
+func TestService_SetBreaking(t *testing.T) {
+	ctx := context.Background()
+	published := &domain.Post{ID: primitive.NewObjectID(), Title: "Earthquake", Content: "A strong earthquake was felt.", Status: domain.PostStatusPublished}
+	draft := &domain.Post{ID: primitive.NewObjectID(), Title: "Draft", Content: "Draft content here.", Status: domain.PostStatusDraft}
+
+	var saved *domain.Breaking
+	var cleared bool
+	repo := &MockRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.Post, error) {
+			if id == draft.ID.Hex() {
+				return draft, nil
+			}
+			return published, nil
+		},
+		SetBreakingFunc: func(ctx context.Context, id string, breaking *domain.Breaking) error {
+			saved = breaking
+			cleared = breaking == nil
+			return nil
+		},
+	}
+	handler := &recordingHandler{}
+	service := NewService(repo, WithEventHandlers(handler))
+
+	post, err := service.SetBreaking(ctx, published.ID.Hex(), time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), saved.Until, time.Second)
+	assert.True(t, post.IsBreaking())
+	require.Len(t, handler.events, 1)
+	assert.Equal(t, domain.PostBreaking, handler.events[0].Type)
+	assert.Same(t, post, handler.events[0].Post)
+
+	post, err = service.SetBreaking(ctx, published.ID.Hex(), 0)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	assert.Nil(t, post.Breaking)
+	require.Len(t, handler.events, 2)
+	assert.Nil(t, handler.events[1].Post.Breaking)
+
+	_, err = service.SetBreaking(ctx, published.ID.Hex(), time.Minute)
+	assert.ErrorIs(t, err, domain.ErrInvalidBreakingDuration)
+	_, err = service.SetBreaking(ctx, draft.ID.Hex(), time.Hour)
+	assert.ErrorIs(t, err, domain.ErrBreakingDraft)
+	assert.Len(t, handler.events, 2)
+}
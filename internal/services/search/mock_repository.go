@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockSearchLogRepository is a mock implementation of domain.SearchLogRepository
+type MockSearchLogRepository struct {
+	RecordFunc  func(ctx context.Context, term string, at time.Time) error
+	PopularFunc func(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error)
+}
+
+func (m *MockSearchLogRepository) Record(ctx context.Context, term string, at time.Time) error {
+	if m.RecordFunc != nil {
+		return m.RecordFunc(ctx, term, at)
+	}
+	return nil
+}
+
+func (m *MockSearchLogRepository) Popular(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+	if m.PopularFunc != nil {
+		return m.PopularFunc(ctx, prefix, since, limit)
+	}
+	return nil, nil
+}
+
+// MockTitleCompleter is a mock implementation of TitleCompleter
+type MockTitleCompleter struct {
+	CompleteFunc func(text string, limit int) []domain.TitleSuggestion
+}
+
+func (m *MockTitleCompleter) Complete(text string, limit int) []domain.TitleSuggestion {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(text, limit)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// popularWindow is how far back searches count towards popular queries.
+const popularWindow = 7 * 24 * time.Hour
+
+// TitleCompleter completes partially typed text against post titles.
+type TitleCompleter interface {
+	Complete(text string, limit int) []domain.TitleSuggestion
+}
+
+type Service struct {
+	completer TitleCompleter
+	log       domain.SearchLogRepository
+}
+
+func NewService(completer TitleCompleter, log domain.SearchLogRepository) *Service {
+	return &Service{
+		completer: completer,
+		log:       log,
+	}
+}
+
+// Suggest returns title completions and popular past queries starting with text.
+func (s *Service) Suggest(ctx context.Context, text string, limit int) (*domain.Suggestions, error) {
+	if limit < 1 {
+		limit = 5
+	}
+
+	text = strings.TrimLeft(text, " ")
+	suggestions := &domain.Suggestions{Query: text}
+	if strings.TrimSpace(text) == "" {
+		return suggestions, nil
+	}
+
+	suggestions.Titles = s.completer.Complete(text, limit)
+
+	popular, err := s.log.Popular(ctx, text, time.Now().Add(-popularWindow), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+	suggestions.Popular = popular
+
+	return suggestions, nil
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Suggest(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		mockPopular   func(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error)
+		expectedError bool
+		wantTitles    int
+		wantPopular   int
+	}{
+		{
+			name: "titles and popular queries",
+			text: "elec",
+			mockPopular: func(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+				assert.Equal(t, "elec", prefix)
+				assert.WithinDuration(t, time.Now().Add(-popularWindow), since, time.Minute)
+				return []domain.PopularQuery{{Term: "election results", Count: 12}}, nil
+			},
+			wantTitles:  1,
+			wantPopular: 1,
+		},
+		{
+			name: "blank text",
+			text: "   ",
+			mockPopular: func(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+				t.Fatal("blank text must not query the log")
+				return nil, nil
+			},
+		},
+		{
+			name: "repository error",
+			text: "elec",
+			mockPopular: func(ctx context.Context, prefix string, since time.Time, limit int) ([]domain.PopularQuery, error) {
+				return nil, errors.New("repository error")
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completer := &MockTitleCompleter{
+				CompleteFunc: func(text string, limit int) []domain.TitleSuggestion {
+					assert.Equal(t, 5, limit)
+					return []domain.TitleSuggestion{{ID: "1", Title: "Election night"}}
+				},
+			}
+			service := NewService(completer, &MockSearchLogRepository{PopularFunc: tt.mockPopular})
+
+			suggestions, err := service.Suggest(context.Background(), tt.text, 0)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, suggestions)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, suggestions.Titles, tt.wantTitles)
+			assert.Len(t, suggestions.Popular, tt.wantPopular)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockRepository is a mock implementation of domain.SourceRepository
+type MockRepository struct {
+	CreateFunc         func(ctx context.Context, source *domain.Source) error
+	GetAllFunc         func(ctx context.Context) ([]*domain.Source, error)
+	GetDueFunc         func(ctx context.Context, now time.Time) ([]*domain.Source, error)
+	SaveFetchStateFunc func(ctx context.Context, source *domain.Source) error
+}
+
+func (m *MockRepository) Create(ctx context.Context, source *domain.Source) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, source)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetAll(ctx context.Context) ([]*domain.Source, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) GetDue(ctx context.Context, now time.Time) ([]*domain.Source, error) {
+	if m.GetDueFunc != nil {
+		return m.GetDueFunc(ctx, now)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) SaveFetchState(ctx context.Context, source *domain.Source) error {
+	if m.SaveFetchStateFunc != nil {
+		return m.SaveFetchStateFunc(ctx, source)
+	}
+	return nil
+}
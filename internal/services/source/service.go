@@ -0,0 +1,37 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+type Service struct {
+	repo domain.SourceRepository
+}
+
+func NewService(repo domain.SourceRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Create registers a feed source. It is fetched on the next ingestion tick.
+func (s *Service) Create(ctx context.Context, in domain.SourceInput) (*domain.Source, error) {
+	source, err := domain.NewSource(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, source); err != nil {
+		return nil, fmt.Errorf("failed to save source: %w", err)
+	}
+	return source, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*domain.Source, error) {
+	sources, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+	return sources, nil
+}
@@ -0,0 +1,78 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     domain.SourceInput
+		createErr error
+		wantErr   error
+	}{
+		{
+			name:  "valid source",
+			input: domain.SourceInput{Name: "Wire", URL: "https://wire.example/rss", Interval: 30 * time.Minute},
+		},
+		{
+			name:    "invalid URL",
+			input:   domain.SourceInput{Name: "Wire", URL: "wire.example"},
+			wantErr: domain.ErrInvalidSourceURL,
+		},
+		{
+			name:      "duplicate URL",
+			input:     domain.SourceInput{Name: "Wire", URL: "https://wire.example/rss"},
+			createErr: domain.ErrDuplicateSource,
+			wantErr:   domain.ErrDuplicateSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *domain.Source
+			repo := &MockRepository{
+				CreateFunc: func(ctx context.Context, source *domain.Source) error {
+					saved = source
+					return tt.createErr
+				},
+			}
+
+			source, err := NewService(repo).Create(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, saved, source)
+			assert.Equal(t, tt.input.Interval, source.Interval)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	sources := []*domain.Source{{Name: "Wire"}}
+	repo := &MockRepository{
+		GetAllFunc: func(ctx context.Context) ([]*domain.Source, error) {
+			return sources, nil
+		},
+	}
+
+	got, err := NewService(repo).List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, sources, got)
+
+	repo.GetAllFunc = func(ctx context.Context) ([]*domain.Source, error) {
+		return nil, errors.New("repository error")
+	}
+	_, err = NewService(repo).List(context.Background())
+	assert.Error(t, err)
+}
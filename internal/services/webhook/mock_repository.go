@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// MockRepository is a mock implementation of domain.WebhookRepository
+type MockRepository struct {
+	CreateFunc func(ctx context.Context, hook *domain.Webhook) error
+	GetAllFunc func(ctx context.Context) ([]*domain.Webhook, error)
+	DeleteFunc func(ctx context.Context, id string) error
+}
+
+func (m *MockRepository) Create(ctx context.Context, hook *domain.Webhook) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, hook)
+	}
+	return nil
+}
+
+func (m *MockRepository) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+// MockDeliveryRepository is a mock implementation of domain.WebhookDeliveryRepository
+type MockDeliveryRepository struct {
+	CreateFunc    func(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetByIDFunc   func(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	GetRecentFunc func(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error)
+	ClaimDueFunc  func(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error)
+	SaveFunc      func(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+func (m *MockDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, delivery)
+	}
+	return nil
+}
+
+func (m *MockDeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockDeliveryRepository) GetRecent(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	if m.GetRecentFunc != nil {
+		return m.GetRecentFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	if m.ClaimDueFunc != nil {
+		return m.ClaimDueFunc(ctx, now, lease)
+	}
+	return nil, nil
+}
+
+func (m *MockDeliveryRepository) Save(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, delivery)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kir/news-app/internal/domain"
+)
+
+// recentDeliveries is the number of deliveries shown in the delivery log.
+const recentDeliveries = 50
+
+// Notifier is told when a delivery was queued outside of post events, so that
+// it is sent without waiting for the next poll.
+type Notifier interface {
+	Notify()
+}
+
+type Service struct {
+	webhooks   domain.WebhookRepository
+	deliveries domain.WebhookDeliveryRepository
+	notifier   Notifier
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithNotifier wakes the delivery workers after a redelivery is queued.
+func WithNotifier(n Notifier) Option {
+	return func(s *Service) {
+		s.notifier = n
+	}
+}
+
+func NewService(webhooks domain.WebhookRepository, deliveries domain.WebhookDeliveryRepository, opts ...Option) *Service {
+	s := &Service{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create subscribes a URL to post events and generates its signing secret.
+func (s *Service) Create(ctx context.Context, in domain.WebhookInput) (*domain.Webhook, error) {
+	hook, err := domain.NewWebhook(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	if err := s.webhooks.Create(ctx, hook); err != nil {
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]*domain.Webhook, error) {
+	hooks, err := s.webhooks.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// Delete removes a subscription. Its pending deliveries are still attempted.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if err := s.webhooks.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// RecentDeliveries returns the latest deliveries, newest first, without payloads.
+func (s *Service) RecentDeliveries(ctx context.Context) ([]*domain.WebhookDelivery, error) {
+	deliveries, err := s.deliveries.GetRecent(ctx, recentDeliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *Service) GetDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	delivery, err := s.deliveries.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a new delivery with the payload of the delivery with the
+// given id. The original delivery is kept in the log unchanged.
+func (s *Service) Redeliver(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	original, err := s.deliveries.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	delivery := original.Redelivery()
+	if err := s.deliveries.Create(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	if s.notifier != nil {
+		s.notifier.Notify()
+	}
+	return delivery, nil
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type countingNotifier struct {
+	calls int
+}
+
+func (n *countingNotifier) Notify() {
+	n.calls++
+}
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     domain.WebhookInput
+		createErr error
+		wantErr   error
+	}{
+		{
+			name:  "valid webhook",
+			input: domain.WebhookInput{URL: "https://hooks.example/news", Events: []domain.PostEventType{domain.PostPublished}},
+		},
+		{
+			name:    "invalid URL",
+			input:   domain.WebhookInput{URL: "hooks.example"},
+			wantErr: domain.ErrInvalidWebhookURL,
+		},
+		{
+			name:    "unknown event",
+			input:   domain.WebhookInput{URL: "https://hooks.example/news", Events: []domain.PostEventType{"post.viewed"}},
+			wantErr: domain.ErrInvalidWebhookEvent,
+		},
+		{
+			name:      "repository error",
+			input:     domain.WebhookInput{URL: "https://hooks.example/news"},
+			createErr: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *domain.Webhook
+			repo := &MockRepository{
+				CreateFunc: func(ctx context.Context, hook *domain.Webhook) error {
+					saved = hook
+					return tt.createErr
+				},
+			}
+			service := NewService(repo, &MockDeliveryRepository{})
+
+			hook, err := service.Create(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, saved)
+				return
+			}
+			if tt.createErr != nil {
+				assert.ErrorIs(t, err, tt.createErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, saved, hook)
+			assert.NotEmpty(t, hook.Secret)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	repo := &MockRepository{
+		DeleteFunc: func(ctx context.Context, id string) error {
+			return domain.ErrWebhookNotFound
+		},
+	}
+	err := NewService(repo, &MockDeliveryRepository{}).Delete(context.Background(), "missing")
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+}
+
+func TestService_Redeliver(t *testing.T) {
+	ctx := context.Background()
+	original := &domain.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		URL:       "https://hooks.example/news",
+		Event:     domain.PostCreated,
+		Payload:   `{"event":"post.created"}`,
+		Signature: "sha256=abc",
+		Status:    domain.DeliveryFailed,
+		Attempts:  domain.MaxDeliveryAttempts,
+	}
+	var created *domain.WebhookDelivery
+	deliveries := &MockDeliveryRepository{
+		GetByIDFunc: func(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+			if id != original.ID.Hex() {
+				return nil, domain.ErrDeliveryNotFound
+			}
+			return original, nil
+		},
+		CreateFunc: func(ctx context.Context, d *domain.WebhookDelivery) error {
+			created = d
+			return nil
+		},
+	}
+	notifier := &countingNotifier{}
+	service := NewService(&MockRepository{}, deliveries, WithNotifier(notifier))
+
+	delivery, err := service.Redeliver(ctx, original.ID.Hex())
+	require.NoError(t, err)
+	assert.Same(t, created, delivery)
+	assert.NotEqual(t, original.ID, delivery.ID)
+	assert.Equal(t, original.Payload, delivery.Payload)
+	assert.Equal(t, original.Signature, delivery.Signature)
+	assert.Equal(t, domain.DeliveryPending, delivery.Status)
+	assert.Equal(t, domain.DeliveryFailed, original.Status)
+	assert.Equal(t, 1, notifier.calls)
+
+	_, err = service.Redeliver(ctx, primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, domain.ErrDeliveryNotFound)
+	assert.Equal(t, 1, notifier.calls)
+}
@@ -0,0 +1,115 @@
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+
+	"github.com/kir/news-app/internal/search"
+)
+
+// MaxDistance is the largest Hamming distance between two fingerprints that
+// still counts as a near-duplicate. Clusters relies on it being below bands.
+const MaxDistance = 6
+
+// bands is the number of 8-bit blocks a fingerprint is split into when
+// clustering. Two fingerprints at most MaxDistance bits apart agree on at least
+// one block, so only fingerprints sharing a block have to be compared.
+const bands = 8
+
+// Fingerprint computes the 64-bit SimHash of text. The text is analyzed like
+// search queries, so inflections and stop words do not change the result, and
+// every occurrence of a term adds its hash to the weights. Texts without any
+// terms have the zero fingerprint.
+func Fingerprint(text string) uint64 {
+	terms := search.Analyze(text)
+	if len(terms) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	for _, term := range terms {
+		h := fnv.New64a()
+		h.Write([]byte(term))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Distance returns the number of bits in which a and b differ.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Near reports whether a and b are fingerprints of near-duplicate texts.
+func Near(a, b uint64) bool {
+	return Distance(a, b) <= MaxDistance
+}
+
+// Cluster groups fingerprints that are transitively near each other. It
+// returns the indexes into fingerprints of every group with more than one
+// member, each group in ascending order and the groups ordered by their first
+// index.
+func Cluster(fingerprints []uint64) [][]int {
+	parent := make([]int, len(fingerprints))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for band := 0; band < bands; band++ {
+		shift := band * 64 / bands
+		buckets := make(map[uint64][]int)
+		for i, fp := range fingerprints {
+			key := (fp >> shift) & (1<<(64/bands) - 1)
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, bucket := range buckets {
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					a, b := bucket[x], bucket[y]
+					if Near(fingerprints[a], fingerprints[b]) {
+						parent[find(a)] = find(b)
+					}
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range fingerprints {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	var clusters [][]int
+	for _, group := range groups {
+		if len(group) > 1 {
+			clusters = append(clusters, group)
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i][0] < clusters[j][0]
+	})
+	return clusters
+}
@@ -0,0 +1,119 @@
+package simhash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	budgetStory = "The city council approved a new budget on Tuesday that increases funding for public transport by 12 percent. " +
+		"The mayor said the extra money would be used to buy electric buses and extend night service on the busiest routes. " +
+		"Opposition members criticised the plan, arguing that the increase would require higher property taxes next year."
+	budgetRewrite = "The city council on Tuesday approved a new budget increasing public transport funding by 12 percent. " +
+		"The mayor said the extra money will buy electric buses and extend night service on the busiest routes. " +
+		"Opposition members criticised the plan, saying it would require higher property taxes next year."
+	frogStory = "Scientists have discovered a new species of frog in the rainforests of Ecuador. " +
+		"The tiny amphibian, less than two centimetres long, was found during an expedition last spring " +
+		"and is believed to be endangered because of deforestation in the region."
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		near bool
+	}{
+		{
+			name: "identical text",
+			a:    budgetStory,
+			b:    budgetStory,
+			near: true,
+		},
+		{
+			name: "case, punctuation and stop words are ignored",
+			a:    "Council approves the budget.",
+			b:    "council approves budget",
+			near: true,
+		},
+		{
+			name: "rewritten story",
+			a:    budgetStory,
+			b:    budgetRewrite,
+			near: true,
+		},
+		{
+			name: "story with appended credit",
+			a:    budgetStory,
+			b:    budgetStory + " Reporting by city desk staff.",
+			near: true,
+		},
+		{
+			name: "unrelated story",
+			a:    budgetStory,
+			b:    frogStory,
+			near: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.near, Near(Fingerprint(tt.a), Fingerprint(tt.b)))
+		})
+	}
+}
+
+func TestFingerprint_Empty(t *testing.T) {
+	assert.Zero(t, Fingerprint(""))
+	assert.Zero(t, Fingerprint("the of and"))
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, Distance(0xF0F0, 0xF0F0))
+	assert.Equal(t, 4, Distance(0xF0F0, 0xF0FF))
+	assert.Equal(t, 64, Distance(0, ^uint64(0)))
+}
+
+func TestCluster(t *testing.T) {
+	story := Fingerprint(budgetStory)
+	rewrite := Fingerprint(budgetRewrite)
+	frog := Fingerprint(frogStory)
+
+	tests := []struct {
+		name         string
+		fingerprints []uint64
+		want         [][]int
+	}{
+		{
+			name:         "no fingerprints",
+			fingerprints: nil,
+			want:         nil,
+		},
+		{
+			name:         "no duplicates",
+			fingerprints: []uint64{story, frog},
+			want:         nil,
+		},
+		{
+			name:         "near duplicates grouped",
+			fingerprints: []uint64{frog, story, rewrite, story},
+			want:         [][]int{{1, 2, 3}},
+		},
+		{
+			name:         "transitive chain",
+			fingerprints: []uint64{0, 0x3F, 0xFC0, 0xFFFFF000000},
+			want:         [][]int{{0, 1, 2}},
+		},
+		{
+			name:         "separate groups",
+			fingerprints: []uint64{frog, story, frog, rewrite},
+			want:         [][]int{{0, 2}, {1, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Cluster(tt.fingerprints))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// MaxURLs is the protocol limit of URLs in one sitemap file.
+	MaxURLs = 50000
+	// MaxNewsURLs is the Google News limit of URLs in one news sitemap.
+	MaxNewsURLs = 1000
+	// NewsWindow is how far back a news sitemap lists articles.
+	NewsWindow = 48 * time.Hour
+)
+
+const (
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	newsNamespace    = "http://www.google.com/schemas/sitemap-news/0.9"
+)
+
+// URL is one entry of a sitemap. News is set in news sitemaps only.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+	News    *News
+}
+
+// News describes an article in a Google News sitemap.
+type News struct {
+	PublicationName string
+	Language        string
+	PublicationDate time.Time
+	Title           string
+}
+
+// IndexEntry is one child sitemap listed in a sitemap index.
+type IndexEntry struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlElement struct {
+	XMLName xml.Name     `xml:"url"`
+	Loc     string       `xml:"loc"`
+	LastMod string       `xml:"lastmod,omitempty"`
+	News    *newsElement `xml:"news:news,omitempty"`
+}
+
+type newsElement struct {
+	Publication     newsPublication `xml:"news:publication"`
+	PublicationDate string          `xml:"news:publication_date"`
+	Title           string          `xml:"news:title"`
+}
+
+type newsPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Sitemaps []indexElement `xml:"sitemap"`
+}
+
+type indexElement struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Writer streams a urlset document: every entry is encoded as soon as it is
+// added, so a sitemap never has to be held in memory. Nothing is written until
+// the first Add or Close, which lets callers still report errors that happen
+// before the first entry.
+type Writer struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	news    bool
+	started bool
+}
+
+// NewWriter returns a Writer for a plain sitemap or, with news set, a Google
+// News sitemap.
+func NewWriter(w io.Writer, news bool) *Writer {
+	return &Writer{w: w, enc: xml.NewEncoder(w), news: news}
+}
+
+// Started reports whether any output has been written.
+func (w *Writer) Started() bool {
+	return w.started
+}
+
+// Add writes one URL entry.
+func (w *Writer) Add(u URL) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	el := urlElement{Loc: u.Loc, LastMod: formatTime(u.LastMod)}
+	if u.News != nil {
+		el.News = &newsElement{
+			Publication:     newsPublication{Name: u.News.PublicationName, Language: u.News.Language},
+			PublicationDate: formatTime(u.News.PublicationDate),
+			Title:           u.News.Title,
+		}
+	}
+	if err := w.enc.Encode(el); err != nil {
+		return fmt.Errorf("failed to encode sitemap url: %w", err)
+	}
+	return nil
+}
+
+// Close ends the document. A sitemap without entries is still well-formed.
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "urlset"}}); err != nil {
+		return fmt.Errorf("failed to close sitemap: %w", err)
+	}
+	if err := w.enc.Flush(); err != nil {
+		return fmt.Errorf("failed to flush sitemap: %w", err)
+	}
+	return nil
+}
+
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	if _, err := io.WriteString(w.w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write sitemap: %w", err)
+	}
+	attrs := []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: sitemapNamespace}}
+	if w.news {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:news"}, Value: newsNamespace})
+	}
+	if err := w.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "urlset"}, Attr: attrs}); err != nil {
+		return fmt.Errorf("failed to start sitemap: %w", err)
+	}
+	return nil
+}
+
+// WriteIndex writes a sitemap index listing the child sitemaps.
+func WriteIndex(w io.Writer, entries []IndexEntry) error {
+	index := sitemapIndex{Xmlns: sitemapNamespace}
+	for _, e := range entries {
+		index.Sitemaps = append(index.Sitemaps, indexElement{Loc: e.Loc, LastMod: formatTime(e.LastMod)})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write sitemap index: %w", err)
+	}
+	if err := xml.NewEncoder(w).Encode(index); err != nil {
+		return fmt.Errorf("failed to encode sitemap index: %w", err)
+	}
+	return nil
+}
+
+// formatTime renders a W3C datetime, or nothing for the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,107 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	modified := time.Date(2025, 3, 14, 11, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, false)
+	assert.False(t, w.Started())
+	assert.Zero(t, buf.Len(), "nothing is written before the first entry")
+
+	require.NoError(t, w.Add(URL{Loc: "https://news.example/posts/1", LastMod: modified}))
+	require.NoError(t, w.Add(URL{Loc: "https://news.example/posts/2?a=1&b=2"}))
+	require.NoError(t, w.Close())
+	assert.True(t, w.Started())
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.URLs, 2)
+	assert.Equal(t, "https://news.example/posts/1", doc.URLs[0].Loc)
+	assert.Equal(t, "2025-03-14T08:00:00Z", doc.URLs[0].LastMod)
+	assert.Equal(t, "https://news.example/posts/2?a=1&b=2", doc.URLs[1].Loc)
+	assert.NotContains(t, buf.String(), "<lastmod></lastmod>")
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf, false).Close())
+
+	var doc struct {
+		XMLName xml.Name `xml:"urlset"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+}
+
+func TestWriter_News(t *testing.T) {
+	published := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, true)
+	require.NoError(t, w.Add(URL{
+		Loc: "https://news.example/posts/1",
+		News: &News{
+			PublicationName: "News Portal",
+			Language:        "en",
+			PublicationDate: published,
+			Title:           "Budget <vote>",
+		},
+	}))
+	require.NoError(t, w.Close())
+
+	assert.Contains(t, buf.String(), `xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"`)
+
+	var doc struct {
+		URLs []struct {
+			News struct {
+				Name            string `xml:"publication>name"`
+				Language        string `xml:"publication>language"`
+				PublicationDate string `xml:"publication_date"`
+				Title           string `xml:"title"`
+			} `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.URLs, 1)
+	news := doc.URLs[0].News
+	assert.Equal(t, "News Portal", news.Name)
+	assert.Equal(t, "en", news.Language)
+	assert.Equal(t, "2025-03-14T09:00:00Z", news.PublicationDate)
+	assert.Equal(t, "Budget <vote>", news.Title)
+}
+
+func TestWriteIndex(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteIndex(&buf, []IndexEntry{
+		{Loc: "https://news.example/sitemaps/posts-1.xml"},
+		{Loc: "https://news.example/sitemaps/news.xml", LastMod: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)},
+	}))
+
+	var index struct {
+		XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+		Sitemaps []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"sitemap"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &index))
+	require.Len(t, index.Sitemaps, 2)
+	assert.Equal(t, "https://news.example/sitemaps/posts-1.xml", index.Sitemaps[0].Loc)
+	assert.Empty(t, index.Sitemaps[0].LastMod)
+	assert.Equal(t, "2025-03-14T09:00:00Z", index.Sitemaps[1].LastMod)
+}
@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"html/template"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Funcs returns the helper functions available to every template.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"add": func(a, b int) int {
+			return a + b
+		},
+		"subtract": func(a, b int) int {
+			return a - b
+		},
+		"multiply": func(a, b int) int {
+			return a * b
+		},
+		"sequence": func(start, end int) []int {
+			var result []int
+			for i := start; i <= end; i++ {
+				result = append(result, i)
+			}
+			return result
+		},
+		"objectIDToString": func(id primitive.ObjectID) string {
+			return id.Hex()
+		},
+		"join": strings.Join,
+		"contributorRoles": func() []domain.ContributorRole {
+			return domain.ContributorRoles
+		},
+	}
+}
+
+// Parse loads all templates below dir.
+func Parse(dir string) (*template.Template, error) {
+	tmpl := template.New("").Funcs(Funcs())
+	for _, pattern := range []string{"*.html", "layout/*.html", "post/*.html", "modals/*.html", "search/*.html", "admin/*.html", "newsletter/*.html", "notifications/*.html", "bookmarks/*.html", "authors/*.html", "apikeys/*.html"} {
+		var err error
+		tmpl, err = tmpl.ParseGlob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// ParseText loads the plain-text email templates below dir.
+func ParseText(dir string) (*texttemplate.Template, error) {
+	tmpl := texttemplate.New("").Funcs(texttemplate.FuncMap(Funcs()))
+	for _, pattern := range []string{"newsletter/*.txt", "notifications/*.txt"} {
+		var err error
+		tmpl, err = tmpl.ParseGlob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
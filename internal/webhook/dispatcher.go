@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Request headers sent with every delivery.
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature-256"
+)
+
+const (
+	// lease is how long a claimed delivery stays hidden from other workers.
+	// A worker that dies mid-attempt leaves the delivery to be retried after it.
+	lease = time.Minute
+	// maxResponseSize caps how much of a response body is read and discarded.
+	maxResponseSize = 64 << 10
+
+	userAgent = "NewsPortal-Webhook/1.0"
+)
+
+// Payload is the JSON body of a delivery. Post is omitted for deletions.
+// ID identifies the event: receivers use it to discard an event they already
+// received, as a redelivery or a delivery repeated after a failure.
+type Payload struct {
+	ID         string               `json:"id"`
+	Event      domain.PostEventType `json:"event"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	PostID     string               `json:"post_id"`
+	Post       *domain.Post         `json:"post,omitempty"`
+}
+
+// Sign returns the signature header value for body: the hex encoded
+// HMAC-SHA256 of body keyed with secret, prefixed with "sha256=".
+// Receivers recompute it to verify that a delivery is authentic.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher turns post events into webhook deliveries and sends them from a
+// pool of workers. Deliveries are persisted before they are sent, so pending
+// deliveries and retries survive restarts.
+type Dispatcher struct {
+	webhooks   domain.WebhookRepository
+	deliveries domain.WebhookDeliveryRepository
+	client     *http.Client
+	workers    int
+	poll       time.Duration
+	wake       chan struct{}
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// Option configures optional Dispatcher settings.
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client used to send deliveries.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithWorkers sets the number of deliveries sent concurrently.
+func WithWorkers(n int) Option {
+	return func(d *Dispatcher) {
+		d.workers = n
+	}
+}
+
+// WithPollInterval sets how often idle workers look for retries that became due.
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.poll = interval
+	}
+}
+
+// WithLogger sets the logger used to report delivery failures.
+func WithLogger(logger *zap.Logger) Option {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+func NewDispatcher(webhooks domain.WebhookRepository, deliveries domain.WebhookDeliveryRepository, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		workers:    4,
+		poll:       5 * time.Second,
+		wake:       make(chan struct{}, 1),
+		now:        time.Now,
+		logger:     zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.workers < 1 {
+		d.workers = 1
+	}
+	return d
+}
+
+// HandlePostEvent implements domain.PostEventHandler. It records a delivery
+// for every webhook subscribed to the event and wakes the workers. Webhooks
+// that already have a delivery of the event are skipped, so that an event
+// published again by the outbox relay is not delivered twice.
+func (d *Dispatcher) HandlePostEvent(ctx context.Context, event domain.PostEvent) error {
+	hooks, err := d.webhooks.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load webhooks: %w", err)
+	}
+
+	var body []byte
+	var errs []error
+	for _, hook := range hooks {
+		if !hook.Subscribes(event.Type) {
+			continue
+		}
+		if body == nil {
+			body, err = json.Marshal(Payload{
+				ID:         event.ID,
+				Event:      event.Type,
+				OccurredAt: event.OccurredAt,
+				PostID:     event.PostID,
+				Post:       event.Post,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to encode webhook payload: %w", err)
+			}
+		}
+
+		delivery := domain.NewWebhookDelivery(hook, event, body, Sign(hook.Secret, body))
+		err := d.deliveries.Create(ctx, delivery)
+		if errors.Is(err, domain.ErrDuplicateDelivery) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to record delivery to %s: %w", hook.URL, err))
+		}
+	}
+
+	if body != nil {
+		d.Notify()
+	}
+	return errors.Join(errs...)
+}
+
+// Publish implements domain.EventPublisher so that the outbox relay can feed
+// the dispatcher. Deliveries are recorded just like in HandlePostEvent.
+func (d *Dispatcher) Publish(ctx context.Context, event domain.PostEvent) error {
+	return d.HandlePostEvent(ctx, event)
+}
+
+// Notify wakes an idle worker to look for due deliveries.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run starts the workers and blocks until ctx is cancelled and every worker
+// has finished its current attempt.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.Info("starting webhook delivery", zap.Int("workers", d.workers))
+
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+	wg.Wait()
+	d.logger.Info("webhook delivery stopped")
+}
+
+// work sends due deliveries until none is left, then sleeps until it is
+// woken up or the poll interval has passed.
+func (d *Dispatcher) work(ctx context.Context) {
+	ticker := time.NewTicker(d.poll)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && d.DeliverNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverNext claims one due delivery and attempts it. It reports whether a
+// delivery was claimed. The outcome is recorded on the delivery, which is
+// retried with backoff when the attempt failed.
+func (d *Dispatcher) DeliverNext(ctx context.Context) bool {
+	delivery, err := d.deliveries.ClaimDue(ctx, d.now(), lease)
+	if err != nil {
+		d.logger.Error("failed to claim webhook delivery", zap.Error(err))
+		return false
+	}
+	if delivery == nil {
+		return false
+	}
+	// More deliveries may be due; let another idle worker look while this one sends.
+	d.Notify()
+
+	status, sendErr := d.send(ctx, delivery)
+	delivery.Attempted(d.now(), status, sendErr)
+	if err := d.deliveries.Save(ctx, delivery); err != nil {
+		d.logger.Error("failed to save webhook delivery", zap.String("delivery_id", delivery.ID.Hex()), zap.Error(err))
+	}
+
+	if sendErr != nil {
+		d.logger.Warn("webhook delivery attempt failed",
+			zap.String("delivery_id", delivery.ID.Hex()),
+			zap.String("url", delivery.URL),
+			zap.Int("attempt", delivery.Attempts),
+			zap.String("status", string(delivery.Status)),
+			zap.Error(sendErr),
+		)
+	}
+	return true
+}
+
+// send posts the delivery payload and returns the response status. Any
+// status outside 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(SignatureHeader, delivery.Signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,244 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kir/news-app/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryWebhooks is an in-memory domain.WebhookRepository.
+type memoryWebhooks struct {
+	hooks []*domain.Webhook
+}
+
+func (m *memoryWebhooks) Create(ctx context.Context, hook *domain.Webhook) error {
+	m.hooks = append(m.hooks, hook)
+	return nil
+}
+
+func (m *memoryWebhooks) GetAll(ctx context.Context) ([]*domain.Webhook, error) {
+	return m.hooks, nil
+}
+
+func (m *memoryWebhooks) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+// memoryDeliveries is an in-memory domain.WebhookDeliveryRepository that
+// stores copies, like a database would.
+type memoryDeliveries struct {
+	mu         sync.Mutex
+	deliveries map[primitive.ObjectID]domain.WebhookDelivery
+}
+
+func newMemoryDeliveries() *memoryDeliveries {
+	return &memoryDeliveries{deliveries: make(map[primitive.ObjectID]domain.WebhookDelivery)}
+}
+
+func (m *memoryDeliveries) Create(ctx context.Context, d *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.deliveries {
+		if d.EventID != "" && existing.EventID == d.EventID && existing.WebhookID == d.WebhookID {
+			return domain.ErrDuplicateDelivery
+		}
+	}
+	m.deliveries[d.ID] = *d
+	return nil
+}
+
+func (m *memoryDeliveries) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.deliveries {
+		if d.ID.Hex() == id {
+			return &d, nil
+		}
+	}
+	return nil, domain.ErrDeliveryNotFound
+}
+
+func (m *memoryDeliveries) GetRecent(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var recent []*domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		d := d
+		recent = append(recent, &d)
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i].CreatedAt.After(recent[j].CreatedAt) })
+	return recent, nil
+}
+
+func (m *memoryDeliveries) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, d := range m.deliveries {
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			m.deliveries[id] = d
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryDeliveries) Save(ctx context.Context, d *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.ID] = *d
+	return nil
+}
+
+func (m *memoryDeliveries) all() []domain.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var all []domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		all = append(all, d)
+	}
+	return all
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"post.created"}`)
+	signature := Sign("secret", body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.Equal(t, signature, Sign("secret", body))
+	assert.NotEqual(t, signature, Sign("other", body))
+	assert.NotEqual(t, signature, Sign("secret", []byte(`{"event":"post.deleted"}`)))
+}
+
+func TestDispatcher_HandlePostEvent(t *testing.T) {
+	all := &domain.Webhook{ID: primitive.NewObjectID(), URL: "https://a.example/hook", Secret: "a"}
+	publishedOnly := &domain.Webhook{ID: primitive.NewObjectID(), URL: "https://b.example/hook", Secret: "b", Events: []domain.PostEventType{domain.PostPublished}}
+	deliveries := newMemoryDeliveries()
+	dispatcher := NewDispatcher(&memoryWebhooks{hooks: []*domain.Webhook{all, publishedOnly}}, deliveries)
+
+	post := &domain.Post{ID: primitive.NewObjectID(), Title: "Budget approved"}
+	event := domain.PostEvent{ID: "event-1", Type: domain.PostCreated, PostID: post.ID.Hex(), Post: post, OccurredAt: time.Now()}
+	require.NoError(t, dispatcher.HandlePostEvent(context.Background(), event))
+
+	recorded := deliveries.all()
+	require.Len(t, recorded, 1)
+	d := recorded[0]
+	assert.Equal(t, all.ID, d.WebhookID)
+	assert.Equal(t, all.URL, d.URL)
+	assert.Equal(t, "event-1", d.EventID)
+	assert.Equal(t, domain.PostCreated, d.Event)
+	assert.Equal(t, domain.DeliveryPending, d.Status)
+	assert.Equal(t, Sign("a", []byte(d.Payload)), d.Signature)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal([]byte(d.Payload), &payload))
+	assert.Equal(t, "event-1", payload.ID)
+	assert.Equal(t, domain.PostCreated, payload.Event)
+	assert.Equal(t, post.ID.Hex(), payload.PostID)
+	require.NotNil(t, payload.Post)
+	assert.Equal(t, "Budget approved", payload.Post.Title)
+
+	// The outbox relay publishes an event again when another publisher failed.
+	require.NoError(t, dispatcher.HandlePostEvent(context.Background(), event))
+	assert.Len(t, deliveries.all(), 1)
+
+	require.NoError(t, dispatcher.HandlePostEvent(context.Background(), domain.PostEvent{ID: "event-2", Type: domain.PostPublished, PostID: post.ID.Hex(), Post: post}))
+	assert.Len(t, deliveries.all(), 3)
+}
+
+func TestDispatcher_DeliverNext(t *testing.T) {
+	status := http.StatusOK
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	hook := &domain.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret"}
+	deliveries := newMemoryDeliveries()
+	dispatcher := NewDispatcher(&memoryWebhooks{hooks: []*domain.Webhook{hook}}, deliveries)
+	ctx := context.Background()
+
+	require.NoError(t, dispatcher.HandlePostEvent(ctx, domain.PostEvent{Type: domain.PostDeleted, PostID: "post-1"}))
+	delivery := deliveries.all()[0]
+	now := delivery.NextAttemptAt
+	dispatcher.now = func() time.Time { return now }
+
+	status = http.StatusServiceUnavailable
+	assert.True(t, dispatcher.DeliverNext(ctx))
+	failed := deliveries.all()[0]
+	assert.Equal(t, domain.DeliveryPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, failed.ResponseStatus)
+	assert.Equal(t, "unexpected status 503", failed.LastError)
+	assert.Equal(t, now.Add(domain.FirstRetryDelay), failed.NextAttemptAt)
+	assert.False(t, dispatcher.DeliverNext(ctx), "retry is not due yet")
+
+	status = http.StatusNoContent
+	now = now.Add(domain.FirstRetryDelay)
+	received = nil
+	assert.True(t, dispatcher.DeliverNext(ctx))
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, string(domain.PostDeleted), received.Header.Get(EventHeader))
+	assert.Equal(t, delivery.ID.Hex(), received.Header.Get(DeliveryHeader))
+	assert.Equal(t, Sign("s3cret", receivedBody), received.Header.Get(SignatureHeader))
+	assert.Equal(t, delivery.Payload, string(receivedBody))
+
+	succeeded := deliveries.all()[0]
+	assert.Equal(t, domain.DeliverySucceeded, succeeded.Status)
+	assert.Equal(t, 2, succeeded.Attempts)
+	assert.Empty(t, succeeded.LastError)
+	assert.False(t, dispatcher.DeliverNext(ctx))
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	received := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(EventHeader)
+	}))
+	defer server.Close()
+
+	hook := &domain.Webhook{ID: primitive.NewObjectID(), URL: server.URL, Secret: "s3cret"}
+	dispatcher := NewDispatcher(&memoryWebhooks{hooks: []*domain.Webhook{hook}}, newMemoryDeliveries(),
+		WithWorkers(2),
+		WithPollInterval(time.Hour),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	require.NoError(t, dispatcher.HandlePostEvent(context.Background(), domain.PostEvent{Type: domain.PostCreated, PostID: "post-1"}))
+	select {
+	case event := <-received:
+		assert.Equal(t, string(domain.PostCreated), event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery was not sent")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatcher did not stop")
+	}
+}
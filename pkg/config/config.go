@@ -10,12 +10,44 @@ type Config struct {
 	MongoURI      string        `env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
 	MongoDatabase string        `env:"MONGO_DATABASE" envDefault:"newsapp"`
 	MongoTimeout  time.Duration `env:"MONGO_TIMEOUT" envDefault:"5s"`
+	// PublicBaseURL is the externally visible site root used for absolute links.
+	PublicBaseURL string `env:"PUBLIC_BASE_URL" envDefault:"http://localhost:8080"`
 	Server        struct {
 		Address      string        `env:"SERVER_ADDRESS" envDefault:":8080"`
 		ReadTimeout  time.Duration `env:"SERVER_READ_TIMEOUT" envDefault:"10s"`
 		WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT" envDefault:"10s"`
 		IdleTimeout  time.Duration `env:"SERVER_IDLE_TIMEOUT" envDefault:"60s"`
 	}
+	// Ingest controls polling of external feed sources.
+	Ingest struct {
+		Enabled bool          `env:"INGEST_ENABLED" envDefault:"true"`
+		Tick    time.Duration `env:"INGEST_TICK" envDefault:"1m"`
+	}
+	// Webhook sizes the pool of workers sending webhook deliveries.
+	Webhook struct {
+		Workers int `env:"WEBHOOK_WORKERS" envDefault:"4"`
+	}
+	// SMTP is the server newsletter emails are sent through. Username may be
+	// empty for servers that accept mail without authentication.
+	SMTP struct {
+		Addr     string `env:"SMTP_ADDR" envDefault:"localhost:1025"`
+		Username string `env:"SMTP_USERNAME"`
+		Password string `env:"SMTP_PASSWORD"`
+		From     string `env:"SMTP_FROM" envDefault:"News Portal <newsletter@localhost>"`
+	}
+	// Notification sizes the pool of workers emailing notifications.
+	Notification struct {
+		Workers int `env:"NOTIFICATION_WORKERS" envDefault:"2"`
+	}
+	// Admin holds the token of the admin pages listing personal data, sent as
+	// the Basic authentication password. Without it those pages are disabled.
+	Admin struct {
+		Token string `env:"ADMIN_TOKEN"`
+	}
+	// Newsletter sets how often the digest scheduler looks for due digests.
+	Newsletter struct {
+		Tick time.Duration `env:"NEWSLETTER_TICK" envDefault:"1m"`
+	}
 }
 
 func Load() (*Config, error) {